@@ -18,6 +18,7 @@ import (
 	clusterPkg "github.com/open-ness/EMCO/src/clm/pkg/cluster"
 	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/validation"
+	orchModule "github.com/open-ness/EMCO/src/orchestrator/pkg/module"
 
 	"github.com/gorilla/mux"
 )
@@ -26,6 +27,21 @@ var cpJSONFile string = "json-schemas/metadata.json"
 var ckvJSONFile string = "json-schemas/cluster-kv.json"
 var clJSONFile string = "json-schemas/cluster-label.json"
 
+// validProviderProject checks the project a cluster provider is dedicated to,
+// if any, exists
+func validProviderProject(w http.ResponseWriter, p clusterPkg.ClusterProvider) bool {
+	if p.Spec.Project == "" {
+		return true
+	}
+	_, err := orchModule.NewProjectClient().GetProject(p.Spec.Project)
+	if err != nil {
+		log.Error(":: Project of the cluster provider not found ::", log.Fields{"Error": err, "Project": p.Spec.Project})
+		http.Error(w, "Project does not exist", http.StatusNotFound)
+		return false
+	}
+	return true
+}
+
 // Used to store backend implementations objects
 // Also simplifies mocking for unit testing purposes
 type clusterHandler struct {
@@ -65,7 +81,11 @@ func (h clusterHandler) createClusterProviderHandler(w http.ResponseWriter, r *h
 		return
 	}
 
-	ret, err := h.client.CreateClusterProvider(p, false)
+	if !validProviderProject(w, p) {
+		return
+	}
+
+	ret, err := h.client.WithContext(r.Context()).CreateClusterProvider(p, false)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "ClusterProvider already exists") {
@@ -127,7 +147,11 @@ func (h clusterHandler) putClusterProviderHandler(w http.ResponseWriter, r *http
 		return
 	}
 
-	ret, err := h.client.CreateClusterProvider(p, true)
+	if !validProviderProject(w, p) {
+		return
+	}
+
+	ret, err := h.client.WithContext(r.Context()).CreateClusterProvider(p, true)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -166,7 +190,7 @@ func (h clusterHandler) getClusterProviderHandler(w http.ResponseWriter, r *http
 			return
 		}
 	} else {
-		ret, err = h.client.GetClusterProvider(name)
+		ret, err = h.client.WithContext(r.Context()).GetClusterProvider(name)
 		if err != nil {
 			log.Error(":: Error getting cluster provider ::", log.Fields{"Error": err, "Name": name})
 			if strings.Contains(err.Error(), "db Find error") {
@@ -195,7 +219,7 @@ func (h clusterHandler) deleteClusterProviderHandler(w http.ResponseWriter, r *h
 	vars := mux.Vars(r)
 	name := vars["name"]
 
-	err := h.client.DeleteClusterProvider(name)
+	err := h.client.WithContext(r.Context()).DeleteClusterProvider(name)
 	if err != nil {
 		log.Error(":: Error deleting cluster provider ::", log.Fields{"Error": err, "Name": name})
 		if strings.Contains(err.Error(), "not found") {
@@ -275,7 +299,23 @@ func (h clusterHandler) createClusterHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	ret, err := h.client.CreateCluster(provider, p, q)
+	// The clusters of a cluster provider dedicated to a project count in the
+	// quota of the project
+	if cp, err := h.client.GetClusterProvider(provider); err == nil && cp.Spec.Project != "" {
+		release, err := orchModule.CheckClusterQuota(cp.Spec.Project, provider, p.Metadata.Name)
+		if err != nil {
+			log.Error(err.Error(), log.Fields{})
+			if strings.Contains(err.Error(), "quota exceeded") {
+				http.Error(w, err.Error(), http.StatusForbidden)
+			} else {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+		defer release()
+	}
+
+	ret, err := h.client.WithContext(r.Context()).CreateCluster(provider, p, q)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "ClusterProvider does not exist") {
@@ -391,7 +431,7 @@ func (h clusterHandler) getClusterHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	retCluster, err := h.client.GetCluster(provider, name)
+	retCluster, err := h.client.WithContext(r.Context()).GetCluster(provider, name)
 	if err != nil {
 		log.Error(":: Error getting cluster ::", log.Fields{"Error": err})
 		if strings.Contains(err.Error(), "db Find error") {
@@ -404,7 +444,7 @@ func (h clusterHandler) getClusterHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	retKubeconfig, err := h.client.GetClusterContent(provider, name)
+	retKubeconfig, err := h.client.WithContext(r.Context()).GetClusterContent(provider, name)
 	if err != nil {
 		log.Error(":: Error getting cluster content ::", log.Fields{"Error": err})
 		if strings.Contains(err.Error(), "not found") {
@@ -486,7 +526,7 @@ func (h clusterHandler) deleteClusterHandler(w http.ResponseWriter, r *http.Requ
 	provider := vars["provider-name"]
 	name := vars["name"]
 
-	err := h.client.DeleteCluster(provider, name)
+	err := h.client.WithContext(r.Context()).DeleteCluster(provider, name)
 	if err != nil {
 		log.Error(":: Error deleting cluster ::", log.Fields{"Error": err})
 		if strings.Contains(err.Error(), "not found") {
@@ -535,7 +575,7 @@ func (h clusterHandler) createClusterLabelHandler(w http.ResponseWriter, r *http
 		return
 	}
 
-	ret, err := h.client.CreateClusterLabel(provider, cluster, p, false)
+	ret, err := h.client.WithContext(r.Context()).CreateClusterLabel(provider, cluster, p, false)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "Cluster does not exist") {
@@ -599,7 +639,7 @@ func (h clusterHandler) putClusterLabelHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	ret, err := h.client.CreateClusterLabel(provider, cluster, p, true)
+	ret, err := h.client.WithContext(r.Context()).CreateClusterLabel(provider, cluster, p, true)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "Cluster does not exist") {
@@ -647,7 +687,7 @@ func (h clusterHandler) getClusterLabelHandler(w http.ResponseWriter, r *http.Re
 			return
 		}
 	} else {
-		ret, err = h.client.GetClusterLabel(provider, cluster, label)
+		ret, err = h.client.WithContext(r.Context()).GetClusterLabel(provider, cluster, label)
 		if err != nil {
 			log.Error(":: Error getting cluster label ::", log.Fields{"Error": err})
 			if strings.Contains(err.Error(), "db Find error") {
@@ -678,7 +718,7 @@ func (h clusterHandler) deleteClusterLabelHandler(w http.ResponseWriter, r *http
 	cluster := vars["cluster-name"]
 	label := vars["label"]
 
-	err := h.client.DeleteClusterLabel(provider, cluster, label)
+	err := h.client.WithContext(r.Context()).DeleteClusterLabel(provider, cluster, label)
 	if err != nil {
 		log.Error(":: Error deleting cluster label ::", log.Fields{"Error": err})
 		if strings.Contains(err.Error(), "not found") {
@@ -728,7 +768,7 @@ func (h clusterHandler) createClusterKvPairsHandler(w http.ResponseWriter, r *ht
 		return
 	}
 
-	ret, err := h.client.CreateClusterKvPairs(provider, cluster, p, false)
+	ret, err := h.client.WithContext(r.Context()).CreateClusterKvPairs(provider, cluster, p, false)
 	if err != nil {
 		log.Error(":: Error creating cluster kv pair ::", log.Fields{"Error": err})
 		if strings.Contains(err.Error(), "Cluster does not exist") {
@@ -793,7 +833,7 @@ func (h clusterHandler) putClusterKvPairsHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
-	ret, err := h.client.CreateClusterKvPairs(provider, cluster, p, true)
+	ret, err := h.client.WithContext(r.Context()).CreateClusterKvPairs(provider, cluster, p, true)
 	if err != nil {
 		log.Error(":: Error updating cluster kv pair ::", log.Fields{"Error": err})
 		if strings.Contains(err.Error(), "Cluster does not exist") {
@@ -842,7 +882,7 @@ func (h clusterHandler) getClusterKvPairsHandler(w http.ResponseWriter, r *http.
 			return
 		}
 	} else if len(kvkey) != 0 {
-		ret, err = h.client.GetClusterKvPairsValue(provider, cluster, kvpair, kvkey)
+		ret, err = h.client.WithContext(r.Context()).GetClusterKvPairsValue(provider, cluster, kvpair, kvkey)
 		if err != nil {
 			log.Error(":: Error getting cluster key value pair key value ::", log.Fields{"Error": err})
 			if strings.Contains(err.Error(), "db Find error") {
@@ -855,7 +895,7 @@ func (h clusterHandler) getClusterKvPairsHandler(w http.ResponseWriter, r *http.
 			return
 		}
 	} else {
-		ret, err = h.client.GetClusterKvPairs(provider, cluster, kvpair)
+		ret, err = h.client.WithContext(r.Context()).GetClusterKvPairs(provider, cluster, kvpair)
 		if err != nil {
 			log.Error(":: Error getting cluster kv pair ::", log.Fields{"Error": err})
 			if strings.Contains(err.Error(), "db Find error") {
@@ -886,7 +926,7 @@ func (h clusterHandler) deleteClusterKvPairsHandler(w http.ResponseWriter, r *ht
 	cluster := vars["cluster-name"]
 	kvpair := vars["kvpair"]
 
-	err := h.client.DeleteClusterKvPairs(provider, cluster, kvpair)
+	err := h.client.WithContext(r.Context()).DeleteClusterKvPairs(provider, cluster, kvpair)
 	if err != nil {
 		log.Error(":: Error deleting cluster kv pair ::", log.Fields{"Error": err})
 		if strings.Contains(err.Error(), "not found") {
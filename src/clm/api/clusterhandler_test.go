@@ -5,6 +5,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"mime/multipart"
@@ -38,6 +39,10 @@ type mockClusterManager struct {
 	Err                  error
 }
 
+func (m *mockClusterManager) WithContext(ctx context.Context) cluster.ClusterManager {
+	return m
+}
+
 func (m *mockClusterManager) CreateClusterProvider(inp cluster.ClusterProvider, exists bool) (cluster.ClusterProvider, error) {
 	if m.Err != nil {
 		return cluster.ClusterProvider{}, m.Err
@@ -14,6 +14,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -22,6 +23,7 @@ import (
 	"reflect"
 	"testing"
 
+	controller "github.com/open-ness/EMCO/src/clm/pkg/controller"
 	clmModel "github.com/open-ness/EMCO/src/clm/pkg/model"
 	mtypes "github.com/open-ness/EMCO/src/orchestrator/pkg/module/types"
 	pkgerrors "github.com/pkg/errors"
@@ -680,6 +682,10 @@ type mockControllerManager struct {
 	Err   error
 }
 
+func (m *mockControllerManager) WithContext(ctx context.Context) controller.ControllerManager {
+	return m
+}
+
 func (m *mockControllerManager) CreateController(ms clmModel.Controller, mayExist bool) (clmModel.Controller, error) {
 	if m.Err != nil {
 		return clmModel.Controller{}, m.Err
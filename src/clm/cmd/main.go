@@ -12,16 +12,19 @@ import (
 	"os/signal"
 	"time"
 
-	"github.com/gorilla/handlers"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/auth"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/config"
 	contextDb "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/contextdb"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/metrics"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/middleware"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/tracing"
 	"github.com/open-ness/EMCO/src/clm/api"
 )
 
 func main() {
 	rand.Seed(time.Now().UnixNano())
+	tracing.Init("clm")
 
 	err := db.InitializeDatabaseConnection("mco")
 	if err != nil {
@@ -37,9 +40,11 @@ func main() {
 	}
 
 	httpRouter := api.NewRouter(nil)
-	loggedRouter := handlers.LoggingHandler(os.Stdout, httpRouter)
+	loggedRouter := middleware.Handler("clm", httpRouter)
 	log.Println("Starting Cluster Manager")
 
+	go metrics.Serve(config.GetConfiguration().MetricsPort)
+
 	httpServer := &http.Server{
 		Handler: loggedRouter,
 		Addr:    ":" + config.GetConfiguration().ServicePort,
@@ -51,6 +56,7 @@ func main() {
 		signal.Notify(c, os.Interrupt)
 		<-c
 		httpServer.Shutdown(context.Background())
+		tracing.Shutdown()
 		close(connectionsClose)
 	}()
 
@@ -4,6 +4,7 @@
 package cluster
 
 import (
+	"context"
 	"strings"
 	"time"
 
@@ -29,7 +30,15 @@ type clientDbInfo struct {
 
 // ClusterProvider contains the parameters needed for ClusterProviders
 type ClusterProvider struct {
-	Metadata mtypes.Metadata `json:"metadata"`
+	Metadata mtypes.Metadata     `json:"metadata"`
+	Spec     ClusterProviderSpec `json:"spec,omitempty"`
+}
+
+// ClusterProviderSpec contains the settings of a ClusterProvider
+type ClusterProviderSpec struct {
+	// Project the clusters of the provider are dedicated to, they count in
+	// the cluster quota of the project
+	Project string `json:"project,omitempty"`
 }
 
 type Cluster struct {
@@ -116,12 +125,15 @@ type ClusterManager interface {
 	GetClusterKvPairsValue(provider, cluster, kvpair, kvkey string) (interface{}, error)
 	GetAllClusterKvPairs(provider, cluster string) ([]ClusterKvPairs, error)
 	DeleteClusterKvPairs(provider, cluster, kvpair string) error
+	WithContext(ctx context.Context) ClusterManager
 }
 
 // ClusterClient implements the Manager
 // It will also be used to maintain some localized state
 type ClusterClient struct {
 	db clientDbInfo
+	// ctx is the context of the request using the client
+	ctx context.Context
 }
 
 // NewClusterClient returns an instance of the ClusterClient
@@ -136,6 +148,14 @@ func NewClusterClient() *ClusterClient {
 	}
 }
 
+// WithContext returns a copy of the client making the database calls of
+// the cluster resources of a request with its context
+func (v *ClusterClient) WithContext(ctx context.Context) ClusterManager {
+	c := *v
+	c.ctx = ctx
+	return &c
+}
+
 // CreateClusterProvider - create a new Cluster Provider
 func (v *ClusterClient) CreateClusterProvider(p ClusterProvider, exists bool) (ClusterProvider, error) {
 
@@ -150,7 +170,7 @@ func (v *ClusterClient) CreateClusterProvider(p ClusterProvider, exists bool) (C
 		return ClusterProvider{}, pkgerrors.New("ClusterProvider already exists")
 	}
 
-	err = db.DBconn.Insert(v.db.storeName, key, nil, v.db.tagMeta, p)
+	err = db.WithContext(v.ctx).Insert(v.db.storeName, key, nil, v.db.tagMeta, p)
 	if err != nil {
 		return ClusterProvider{}, pkgerrors.Wrap(err, "Creating DB Entry")
 	}
@@ -166,7 +186,7 @@ func (v *ClusterClient) GetClusterProvider(name string) (ClusterProvider, error)
 		ClusterProviderName: name,
 	}
 
-	value, err := db.DBconn.Find(v.db.storeName, key, v.db.tagMeta)
+	value, err := db.WithContext(v.ctx).Find(v.db.storeName, key, v.db.tagMeta)
 	if err != nil {
 		return ClusterProvider{}, pkgerrors.Wrap(err, "db Find error")
 	} else if len(value) == 0 {
@@ -220,7 +240,7 @@ func (v *ClusterClient) DeleteClusterProvider(name string) error {
 		ClusterProviderName: name,
 	}
 
-	err := db.DBconn.Remove(v.db.storeName, key)
+	err := db.WithContext(v.ctx).Remove(v.db.storeName, key)
 	if err != nil {
 		if strings.Contains(err.Error(), "Error finding:") {
 			return pkgerrors.Wrap(err, "db Remove error - not found")
@@ -255,7 +275,7 @@ func (v *ClusterClient) CreateCluster(provider string, p Cluster, q ClusterConte
 		return Cluster{}, pkgerrors.New("Cluster already exists")
 	}
 
-	err = db.DBconn.Insert(v.db.storeName, key, nil, v.db.tagMeta, p)
+	err = db.WithContext(v.ctx).Insert(v.db.storeName, key, nil, v.db.tagMeta, p)
 	if err != nil {
 		return Cluster{}, pkgerrors.Wrap(err, "Creating DB Entry")
 	}
@@ -269,7 +289,7 @@ func (v *ClusterClient) CreateCluster(provider string, p Cluster, q ClusterConte
 	}
 	s.Actions = append(s.Actions, a)
 
-	err = db.DBconn.Insert(v.db.storeName, key, nil, v.db.tagState, s)
+	err = db.WithContext(v.ctx).Insert(v.db.storeName, key, nil, v.db.tagState, s)
 	if err != nil {
 		return Cluster{}, pkgerrors.Wrap(err, "Creating cluster StateInfo")
 	}
@@ -304,7 +324,7 @@ func (v *ClusterClient) GetCluster(provider, name string) (Cluster, error) {
 		ClusterName:         name,
 	}
 
-	value, err := db.DBconn.Find(v.db.storeName, key, v.db.tagMeta)
+	value, err := db.WithContext(v.ctx).Find(v.db.storeName, key, v.db.tagMeta)
 	if err != nil {
 		return Cluster{}, pkgerrors.Wrap(err, "db Find error")
 	} else if len(value) == 0 {
@@ -353,7 +373,7 @@ func (v *ClusterClient) GetClusterState(provider, name string) (state.StateInfo,
 		ClusterName:         name,
 	}
 
-	result, err := db.DBconn.Find(v.db.storeName, key, v.db.tagState)
+	result, err := db.WithContext(v.ctx).Find(v.db.storeName, key, v.db.tagState)
 	if err != nil {
 		return state.StateInfo{}, pkgerrors.Wrap(err, "db Find error")
 	} else if len(result) == 0 {
@@ -466,7 +486,7 @@ func (v *ClusterClient) DeleteCluster(provider, name string) error {
 	if err != nil {
 		// If the StateInfo cannot be found, then a proper cluster record is not present.
 		// Call the DB delete to clean up any errant record without a StateInfo element that may exist.
-		err = db.DBconn.Remove(v.db.storeName, key)
+		err = db.WithContext(v.ctx).Remove(v.db.storeName, key)
 		if err != nil {
 			if strings.Contains(err.Error(), "Error finding:") {
 				return pkgerrors.Wrap(err, "db Remove error - not found")
@@ -510,7 +530,7 @@ func (v *ClusterClient) DeleteCluster(provider, name string) error {
 		}
 	}
 
-	err = db.DBconn.Remove(v.db.storeName, key)
+	err = db.WithContext(v.ctx).Remove(v.db.storeName, key)
 	if err != nil {
 		return pkgerrors.Wrap(err, "Delete Cluster Entry;")
 	}
@@ -557,7 +577,7 @@ func (v *ClusterClient) CreateClusterLabel(provider string, cluster string, p Cl
 		return ClusterLabel{}, pkgerrors.New("Cluster Label already exists")
 	}
 
-	err = db.DBconn.Insert(v.db.storeName, key, nil, v.db.tagMeta, p)
+	err = db.WithContext(v.ctx).Insert(v.db.storeName, key, nil, v.db.tagMeta, p)
 	if err != nil {
 		return ClusterLabel{}, pkgerrors.Wrap(err, "Creating DB Entry")
 	}
@@ -574,7 +594,7 @@ func (v *ClusterClient) GetClusterLabel(provider, cluster, label string) (Cluste
 		ClusterLabelName:    label,
 	}
 
-	value, err := db.DBconn.Find(v.db.storeName, key, v.db.tagMeta)
+	value, err := db.WithContext(v.ctx).Find(v.db.storeName, key, v.db.tagMeta)
 	if err != nil {
 		return ClusterLabel{}, pkgerrors.Wrap(err, "db Find error")
 	} else if len(value) == 0 {
@@ -636,7 +656,7 @@ func (v *ClusterClient) DeleteClusterLabel(provider, cluster, label string) erro
 		ClusterLabelName:    label,
 	}
 
-	err := db.DBconn.Remove(v.db.storeName, key)
+	err := db.WithContext(v.ctx).Remove(v.db.storeName, key)
 	if err != nil {
 		if strings.Contains(err.Error(), "Error finding:") {
 			return pkgerrors.Wrap(err, "db Remove error - not found")
@@ -670,7 +690,7 @@ func (v *ClusterClient) CreateClusterKvPairs(provider string, cluster string, p
 		return ClusterKvPairs{}, pkgerrors.New("Cluster KV Pair already exists")
 	}
 
-	err = db.DBconn.Insert(v.db.storeName, key, nil, v.db.tagMeta, p)
+	err = db.WithContext(v.ctx).Insert(v.db.storeName, key, nil, v.db.tagMeta, p)
 	if err != nil {
 		return ClusterKvPairs{}, pkgerrors.Wrap(err, "Creating DB Entry")
 	}
@@ -687,7 +707,7 @@ func (v *ClusterClient) GetClusterKvPairs(provider, cluster, kvpair string) (Clu
 		ClusterKvPairsName:  kvpair,
 	}
 
-	value, err := db.DBconn.Find(v.db.storeName, key, v.db.tagMeta)
+	value, err := db.WithContext(v.ctx).Find(v.db.storeName, key, v.db.tagMeta)
 	if err != nil {
 		return ClusterKvPairs{}, pkgerrors.Wrap(err, "db Find error")
 	} else if len(value) == 0 {
@@ -716,7 +736,7 @@ func (v *ClusterClient) GetClusterKvPairsValue(provider, cluster, kvpair, kvkey
 		ClusterKvPairsName:  kvpair,
 	}
 
-	value, err := db.DBconn.Find(v.db.storeName, key, v.db.tagMeta)
+	value, err := db.WithContext(v.ctx).Find(v.db.storeName, key, v.db.tagMeta)
 	if err != nil {
 		return ClusterKvPairs{}, pkgerrors.Wrap(err, "db Find error")
 	} else if len(value) == 0 {
@@ -786,7 +806,7 @@ func (v *ClusterClient) DeleteClusterKvPairs(provider, cluster, kvpair string) e
 		ClusterKvPairsName:  kvpair,
 	}
 
-	err := db.DBconn.Remove(v.db.storeName, key)
+	err := db.WithContext(v.ctx).Remove(v.db.storeName, key)
 	if err != nil {
 		if strings.Contains(err.Error(), "Error finding:") {
 			return pkgerrors.Wrap(err, "db Remove error - not found")
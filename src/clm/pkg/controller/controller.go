@@ -4,6 +4,7 @@
 package controller
 
 import (
+	"context"
 	"strings"
 
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
@@ -21,6 +22,7 @@ type ControllerManager interface {
 	GetControllers() ([]clmModel.Controller, error)
 	InitControllers()
 	DeleteController(name string) error
+	WithContext(ctx context.Context) ControllerManager
 }
 
 // ControllerClient implements the Manager
@@ -28,6 +30,8 @@ type ControllerManager interface {
 type ControllerClient struct {
 	collectionName string
 	tagMeta        string
+	// ctx is the context of the request using the client
+	ctx context.Context
 }
 
 // NewControllerClient returns an instance of the ControllerClient
@@ -39,6 +43,14 @@ func NewControllerClient() *ControllerClient {
 	}
 }
 
+// WithContext returns a copy of the client making the database calls of
+// the controller of a request with its context
+func (mc *ControllerClient) WithContext(ctx context.Context) ControllerManager {
+	c := *mc
+	c.ctx = ctx
+	return &c
+}
+
 // CreateController a new collection based on the Controller
 func (mc *ControllerClient) CreateController(m clmModel.Controller, mayExist bool) (clmModel.Controller, error) {
 
@@ -55,7 +67,7 @@ func (mc *ControllerClient) CreateController(m clmModel.Controller, mayExist boo
 		return clmModel.Controller{}, pkgerrors.New("ClmController already exists")
 	}
 
-	err = db.DBconn.Insert(mc.collectionName, key, nil, mc.tagMeta, m)
+	err = db.WithContext(mc.ctx).Insert(mc.collectionName, key, nil, mc.tagMeta, m)
 	if err != nil {
 		return clmModel.Controller{}, pkgerrors.Wrap(err, "Creating DB Entry")
 	}
@@ -74,7 +86,7 @@ func (mc *ControllerClient) GetController(name string) (clmModel.Controller, err
 	key := clmModel.ControllerKey{
 		ControllerName: name,
 	}
-	value, err := db.DBconn.Find(mc.collectionName, key, mc.tagMeta)
+	value, err := db.WithContext(mc.ctx).Find(mc.collectionName, key, mc.tagMeta)
 	if err != nil {
 		return clmModel.Controller{}, pkgerrors.Wrap(err, "db Find error")
 	}
@@ -125,7 +137,7 @@ func (mc *ControllerClient) DeleteController(name string) error {
 	key := clmModel.ControllerKey{
 		ControllerName: name,
 	}
-	err := db.DBconn.Remove(mc.collectionName, key)
+	err := db.WithContext(mc.ctx).Remove(mc.collectionName, key)
 	if err != nil {
 		if strings.Contains(err.Error(), "Error finding:") {
 			return pkgerrors.Wrap(err, "db Remove error - not found")
@@ -57,6 +57,9 @@ func NewRouter(
 	lcRouter.HandleFunc(
 		"/logical-clouds/{logical-cloud-name}",
 		logicalCloudHandler.deleteHandler).Methods("DELETE")
+	lcRouter.HandleFunc(
+		"/logical-clouds/{logical-cloud-name}/events",
+		logicalCloudHandler.eventsHandler).Methods("GET")
 	// lcRouter.HandleFunc(
 	// 	"/logical-clouds/{logical-cloud-name}",
 	// 	logicalCloudHandler.updateHandler).Methods("PUT")
@@ -46,7 +46,7 @@ func (h clusterHandler) createHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ret, err := h.client.CreateCluster(project, logicalCloud, v)
+	ret, err := h.client.WithContext(r.Context()).CreateCluster(project, logicalCloud, v)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "Unable to find the project") {
@@ -114,7 +114,7 @@ func (h clusterHandler) getHandler(w http.ResponseWriter, r *http.Request) {
 	var ret interface{}
 	var err error
 
-	ret, err = h.client.GetCluster(project, logicalCloud, name)
+	ret, err = h.client.WithContext(r.Context()).GetCluster(project, logicalCloud, name)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "Cluster Reference does not exist") {
@@ -162,7 +162,7 @@ func (h clusterHandler) updateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ret, err := h.client.UpdateCluster(project, logicalCloud, name, v)
+	ret, err := h.client.WithContext(r.Context()).UpdateCluster(project, logicalCloud, name, v)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if err.Error() == "Cluster Reference does not exist" {
@@ -191,7 +191,7 @@ func (h clusterHandler) deleteHandler(w http.ResponseWriter, r *http.Request) {
 	logicalCloud := vars["logical-cloud-name"]
 	name := vars["cluster-reference"]
 
-	err := h.client.DeleteCluster(project, logicalCloud, name)
+	err := h.client.WithContext(r.Context()).DeleteCluster(project, logicalCloud, name)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "Can't remove Cluster Reference") {
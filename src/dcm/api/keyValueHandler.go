@@ -45,7 +45,7 @@ func (h keyValueHandler) createHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ret, err := h.client.CreateKVPair(project, logicalCloud, v)
+	ret, err := h.client.WithContext(r.Context()).CreateKVPair(project, logicalCloud, v)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -98,7 +98,7 @@ func (h keyValueHandler) getHandler(w http.ResponseWriter, r *http.Request) {
 	var ret interface{}
 	var err error
 
-	ret, err = h.client.GetKVPair(project, logicalCloud, name)
+	ret, err = h.client.WithContext(r.Context()).GetKVPair(project, logicalCloud, name)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if err.Error() == "KV Pair does not exist" {
@@ -146,7 +146,7 @@ func (h keyValueHandler) updateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ret, err := h.client.UpdateKVPair(project, logicalCloud, name, v)
+	ret, err := h.client.WithContext(r.Context()).UpdateKVPair(project, logicalCloud, name, v)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if err.Error() == "KV Pair does not exist" {
@@ -174,7 +174,7 @@ func (h keyValueHandler) deleteHandler(w http.ResponseWriter, r *http.Request) {
 	logicalCloud := vars["logical-cloud-name"]
 	name := vars["kv-pair-name"]
 
-	err := h.client.DeleteKVPair(project, logicalCloud, name)
+	err := h.client.WithContext(r.Context()).DeleteKVPair(project, logicalCloud, name)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -5,6 +5,7 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
@@ -50,6 +51,12 @@ func (h logicalCloudHandler) createHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	if err := v.Specification.RetryPolicy.Validate(); err != nil {
+		log.Error(err.Error(), log.Fields{})
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// Validate that the specified Project exists
 	// before associating a Logical Cloud with it
 	p := orch.NewProjectClient()
@@ -60,7 +67,7 @@ func (h logicalCloudHandler) createHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	ret, err := h.client.Create(project, v)
+	ret, err := h.client.WithContext(r.Context()).Create(project, v)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "Unable to find the project") {
@@ -117,7 +124,7 @@ func (h logicalCloudHandler) getHandler(w http.ResponseWriter, r *http.Request)
 	var ret interface{}
 	var err error
 
-	ret, err = h.client.Get(project, name)
+	ret, err = h.client.WithContext(r.Context()).Get(project, name)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "Logical Cloud does not exist") {
@@ -138,6 +145,51 @@ func (h logicalCloudHandler) getHandler(w http.ResponseWriter, r *http.Request)
 	}
 }
 
+// eventsHandler streams the state changes of a particular logical cloud as
+// server-sent events until the client disconnects
+func (h logicalCloudHandler) eventsHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	project := vars["project-name"]
+	name := vars["logical-cloud-name"]
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		log.Error("Streaming not supported", log.Fields{})
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	events, err := h.client.Events(r.Context(), project, name)
+	if err != nil {
+		log.Error(err.Error(), log.Fields{})
+		if strings.Contains(err.Error(), "Logical Cloud does not exist") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for e := range events {
+		data, err := json.Marshal(e)
+		if err != nil {
+			log.Error(err.Error(), log.Fields{})
+			continue
+		}
+		_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+		if err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
+
 // updateHandler handles Update operations on a particular logical cloud
 func (h logicalCloudHandler) updateHandler(w http.ResponseWriter, r *http.Request) {
 	var v dcm.LogicalCloud
@@ -163,7 +215,13 @@ func (h logicalCloudHandler) updateHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	ret, err := h.client.Update(project, name, v)
+	if err := v.Specification.RetryPolicy.Validate(); err != nil {
+		log.Error(err.Error(), log.Fields{})
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ret, err := h.client.WithContext(r.Context()).Update(project, name, v)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if err.Error() == "Logical Cloud does not exist" {
@@ -190,7 +248,7 @@ func (h logicalCloudHandler) deleteHandler(w http.ResponseWriter, r *http.Reques
 	project := vars["project-name"]
 	name := vars["logical-cloud-name"]
 
-	err := h.client.Delete(project, name)
+	err := h.client.WithContext(r.Context()).Delete(project, name)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "Logical Cloud does not exist") {
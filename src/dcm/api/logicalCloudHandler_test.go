@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/open-ness/EMCO/src/dcm/pkg/module"
 	orch_mocks "github.com/open-ness/EMCO/src/orchestrator/api/mocks"
 	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
 )
 
 var _ = Describe("LogicalCloudHandler", func() {
@@ -515,6 +517,44 @@ var _ = Describe("LogicalCloudHandler", func() {
 		}),
 	)
 
+	DescribeTable("Stream LogicalCloud events tests",
+		func(t testCase, expectedBody string) {
+			// set up client mock responses
+			var events chan module.LogicalCloudEvent
+			if t.mockError == nil {
+				events = make(chan module.LogicalCloudEvent, 2)
+				events <- module.LogicalCloudEvent{Type: module.LogicalCloudEventTypeEnum.AppContext, ContextId: "1", Status: "Instantiated", Previous: "Instantiating"}
+				events <- module.LogicalCloudEvent{Type: module.LogicalCloudEventTypeEnum.Cluster, ContextId: "1", Cluster: "p1+c1", Status: "Available"}
+				close(events)
+			}
+			t.lcClient.On("Events", mock.Anything, "test-project", t.inputName).Return((<-chan module.LogicalCloudEvent)(events), t.mockError)
+
+			// make HTTP request
+			request := httptest.NewRequest("GET", "/v2/projects/test-project/logical-clouds/"+t.inputName+"/events", nil)
+			resp := executeRequest(request, NewRouter(t.lcClient, t.clClient, t.upClient, t.quotaClient, t.kvClient))
+
+			// Check returned code
+			Expect(resp.StatusCode).To(Equal(t.expectedCode))
+
+			// Check returned body
+			body, _ := ioutil.ReadAll(resp.Body)
+			Expect(string(body)).To(ContainSubstring(expectedBody))
+		},
+
+		Entry("successful stream", testCase{
+			inputName:    "testlogicalcloud",
+			expectedCode: http.StatusOK,
+			lcClient:     &mocks.LogicalCloudManager{},
+		}, "event: appcontext\ndata: {\"type\":\"appcontext\",\"instance\":\"1\",\"status\":\"Instantiated\",\"previous\":\"Instantiating\""),
+
+		Entry("fails due to not found", testCase{
+			inputName:    "testlogicalcloud",
+			expectedCode: http.StatusNotFound,
+			mockError:    pkgerrors.New("Logical Cloud does not exist"),
+			lcClient:     &mocks.LogicalCloudManager{},
+		}, "Logical Cloud does not exist"),
+	)
+
 	// TODO add testing for instantiate and terminate
 	// TODO add additional mocking for cluster client:
 	// DescribeTable("Instantiate Logical Cloud (L1)",
@@ -2,6 +2,7 @@
 
 package mocks
 
+import context "context"
 import mock "github.com/stretchr/testify/mock"
 import module "github.com/open-ness/EMCO/src/dcm/pkg/module"
 
@@ -130,3 +131,8 @@ func (_m *ClusterManager) UpdateCluster(project string, logicalCloud string, nam
 
 	return r0, r1
 }
+
+// WithContext returns the mock, the requests of the tests have no version
+func (_m *ClusterManager) WithContext(ctx context.Context) module.ClusterManager {
+	return _m
+}
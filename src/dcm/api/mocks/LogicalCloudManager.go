@@ -2,6 +2,7 @@
 
 package mocks
 
+import context "context"
 import mock "github.com/stretchr/testify/mock"
 import module "github.com/open-ness/EMCO/src/dcm/pkg/module"
 
@@ -45,6 +46,29 @@ func (_m *LogicalCloudManager) Delete(project string, name string) error {
 	return r0
 }
 
+// Events provides a mock function with given fields: ctx, project, name
+func (_m *LogicalCloudManager) Events(ctx context.Context, project string, name string) (<-chan module.LogicalCloudEvent, error) {
+	ret := _m.Called(ctx, project, name)
+
+	var r0 <-chan module.LogicalCloudEvent
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) <-chan module.LogicalCloudEvent); ok {
+		r0 = rf(ctx, project, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan module.LogicalCloudEvent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, project, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Get provides a mock function with given fields: project, name
 func (_m *LogicalCloudManager) Get(project string, name string) (module.LogicalCloud, error) {
 	ret := _m.Called(project, name)
@@ -109,3 +133,8 @@ func (_m *LogicalCloudManager) Update(project string, name string, c module.Logi
 
 	return r0, r1
 }
+
+// WithContext returns the mock, the requests of the tests have no version
+func (_m *LogicalCloudManager) WithContext(ctx context.Context) module.LogicalCloudManager {
+	return _m
+}
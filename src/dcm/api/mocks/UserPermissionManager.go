@@ -2,6 +2,7 @@
 
 package mocks
 
+import context "context"
 import mock "github.com/stretchr/testify/mock"
 import module "github.com/open-ness/EMCO/src/dcm/pkg/module"
 
@@ -109,3 +110,8 @@ func (_m *UserPermissionManager) UpdateUserPerm(project string, logicalCloud str
 
 	return r0, r1
 }
+
+// WithContext returns the mock, the requests of the tests have no version
+func (_m *UserPermissionManager) WithContext(ctx context.Context) module.UserPermissionManager {
+	return _m
+}
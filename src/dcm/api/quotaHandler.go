@@ -46,7 +46,7 @@ func (h quotaHandler) createHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ret, err := h.client.CreateQuota(project, logicalCloud, v)
+	ret, err := h.client.WithContext(r.Context()).CreateQuota(project, logicalCloud, v)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "Logical Cloud does not exist") {
@@ -105,7 +105,7 @@ func (h quotaHandler) getHandler(w http.ResponseWriter, r *http.Request) {
 	var ret interface{}
 	var err error
 
-	ret, err = h.client.GetQuota(project, logicalCloud, name)
+	ret, err = h.client.WithContext(r.Context()).GetQuota(project, logicalCloud, name)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if err.Error() == "Cluster Quota does not exist" {
@@ -153,7 +153,7 @@ func (h quotaHandler) updateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ret, err := h.client.UpdateQuota(project, logicalCloud, name, v)
+	ret, err := h.client.WithContext(r.Context()).UpdateQuota(project, logicalCloud, name, v)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if err.Error() == "Cluster Quota does not exist" {
@@ -182,7 +182,7 @@ func (h quotaHandler) deleteHandler(w http.ResponseWriter, r *http.Request) {
 	logicalCloud := vars["logical-cloud-name"]
 	name := vars["quota-name"]
 
-	err := h.client.DeleteQuota(project, logicalCloud, name)
+	err := h.client.WithContext(r.Context()).DeleteQuota(project, logicalCloud, name)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -46,7 +46,7 @@ func (h userPermissionHandler) createHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	ret, err := h.client.CreateUserPerm(project, logicalCloud, v)
+	ret, err := h.client.WithContext(r.Context()).CreateUserPerm(project, logicalCloud, v)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -99,7 +99,7 @@ func (h userPermissionHandler) getHandler(w http.ResponseWriter, r *http.Request
 	var ret interface{}
 	var err error
 
-	ret, err = h.client.GetUserPerm(project, logicalCloud, name)
+	ret, err = h.client.WithContext(r.Context()).GetUserPerm(project, logicalCloud, name)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if err.Error() == "User Permission does not exist" {
@@ -147,7 +147,7 @@ func (h userPermissionHandler) updateHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	ret, err := h.client.UpdateUserPerm(project, logicalCloud, name, v)
+	ret, err := h.client.WithContext(r.Context()).UpdateUserPerm(project, logicalCloud, name, v)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if err.Error() == "User Permission does not exist" {
@@ -176,7 +176,7 @@ func (h userPermissionHandler) deleteHandler(w http.ResponseWriter, r *http.Requ
 	logicalCloud := vars["logical-cloud-name"]
 	name := vars["permission-name"]
 
-	err := h.client.DeleteUserPerm(project, logicalCloud, name)
+	err := h.client.WithContext(r.Context()).DeleteUserPerm(project, logicalCloud, name)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		http.Error(w, err.Error(), http.StatusInternalServerError)
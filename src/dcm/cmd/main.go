@@ -12,17 +12,20 @@ import (
 	"os/signal"
 	"time"
 
-	"github.com/gorilla/handlers"
 	"github.com/open-ness/EMCO/src/dcm/api"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/auth"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/config"
 	contextDb "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/contextdb"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/metrics"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/middleware"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/tracing"
 )
 
 func main() {
 
 	rand.Seed(time.Now().UnixNano())
+	tracing.Init("dcm")
 
 	err := db.InitializeDatabaseConnection("mco")
 	if err != nil {
@@ -39,9 +42,11 @@ func main() {
 	}
 
 	httpRouter := api.NewRouter(nil, nil, nil, nil, nil)
-	loggedRouter := handlers.LoggingHandler(os.Stdout, httpRouter)
+	loggedRouter := middleware.Handler("dcm", httpRouter)
 	log.Println("Starting Distributed Cloud Manager API")
 
+	go metrics.Serve(config.GetConfiguration().MetricsPort)
+
 	httpServer := &http.Server{
 		Handler: loggedRouter,
 		Addr:    ":" + config.GetConfiguration().ServicePort,
@@ -53,6 +58,7 @@ func main() {
 		signal.Notify(c, os.Interrupt)
 		<-c
 		httpServer.Shutdown(context.Background())
+		tracing.Shutdown()
 		close(connectionsClose)
 	}()
 
@@ -4,6 +4,7 @@
 package module
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -334,7 +335,7 @@ func callRsyncInstall(contextid interface{}) error {
 	}
 
 	appContextID := fmt.Sprintf("%v", contextid)
-	err = installappclient.InvokeInstallApp(appContextID)
+	err = installappclient.InvokeInstallApp(context.Background(), appContextID)
 	if err != nil {
 		log.Error("", log.Fields{"err": err})
 		return err
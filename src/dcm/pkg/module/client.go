@@ -16,6 +16,7 @@ import (
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
 	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/rpc"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/module/subscription"
 	readynotifypb "github.com/open-ness/EMCO/src/rsync/pkg/grpc/readynotify"
 )
 
@@ -149,6 +150,9 @@ func processAlert(client readynotifypb.ReadyNotifyClient, stream readynotifypb.R
 		// if this point is reached, the kubeconfig is already stored in CloudConfig
 	}
 	log.Info("[ReadyNotify gRPC] All CloudConfigs for Logical Cloud have been created", log.Fields{"project": project, "logicalCloud": logicalCloud})
+	subscription.Publish(project, subscription.EventTypeEnum.LogicalCloudReady,
+		subscription.LogicalCloudSource(project, logicalCloud), logicalCloud,
+		map[string]string{"instance": appContextID})
 
 	_ = unsubscribe(client, appContextID)
 }
@@ -4,6 +4,7 @@
 package module
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"strings"
@@ -94,6 +95,7 @@ type ClusterManager interface {
 	DeleteCluster(project, logicalCloud, name string) error
 	UpdateCluster(project, logicalCloud, name string, c Cluster) (Cluster, error)
 	GetClusterConfig(project, logicalcloud, name string) (string, error)
+	WithContext(ctx context.Context) ClusterManager
 }
 
 // ClusterClient implements the ClusterManager
@@ -101,6 +103,8 @@ type ClusterManager interface {
 type ClusterClient struct {
 	storeName string
 	tagMeta   string
+	// ctx is the context of the request using the client
+	ctx context.Context
 }
 
 // ClusterClient returns an instance of the ClusterClient
@@ -112,6 +116,14 @@ func NewClusterClient() *ClusterClient {
 	}
 }
 
+// WithContext returns a copy of the client making the database calls of
+// the cluster reference of a request with its context
+func (v *ClusterClient) WithContext(ctx context.Context) ClusterManager {
+	c := *v
+	c.ctx = ctx
+	return &c
+}
+
 // Create entry for the cluster reference resource in the database
 func (v *ClusterClient) CreateCluster(project, logicalCloud string, c Cluster) (Cluster, error) {
 
@@ -160,7 +172,7 @@ func (v *ClusterClient) CreateCluster(project, logicalCloud string, c Cluster) (
 		return Cluster{}, pkgerrors.New("Cluster reference already exists")
 	}
 
-	err = db.DBconn.Insert(v.storeName, key, nil, v.tagMeta, c)
+	err = db.WithContext(v.ctx).Insert(v.storeName, key, nil, v.tagMeta, c)
 	if err != nil {
 		return Cluster{}, pkgerrors.Wrap(err, "Creating DB Entry")
 	}
@@ -178,7 +190,7 @@ func (v *ClusterClient) GetCluster(project, logicalCloud, clusterReference strin
 		ClusterReference: clusterReference,
 	}
 
-	value, err := db.DBconn.Find(v.storeName, key, v.tagMeta)
+	value, err := db.WithContext(v.ctx).Find(v.storeName, key, v.tagMeta)
 	if err != nil {
 		return Cluster{}, pkgerrors.Wrap(err, "Error getting Cluster reference")
 	}
@@ -242,7 +254,7 @@ func (v *ClusterClient) DeleteCluster(project, logicalCloud, clusterReference st
 	context, _, err := GetLogicalCloudContext(lcClient.storeName, lckey, lcClient.tagContext, project, logicalCloud)
 	if err != nil {
 		// Just go ahead and delete the reference if there is no logical cloud context yet
-		err := db.DBconn.Remove(v.storeName, key)
+		err := db.WithContext(v.ctx).Remove(v.storeName, key)
 		if err != nil {
 			return pkgerrors.Wrap(err, "Failed deleting Cluster Reference")
 		}
@@ -273,7 +285,7 @@ func (v *ClusterClient) DeleteCluster(project, logicalCloud, clusterReference st
 		// try to delete anyway since termination failed
 		fallthrough
 	case appcontext.AppContextStatusEnum.Terminated:
-		err := db.DBconn.Remove(v.storeName, key)
+		err := db.WithContext(v.ctx).Remove(v.storeName, key)
 		if err != nil {
 			return pkgerrors.Wrap(err, "Error deleting Cluster Reference")
 		}
@@ -303,7 +315,7 @@ func (v *ClusterClient) UpdateCluster(project, logicalCloud, clusterReference st
 	if err != nil {
 		return Cluster{}, pkgerrors.New("Cluster Reference does not exist")
 	}
-	err = db.DBconn.Insert(v.storeName, key, nil, v.tagMeta, c)
+	err = db.WithContext(v.ctx).Insert(v.storeName, key, nil, v.tagMeta, c)
 	if err != nil {
 		return Cluster{}, pkgerrors.Wrap(err, "Updating DB Entry")
 	}
@@ -0,0 +1,218 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package module
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
+	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/rpc"
+	readynotifypb "github.com/open-ness/EMCO/src/rsync/pkg/grpc/readynotify"
+)
+
+// LogicalCloudEvent is a state change of a Logical Cloud
+type LogicalCloudEvent struct {
+	Type      LogicalCloudEventType `json:"type"`
+	ContextId string                `json:"instance,omitempty"`
+	Cluster   string                `json:"cluster,omitempty"`
+	Status    string                `json:"status"`
+	Previous  string                `json:"previous,omitempty"`
+	TimeStamp time.Time             `json:"time"`
+}
+
+type LogicalCloudEventType = string
+
+type logicalCloudEventTypes struct {
+	AppContext LogicalCloudEventType
+	Cluster    LogicalCloudEventType
+}
+
+// LogicalCloudEventTypeEnum defines the kinds of changes reported as events
+var LogicalCloudEventTypeEnum = &logicalCloudEventTypes{
+	AppContext: "appcontext", // status of the AppContext of the Logical Cloud
+	Cluster:    "cluster",    // ready status of a cluster of the Logical Cloud
+}
+
+// statePollInterval is the interval between the reads of the state of a
+// watched Logical Cloud. The state is read from the database, so the changes
+// made by any instance of dcm are streamed.
+var statePollInterval = 2 * time.Second
+
+// Events validates the Logical Cloud and starts watching its state. The
+// changes are sent on the returned channel, which is closed once the context
+// is done. The first events are the current state.
+func (v *LogicalCloudClient) Events(ctx context.Context, project, logicalCloudName string) (<-chan LogicalCloudEvent, error) {
+	_, err := v.Get(project, logicalCloudName)
+	if err != nil {
+		return nil, err
+	}
+	events := make(chan LogicalCloudEvent)
+	go v.watchEvents(ctx, project, logicalCloudName, events)
+	return events, nil
+}
+
+// watchEvents computes the snapshot again once the state is polled or rsync
+// notifies a status change of the AppContext, and sends the changes
+func (v *LogicalCloudClient) watchEvents(ctx context.Context, project, logicalCloudName string, events chan<- LogicalCloudEvent) {
+	defer close(events)
+	poll := time.NewTicker(statePollInterval)
+	defer poll.Stop()
+
+	var prev map[string]LogicalCloudEvent
+	var notifyCtxId string
+	var notify <-chan struct{}
+	unsubscribe := func() {}
+	defer func() { unsubscribe() }()
+
+	for {
+		if _, err := v.Get(project, logicalCloudName); err != nil {
+			// The Logical Cloud was deleted
+			log.Info("Stop watching Logical Cloud events", log.Fields{"project": project, "logicalCloud": logicalCloudName, "error": err})
+			return
+		}
+		key := LogicalCloudKey{
+			Project:          project,
+			LogicalCloudName: logicalCloudName,
+		}
+		ac, ctxId, _ := GetLogicalCloudContext(v.storeName, key, v.tagContext, project, logicalCloudName)
+		cur := getLogicalCloudSnapshot(ac, ctxId)
+		for _, e := range diffLogicalCloudSnapshots(prev, cur) {
+			select {
+			case events <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+		prev = cur
+
+		if ctxId != notifyCtxId {
+			unsubscribe()
+			notifyCtxId = ctxId
+			notify, unsubscribe = subscribeReadyNotify(ctxId)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-poll.C:
+		case <-notify:
+		}
+	}
+}
+
+// subscribeReadyNotify subscribes to the rsync notifications for the AppContext.
+// The returned channel is nil if rsync can't be reached.
+func subscribeReadyNotify(ctxId string) (<-chan struct{}, func()) {
+	if ctxId == "" {
+		return nil, func() {}
+	}
+	conn := rpc.GetRpcConn(rsyncName)
+	if conn == nil {
+		initRsyncClient()
+		conn = rpc.GetRpcConn(rsyncName)
+	}
+	if conn == nil {
+		return nil, func() {}
+	}
+	client := readynotifypb.NewReadyNotifyClient(conn)
+	topic := &readynotifypb.Topic{
+		ClientName: fmt.Sprintf("dcm-events-%d", rand.Int63()),
+		AppContext: ctxId,
+	}
+	stream, err := client.Alert(context.Background(), topic)
+	if err != nil {
+		log.Info("Failed to subscribe to rsync notifications", log.Fields{"appContextId": ctxId, "error": err})
+		return nil, func() {}
+	}
+	notify := make(chan struct{}, 1)
+	go func() {
+		for {
+			if _, err := stream.Recv(); err != nil {
+				return
+			}
+			select {
+			case notify <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return notify, func() {
+		// Ends the stream of the subscription
+		client.Unsubscribe(context.Background(), topic)
+	}
+}
+
+// getLogicalCloudSnapshot returns the current state of the Logical Cloud as
+// events, identified by what they report on. A Logical Cloud not instantiated
+// yet has no events.
+func getLogicalCloudSnapshot(ac appcontext.AppContext, ctxId string) map[string]LogicalCloudEvent {
+	snapshot := make(map[string]LogicalCloudEvent)
+	if ctxId == "" {
+		return snapshot
+	}
+	acStatus, err := GetAppContextStatus(ac)
+	if err != nil {
+		return snapshot
+	}
+	snapshot["0"] = LogicalCloudEvent{Type: LogicalCloudEventTypeEnum.AppContext, ContextId: ctxId, Status: string(acStatus.Status)}
+
+	clusters, err := ac.GetClusterNames("logical-cloud")
+	if err != nil {
+		return snapshot
+	}
+	for _, cluster := range clusters {
+		snapshot["1/"+cluster] = LogicalCloudEvent{Type: LogicalCloudEventTypeEnum.Cluster, ContextId: ctxId,
+			Cluster: cluster, Status: string(getClusterReadyStatus(ac, cluster))}
+	}
+	return snapshot
+}
+
+// getClusterReadyStatus returns the ready status rsync set for the cluster
+func getClusterReadyStatus(ac appcontext.AppContext, cluster string) appcontext.StatusValue {
+	ch, err := ac.GetClusterHandle("logical-cloud", cluster)
+	if err != nil {
+		return appcontext.ClusterReadyStatusEnum.Unknown
+	}
+	rsh, err := ac.GetLevelHandle(ch, "readystatus")
+	if err != nil || rsh == nil {
+		return appcontext.ClusterReadyStatusEnum.Unknown
+	}
+	s, err := ac.GetValue(rsh)
+	if err != nil {
+		return appcontext.ClusterReadyStatusEnum.Unknown
+	}
+	if status, ok := s.(string); ok {
+		return appcontext.StatusValue(status)
+	}
+	return appcontext.ClusterReadyStatusEnum.Unknown
+}
+
+// diffLogicalCloudSnapshots returns the events of cur that changed since
+// prev, the AppContext status first and then the clusters
+func diffLogicalCloudSnapshots(prev, cur map[string]LogicalCloudEvent) []LogicalCloudEvent {
+	keys := make([]string, 0, len(cur))
+	for k := range cur {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	now := time.Now()
+	events := []LogicalCloudEvent{}
+	for _, k := range keys {
+		e := cur[k]
+		p, ok := prev[k]
+		if ok && p.Status == e.Status && p.ContextId == e.ContextId {
+			continue
+		}
+		e.Previous = p.Status
+		e.TimeStamp = now
+		events = append(events, e)
+	}
+	return events
+}
@@ -4,6 +4,7 @@
 package module
 
 import (
+	"context"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
 	pkgerrors "github.com/pkg/errors"
 )
@@ -42,6 +43,7 @@ type KeyValueManager interface {
 	GetAllKVPairs(project, logicalCloud string) ([]KeyValue, error)
 	DeleteKVPair(project, logicalCloud, name string) error
 	UpdateKVPair(project, logicalCloud, name string, c KeyValue) (KeyValue, error)
+	WithContext(ctx context.Context) KeyValueManager
 }
 
 // KeyValueClient implements the KeyValueManager
@@ -49,6 +51,8 @@ type KeyValueManager interface {
 type KeyValueClient struct {
 	storeName string
 	tagMeta   string
+	// ctx is the context of the request using the client
+	ctx context.Context
 }
 
 // KeyValueClient returns an instance of the KeyValueClient
@@ -60,6 +64,14 @@ func NewKeyValueClient() *KeyValueClient {
 	}
 }
 
+// WithContext returns a copy of the client making the database calls of
+// the key value pair of a request with its context
+func (v *KeyValueClient) WithContext(ctx context.Context) KeyValueManager {
+	c := *v
+	c.ctx = ctx
+	return &c
+}
+
 // Create entry for the key value resource in the database
 func (v *KeyValueClient) CreateKVPair(project, logicalCloud string, c KeyValue) (KeyValue, error) {
 
@@ -87,7 +99,7 @@ func (v *KeyValueClient) CreateKVPair(project, logicalCloud string, c KeyValue)
 		return KeyValue{}, pkgerrors.New("Key Value already exists")
 	}
 
-	err = db.DBconn.Insert(v.storeName, key, nil, v.tagMeta, c)
+	err = db.WithContext(v.ctx).Insert(v.storeName, key, nil, v.tagMeta, c)
 	if err != nil {
 		return KeyValue{}, pkgerrors.Wrap(err, "Creating DB Entry")
 	}
@@ -104,7 +116,7 @@ func (v *KeyValueClient) GetKVPair(project, logicalCloud, kvPairName string) (Ke
 		LogicalCloudName: logicalCloud,
 		KeyValueName:     kvPairName,
 	}
-	value, err := db.DBconn.Find(v.storeName, key, v.tagMeta)
+	value, err := db.WithContext(v.ctx).Find(v.storeName, key, v.tagMeta)
 	if err != nil {
 		return KeyValue{}, pkgerrors.Wrap(err, "Get Key Value")
 	}
@@ -158,7 +170,7 @@ func (v *KeyValueClient) DeleteKVPair(project, logicalCloud, kvPairName string)
 		LogicalCloudName: logicalCloud,
 		KeyValueName:     kvPairName,
 	}
-	err := db.DBconn.Remove(v.storeName, key)
+	err := db.WithContext(v.ctx).Remove(v.storeName, key)
 	if err != nil {
 		return pkgerrors.Wrap(err, "Delete Key Value")
 	}
@@ -182,7 +194,7 @@ func (v *KeyValueClient) UpdateKVPair(project, logicalCloud, kvPairName string,
 	if err != nil {
 		return KeyValue{}, pkgerrors.New("KV Pair does not exist")
 	}
-	err = db.DBconn.Insert(v.storeName, key, nil, v.tagMeta, c)
+	err = db.WithContext(v.ctx).Insert(v.storeName, key, nil, v.tagMeta, c)
 	if err != nil {
 		return KeyValue{}, pkgerrors.Wrap(err, "Updating DB Entry")
 	}
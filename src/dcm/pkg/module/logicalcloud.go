@@ -4,6 +4,7 @@
 package module
 
 import (
+	"context"
 	"encoding/json"
 
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
@@ -33,6 +34,9 @@ type Spec struct {
 	NameSpace string   `json:"namespace"`
 	Level     string   `json:"level"`
 	User      UserData `json:"user"`
+	// How long rsync retries the clusters of the DeploymentIntentGroups
+	// of the logical cloud
+	RetryPolicy *module.RetryPolicy `json:"retry-policy,omitempty"`
 }
 
 // UserData contains the parameters needed for user
@@ -60,6 +64,8 @@ type LogicalCloudManager interface {
 	GetAll(project string) ([]LogicalCloud, error)
 	Delete(project, name string) error
 	Update(project, name string, c LogicalCloud) (LogicalCloud, error)
+	Events(ctx context.Context, project, name string) (<-chan LogicalCloudEvent, error)
+	WithContext(ctx context.Context) LogicalCloudManager
 }
 
 // LogicalCloudClient implements the LogicalCloudManager
@@ -68,6 +74,8 @@ type LogicalCloudClient struct {
 	storeName  string
 	tagMeta    string
 	tagContext string
+	// ctx is the context of the request using the client
+	ctx context.Context
 }
 
 // LogicalCloudClient returns an instance of the LogicalCloudClient
@@ -80,6 +88,14 @@ func NewLogicalCloudClient() *LogicalCloudClient {
 	}
 }
 
+// WithContext returns a copy of the client making the database calls of
+// the logical cloud of a request with its context
+func (v *LogicalCloudClient) WithContext(ctx context.Context) LogicalCloudManager {
+	c := *v
+	c.ctx = ctx
+	return &c
+}
+
 // Create entry for the logical cloud resource in the database
 func (v *LogicalCloudClient) Create(project string, c LogicalCloud) (LogicalCloud, error) {
 
@@ -106,7 +122,7 @@ func (v *LogicalCloudClient) Create(project string, c LogicalCloud) (LogicalClou
 		c.Specification.Level = "1"
 	}
 
-	err = db.DBconn.Insert(v.storeName, key, nil, v.tagMeta, c)
+	err = db.WithContext(v.ctx).Insert(v.storeName, key, nil, v.tagMeta, c)
 	if err != nil {
 		return LogicalCloud{}, pkgerrors.Wrap(err, "Error creating DB Entry")
 	}
@@ -122,7 +138,7 @@ func (v *LogicalCloudClient) Get(project, logicalCloudName string) (LogicalCloud
 		Project:          project,
 		LogicalCloudName: logicalCloudName,
 	}
-	value, err := db.DBconn.Find(v.storeName, key, v.tagMeta)
+	value, err := db.WithContext(v.ctx).Find(v.storeName, key, v.tagMeta)
 	if err != nil {
 		return LogicalCloud{}, pkgerrors.Wrap(err, "Error getting Logical Cloud")
 	}
@@ -184,7 +200,7 @@ func (v *LogicalCloudClient) Delete(project, logicalCloudName string) error {
 	context, _, err := GetLogicalCloudContext(v.storeName, key, v.tagContext, project, logicalCloudName)
 	// If there's no context for Logical Cloud, just go ahead and delete it now
 	if err != nil {
-		err = db.DBconn.Remove(v.storeName, key)
+		err = db.WithContext(v.ctx).Remove(v.storeName, key)
 		if err != nil {
 			return pkgerrors.Wrap(err, "Error when deleting Logical Cloud (scenario with no context)")
 		}
@@ -222,7 +238,7 @@ func (v *LogicalCloudClient) Delete(project, logicalCloudName string) error {
 			return pkgerrors.Wrap(err, "Error deleting AppContext CompositeApp Logical Cloud")
 		}
 
-		err = db.DBconn.Remove(v.storeName, key)
+		err = db.WithContext(v.ctx).Remove(v.storeName, key)
 		if err != nil {
 			log.Error("Error when deleting Logical Cloud (scenario with Terminated status)", log.Fields{"logicalcloud": logicalCloudName})
 			return pkgerrors.Wrap(err, "Error when deleting Logical Cloud (scenario with Terminated status)")
@@ -251,7 +267,7 @@ func (v *LogicalCloudClient) Update(project, logicalCloudName string, c LogicalC
 	if err != nil {
 		return LogicalCloud{}, pkgerrors.New("Logical Cloud does not exist")
 	}
-	err = db.DBconn.Insert(v.storeName, key, nil, v.tagMeta, c)
+	err = db.WithContext(v.ctx).Insert(v.storeName, key, nil, v.tagMeta, c)
 	if err != nil {
 		return LogicalCloud{}, pkgerrors.Wrap(err, "Updating DB Entry")
 	}
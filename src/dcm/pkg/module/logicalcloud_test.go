@@ -1,8 +1,10 @@
 package module_test
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/golang/protobuf/proto"
@@ -42,6 +44,17 @@ var _ = Describe("Logicalcloud", func() {
 			BeforeEach(func() {
 				_createExistingLogicalCloud(mdb, "1", true, false)
 			})
+			It("event stream should end once the logical cloud is deleted", func() {
+				events, err := client.Events(context.Background(), "project", "testlc")
+				Expect(err).ShouldNot(HaveOccurred())
+				// Not instantiated yet, so no state to report
+				Consistently(events).ShouldNot(Receive())
+
+				err = client.Delete("project", "testlc")
+				Expect(err).ShouldNot(HaveOccurred())
+				// Seen once the state is polled from the database
+				Eventually(events, 5*time.Second).Should(BeClosed())
+			})
 			It("instantiation (non-privileged) should be successful", func() {
 
 				// Mock gRPC InstallApp()
@@ -4,6 +4,7 @@
 package module
 
 import (
+	"context"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
 	pkgerrors "github.com/pkg/errors"
 )
@@ -65,6 +66,7 @@ type QuotaManager interface {
 	GetAllQuotas(project, logicalCloud string) ([]Quota, error)
 	DeleteQuota(project, logicalCloud, name string) error
 	UpdateQuota(project, logicalCloud, name string, c Quota) (Quota, error)
+	WithContext(ctx context.Context) QuotaManager
 }
 
 // QuotaClient implements the QuotaManager
@@ -72,6 +74,8 @@ type QuotaManager interface {
 type QuotaClient struct {
 	storeName string
 	tagMeta   string
+	// ctx is the context of the request using the client
+	ctx context.Context
 }
 
 // QuotaClient returns an instance of the QuotaClient
@@ -83,6 +87,14 @@ func NewQuotaClient() *QuotaClient {
 	}
 }
 
+// WithContext returns a copy of the client making the database calls of
+// the quota of a request with its context
+func (v *QuotaClient) WithContext(ctx context.Context) QuotaManager {
+	c := *v
+	c.ctx = ctx
+	return &c
+}
+
 // Create entry for the quota resource in the database
 func (v *QuotaClient) CreateQuota(project, logicalCloud string, c Quota) (Quota, error) {
 
@@ -118,7 +130,7 @@ func (v *QuotaClient) CreateQuota(project, logicalCloud string, c Quota) (Quota,
 		return Quota{}, pkgerrors.New("Quota already exists")
 	}
 
-	err = db.DBconn.Insert(v.storeName, key, nil, v.tagMeta, c)
+	err = db.WithContext(v.ctx).Insert(v.storeName, key, nil, v.tagMeta, c)
 	if err != nil {
 		return Quota{}, pkgerrors.Wrap(err, "Creating DB Entry")
 	}
@@ -135,7 +147,7 @@ func (v *QuotaClient) GetQuota(project, logicalCloud, quotaName string) (Quota,
 		LogicalCloudName: logicalCloud,
 		QuotaName:        quotaName,
 	}
-	value, err := db.DBconn.Find(v.storeName, key, v.tagMeta)
+	value, err := db.WithContext(v.ctx).Find(v.storeName, key, v.tagMeta)
 	if err != nil {
 		return Quota{}, pkgerrors.Wrap(err, "Quota")
 	}
@@ -187,7 +199,7 @@ func (v *QuotaClient) DeleteQuota(project, logicalCloud, quotaName string) error
 		LogicalCloudName: logicalCloud,
 		QuotaName:        quotaName,
 	}
-	err := db.DBconn.Remove(v.storeName, key)
+	err := db.WithContext(v.ctx).Remove(v.storeName, key)
 	if err != nil {
 		return pkgerrors.Wrap(err, "Delete Quota")
 	}
@@ -211,7 +223,7 @@ func (v *QuotaClient) UpdateQuota(project, logicalCloud, quotaName string, c Quo
 	if err != nil {
 		return Quota{}, pkgerrors.New("Cluster Quota does not exist")
 	}
-	err = db.DBconn.Insert(v.storeName, key, nil, v.tagMeta, c)
+	err = db.WithContext(v.ctx).Insert(v.storeName, key, nil, v.tagMeta, c)
 	if err != nil {
 		return Quota{}, pkgerrors.Wrap(err, "Updating DB Entry")
 	}
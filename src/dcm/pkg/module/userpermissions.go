@@ -4,6 +4,7 @@
 package module
 
 import (
+	"context"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
 	pkgerrors "github.com/pkg/errors"
 )
@@ -44,6 +45,7 @@ type UserPermissionManager interface {
 	GetAllUserPerms(project, logicalCloud string) ([]UserPermission, error)
 	DeleteUserPerm(project, logicalCloud, name string) error
 	UpdateUserPerm(project, logicalCloud, name string, c UserPermission) (UserPermission, error)
+	WithContext(ctx context.Context) UserPermissionManager
 }
 
 // UserPermissionClient implements the UserPermissionManager
@@ -51,6 +53,8 @@ type UserPermissionManager interface {
 type UserPermissionClient struct {
 	storeName string
 	tagMeta   string
+	// ctx is the context of the request using the client
+	ctx context.Context
 }
 
 // UserPermissionClient returns an instance of the UserPermissionClient
@@ -62,6 +66,14 @@ func NewUserPermissionClient() *UserPermissionClient {
 	}
 }
 
+// WithContext returns a copy of the client making the database calls of
+// the user permission of a request with its context
+func (v *UserPermissionClient) WithContext(ctx context.Context) UserPermissionManager {
+	c := *v
+	c.ctx = ctx
+	return &c
+}
+
 // Create entry for the User Permission resource in the database
 func (v *UserPermissionClient) CreateUserPerm(project, logicalCloud string, c UserPermission) (UserPermission, error) {
 
@@ -90,7 +102,7 @@ func (v *UserPermissionClient) CreateUserPerm(project, logicalCloud string, c Us
 		return UserPermission{}, pkgerrors.New("User Permission already exists")
 	}
 
-	err = db.DBconn.Insert(v.storeName, key, nil, v.tagMeta, c)
+	err = db.WithContext(v.ctx).Insert(v.storeName, key, nil, v.tagMeta, c)
 	if err != nil {
 		return UserPermission{}, pkgerrors.Wrap(err, "Creating DB Entry")
 	}
@@ -108,7 +120,7 @@ func (v *UserPermissionClient) GetUserPerm(project, logicalCloud, userPermName s
 		UserPermissionName: userPermName,
 	}
 
-	value, err := db.DBconn.Find(v.storeName, key, v.tagMeta)
+	value, err := db.WithContext(v.ctx).Find(v.storeName, key, v.tagMeta)
 	if err != nil {
 		return UserPermission{}, pkgerrors.Wrap(err, "Get User Permission")
 	}
@@ -159,7 +171,7 @@ func (v *UserPermissionClient) DeleteUserPerm(project, logicalCloud, userPermNam
 		LogicalCloudName:   logicalCloud,
 		UserPermissionName: userPermName,
 	}
-	err := db.DBconn.Remove(v.storeName, key)
+	err := db.WithContext(v.ctx).Remove(v.storeName, key)
 	if err != nil {
 		return pkgerrors.Wrap(err, "Delete User Permission")
 	}
@@ -185,7 +197,7 @@ func (v *UserPermissionClient) UpdateUserPerm(project, logicalCloud, userPermNam
 		return UserPermission{}, pkgerrors.New(
 			"User Permission does not exist")
 	}
-	err = db.DBconn.Insert(v.storeName, key, nil, v.tagMeta, c)
+	err = db.WithContext(v.ctx).Insert(v.storeName, key, nil, v.tagMeta, c)
 	if err != nil {
 		return UserPermission{}, pkgerrors.Wrap(err, "Updating DB Entry")
 	}
@@ -49,7 +49,7 @@ func (h controllerHandler) createHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	ret, err := h.client.CreateController(m, false)
+	ret, err := h.client.WithContext(r.Context()).CreateController(m, false)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		http.Error(w, err.Error(), http.StatusConflict)
@@ -99,7 +99,7 @@ func (h controllerHandler) putHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ret, err := h.client.CreateController(m, true)
+	ret, err := h.client.WithContext(r.Context()).CreateController(m, true)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		http.Error(w, err.Error(), http.StatusConflict)
@@ -138,7 +138,7 @@ func (h controllerHandler) getHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	} else {
 
-		ret, err = h.client.GetController(name)
+		ret, err = h.client.WithContext(r.Context()).GetController(name)
 		if err != nil {
 			log.Error(err.Error(), log.Fields{})
 			if strings.Contains(err.Error(), "db Find error") {
@@ -167,7 +167,7 @@ func (h controllerHandler) deleteHandler(w http.ResponseWriter, r *http.Request)
 	vars := mux.Vars(r)
 	name := vars["controller-name"]
 
-	err := h.client.DeleteController(name)
+	err := h.client.WithContext(r.Context()).DeleteController(name)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "not found") {
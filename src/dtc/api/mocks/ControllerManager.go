@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	controller "github.com/open-ness/EMCO/src/orchestrator/pkg/module/controller"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -95,3 +97,8 @@ func (_m *ControllerManager) GetControllers() ([]controller.Controller, error) {
 func (_m *ControllerManager) InitControllers() {
 	_m.Called()
 }
+
+// WithContext returns the mock, the requests of the tests have no version
+func (_m *ControllerManager) WithContext(ctx context.Context) controller.ControllerManager {
+	return _m
+}
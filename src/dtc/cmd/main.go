@@ -15,12 +15,14 @@ import (
 	"strings"
 	"time"
 
-	"github.com/gorilla/handlers"
 	updatepb "github.com/open-ness/EMCO/src/orchestrator/pkg/grpc/contextupdate"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/auth"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/config"
 	contextDb "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/contextdb"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/metrics"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/middleware"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/tracing"
 	"github.com/open-ness/EMCO/src/dtc/api"
 	register "github.com/open-ness/EMCO/src/dtc/pkg/grpc"
 	"github.com/open-ness/EMCO/src/dtc/pkg/grpc/contextupdateserver"
@@ -60,6 +62,8 @@ func startGrpcServer() error {
 		}
 		opts = []grpc.ServerOption{grpc.Creds(creds)}
 	}
+	opts = append(opts, metrics.ServerOptions()...)
+	opts = append(opts, tracing.ServerOptions()...)
 	grpcServer := grpc.NewServer(opts...)
 	updatepb.RegisterContextupdateServer(grpcServer, contextupdateserver.NewContextupdateServer())
 
@@ -73,6 +77,7 @@ func startGrpcServer() error {
 
 func main() {
 	rand.Seed(time.Now().UnixNano())
+	tracing.Init("dtc")
 
 	err := db.InitializeDatabaseConnection("mco")
 	if err != nil {
@@ -88,9 +93,11 @@ func main() {
 	}
 
 	httpRouter := api.NewRouter(nil)
-	loggedRouter := handlers.LoggingHandler(os.Stdout, httpRouter)
+	loggedRouter := middleware.Handler("dtc", httpRouter)
 	log.Println("Starting Traffic Controller")
 
+	go metrics.Serve(config.GetConfiguration().MetricsPort)
+
 	httpServer := &http.Server{
 		Handler: loggedRouter,
 		Addr:    ":" + config.GetConfiguration().ServicePort,
@@ -109,6 +116,7 @@ func main() {
 		signal.Notify(c, os.Interrupt)
 		<-c
 		httpServer.Shutdown(context.Background())
+		tracing.Shutdown()
 		close(connectionsClose)
 	}()
 
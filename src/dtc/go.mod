@@ -15,7 +15,7 @@ require (
 	github.com/stretchr/testify v1.7.0
 	google.golang.org/grpc v1.28.0
 	google.golang.org/protobuf v1.24.0
-	gopkg.in/yaml.v2 v2.3.0
+	gopkg.in/yaml.v2 v2.4.0
 	k8s.io/api v0.20.2
 	k8s.io/apimachinery v0.20.2
 )
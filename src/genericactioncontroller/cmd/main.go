@@ -15,7 +15,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/gorilla/handlers"
 	"github.com/open-ness/EMCO/src/genericactioncontroller/api"
 	register "github.com/open-ness/EMCO/src/genericactioncontroller/pkg/grpc"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/auth"
@@ -26,6 +25,9 @@ import (
 	updatepb "github.com/open-ness/EMCO/src/orchestrator/pkg/grpc/contextupdate"
 
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/metrics"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/middleware"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/tracing"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/testdata"
@@ -62,6 +64,8 @@ func startGrpcServer() error {
 		}
 		opts = []grpc.ServerOption{grpc.Creds(creds)}
 	}
+	opts = append(opts, metrics.ServerOptions()...)
+	opts = append(opts, tracing.ServerOptions()...)
 	grpcServer := grpc.NewServer(opts...)
 
 	updatepb.RegisterContextupdateServer(grpcServer, contextupdateserver.NewContextupdateServer())
@@ -76,6 +80,7 @@ func startGrpcServer() error {
 
 func main() {
 	rand.Seed(time.Now().UnixNano())
+	tracing.Init("genericactioncontroller")
 	err := db.InitializeDatabaseConnection("mco")
 	if err != nil {
 		log.Println("Unable to initialize mongo database connection...")
@@ -90,9 +95,11 @@ func main() {
 	}
 
 	httpRouter := api.NewRouter(nil)
-	loggedRouter := handlers.LoggingHandler(os.Stdout, httpRouter)
+	loggedRouter := middleware.Handler("genericactioncontroller", httpRouter)
 	log.Println("Starting Generic Action Controller...")
 
+	go metrics.Serve(config.GetConfiguration().MetricsPort)
+
 	httpServer := &http.Server{
 		Handler: loggedRouter,
 		Addr:    ":" + config.GetConfiguration().ServicePort,
@@ -111,6 +118,7 @@ func main() {
 		signal.Notify(c, os.Interrupt)
 		<-c
 		httpServer.Shutdown(context.Background())
+		tracing.Shutdown()
 		close(connectionsClose)
 	}()
 
@@ -9,7 +9,7 @@ require (
 	github.com/open-ness/EMCO/src/orchestrator v0.0.0-00010101000000-000000000000
 	github.com/pkg/errors v0.9.1
 	google.golang.org/grpc v1.28.0
-	gopkg.in/yaml.v2 v2.3.0
+	gopkg.in/yaml.v2 v2.4.0
 	sigs.k8s.io/yaml v1.2.0
 )
 
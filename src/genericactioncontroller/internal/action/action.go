@@ -4,6 +4,7 @@ package action
 // Copyright (c) 2020 Intel Corporation
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"strings"
@@ -19,7 +20,7 @@ import (
 const SEPARATOR = "+"
 
 // UpdateAppContext is the method which calls the backend logic of this controller.
-func UpdateAppContext(intentName, appContextID string) error {
+func UpdateAppContext(ctx context.Context, intentName, appContextID string) error {
 	log.Info("Begin updating app context ", log.Fields{"intent-name": intentName, "appcontext": appContextID})
 
 	var ac appcontext.AppContext
@@ -28,6 +29,7 @@ func UpdateAppContext(intentName, appContextID string) error {
 		log.Error("Loading AppContext failed ", log.Fields{"intent-name": intentName, "appcontext": appContextID, "Error": err.Error()})
 		return pkgerrors.Errorf("Internal error")
 	}
+	ac = ac.WithContext(ctx)
 
 	caMeta, err := ac.GetCompositeAppMeta()
 	if err != nil {
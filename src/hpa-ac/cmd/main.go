@@ -20,6 +20,8 @@ import (
 	updatepb "github.com/open-ness/EMCO/src/orchestrator/pkg/grpc/contextupdate"
 
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/metrics"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/tracing"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/testdata"
@@ -54,6 +56,8 @@ func startGrpcServer() error {
 		}
 		opts = []grpc.ServerOption{grpc.Creds(creds)}
 	}
+	opts = append(opts, metrics.ServerOptions()...)
+	opts = append(opts, tracing.ServerOptions()...)
 	grpcServer := grpc.NewServer(opts...)
 
 	updatepb.RegisterContextupdateServer(grpcServer, contextupdateserver.NewContextupdateServer())
@@ -69,6 +73,7 @@ func startGrpcServer() error {
 func main() {
 	log.Printf("\nHPA ActionController config @ [%v]\n", config.GetConfiguration())
 	rand.Seed(time.Now().UnixNano())
+	tracing.Init("hpa-ac")
 	err := db.InitializeDatabaseConnection("mco")
 	if err != nil {
 		log.Println("Unable to initialize database connection...")
@@ -82,6 +87,8 @@ func main() {
 		log.Fatalln("Exiting...")
 	}
 
+	go metrics.Serve(config.GetConfiguration().MetricsPort)
+
 	err = startGrpcServer()
 	if err != nil {
 		log.Fatalf("hpaActionController GRPC server failed to start")
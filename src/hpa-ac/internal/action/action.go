@@ -4,6 +4,7 @@
 package action
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -28,7 +29,7 @@ import (
 const SEPARATOR = "+"
 
 // UpdateAppContext breaks down the spec from hpa placement controller and updates appcontext for rsync
-func UpdateAppContext(intentName, appContextID string) error {
+func UpdateAppContext(ctx context.Context, intentName, appContextID string) error {
 	log.Info("UpdateAppContext HPA .. start", log.Fields{"intent-name": intentName, "appcontext": appContextID})
 
 	var ac appcontext.AppContext
@@ -37,6 +38,7 @@ func UpdateAppContext(intentName, appContextID string) error {
 		log.Error("UpdateAppContext HPA ..Loading AppContext failed.", log.Fields{"intent-name": intentName, "appcontext": appContextID, "Error": err})
 		return pkgerrors.Errorf("UpdateAppContext HPA .. Error in loading AppContext failed. Internal error")
 	}
+	ac = ac.WithContext(ctx)
 
 	caMeta, err := ac.GetCompositeAppMeta()
 	if err != nil {
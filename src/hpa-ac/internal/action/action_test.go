@@ -1,6 +1,7 @@
 package action_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -331,7 +332,7 @@ spec:
 			Expect(err).To(BeNil())
 
 			contextID := fmt.Sprintf("%v", cfca.ctxval)
-			err = action.UpdateAppContext("hpa-action-controller", contextID)
+			err = action.UpdateAppContext(context.Background(), "hpa-action-controller", contextID)
 			Expect(err).To(BeNil())
 		})
 
@@ -356,7 +357,7 @@ spec:
 			Expect(err).To(BeNil())
 
 			contextID := fmt.Sprintf("%v", cfca.ctxval)
-			err = action.UpdateAppContext("hpa-action-controller", contextID)
+			err = action.UpdateAppContext(context.Background(), "hpa-action-controller", contextID)
 			Expect(err).To(BeNil())
 		})
 
@@ -381,7 +382,7 @@ spec:
 			Expect(err).To(BeNil())
 
 			contextID := fmt.Sprintf("%v", cfca.ctxval)
-			err = action.UpdateAppContext("hpa-action-controller", contextID)
+			err = action.UpdateAppContext(context.Background(), "hpa-action-controller", contextID)
 			Expect(err).To(BeNil())
 		})
 
@@ -407,7 +408,7 @@ spec:
 			Expect(err).To(BeNil())
 
 			contextID := fmt.Sprintf("%v", cfca.ctxval)
-			err = action.UpdateAppContext("hpa-action-controller", contextID)
+			err = action.UpdateAppContext(context.Background(), "hpa-action-controller", contextID)
 			Expect(err).To(BeNil())
 		})
 
@@ -433,7 +434,7 @@ spec:
 			Expect(err).To(BeNil())
 
 			contextID := fmt.Sprintf("%v", cfca.ctxval)
-			err = action.UpdateAppContext("hpa-action-controller", contextID)
+			err = action.UpdateAppContext(context.Background(), "hpa-action-controller", contextID)
 			Expect(err).To(BeNil())
 		})
 
@@ -459,7 +460,7 @@ spec:
 			Expect(err).To(BeNil())
 
 			contextID := fmt.Sprintf("%v", cfca.ctxval)
-			err = action.UpdateAppContext("hpa-action-controller", contextID)
+			err = action.UpdateAppContext(context.Background(), "hpa-action-controller", contextID)
 			Expect(err).To(BeNil())
 		})
 
@@ -485,7 +486,7 @@ spec:
 			Expect(err).To(BeNil())
 
 			contextID := fmt.Sprintf("%v", cfca.ctxval)
-			err = action.UpdateAppContext("hpa-action-controller", contextID)
+			err = action.UpdateAppContext(context.Background(), "hpa-action-controller", contextID)
 			Expect(err).To(BeNil())
 		})
 
@@ -494,7 +495,7 @@ spec:
 			Expect(err).To(BeNil())
 
 			contextID := fmt.Sprintf("%v", cfca.ctxval)
-			err = action.UpdateAppContext("hpa-action-controller", contextID)
+			err = action.UpdateAppContext(context.Background(), "hpa-action-controller", contextID)
 			Expect(err).To(BeNil())
 		})
 
@@ -503,7 +504,7 @@ spec:
 			Expect(err).To(BeNil())
 
 			contextID := fmt.Sprintf("%v", cfca.ctxval)
-			err = action.UpdateAppContext("hpa-action-controller", contextID)
+			err = action.UpdateAppContext(context.Background(), "hpa-action-controller", contextID)
 			Expect(err).To(BeNil())
 		})
 
@@ -512,13 +513,13 @@ spec:
 			Expect(err).To(BeNil())
 
 			contextID := fmt.Sprintf("%v", cfca.ctxval)
-			err = action.UpdateAppContext("hpa-action-controller", contextID)
+			err = action.UpdateAppContext(context.Background(), "hpa-action-controller", contextID)
 			Expect(err).To(BeNil())
 		})
 
 		It("*** GINKGO TESTCASE: unsuccessful update-context due to invalid deploymentspec in etcd", func() {
 			contextID := fmt.Sprintf("%v", cfca.ctxval)
-			err := action.UpdateAppContext("hpa-action-controller", contextID)
+			err := action.UpdateAppContext(context.Background(), "hpa-action-controller", contextID)
 			Expect(err).To(HaveOccurred())
 		})
 
@@ -527,7 +528,7 @@ spec:
 			Expect(err).To(BeNil())
 
 			contextID := fmt.Sprintf("%v", cfca.ctxval)
-			err = action.UpdateAppContext("hpa-action-controller", contextID)
+			err = action.UpdateAppContext(context.Background(), "hpa-action-controller", contextID)
 			Expect(err).To(HaveOccurred())
 		})
 
@@ -552,7 +553,7 @@ spec:
 			Expect(err).To(BeNil())
 
 			contextID := fmt.Sprintf("%v", cfca.ctxval)
-			err = action.UpdateAppContext("hpa-action-controller", contextID)
+			err = action.UpdateAppContext(context.Background(), "hpa-action-controller", contextID)
 			Expect(err).To(HaveOccurred())
 		})
 
@@ -561,14 +562,14 @@ spec:
 			Expect(err).To(BeNil())
 
 			contextID := fmt.Sprintf("%v", cfca.ctxval)
-			err = action.UpdateAppContext("hpa-action-controller", contextID)
+			err = action.UpdateAppContext(context.Background(), "hpa-action-controller", contextID)
 			Expect(err).To(HaveOccurred())
 		})
 
 		It("*** GINKGO TESTCASE: successful update-context when hpa-intent app is associated with composite-app with NO apps", func() {
 			(mdb.Items[0])[orchMod.AppKey{App: "", Project: project, CompositeApp: compApp, CompositeAppVersion: version}.String()] = nil
 			contextID := fmt.Sprintf("%v", cfca.ctxval)
-			err := action.UpdateAppContext("hpa-action-controller", contextID)
+			err := action.UpdateAppContext(context.Background(), "hpa-action-controller", contextID)
 			Expect(err).To(BeNil())
 		})
 
@@ -588,7 +589,7 @@ spec:
 						"}"),
 			}
 			contextID := fmt.Sprintf("%v", cfca.ctxval)
-			err := action.UpdateAppContext("hpa-action-controller", contextID)
+			err := action.UpdateAppContext(context.Background(), "hpa-action-controller", contextID)
 			Expect(err).To(BeNil())
 		})
 
@@ -597,7 +598,7 @@ spec:
 				Project: project, CompositeApp: compApp,
 				Version: version, DeploymentIntentGroup: dig}.String()] = nil
 			contextID := fmt.Sprintf("%v", cfca.ctxval)
-			err := action.UpdateAppContext("hpa-action-controller", contextID)
+			err := action.UpdateAppContext(context.Background(), "hpa-action-controller", contextID)
 			Expect(err).To(BeNil())
 		})
 
@@ -617,7 +618,7 @@ spec:
 						"}"),
 			}
 			contextID := fmt.Sprintf("%v", cfca.ctxval)
-			err := action.UpdateAppContext("hpa-action-controller", contextID)
+			err := action.UpdateAppContext(context.Background(), "hpa-action-controller", contextID)
 			Expect(err).To(BeNil())
 		})
 
@@ -626,7 +627,7 @@ spec:
 				Project: project, CompositeApp: compApp,
 				Version: version, DeploymentIntentGroup: dig}.String()] = nil
 			contextID := fmt.Sprintf("%v", cfca.ctxval)
-			err := action.UpdateAppContext("hpa-action-controller", contextID)
+			err := action.UpdateAppContext(context.Background(), "hpa-action-controller", contextID)
 			Expect(err).To(BeNil())
 		})
 
@@ -647,7 +648,7 @@ spec:
 						"}"),
 			}
 			contextID := fmt.Sprintf("%v", cfca.ctxval)
-			err := action.UpdateAppContext("hpa-action-controller", contextID)
+			err := action.UpdateAppContext(context.Background(), "hpa-action-controller", contextID)
 			Expect(err).To(BeNil())
 		})
 
@@ -659,7 +660,7 @@ spec:
 			Expect(err).To(BeNil())
 
 			contextID := fmt.Sprintf("%v", cfca.ctxval)
-			err = action.UpdateAppContext("hpa-action-controller", contextID)
+			err = action.UpdateAppContext(context.Background(), "hpa-action-controller", contextID)
 			Expect(err).To(BeNil())
 		})
 
@@ -671,7 +672,7 @@ spec:
 			Expect(err).To(BeNil())
 
 			contextID := fmt.Sprintf("%v", cfca.ctxval)
-			err = action.UpdateAppContext("hpa-action-controller", contextID)
+			err = action.UpdateAppContext(context.Background(), "hpa-action-controller", contextID)
 			Expect(err).To(BeNil())
 		})
 
@@ -680,12 +681,12 @@ spec:
 			Expect(err).To(BeNil())
 
 			contextID := fmt.Sprintf("%v", cfca.ctxval)
-			err = action.UpdateAppContext("hpa-action-controller", contextID)
+			err = action.UpdateAppContext(context.Background(), "hpa-action-controller", contextID)
 			Expect(err).To(HaveOccurred())
 		})
 
 		It("*** GINKGO TESTCASE: failed update-context with nil contextID", func() {
-			err := action.UpdateAppContext("hpa-action-controller", "")
+			err := action.UpdateAppContext(context.Background(), "hpa-action-controller", "")
 			Expect(err).To(HaveOccurred())
 		})
 
@@ -723,7 +724,7 @@ spec:
 			}
 
 			contextID := fmt.Sprintf("%v", cfca.ctxval)
-			err := action.UpdateAppContext("hpa-action-controller", contextID)
+			err := action.UpdateAppContext(context.Background(), "hpa-action-controller", contextID)
 			Expect(err).To(BeNil())
 		})
 	})
@@ -15,7 +15,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/gorilla/handlers"
 	clmcontrollerpb "github.com/open-ness/EMCO/src/clm/pkg/grpc/controller-eventchannel"
 	"github.com/open-ness/EMCO/src/hpa-plc/api"
 	register "github.com/open-ness/EMCO/src/hpa-plc/pkg/grpc"
@@ -26,6 +25,9 @@ import (
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/config"
 	contextDb "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/contextdb"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/metrics"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/middleware"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/tracing"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/testdata"
@@ -58,6 +60,8 @@ func startGrpcServer() error {
 		}
 		opts = []grpc.ServerOption{grpc.Creds(creds)}
 	}
+	opts = append(opts, metrics.ServerOptions()...)
+	opts = append(opts, tracing.ServerOptions()...)
 	grpcServer := grpc.NewServer(opts...)
 	plsctrlclientpb.RegisterPlacementControllerServer(grpcServer, placementcontrollerserver.NewHpaPlacementControllerServer())
 	clmcontrollerpb.RegisterClmControllerEventChannelServer(grpcServer, clmControllerserver.NewControllerEventchannelServer())
@@ -73,6 +77,7 @@ func startGrpcServer() error {
 func main() {
 	log.Printf("\nHPA PlacementController config @ [%v]\n", config.GetConfiguration())
 	rand.Seed(time.Now().UnixNano())
+	tracing.Init("hpa-plc")
 
 	err := db.InitializeDatabaseConnection("mco")
 	if err != nil {
@@ -88,7 +93,9 @@ func main() {
 	}
 
 	httpRouter := api.NewRouter(nil)
-	loggedRouter := handlers.LoggingHandler(os.Stdout, httpRouter)
+	loggedRouter := middleware.Handler("hpa-plc", httpRouter)
+	go metrics.Serve(config.GetConfiguration().MetricsPort)
+
 	httpServer := &http.Server{
 		Handler: loggedRouter,
 		Addr:    ":" + config.GetConfiguration().ServicePort,
@@ -108,6 +115,7 @@ func main() {
 		signal.Notify(c, os.Interrupt)
 		<-c
 		err := httpServer.Shutdown(context.Background())
+		tracing.Shutdown()
 		if err != nil {
 			log.Fatalf("http server failed to shutdown")
 		}
@@ -99,7 +99,7 @@ func (h networkHandler) createNetworkHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	ret, err := h.client.CreateNetwork(p, clusterProvider, cluster, false)
+	ret, err := h.client.WithContext(r.Context()).CreateNetwork(p, clusterProvider, cluster, false)
 	if err != nil {
 		log.Error(":: Error creating network ::", log.Fields{"Error": err})
 		if strings.Contains(err.Error(), "Unable to find the cluster") {
@@ -164,7 +164,7 @@ func (h networkHandler) putNetworkHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	ret, err := h.client.CreateNetwork(p, clusterProvider, cluster, true)
+	ret, err := h.client.WithContext(r.Context()).CreateNetwork(p, clusterProvider, cluster, true)
 	if err != nil {
 		log.Error(":: Error updating network ::", log.Fields{"Error": err})
 		if strings.Contains(err.Error(), "Unable to find the cluster") {
@@ -209,7 +209,7 @@ func (h networkHandler) getNetworkHandler(w http.ResponseWriter, r *http.Request
 			return
 		}
 	} else {
-		ret, err = h.client.GetNetwork(name, clusterProvider, cluster)
+		ret, err = h.client.WithContext(r.Context()).GetNetwork(name, clusterProvider, cluster)
 		if err != nil {
 			log.Error(":: Error getting network ::", log.Fields{"Error": err})
 			if strings.Contains(err.Error(), "db Find error") {
@@ -238,7 +238,7 @@ func (h networkHandler) deleteNetworkHandler(w http.ResponseWriter, r *http.Requ
 	cluster := vars["cluster-name"]
 	name := vars["name"]
 
-	err := h.client.DeleteNetwork(name, clusterProvider, cluster)
+	err := h.client.WithContext(r.Context()).DeleteNetwork(name, clusterProvider, cluster)
 	if err != nil {
 		log.Error(":: Error deleting network ::", log.Fields{"Error": err, "Name": name})
 		if strings.Contains(err.Error(), "Unable to find") {
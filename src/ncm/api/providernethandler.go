@@ -151,7 +151,7 @@ func (h providernetHandler) createProviderNetHandler(w http.ResponseWriter, r *h
 		return
 	}
 
-	ret, err := h.client.CreateProviderNet(p, clusterProvider, cluster, false)
+	ret, err := h.client.WithContext(r.Context()).CreateProviderNet(p, clusterProvider, cluster, false)
 	if err != nil {
 		log.Error(":: Error creating provider network ::", log.Fields{"Error": err})
 		if strings.Contains(err.Error(), "Unable to find the cluster") {
@@ -215,7 +215,7 @@ func (h providernetHandler) putProviderNetHandler(w http.ResponseWriter, r *http
 		return
 	}
 
-	ret, err := h.client.CreateProviderNet(p, clusterProvider, cluster, true)
+	ret, err := h.client.WithContext(r.Context()).CreateProviderNet(p, clusterProvider, cluster, true)
 	if err != nil {
 		log.Error(":: Error updating provider network ::", log.Fields{"Error": err})
 		if strings.Contains(err.Error(), "Unable to find the cluster") {
@@ -260,7 +260,7 @@ func (h providernetHandler) getProviderNetHandler(w http.ResponseWriter, r *http
 			return
 		}
 	} else {
-		ret, err = h.client.GetProviderNet(name, clusterProvider, cluster)
+		ret, err = h.client.WithContext(r.Context()).GetProviderNet(name, clusterProvider, cluster)
 		if err != nil {
 			log.Error(":: Error getting provider network ::", log.Fields{"Error": err})
 			if strings.Contains(err.Error(), "db Find error") {
@@ -289,7 +289,7 @@ func (h providernetHandler) deleteProviderNetHandler(w http.ResponseWriter, r *h
 	cluster := vars["cluster-name"]
 	name := vars["name"]
 
-	err := h.client.DeleteProviderNet(name, clusterProvider, cluster)
+	err := h.client.WithContext(r.Context()).DeleteProviderNet(name, clusterProvider, cluster)
 	if err != nil {
 		log.Error(":: Error deleting provider network ::", log.Fields{"Error": err, "Name": name})
 		if strings.Contains(err.Error(), "Unable to find") {
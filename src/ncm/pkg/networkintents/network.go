@@ -4,6 +4,7 @@
 package networkintents
 
 import (
+	"context"
 	"strings"
 
 	clusterPkg "github.com/open-ness/EMCO/src/clm/pkg/cluster"
@@ -52,12 +53,15 @@ type NetworkManager interface {
 	GetNetwork(name, clusterProvider, cluster string) (Network, error)
 	GetNetworks(clusterProvider, cluster string) ([]Network, error)
 	DeleteNetwork(name, clusterProvider, cluster string) error
+	WithContext(ctx context.Context) NetworkManager
 }
 
 // NetworkClient implements the Manager
 // It will also be used to maintain some localized state
 type NetworkClient struct {
 	db ncmtypes.ClientDbInfo
+	// ctx is the context of the request using the client
+	ctx context.Context
 }
 
 // NewNetworkClient returns an instance of the NetworkClient
@@ -71,6 +75,14 @@ func NewNetworkClient() *NetworkClient {
 	}
 }
 
+// WithContext returns a copy of the client making the database calls of
+// the network of a request with its context
+func (v *NetworkClient) WithContext(ctx context.Context) NetworkManager {
+	c := *v
+	c.ctx = ctx
+	return &c
+}
+
 // CreateNetwork - create a new Network
 func (v *NetworkClient) CreateNetwork(p Network, clusterProvider, cluster string, exists bool) (Network, error) {
 
@@ -111,7 +123,7 @@ func (v *NetworkClient) CreateNetwork(p Network, clusterProvider, cluster string
 		return Network{}, pkgerrors.New("Network already exists")
 	}
 
-	err = db.DBconn.Insert(v.db.StoreName, key, nil, v.db.TagMeta, p)
+	err = db.WithContext(v.ctx).Insert(v.db.StoreName, key, nil, v.db.TagMeta, p)
 	if err != nil {
 		return Network{}, pkgerrors.Wrap(err, "Creating DB Entry")
 	}
@@ -129,7 +141,7 @@ func (v *NetworkClient) GetNetwork(name, clusterProvider, cluster string) (Netwo
 		NetworkName:         name,
 	}
 
-	value, err := db.DBconn.Find(v.db.StoreName, key, v.db.TagMeta)
+	value, err := db.WithContext(v.ctx).Find(v.db.StoreName, key, v.db.TagMeta)
 	if err != nil {
 		return Network{}, pkgerrors.Wrap(err, "db Find error")
 	}
@@ -212,7 +224,7 @@ func (v *NetworkClient) DeleteNetwork(name, clusterProvider, cluster string) err
 		NetworkName:         name,
 	}
 
-	err = db.DBconn.Remove(v.db.StoreName, key)
+	err = db.WithContext(v.ctx).Remove(v.db.StoreName, key)
 	if err != nil {
 		if strings.Contains(err.Error(), "Error finding:") {
 			return pkgerrors.Wrap(err, "db Remove error - not found")
@@ -4,6 +4,7 @@
 package networkintents
 
 import (
+	"context"
 	"strings"
 
 	clusterPkg "github.com/open-ness/EMCO/src/clm/pkg/cluster"
@@ -54,12 +55,15 @@ type ProviderNetManager interface {
 	GetProviderNet(name, clusterProvider, cluster string) (ProviderNet, error)
 	GetProviderNets(clusterProvider, cluster string) ([]ProviderNet, error)
 	DeleteProviderNet(name, clusterProvider, cluster string) error
+	WithContext(ctx context.Context) ProviderNetManager
 }
 
 // ProviderNetClient implements the Manager
 // It will also be used to maintain some localized state
 type ProviderNetClient struct {
 	db ncmtypes.ClientDbInfo
+	// ctx is the context of the request using the client
+	ctx context.Context
 }
 
 // NewProviderNetClient returns an instance of the ProviderNetClient
@@ -73,6 +77,14 @@ func NewProviderNetClient() *ProviderNetClient {
 	}
 }
 
+// WithContext returns a copy of the client making the database calls of
+// the provider network of a request with its context
+func (v *ProviderNetClient) WithContext(ctx context.Context) ProviderNetManager {
+	c := *v
+	c.ctx = ctx
+	return &c
+}
+
 // CreateProviderNet - create a new ProviderNet
 func (v *ProviderNetClient) CreateProviderNet(p ProviderNet, clusterProvider, cluster string, exists bool) (ProviderNet, error) {
 
@@ -113,7 +125,7 @@ func (v *ProviderNetClient) CreateProviderNet(p ProviderNet, clusterProvider, cl
 		return ProviderNet{}, pkgerrors.New("ProviderNet already exists")
 	}
 
-	err = db.DBconn.Insert(v.db.StoreName, key, nil, v.db.TagMeta, p)
+	err = db.WithContext(v.ctx).Insert(v.db.StoreName, key, nil, v.db.TagMeta, p)
 	if err != nil {
 		return ProviderNet{}, pkgerrors.Wrap(err, "Creating DB Entry")
 	}
@@ -131,7 +143,7 @@ func (v *ProviderNetClient) GetProviderNet(name, clusterProvider, cluster string
 		ProviderNetName:     name,
 	}
 
-	value, err := db.DBconn.Find(v.db.StoreName, key, v.db.TagMeta)
+	value, err := db.WithContext(v.ctx).Find(v.db.StoreName, key, v.db.TagMeta)
 	if err != nil {
 		return ProviderNet{}, pkgerrors.Wrap(err, "db Find error")
 	}
@@ -214,7 +226,7 @@ func (v *ProviderNetClient) DeleteProviderNet(name, clusterProvider, cluster str
 		ProviderNetName:     name,
 	}
 
-	err = db.DBconn.Remove(v.db.StoreName, key)
+	err = db.WithContext(v.ctx).Remove(v.db.StoreName, key)
 	if err != nil {
 		if strings.Contains(err.Error(), "Error finding:") {
 			return pkgerrors.Wrap(err, "db Remove error - not found")
@@ -4,6 +4,7 @@
 package scheduler
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -97,7 +98,7 @@ func callRsyncInstall(contextid interface{}) error {
 	}
 
 	appContextID := fmt.Sprintf("%v", contextid)
-	err = installappclient.InvokeInstallApp(appContextID)
+	err = installappclient.InvokeInstallApp(context.Background(), appContextID)
 	if err != nil {
 		return err
 	}
@@ -15,6 +15,8 @@ import (
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/config"
 	contextDb "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/contextdb"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/metrics"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/tracing"
 	register "github.com/open-ness/EMCO/src/nps/pkg/grpc"
 	"github.com/open-ness/EMCO/src/nps/pkg/grpc/contextupdateserver"
 	"google.golang.org/grpc"
@@ -53,6 +55,8 @@ func startGrpcServer() error {
 		}
 		opts = []grpc.ServerOption{grpc.Creds(creds)}
 	}
+	opts = append(opts, metrics.ServerOptions()...)
+	opts = append(opts, tracing.ServerOptions()...)
 	grpcServer := grpc.NewServer(opts...)
 	updatepb.RegisterContextupdateServer(grpcServer, contextupdateserver.NewContextupdateServer())
 
@@ -66,6 +70,7 @@ func startGrpcServer() error {
 
 func main() {
 	rand.Seed(time.Now().UnixNano())
+	tracing.Init("nps")
 
 	err := db.InitializeDatabaseConnection("mco")
 	if err != nil {
@@ -79,6 +84,8 @@ func main() {
 		log.Println(err)
 		log.Fatalln("Exiting...")
 	}
+	go metrics.Serve(config.GetConfiguration().MetricsPort)
+
 	err = startGrpcServer()
 	if err != nil {
 		log.Fatalf("GRPC server failed to start")
@@ -4,6 +4,7 @@
 package networkpolicy
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 
@@ -15,7 +16,7 @@ import (
 )
 
 // Action applies the supplied intent against the given AppContext ID
-func UpdateAppContext(intentName, appContextId string) error {
+func UpdateAppContext(ctx context.Context, intentName, appContextId string) error {
 	var ac appcontext.AppContext
 	_, err := ac.LoadAppContext(appContextId)
 	if err != nil {
@@ -24,6 +25,7 @@ func UpdateAppContext(intentName, appContextId string) error {
 		})
 		return pkgerrors.Wrapf(err, "Error loading AppContext with Id: %v", appContextId)
 	}
+	ac = ac.WithContext(ctx)
 
 	caMeta, err := ac.GetCompositeAppMeta()
 	if err != nil {
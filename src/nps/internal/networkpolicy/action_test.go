@@ -4,6 +4,7 @@
 package networkpolicy_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	. "github.com/onsi/ginkgo"
@@ -214,7 +215,7 @@ spec:
 
 			contextID := fmt.Sprintf("%v", cfca.ctxval)
 
-			err = networkpolicy.UpdateAppContext("testtgi", contextID)
+			err = networkpolicy.UpdateAppContext(context.Background(), "testtgi", contextID)
 			Expect(err).To(BeNil())
 			rh, err := cfca.context.GetResourceHandle("server", "provider1+cluster1", "testtgi-testisi")
 			Expect(err).To(BeNil())
@@ -231,15 +232,15 @@ spec:
 		})
 		It("cover invalid context error", func() {
 			edb.Err = pkgerrors.New("Error invalid context ID:")
-			err := networkpolicy.UpdateAppContext("testtgi", "dummycontextid")
+			err := networkpolicy.UpdateAppContext(context.Background(), "testtgi", "dummycontextid")
 			Expect(err).To(HaveOccurred())
 		})
 		It("cover invalid meta data error", func() {
-			context := appcontext.AppContext{}
-			ctxval, err := context.InitAppContext()
+			ac := appcontext.AppContext{}
+			ctxval, err := ac.InitAppContext()
 			Expect(err).To(BeNil())
 			contextID := fmt.Sprintf("%v", ctxval)
-			err = networkpolicy.UpdateAppContext("testtgi", contextID)
+			err = networkpolicy.UpdateAppContext(context.Background(), "testtgi", contextID)
 			Expect(err).To(HaveOccurred())
 		})
 		It("cover error getting server inbound intents", func() {
@@ -263,7 +264,7 @@ spec:
 			resOrder, err = json.Marshal(map[string][]string{"resorder": []string{"r1"}})
 			_, err = cfca.context.AddInstruction(capc2, "resource", "order", string(resOrder))
 			contextID := fmt.Sprintf("%v", cfca.ctxval)
-			err = networkpolicy.UpdateAppContext("testtgi", contextID)
+			err = networkpolicy.UpdateAppContext(context.Background(), "testtgi", contextID)
 			Expect(err).To(HaveOccurred())
 		})
 		It("cover error getting clients inbound intents", func() {
@@ -293,7 +294,7 @@ spec:
 			Expect(isi).To(Equal(ISI))
 			Expect(err).To(BeNil())
 			contextID := fmt.Sprintf("%v", cfca.ctxval)
-			err = networkpolicy.UpdateAppContext("testtgi", contextID)
+			err = networkpolicy.UpdateAppContext(context.Background(), "testtgi", contextID)
 			Expect(err).To(HaveOccurred())
 		})
 		It("cover invalid cluster name", func() {
@@ -326,7 +327,7 @@ spec:
 			Expect(ici).To(Equal(ICI))
 			Expect(err).To(BeNil())
 			contextID := fmt.Sprintf("%v", cfca.ctxval)
-			err = networkpolicy.UpdateAppContext("testtgi", contextID)
+			err = networkpolicy.UpdateAppContext(context.Background(), "testtgi", contextID)
 			Expect(err).To(HaveOccurred())
 		})
 	})
@@ -53,7 +53,7 @@ func (h intentHandler) addIntentHandler(w http.ResponseWriter, r *http.Request)
 	v := vars["composite-app-version"]
 	d := vars["deployment-intent-group-name"]
 
-	intent, addError := h.client.AddIntent(i, p, ca, v, d)
+	intent, addError := h.client.WithContext(r.Context()).AddIntent(i, p, ca, v, d)
 	if addError != nil {
 		log.Error(addError.Error(), log.Fields{})
 		if strings.Contains(addError.Error(), "Unable to find the project") {
@@ -106,7 +106,7 @@ func (h intentHandler) getIntentByNameHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	mapOfIntents, err := h.client.GetIntentByName(iN, p, ca, v, di)
+	mapOfIntents, err := h.client.WithContext(r.Context()).GetIntentByName(iN, p, ca, v, di)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "db Find error") {
@@ -205,7 +205,7 @@ func (h intentHandler) getIntentHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	intent, err := h.client.GetIntent(i, p, ca, v, di)
+	intent, err := h.client.WithContext(r.Context()).GetIntent(i, p, ca, v, di)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "db Find error") {
@@ -235,7 +235,7 @@ func (h intentHandler) deleteIntentHandler(w http.ResponseWriter, r *http.Reques
 	v := vars["composite-app-version"]
 	di := vars["deployment-intent-group-name"]
 
-	err := h.client.DeleteIntent(i, p, ca, v, di)
+	err := h.client.WithContext(r.Context()).DeleteIntent(i, p, ca, v, di)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "not found") {
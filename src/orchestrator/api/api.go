@@ -5,8 +5,10 @@ package api
 
 import (
 	"github.com/gorilla/mux"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/audit"
 	moduleLib "github.com/open-ness/EMCO/src/orchestrator/pkg/module"
 	controller "github.com/open-ness/EMCO/src/orchestrator/pkg/module/controller"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/module/subscription"
 )
 
 var moduleClient *moduleLib.Client
@@ -22,7 +24,10 @@ func NewRouter(projectClient moduleLib.ProjectManager,
 	intentClient moduleLib.IntentManager,
 	compositeProfileClient moduleLib.CompositeProfileManager,
 	appProfileClient moduleLib.AppProfileManager,
-	instantiationClient moduleLib.InstantiationManager) *mux.Router {
+	instantiationClient moduleLib.InstantiationManager,
+	appDependencyClient moduleLib.AppDependencyManager,
+	subscriptionClient subscription.SubscriptionManager,
+	auditClient audit.AuditManager) *mux.Router {
 
 	router := mux.NewRouter().PathPrefix("/v2").Subrouter()
 
@@ -45,9 +50,33 @@ func NewRouter(projectClient moduleLib.ProjectManager,
 	router.HandleFunc("/projects", projHandler.createHandler).Methods("POST")
 	router.HandleFunc("/projects/{project-name}", projHandler.updateHandler).Methods("PUT")
 	router.HandleFunc("/projects/{project-name}", projHandler.getHandler).Methods("GET")
+	router.HandleFunc("/projects/{project-name}/usage", projHandler.usageHandler).Methods("GET")
 	router.HandleFunc("/projects", projHandler.getHandler).Methods("GET")
 	router.HandleFunc("/projects/{project-name}", projHandler.deleteHandler).Methods("DELETE")
 
+	//setting routes for subscriptions
+	if subscriptionClient == nil {
+		subscriptionClient = moduleClient.Subscription
+	}
+	subscriptionHandler := subscriptionHandler{
+		client: subscriptionClient,
+	}
+	router.HandleFunc("/projects/{project-name}/subscriptions", subscriptionHandler.createHandler).Methods("POST")
+	router.HandleFunc("/projects/{project-name}/subscriptions", subscriptionHandler.getHandler).Methods("GET")
+	router.HandleFunc("/projects/{project-name}/subscriptions/{subscription-name}", subscriptionHandler.getHandler).Methods("GET")
+	router.HandleFunc("/projects/{project-name}/subscriptions/{subscription-name}", subscriptionHandler.updateHandler).Methods("PUT")
+	router.HandleFunc("/projects/{project-name}/subscriptions/{subscription-name}", subscriptionHandler.deleteHandler).Methods("DELETE")
+	router.HandleFunc("/projects/{project-name}/subscriptions/{subscription-name}/deliveries", subscriptionHandler.deliveriesHandler).Methods("GET")
+
+	//setting routes for the audit log
+	if auditClient == nil {
+		auditClient = moduleClient.Audit
+	}
+	auditHandler := auditHandler{
+		client: auditClient,
+	}
+	router.HandleFunc("/audit", auditHandler.getHandler).Methods("GET")
+
 	//setting routes for compositeApp
 	if compositeAppClient == nil {
 		compositeAppClient = moduleClient.CompositeApp
@@ -74,6 +103,18 @@ func NewRouter(projectClient moduleLib.ProjectManager,
 	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{version}/apps", appHandler.getAppHandler).Methods("GET")
 	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{version}/apps/{app-name}", appHandler.deleteAppHandler).Methods("DELETE")
 
+	if appDependencyClient == nil {
+		appDependencyClient = moduleClient.AppDependency
+	}
+	appDependencyHandler := appDependencyHandler{
+		client: appDependencyClient,
+	}
+	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{version}/apps/{app-name}/dependency", appDependencyHandler.createHandler).Methods("POST")
+	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{version}/apps/{app-name}/dependency", appDependencyHandler.getHandler).Methods("GET")
+	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{version}/apps/{app-name}/dependency/{dependency-name}", appDependencyHandler.getHandler).Methods("GET")
+	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{version}/apps/{app-name}/dependency/{dependency-name}", appDependencyHandler.updateHandler).Methods("PUT")
+	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{version}/apps/{app-name}/dependency/{dependency-name}", appDependencyHandler.deleteHandler).Methods("DELETE")
+
 	if compositeProfileClient == nil {
 		compositeProfileClient = moduleClient.CompositeProfile
 	}
@@ -184,9 +225,11 @@ func NewRouter(projectClient moduleLib.ProjectManager,
 	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/terminate", instantiationHandler.terminateHandler).Methods("POST")
 	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/instantiate", instantiationHandler.instantiateHandler).Methods("POST")
 	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/stop", instantiationHandler.stopHandler).Methods("POST")
+	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/plan", instantiationHandler.planHandler).Methods("POST")
 	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/status", instantiationHandler.statusHandler).Methods("GET")
 	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/status",
 		instantiationHandler.statusHandler).Queries("instance", "{instance}", "type", "{type}", "output", "{output}", "app", "{app}", "cluster", "{cluster}", "resource", "{resource}", "apps", "{apps}", "clusters", "{clusters}", "resources", "{resources}")
+	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/events", instantiationHandler.eventsHandler).Methods("GET")
 
 	// setting routes for Update
 	updateHandler := updateHandler{
@@ -196,5 +239,7 @@ func NewRouter(projectClient moduleLib.ProjectManager,
 	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/migrate", updateHandler.migrateHandler).Methods("POST")
 	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/update", updateHandler.updateHandler).Methods("POST")
 	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/rollback", updateHandler.rollbackHandler).Methods("POST")
+	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/revisions", updateHandler.revisionsHandler).Methods("GET")
+	router.HandleFunc("/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/revisions/diff", updateHandler.revisionDiffHandler).Methods("GET")
 	return router
 }
\ No newline at end of file
@@ -0,0 +1,165 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/validation"
+	moduleLib "github.com/open-ness/EMCO/src/orchestrator/pkg/module"
+
+	"github.com/gorilla/mux"
+)
+
+var appDependencyJSONFile string = "json-schemas/app-dependency.json"
+
+/* Used to store backend implementation objects
+Also simplifies mocking for unit testing purposes
+*/
+type appDependencyHandler struct {
+	client moduleLib.AppDependencyManager
+}
+
+// createHandler handles the create operation of an app dependency
+func (h appDependencyHandler) createHandler(w http.ResponseWriter, r *http.Request) {
+	h.createOrUpdateHandler(w, r, false)
+}
+
+// updateHandler handles the update operation of an app dependency
+func (h appDependencyHandler) updateHandler(w http.ResponseWriter, r *http.Request) {
+	h.createOrUpdateHandler(w, r, true)
+}
+
+func (h appDependencyHandler) createOrUpdateHandler(w http.ResponseWriter, r *http.Request, exists bool) {
+	var d moduleLib.AppDependency
+
+	err := json.NewDecoder(r.Body).Decode(&d)
+	switch {
+	case err == io.EOF:
+		log.Error(err.Error(), log.Fields{})
+		http.Error(w, "Empty body", http.StatusBadRequest)
+		return
+	case err != nil:
+		log.Error(err.Error(), log.Fields{})
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	// Verify JSON Body
+	err, httpError := validation.ValidateJsonSchemaData(appDependencyJSONFile, d)
+	if err != nil {
+		log.Error(err.Error(), log.Fields{})
+		http.Error(w, err.Error(), httpError)
+		return
+	}
+
+	vars := mux.Vars(r)
+	projectName := vars["project-name"]
+	compositeAppName := vars["composite-app-name"]
+	version := vars["version"]
+	appName := vars["app-name"]
+
+	if exists && d.MetaData.Name != vars["dependency-name"] {
+		log.Error("Dependency name in URL and body don't match", log.Fields{})
+		http.Error(w, "Dependency name in URL and body don't match", http.StatusBadRequest)
+		return
+	}
+
+	ret, err := h.client.WithContext(r.Context()).CreateAppDependency(d, projectName, compositeAppName, version, appName, exists)
+	if err != nil {
+		log.Error(err.Error(), log.Fields{})
+		if strings.Contains(err.Error(), "Unable to find") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else if strings.Contains(err.Error(), "already exists") {
+			http.Error(w, err.Error(), http.StatusConflict)
+		} else if strings.Contains(err.Error(), "Invalid") ||
+			strings.Contains(err.Error(), "itself") ||
+			strings.Contains(err.Error(), "loop") {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	code := http.StatusCreated
+	if exists {
+		code = http.StatusOK
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	err = json.NewEncoder(w).Encode(ret)
+	if err != nil {
+		log.Error(err.Error(), log.Fields{})
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// getHandler handles the GET operations on app dependencies
+// Returns all the dependencies of the app if no dependency name is given
+func (h appDependencyHandler) getHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name := vars["dependency-name"]
+	projectName := vars["project-name"]
+	compositeAppName := vars["composite-app-name"]
+	version := vars["version"]
+	appName := vars["app-name"]
+
+	var ret interface{}
+	var err error
+
+	if len(name) == 0 {
+		ret, err = h.client.GetAllAppDependency(projectName, compositeAppName, version, appName)
+	} else {
+		ret, err = h.client.WithContext(r.Context()).GetAppDependency(name, projectName, compositeAppName, version, appName)
+	}
+	if err != nil {
+		log.Error(err.Error(), log.Fields{})
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(ret)
+	if err != nil {
+		log.Error(err.Error(), log.Fields{})
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// deleteHandler handles the delete operation of an app dependency
+func (h appDependencyHandler) deleteHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name := vars["dependency-name"]
+	projectName := vars["project-name"]
+	compositeAppName := vars["composite-app-name"]
+	version := vars["version"]
+	appName := vars["app-name"]
+
+	err := h.client.WithContext(r.Context()).DeleteAppDependency(name, projectName, compositeAppName, version, appName)
+	if err != nil {
+		log.Error(err.Error(), log.Fields{})
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else if strings.Contains(err.Error(), "conflict") {
+			http.Error(w, err.Error(), http.StatusConflict)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
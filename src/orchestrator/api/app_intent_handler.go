@@ -68,7 +68,7 @@ func (h appIntentHandler) createAppIntentHandler(w http.ResponseWriter, r *http.
 	intent := vars["intent-name"]
 	digName := vars["deployment-intent-group-name"]
 
-	appIntent, createErr := h.client.CreateAppIntent(a, projectName, compositeAppName, version, intent, digName)
+	appIntent, createErr := h.client.WithContext(r.Context()).CreateAppIntent(a, projectName, compositeAppName, version, intent, digName)
 	if createErr != nil {
 		log.Error(createErr.Error(), log.Fields{})
 		if strings.Contains(createErr.Error(), "Unable to find the project") {
@@ -141,7 +141,7 @@ func (h appIntentHandler) getAppIntentHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	appIntent, err := h.client.GetAppIntent(ai, p, ca, v, i, dig)
+	appIntent, err := h.client.WithContext(r.Context()).GetAppIntent(ai, p, ca, v, i, dig)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "db Find error") {
@@ -264,7 +264,7 @@ func (h appIntentHandler) deleteAppIntentHandler(w http.ResponseWriter, r *http.
 	ai := vars["app-intent-name"]
 	digName := vars["deployment-intent-group-name"]
 
-	err := h.client.DeleteAppIntent(ai, p, ca, v, i, digName)
+	err := h.client.WithContext(r.Context()).DeleteAppIntent(ai, p, ca, v, i, digName)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "not found") {
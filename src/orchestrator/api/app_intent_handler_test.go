@@ -5,6 +5,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
@@ -28,6 +29,10 @@ type mockAppIntentManager struct {
 	Err   error
 }
 
+func (m *mockAppIntentManager) WithContext(ctx context.Context) moduleLib.AppIntentManager {
+	return m
+}
+
 func (m *mockAppIntentManager) CreateAppIntent(a moduleLib.AppIntent, p string, ca string, v string, i string, digName string) (moduleLib.AppIntent, error) {
 	if m.Err != nil {
 		return moduleLib.AppIntent{}, m.Err
@@ -355,7 +360,7 @@ func Test_appintent_createHandler(t *testing.T) {
 	for _, testCase := range testCases {
 		t.Run(testCase.label, func(t *testing.T) {
 			request := httptest.NewRequest("POST", "/v2/projects/{project-name}/composite-apps/{composite-app-name}/{version}/deployment-intent-groups/{deployment-intent-group-name}/generic-placement-intents/{intent-name}/app-intents", testCase.reader)
-			resp := executeRequestReturnWithBody(request, NewRouter(nil, nil, nil, nil, nil, testCase.cAppIntentClient, nil, nil, nil, nil, nil))
+			resp := executeRequestReturnWithBody(request, NewRouter(nil, nil, nil, nil, nil, testCase.cAppIntentClient, nil, nil, nil, nil, nil, nil, nil, nil))
 
 			b := string(resp.Body.Bytes())
 
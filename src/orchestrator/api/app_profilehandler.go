@@ -120,7 +120,14 @@ func (h appProfileHandler) createAppProfileHandler(w http.ResponseWriter, r *htt
 		return
 	}
 
-	ret, createErr := h.client.CreateAppProfile(project, compositeApp, compositeAppVersion, compositeProfile, ap, ac, false)
+	err = moduleLib.ValidateAppProfile(project, compositeApp, compositeAppVersion, ap, ac)
+	if err != nil {
+		log.Error(err.Error(), log.Fields{})
+		writeValidationError(w, err)
+		return
+	}
+
+	ret, createErr := h.client.WithContext(r.Context()).CreateAppProfile(project, compositeApp, compositeAppVersion, compositeProfile, ap, ac, false)
 	if createErr != nil {
 		log.Error(createErr.Error(), log.Fields{})
 		if strings.Contains(createErr.Error(), "Unable to find the compositeProfile") {
@@ -203,7 +210,7 @@ func (h appProfileHandler) getAppProfileHandler(w http.ResponseWriter, r *http.R
 	var retAppProfileContent moduleLib.AppProfileContent
 
 	if len(appName) != 0 {
-		retAppProfile, err = h.client.GetAppProfileByApp(project, compositeApp, compositeAppVersion, compositeProfile, appName)
+		retAppProfile, err = h.client.WithContext(r.Context()).GetAppProfileByApp(project, compositeApp, compositeAppVersion, compositeProfile, appName)
 		if err != nil {
 			log.Error(err.Error(), log.Fields{})
 			if strings.Contains(err.Error(), "db Find error") {
@@ -216,7 +223,7 @@ func (h appProfileHandler) getAppProfileHandler(w http.ResponseWriter, r *http.R
 			return
 		}
 
-		retAppProfileContent, err = h.client.GetAppProfileContentByApp(project, compositeApp, compositeAppVersion, compositeProfile, appName)
+		retAppProfileContent, err = h.client.WithContext(r.Context()).GetAppProfileContentByApp(project, compositeApp, compositeAppVersion, compositeProfile, appName)
 		if err != nil {
 			log.Error(err.Error(), log.Fields{})
 			if strings.Contains(err.Error(), "db Find error") {
@@ -229,7 +236,7 @@ func (h appProfileHandler) getAppProfileHandler(w http.ResponseWriter, r *http.R
 			return
 		}
 	} else {
-		retAppProfile, err = h.client.GetAppProfile(project, compositeApp, compositeAppVersion, compositeProfile, name)
+		retAppProfile, err = h.client.WithContext(r.Context()).GetAppProfile(project, compositeApp, compositeAppVersion, compositeProfile, name)
 		if err != nil {
 			log.Error(err.Error(), log.Fields{})
 			if strings.Contains(err.Error(), "db Find error") {
@@ -242,7 +249,7 @@ func (h appProfileHandler) getAppProfileHandler(w http.ResponseWriter, r *http.R
 			return
 		}
 
-		retAppProfileContent, err = h.client.GetAppProfileContent(project, compositeApp, compositeAppVersion, compositeProfile, name)
+		retAppProfileContent, err = h.client.WithContext(r.Context()).GetAppProfileContent(project, compositeApp, compositeAppVersion, compositeProfile, name)
 		if err != nil {
 			log.Error(err.Error(), log.Fields{})
 			if strings.Contains(err.Error(), "db Find error") {
@@ -328,7 +335,7 @@ func (h appProfileHandler) deleteAppProfileHandler(w http.ResponseWriter, r *htt
 	compositeProfile := vars["composite-profile-name"]
 	name := vars["app-profile"]
 
-	err := h.client.DeleteAppProfile(project, compositeApp, compositeAppVersion, compositeProfile, name)
+	err := h.client.WithContext(r.Context()).DeleteAppProfile(project, compositeApp, compositeAppVersion, compositeProfile, name)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "not found") {
@@ -424,7 +431,14 @@ func (h appProfileHandler) updateAppProfileHandler(w http.ResponseWriter, r *htt
 		return
 	}
 
-	ret, createErr := h.client.CreateAppProfile(project, compositeApp, compositeAppVersion, compositeProfile, ap, ac, true)
+	err = moduleLib.ValidateAppProfile(project, compositeApp, compositeAppVersion, ap, ac)
+	if err != nil {
+		log.Error(err.Error(), log.Fields{})
+		writeValidationError(w, err)
+		return
+	}
+
+	ret, createErr := h.client.WithContext(r.Context()).CreateAppProfile(project, compositeApp, compositeAppVersion, compositeProfile, ap, ac, true)
 	if createErr != nil {
 		log.Error(createErr.Error(), log.Fields{})
 		if strings.Contains(createErr.Error(), "Unable to find the compositeProfile") {
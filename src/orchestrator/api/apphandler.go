@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"io"
 	"io/ioutil"
 	"mime"
@@ -18,11 +19,13 @@ import (
 	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/validation"
 	moduleLib "github.com/open-ness/EMCO/src/orchestrator/pkg/module"
+	"github.com/open-ness/EMCO/src/orchestrator/utils/helm"
 
 	"github.com/gorilla/mux"
+	pkgerrors "github.com/pkg/errors"
 )
 
-var appJSONFile string = "json-schemas/metadata.json"
+var appJSONFile string = "json-schemas/app.json"
 
 // appHandler to store backend implementations objects
 // Also simplifies mocking for unit testing purposes
@@ -32,6 +35,74 @@ type appHandler struct {
 	client moduleLib.AppManager
 }
 
+// readAppFile reads the chart of an App uploaded in the file part of the
+// request. The Apps referencing a chart in a repository have no file.
+func readAppFile(r *http.Request, a moduleLib.App) (moduleLib.AppContent, error) {
+	var ac moduleLib.AppContent
+
+	//Read the file section and ignore the header
+	file, _, err := r.FormFile("file")
+	if a.Spec.Chart != nil {
+		if err == nil {
+			file.Close()
+			return ac, pkgerrors.New("An app referencing a chart has no file")
+		}
+		return ac, nil
+	}
+	if err != nil {
+		return ac, pkgerrors.New("Unable to process file")
+	}
+
+	defer file.Close()
+	//Convert the file content to base64 for storage
+	content, err := ioutil.ReadAll(file)
+	if err != nil {
+		return ac, pkgerrors.New("Unable to read file")
+	}
+	// Limit file Size to 1 GB
+	if len(content) > int(oneGB) {
+		return ac, pkgerrors.New("File Size Exceeds 1 GB")
+	}
+	err = validation.IsTarGz(bytes.NewBuffer(content))
+	if err != nil {
+		return ac, pkgerrors.New("Error in file format")
+	}
+
+	ac.FileContent = base64.StdEncoding.EncodeToString(content)
+	return ac, nil
+}
+
+// validationErrorResponse is the response to an app that fails to render or
+// has invalid manifests, with the errors of each file
+type validationErrorResponse struct {
+	Error string `json:"error"`
+	*helm.ValidationError
+}
+
+// writeValidationError writes the errors of each file of a
+// helm.ValidationError, or the error
+func writeValidationError(w http.ResponseWriter, err error) {
+	var verr *helm.ValidationError
+	if !errors.As(err, &verr) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	json.NewEncoder(w).Encode(validationErrorResponse{Error: err.Error(), ValidationError: verr})
+}
+
+// redactApp removes the password of the chart repository of an App from the
+// responses
+func redactApp(a moduleLib.App) moduleLib.App {
+	if a.Spec.Chart != nil && a.Spec.Chart.Password != "" {
+		chart := *a.Spec.Chart
+		chart.Password = ""
+		a.Spec.Chart = &chart
+	}
+	return a
+}
+
 // createAppHandler handles creation of the App entry in the database
 // This is a multipart handler. See following example curl request
 // curl -X POST http://localhost:9015/v2/projects/sampleProject/composite-apps/sampleCompositeApp/v1/apps \
@@ -72,43 +143,25 @@ func (h appHandler) createAppHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	//Read the file section and ignore the header
-	file, _, err := r.FormFile("file")
+	ac, err = readAppFile(r, a)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
-		http.Error(w, "Unable to process file", http.StatusUnprocessableEntity)
-		return
-	}
-
-	defer file.Close()
-	//Convert the file content to base64 for storage
-	content, err := ioutil.ReadAll(file)
-	if err != nil {
-		log.Error(err.Error(), log.Fields{})
-		http.Error(w, "Unable to read file", http.StatusUnprocessableEntity)
-		return
-	}
-	// Limit file Size to 1 GB
-	if len(content) > int(oneGB) {
-		log.Error(err.Error(), log.Fields{})
-		http.Error(w, "File Size Exceeds 1 GB", http.StatusUnprocessableEntity)
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
 		return
 	}
-	err = validation.IsTarGz(bytes.NewBuffer(content))
+	err = moduleLib.ValidateApp(a, ac)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
-		http.Error(w, "Error in file format", http.StatusUnprocessableEntity)
+		writeValidationError(w, err)
 		return
 	}
 
-	ac.FileContent = base64.StdEncoding.EncodeToString(content)
-
 	vars := mux.Vars(r)
 	projectName := vars["project-name"]
 	compositeAppName := vars["composite-app-name"]
 	compositeAppVersion := vars["version"]
 
-	ret, createErr := h.client.CreateApp(a, ac, projectName, compositeAppName, compositeAppVersion, false)
+	ret, createErr := h.client.WithContext(r.Context()).CreateApp(a, ac, projectName, compositeAppName, compositeAppVersion, false)
 	if createErr != nil {
 		log.Error(createErr.Error(), log.Fields{})
 		if strings.Contains(createErr.Error(), "Unable to find the project") {
@@ -117,6 +170,10 @@ func (h appHandler) createAppHandler(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, createErr.Error(), http.StatusNotFound)
 		} else if strings.Contains(createErr.Error(), "App already exists") {
 			http.Error(w, createErr.Error(), http.StatusConflict)
+		} else if strings.Contains(createErr.Error(), "quota exceeded") {
+			http.Error(w, createErr.Error(), http.StatusForbidden)
+		} else if strings.Contains(createErr.Error(), "Invalid chart reference") || strings.Contains(createErr.Error(), "Invalid app type") {
+			http.Error(w, createErr.Error(), http.StatusBadRequest)
 		} else {
 			http.Error(w, createErr.Error(), http.StatusInternalServerError)
 		}
@@ -125,7 +182,7 @@ func (h appHandler) createAppHandler(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	err = json.NewEncoder(w).Encode(ret)
+	err = json.NewEncoder(w).Encode(redactApp(ret))
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -160,7 +217,7 @@ func (h appHandler) getAppHandler(w http.ResponseWriter, r *http.Request) {
 		}
 
 		for _, app := range ret {
-			retList = append(retList, moduleLib.App{Metadata: app.Metadata})
+			retList = append(retList, redactApp(moduleLib.App{Metadata: app.Metadata, Spec: app.Spec}))
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -184,7 +241,7 @@ func (h appHandler) getAppHandler(w http.ResponseWriter, r *http.Request) {
 	var retApp moduleLib.App
 	var retAppContent moduleLib.AppContent
 
-	retApp, err = h.client.GetApp(name, projectName, compositeAppName, compositeAppVersion)
+	retApp, err = h.client.WithContext(r.Context()).GetApp(name, projectName, compositeAppName, compositeAppVersion)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "db Find error") {
@@ -196,8 +253,9 @@ func (h appHandler) getAppHandler(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
+	retApp = redactApp(retApp)
 
-	retAppContent, err = h.client.GetAppContent(name, projectName, compositeAppName, compositeAppVersion)
+	retAppContent, err = h.client.WithContext(r.Context()).GetAppContent(name, projectName, compositeAppName, compositeAppVersion)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "db Find error") {
@@ -283,7 +341,7 @@ func (h appHandler) deleteAppHandler(w http.ResponseWriter, r *http.Request) {
 	compositeAppVersion := vars["version"]
 	name := vars["app-name"]
 
-	err := h.client.DeleteApp(name, projectName, compositeAppName, compositeAppVersion)
+	err := h.client.WithContext(r.Context()).DeleteApp(name, projectName, compositeAppName, compositeAppVersion)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "not found") {
@@ -335,43 +393,25 @@ func (h appHandler) updateAppHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	//Read the file section and ignore the header
-	file, _, err := r.FormFile("file")
-	if err != nil {
-		log.Error(err.Error(), log.Fields{})
-		http.Error(w, "Unable to process file", http.StatusUnprocessableEntity)
-		return
-	}
-
-	defer file.Close()
-	//Convert the file content to base64 for storage
-	content, err := ioutil.ReadAll(file)
+	ac, err = readAppFile(r, a)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
-		http.Error(w, "Unable to read file", http.StatusUnprocessableEntity)
-		return
-	}
-	// Limit file Size to 1 GB
-	if len(content) > int(oneGB) {
-		log.Error(err.Error(), log.Fields{})
-		http.Error(w, "File Size Exceeds 1 GB", http.StatusUnprocessableEntity)
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
 		return
 	}
-	err = validation.IsTarGz(bytes.NewBuffer(content))
+	err = moduleLib.ValidateApp(a, ac)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
-		http.Error(w, "Error in file format", http.StatusUnprocessableEntity)
+		writeValidationError(w, err)
 		return
 	}
 
-	ac.FileContent = base64.StdEncoding.EncodeToString(content)
-
 	vars := mux.Vars(r)
 	projectName := vars["project-name"]
 	compositeAppName := vars["composite-app-name"]
 	compositeAppVersion := vars["version"]
 
-	ret, createErr := h.client.CreateApp(a, ac, projectName, compositeAppName, compositeAppVersion, true)
+	ret, createErr := h.client.WithContext(r.Context()).CreateApp(a, ac, projectName, compositeAppName, compositeAppVersion, true)
 	if createErr != nil {
 		log.Error(createErr.Error(), log.Fields{})
 		if strings.Contains(createErr.Error(), "Unable to find the project") {
@@ -380,6 +420,10 @@ func (h appHandler) updateAppHandler(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, createErr.Error(), http.StatusNotFound)
 		} else if strings.Contains(createErr.Error(), "App already exists") {
 			http.Error(w, createErr.Error(), http.StatusConflict)
+		} else if strings.Contains(createErr.Error(), "quota exceeded") {
+			http.Error(w, createErr.Error(), http.StatusForbidden)
+		} else if strings.Contains(createErr.Error(), "Invalid chart reference") || strings.Contains(createErr.Error(), "Invalid app type") {
+			http.Error(w, createErr.Error(), http.StatusBadRequest)
 		} else {
 			http.Error(w, createErr.Error(), http.StatusInternalServerError)
 		}
@@ -388,7 +432,7 @@ func (h appHandler) updateAppHandler(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	err = json.NewEncoder(w).Encode(ret)
+	err = json.NewEncoder(w).Encode(redactApp(ret))
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/audit"
+	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+)
+
+/* Used to store backend implementation objects
+Also simplifies mocking for unit testing purposes
+*/
+type auditHandler struct {
+	client audit.AuditManager
+}
+
+// getHandler returns the audit records of all the services, filtered by the
+// project, service, principal, from and to query parameters. The times are
+// in RFC 3339 format, from is inclusive and to exclusive.
+func (h auditHandler) getHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	f := audit.Filter{
+		Project:   q.Get("project"),
+		Service:   q.Get("service"),
+		Principal: q.Get("principal"),
+	}
+	var err error
+	if from := q.Get("from"); from != "" {
+		f.From, err = time.Parse(time.RFC3339, from)
+		if err != nil {
+			log.Error(err.Error(), log.Fields{})
+			http.Error(w, "Invalid from time: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if to := q.Get("to"); to != "" {
+		f.To, err = time.Parse(time.RFC3339, to)
+		if err != nil {
+			log.Error(err.Error(), log.Fields{})
+			http.Error(w, "Invalid to time: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	ret, err := h.client.GetRecords(f)
+	if err != nil {
+		log.Error(err.Error(), log.Fields{})
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(ret)
+	if err != nil {
+		log.Error(err.Error(), log.Fields{})
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -51,13 +51,15 @@ func (h compositeAppHandler) createHandler(w http.ResponseWriter, r *http.Reques
 	vars := mux.Vars(r)
 	projectName := vars["project-name"]
 
-	ret, err := h.client.CreateCompositeApp(c, projectName, false)
+	ret, err := h.client.WithContext(r.Context()).CreateCompositeApp(c, projectName, false)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "Unable to find the project") {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else if strings.Contains(err.Error(), "CompositeApp already exists") {
 			http.Error(w, err.Error(), http.StatusConflict)
+		} else if strings.Contains(err.Error(), "quota exceeded") {
+			http.Error(w, err.Error(), http.StatusForbidden)
 		} else {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 		}
@@ -82,7 +84,7 @@ func (h compositeAppHandler) getHandler(w http.ResponseWriter, r *http.Request)
 	version := vars["version"]
 	projectName := vars["project-name"]
 
-	ret, err := h.client.GetCompositeApp(name, version, projectName)
+	ret, err := h.client.WithContext(r.Context()).GetCompositeApp(name, version, projectName)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "db Find error") {
@@ -148,7 +150,7 @@ func (h compositeAppHandler) deleteHandler(w http.ResponseWriter, r *http.Reques
 	version := vars["version"]
 	projectName := vars["project-name"]
 
-	err := h.client.DeleteCompositeApp(name, version, projectName)
+	err := h.client.WithContext(r.Context()).DeleteCompositeApp(name, version, projectName)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "not found") {
@@ -189,13 +191,15 @@ func (h compositeAppHandler) updateHandler(w http.ResponseWriter, r *http.Reques
 	vars := mux.Vars(r)
 	projectName := vars["project-name"]
 
-	ret, err := h.client.CreateCompositeApp(c, projectName, true)
+	ret, err := h.client.WithContext(r.Context()).CreateCompositeApp(c, projectName, true)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "Unable to find the project") {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else if strings.Contains(err.Error(), "CompositeApp already exists") {
 			http.Error(w, err.Error(), http.StatusConflict)
+		} else if strings.Contains(err.Error(), "quota exceeded") {
+			http.Error(w, err.Error(), http.StatusForbidden)
 		} else {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 		}
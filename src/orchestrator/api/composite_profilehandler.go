@@ -55,7 +55,7 @@ func (h compositeProfileHandler) createHandler(w http.ResponseWriter, r *http.Re
 	compositeAppName := vars["composite-app-name"]
 	version := vars["composite-app-version"]
 
-	cProf, createErr := h.client.CreateCompositeProfile(cpf, projectName, compositeAppName, version, false)
+	cProf, createErr := h.client.WithContext(r.Context()).CreateCompositeProfile(cpf, projectName, compositeAppName, version, false)
 	if createErr != nil {
 		log.Error(createErr.Error(), log.Fields{})
 		if strings.Contains(createErr.Error(), "Unable to find the project") {
@@ -137,7 +137,7 @@ func (h compositeProfileHandler) getHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	cProf, err := h.client.GetCompositeProfile(cProfName, projectName, compositeAppName, version)
+	cProf, err := h.client.WithContext(r.Context()).GetCompositeProfile(cProfName, projectName, compositeAppName, version)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "db Find error") {
@@ -168,7 +168,7 @@ func (h compositeProfileHandler) deleteHandler(w http.ResponseWriter, r *http.Re
 	ca := vars["composite-app-name"]
 	v := vars["composite-app-version"]
 
-	err := h.client.DeleteCompositeProfile(c, p, ca, v)
+	err := h.client.WithContext(r.Context()).DeleteCompositeProfile(c, p, ca, v)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "not found") {
@@ -213,7 +213,7 @@ func (h compositeProfileHandler) updateHandler(w http.ResponseWriter, r *http.Re
 	compositeAppName := vars["composite-app-name"]
 	version := vars["composite-app-version"]
 
-	cProf, createErr := h.client.CreateCompositeProfile(cpf, projectName, compositeAppName, version, true)
+	cProf, createErr := h.client.WithContext(r.Context()).CreateCompositeProfile(cpf, projectName, compositeAppName, version, true)
 	if createErr != nil {
 		log.Error(createErr.Error(), log.Fields{})
 		if strings.Contains(createErr.Error(), "Unable to find the project") {
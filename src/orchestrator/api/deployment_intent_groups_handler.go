@@ -55,7 +55,7 @@ func (h deploymentIntentGroupHandler) createDeploymentIntentGroupHandler(w http.
 	compositeAppName := vars["composite-app-name"]
 	version := vars["composite-app-version"]
 
-	dIntent, createErr := h.client.CreateDeploymentIntentGroup(d, projectName, compositeAppName, version)
+	dIntent, createErr := h.client.WithContext(r.Context()).CreateDeploymentIntentGroup(d, projectName, compositeAppName, version)
 	if createErr != nil {
 		log.Error(createErr.Error(), log.Fields{})
 		if strings.Contains(createErr.Error(), "Unable to find the project") {
@@ -64,6 +64,10 @@ func (h deploymentIntentGroupHandler) createDeploymentIntentGroupHandler(w http.
 			http.Error(w, createErr.Error(), http.StatusNotFound)
 		} else if strings.Contains(createErr.Error(), "DeploymentIntent already exists") {
 			http.Error(w, createErr.Error(), http.StatusConflict)
+		} else if strings.Contains(createErr.Error(), "quota exceeded") {
+			http.Error(w, createErr.Error(), http.StatusForbidden)
+		} else if strings.Contains(createErr.Error(), "Invalid override values") {
+			http.Error(w, createErr.Error(), http.StatusBadRequest)
 		} else {
 			http.Error(w, createErr.Error(), http.StatusInternalServerError)
 		}
@@ -111,7 +115,7 @@ func (h deploymentIntentGroupHandler) getDeploymentIntentGroupHandler(w http.Res
 		return
 	}
 
-	dIntentGrp, err := h.client.GetDeploymentIntentGroup(di, p, ca, v)
+	dIntentGrp, err := h.client.WithContext(r.Context()).GetDeploymentIntentGroup(di, p, ca, v)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "db Find error") {
@@ -176,7 +180,7 @@ func (h deploymentIntentGroupHandler) deleteDeploymentIntentGroupHandler(w http.
 	v := vars["composite-app-version"]
 	di := vars["deployment-intent-group-name"]
 
-	err := h.client.DeleteDeploymentIntentGroup(di, p, ca, v)
+	err := h.client.WithContext(r.Context()).DeleteDeploymentIntentGroup(di, p, ca, v)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "Error getting appcontext") {
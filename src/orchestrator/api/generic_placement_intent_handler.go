@@ -56,7 +56,7 @@ func (h genericPlacementIntentHandler) createGenericPlacementIntentHandler(w htt
 	version := vars["composite-app-version"]
 	digName := vars["deployment-intent-group-name"]
 
-	gPIntent, createErr := h.client.CreateGenericPlacementIntent(g, projectName, compositeAppName, version, digName)
+	gPIntent, createErr := h.client.WithContext(r.Context()).CreateGenericPlacementIntent(g, projectName, compositeAppName, version, digName)
 	if createErr != nil {
 		log.Error(createErr.Error(), log.Fields{})
 		if strings.Contains(createErr.Error(), "Unable to find the project") {
@@ -119,7 +119,7 @@ func (h genericPlacementIntentHandler) getGenericPlacementHandler(w http.Respons
 		return
 	}
 
-	gPIntent, err := h.client.GetGenericPlacementIntent(intentName, projectName, compositeAppName, version, dig)
+	gPIntent, err := h.client.WithContext(r.Context()).GetGenericPlacementIntent(intentName, projectName, compositeAppName, version, dig)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "db Find error") {
@@ -185,7 +185,7 @@ func (h genericPlacementIntentHandler) deleteGenericPlacementHandler(w http.Resp
 	v := vars["composite-app-version"]
 	digName := vars["deployment-intent-group-name"]
 
-	err := h.client.DeleteGenericPlacementIntent(i, p, ca, v, digName)
+	err := h.client.WithContext(r.Context()).DeleteGenericPlacementIntent(i, p, ca, v, digName)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "not found") {
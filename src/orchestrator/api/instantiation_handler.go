@@ -5,6 +5,7 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
@@ -33,7 +34,13 @@ func (h instantiationHandler) approveHandler(w http.ResponseWriter, r *http.Requ
 	iErr := h.client.Approve(p, ca, v, di)
 	if iErr != nil {
 		log.Error(iErr.Error(), log.Fields{})
-		http.Error(w, iErr.Error(), http.StatusInternalServerError)
+		if strings.Contains(iErr.Error(), "Error fetching chart") {
+			http.Error(w, iErr.Error(), http.StatusBadGateway)
+		} else if strings.Contains(iErr.Error(), "quota exceeded") {
+			http.Error(w, iErr.Error(), http.StatusForbidden)
+		} else {
+			writeValidationError(w, iErr)
+		}
 		return
 	}
 	w.WriteHeader(http.StatusAccepted)
@@ -48,7 +55,7 @@ func (h instantiationHandler) instantiateHandler(w http.ResponseWriter, r *http.
 	v := vars["composite-app-version"]
 	di := vars["deployment-intent-group-name"]
 
-	iErr := h.client.Instantiate(p, ca, v, di)
+	iErr := h.client.Instantiate(r.Context(), p, ca, v, di)
 	if iErr != nil {
 		log.Error(iErr.Error(), log.Fields{})
 		switch iErr.Error() {
@@ -65,7 +72,11 @@ func (h instantiationHandler) instantiateHandler(w http.ResponseWriter, r *http.
 		case "No Qualified Clusters to deploy App":
 			http.Error(w, iErr.Error(), http.StatusInternalServerError)
 		default:
-			http.Error(w, iErr.Error(), http.StatusInternalServerError)
+			if strings.Contains(iErr.Error(), "quota exceeded") {
+				http.Error(w, iErr.Error(), http.StatusForbidden)
+			} else {
+				http.Error(w, iErr.Error(), http.StatusInternalServerError)
+			}
 		}
 		return
 	}
@@ -109,6 +120,39 @@ func (h instantiationHandler) stopHandler(w http.ResponseWriter, r *http.Request
 
 }
 
+// planHandler returns the resources that an instantiation of the
+// DeploymentIntentGroup would deploy, without deploying them
+func (h instantiationHandler) planHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	p := vars["project-name"]
+	ca := vars["composite-app-name"]
+	v := vars["composite-app-version"]
+	di := vars["deployment-intent-group-name"]
+
+	plan, iErr := h.client.Plan(p, ca, v, di)
+	if iErr != nil {
+		log.Error(iErr.Error(), log.Fields{})
+		if strings.Contains(iErr.Error(), "Not finding the deploymentIntentGroup") {
+			http.Error(w, iErr.Error(), http.StatusNotFound)
+		} else if strings.Contains(iErr.Error(), "before planning") || strings.Contains(iErr.Error(), "invalid state for planning") {
+			http.Error(w, iErr.Error(), http.StatusConflict)
+		} else {
+			http.Error(w, iErr.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err := json.NewEncoder(w).Encode(plan)
+	if err != nil {
+		log.Error(err.Error(), log.Fields{})
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
 func (h instantiationHandler) statusHandler(w http.ResponseWriter, r *http.Request) {
 
 	vars := mux.Vars(r)
@@ -276,3 +320,51 @@ func (h instantiationHandler) statusHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 }
+
+// eventsHandler streams the state and status changes of the DeploymentIntentGroup
+// as server-sent events until the client disconnects
+func (h instantiationHandler) eventsHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	p := vars["project-name"]
+	ca := vars["composite-app-name"]
+	v := vars["composite-app-version"]
+	di := vars["deployment-intent-group-name"]
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		log.Error("Streaming not supported", log.Fields{})
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	events, iErr := h.client.Events(r.Context(), p, ca, v, di)
+	if iErr != nil {
+		log.Error(iErr.Error(), log.Fields{})
+		if strings.Contains(iErr.Error(), "state not found") {
+			http.Error(w, iErr.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, iErr.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for e := range events {
+		data, err := json.Marshal(e)
+		if err != nil {
+			log.Error(err.Error(), log.Fields{})
+			continue
+		}
+		_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+		if err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
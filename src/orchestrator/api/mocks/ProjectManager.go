@@ -88,3 +88,24 @@ func (_m *ProjectManager) GetProject(name string) (module.Project, error) {
 
 	return r0, r1
 }
+
+// GetProjectUsage provides a mock function with given fields: name
+func (_m *ProjectManager) GetProjectUsage(name string) (module.ProjectUsage, error) {
+	ret := _m.Called(name)
+
+	var r0 module.ProjectUsage
+	if rf, ok := ret.Get(0).(func(string) module.ProjectUsage); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Get(0).(module.ProjectUsage)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -10,12 +10,13 @@ import (
 	"strings"
 
 	"github.com/gorilla/mux"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/auth"
 	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/validation"
 	moduleLib "github.com/open-ness/EMCO/src/orchestrator/pkg/module"
 )
 
-var projectJSONFile string = "json-schemas/metadata.json"
+var projectJSONFile string = "json-schemas/project.json"
 
 // Used to store backend implementations objects
 // Also simplifies mocking for unit testing purposes
@@ -49,7 +50,7 @@ func (h projectHandler) createHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ret, createErr := h.client.CreateProject(p, false)
+	ret, createErr := h.client.WithContext(r.Context()).CreateProject(p, false)
 	if createErr != nil {
 		log.Error(createErr.Error(), log.Fields{})
 		if strings.Contains(createErr.Error(), "Project already exists") {
@@ -89,6 +90,14 @@ func (h projectHandler) updateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Verify JSON Body
+	err, httpError := validation.ValidateJsonSchemaData(projectJSONFile, p)
+	if err != nil {
+		log.Error(err.Error(), log.Fields{})
+		http.Error(w, err.Error(), httpError)
+		return
+	}
+
 	// Name is required.
 	if p.MetaData.Name == "" {
 		log.Error("Missing name in PUT request", log.Fields{})
@@ -103,7 +112,7 @@ func (h projectHandler) updateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ret, err := h.client.CreateProject(p, true)
+	ret, err := h.client.WithContext(r.Context()).CreateProject(p, true)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "already exists") {
@@ -148,7 +157,11 @@ func (h projectHandler) getHandler(w http.ResponseWriter, r *http.Request) {
 		}
 
 		for _, p := range projects {
-			pList = append(pList, moduleLib.Project{MetaData: p.MetaData})
+			// Only the projects the caller has a role in are listed
+			if !auth.ProjectAllowed(r.Context(), p.MetaData.Name) {
+				continue
+			}
+			pList = append(pList, moduleLib.Project{MetaData: p.MetaData, Spec: p.Spec})
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -163,7 +176,35 @@ func (h projectHandler) getHandler(w http.ResponseWriter, r *http.Request) {
 
 	}
 
-	ret, err := h.client.GetProject(name)
+	ret, err := h.client.WithContext(r.Context()).GetProject(name)
+	if err != nil {
+		log.Error(err.Error(), log.Fields{})
+		if strings.Contains(err.Error(), "db Find error") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else if strings.Contains(err.Error(), "not found") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(ret)
+	if err != nil {
+		log.Error(err.Error(), log.Fields{})
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// usageHandler returns the resources the project uses and its limits
+func (h projectHandler) usageHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name := vars["project-name"]
+
+	ret, err := h.client.GetProjectUsage(name)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "db Find error") {
@@ -191,7 +232,7 @@ func (h projectHandler) deleteHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	name := vars["project-name"]
 
-	_, err := h.client.GetProject(name)
+	_, err := h.client.WithContext(r.Context()).GetProject(name)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "db Find error") {
@@ -204,7 +245,7 @@ func (h projectHandler) deleteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.client.DeleteProject(name)
+	err = h.client.WithContext(r.Context()).DeleteProject(name)
 	if err != nil {
 		log.Error(err.Error(), log.Fields{})
 		if strings.Contains(err.Error(), "not found") {
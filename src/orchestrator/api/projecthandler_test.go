@@ -5,6 +5,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -27,6 +28,10 @@ type mockProjectManager struct {
 	Err   error
 }
 
+func (m *mockProjectManager) WithContext(ctx context.Context) moduleLib.ProjectManager {
+	return m
+}
+
 func (m *mockProjectManager) CreateProject(inp moduleLib.Project, exists bool) (moduleLib.Project, error) {
 	if m.Err != nil {
 		return moduleLib.Project{}, m.Err
@@ -51,8 +56,16 @@ func (m *mockProjectManager) GetAllProjects() ([]moduleLib.Project, error) {
 	return []moduleLib.Project{}, m.Err
 }
 
+func (m *mockProjectManager) GetProjectUsage(name string) (moduleLib.ProjectUsage, error) {
+	if m.Err != nil {
+		return moduleLib.ProjectUsage{}, m.Err
+	}
+
+	return moduleLib.ProjectUsage{Project: name, Limits: m.Items[0].Spec.Limits}, nil
+}
+
 func init() {
-	projectJSONFile = "../json-schemas/metadata.json"
+	projectJSONFile = "../json-schemas/project.json"
 }
 
 func TestProjectCreateHandler(t *testing.T) {
@@ -109,12 +122,27 @@ func TestProjectCreateHandler(t *testing.T) {
 			expectedCode:  http.StatusBadRequest,
 			projectClient: &mockProjectManager{},
 		},
+		{
+			label: "Negative Project Limit",
+			reader: bytes.NewBuffer([]byte(`{
+				"metadata" : {
+					"name": "testProject"
+				},
+				"spec" : {
+					"limits": {
+						"compositeApps": -1
+					}
+				}
+			}`)),
+			expectedCode:  http.StatusBadRequest,
+			projectClient: &mockProjectManager{},
+		},
 	}
 
 	for _, testCase := range testCases {
 		t.Run(testCase.label, func(t *testing.T) {
 			request := httptest.NewRequest("POST", "/v2/projects", testCase.reader)
-			resp := executeRequest(request, NewRouter(testCase.projectClient, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil))
+			resp := executeRequest(request, NewRouter(testCase.projectClient, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil))
 
 			//Check returned code
 			if resp.StatusCode != testCase.expectedCode {
@@ -207,7 +235,7 @@ func TestProjectUpdateHandler(t *testing.T) {
 	for _, testCase := range testCases {
 		t.Run(testCase.label, func(t *testing.T) {
 			request := httptest.NewRequest("PUT", "/v2/projects/"+testCase.name, testCase.reader)
-			resp := executeRequest(request, NewRouter(testCase.projectClient, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil))
+			resp := executeRequest(request, NewRouter(testCase.projectClient, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil))
 
 			//Check returned code
 			if resp.StatusCode != testCase.expectedCode {
@@ -276,7 +304,7 @@ func TestProjectGetHandler(t *testing.T) {
 	for _, testCase := range testCases {
 		t.Run(testCase.label, func(t *testing.T) {
 			request := httptest.NewRequest("GET", "/v2/projects/"+testCase.name, nil)
-			resp := executeRequest(request, NewRouter(testCase.projectClient, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil))
+			resp := executeRequest(request, NewRouter(testCase.projectClient, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil))
 
 			//Check returned code
 			if resp.StatusCode != testCase.expectedCode {
@@ -297,6 +325,71 @@ func TestProjectGetHandler(t *testing.T) {
 	}
 }
 
+func TestProjectUsageHandler(t *testing.T) {
+
+	testCases := []struct {
+		label         string
+		expected      moduleLib.ProjectUsage
+		name          string
+		expectedCode  int
+		projectClient *mockProjectManager
+	}{
+		{
+			label:        "Get Project Usage",
+			expectedCode: http.StatusOK,
+			expected: moduleLib.ProjectUsage{
+				Project: "testProject",
+				Limits:  moduleLib.ProjectLimits{CompositeApps: 2, ChartStorage: 1048576},
+			},
+			name: "testProject",
+			projectClient: &mockProjectManager{
+				Items: []moduleLib.Project{
+					{
+						MetaData: moduleLib.ProjectMetaData{
+							Name: "testProject",
+						},
+						Spec: moduleLib.ProjectSpec{
+							Limits: moduleLib.ProjectLimits{CompositeApps: 2, ChartStorage: 1048576},
+						},
+					},
+				},
+			},
+		},
+		{
+			label:        "Get Non-Existing Project Usage",
+			expectedCode: http.StatusNotFound,
+			name:         "nonexistingproject",
+			projectClient: &mockProjectManager{
+				Items: []moduleLib.Project{},
+				Err:   pkgerrors.New("Project not found"),
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			request := httptest.NewRequest("GET", "/v2/projects/"+testCase.name+"/usage", nil)
+			resp := executeRequest(request, NewRouter(testCase.projectClient, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil))
+
+			//Check returned code
+			if resp.StatusCode != testCase.expectedCode {
+				t.Fatalf("Expected %d; Got: %d", testCase.expectedCode, resp.StatusCode)
+			}
+
+			//Check returned body only if statusOK
+			if resp.StatusCode == http.StatusOK {
+				got := moduleLib.ProjectUsage{}
+				json.NewDecoder(resp.Body).Decode(&got)
+
+				if reflect.DeepEqual(testCase.expected, got) == false {
+					t.Errorf("usageHandler returned unexpected body: got %v;"+
+						" expected %v", got, testCase.expected)
+				}
+			}
+		})
+	}
+}
+
 func TestProjectDeleteHandler(t *testing.T) {
 
 	testCases := []struct {
@@ -337,7 +430,7 @@ func TestProjectDeleteHandler(t *testing.T) {
 	for _, testCase := range testCases {
 		t.Run(testCase.label, func(t *testing.T) {
 			request := httptest.NewRequest("DELETE", "/v2/projects/"+testCase.name, nil)
-			resp := executeRequest(request, NewRouter(testCase.projectClient, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil))
+			resp := executeRequest(request, NewRouter(testCase.projectClient, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil))
 
 			//Check returned code
 			if resp.StatusCode != testCase.expectedCode {
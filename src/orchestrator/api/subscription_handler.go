@@ -0,0 +1,181 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/validation"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/module/subscription"
+
+	"github.com/gorilla/mux"
+)
+
+var subscriptionJSONFile string = "json-schemas/subscription.json"
+
+/* Used to store backend implementation objects
+Also simplifies mocking for unit testing purposes
+*/
+type subscriptionHandler struct {
+	client subscription.SubscriptionManager
+}
+
+// createHandler handles the create operation of a subscription
+func (h subscriptionHandler) createHandler(w http.ResponseWriter, r *http.Request) {
+	h.createOrUpdateHandler(w, r, false)
+}
+
+// updateHandler handles the update operation of a subscription
+func (h subscriptionHandler) updateHandler(w http.ResponseWriter, r *http.Request) {
+	h.createOrUpdateHandler(w, r, true)
+}
+
+func (h subscriptionHandler) createOrUpdateHandler(w http.ResponseWriter, r *http.Request, exists bool) {
+	var s subscription.Subscription
+
+	err := json.NewDecoder(r.Body).Decode(&s)
+	switch {
+	case err == io.EOF:
+		log.Error(err.Error(), log.Fields{})
+		http.Error(w, "Empty body", http.StatusBadRequest)
+		return
+	case err != nil:
+		log.Error(err.Error(), log.Fields{})
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	// Verify JSON Body
+	err, httpError := validation.ValidateJsonSchemaData(subscriptionJSONFile, s)
+	if err != nil {
+		log.Error(err.Error(), log.Fields{})
+		http.Error(w, err.Error(), httpError)
+		return
+	}
+
+	vars := mux.Vars(r)
+	projectName := vars["project-name"]
+
+	if exists && s.Metadata.Name != vars["subscription-name"] {
+		log.Error("Subscription name in URL and body don't match", log.Fields{})
+		http.Error(w, "Subscription name in URL and body don't match", http.StatusBadRequest)
+		return
+	}
+
+	ret, err := h.client.CreateSubscription(s, projectName, exists)
+	if err != nil {
+		log.Error(err.Error(), log.Fields{})
+		if strings.Contains(err.Error(), "Unable to find") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else if strings.Contains(err.Error(), "already exists") {
+			http.Error(w, err.Error(), http.StatusConflict)
+		} else if strings.Contains(err.Error(), "Invalid") {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	code := http.StatusCreated
+	if exists {
+		code = http.StatusOK
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	err = json.NewEncoder(w).Encode(ret)
+	if err != nil {
+		log.Error(err.Error(), log.Fields{})
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// getHandler handles the GET operations on subscriptions
+// Returns all the subscriptions of the project if no subscription name is given
+func (h subscriptionHandler) getHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name := vars["subscription-name"]
+	projectName := vars["project-name"]
+
+	var ret interface{}
+	var err error
+
+	if len(name) == 0 {
+		ret, err = h.client.GetAllSubscriptions(projectName)
+	} else {
+		ret, err = h.client.GetSubscription(name, projectName)
+	}
+	if err != nil {
+		log.Error(err.Error(), log.Fields{})
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(ret)
+	if err != nil {
+		log.Error(err.Error(), log.Fields{})
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// deliveriesHandler returns the recent deliveries of events to the subscription
+func (h subscriptionHandler) deliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name := vars["subscription-name"]
+	projectName := vars["project-name"]
+
+	ret, err := h.client.GetDeliveries(name, projectName)
+	if err != nil {
+		log.Error(err.Error(), log.Fields{})
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(ret)
+	if err != nil {
+		log.Error(err.Error(), log.Fields{})
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// deleteHandler handles the delete operation of a subscription
+func (h subscriptionHandler) deleteHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name := vars["subscription-name"]
+	projectName := vars["project-name"]
+
+	err := h.client.DeleteSubscription(name, projectName)
+	if err != nil {
+		log.Error(err.Error(), log.Fields{})
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else if strings.Contains(err.Error(), "conflict") {
+			http.Error(w, err.Error(), http.StatusConflict)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
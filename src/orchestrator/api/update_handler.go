@@ -5,6 +5,7 @@ import (
 	"github.com/gorilla/mux"
 	"io"
 	"net/http"
+	"strings"
 	moduleLib "github.com/open-ness/EMCO/src/orchestrator/pkg/module"
 	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/validation"
@@ -77,9 +78,13 @@ func (h updateHandler) updateHandler(w http.ResponseWriter, r *http.Request) {
 	v := vars["composite-app-version"]
 	di := vars["deployment-intent-group-name"]
 
-	revisionID, iErr := h.client.Update(p, ca, v, di)
+	revisionID, iErr := h.client.Update(r.Context(), p, ca, v, di)
 	if iErr != nil {
 		log.Error(iErr.Error(), log.Fields{})
+		if strings.Contains(iErr.Error(), "quota exceeded") {
+			http.Error(w, iErr.Error(), http.StatusForbidden)
+			return
+		}
 		utils.HandleLogicalCloudError(iErr.Error(), &w)
 		return
 	}
@@ -139,4 +144,75 @@ func (h updateHandler) rollbackHandler(w http.ResponseWriter, r *http.Request) {
 		"dep-group": di, "revision": rbRev, "return-value": iErr})
 	w.WriteHeader(http.StatusAccepted)
 
-}
\ No newline at end of file
+}
+
+// revisionsHandler returns the revisions of the DeploymentIntentGroup
+func (h updateHandler) revisionsHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	p := vars["project-name"]
+	ca := vars["composite-app-name"]
+	v := vars["composite-app-version"]
+	di := vars["deployment-intent-group-name"]
+
+	revisions, iErr := h.client.Revisions(p, ca, v, di)
+	if iErr != nil {
+		log.Error(iErr.Error(), log.Fields{})
+		if strings.Contains(iErr.Error(), "no state info") {
+			http.Error(w, iErr.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, iErr.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err := json.NewEncoder(w).Encode(revisions)
+	if err != nil {
+		log.Error(err.Error(), log.Fields{})
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// revisionDiffHandler returns the difference between two revisions of the DeploymentIntentGroup
+func (h updateHandler) revisionDiffHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	p := vars["project-name"]
+	ca := vars["composite-app-name"]
+	v := vars["composite-app-version"]
+	di := vars["deployment-intent-group-name"]
+
+	from := r.URL.Query().Get("from")
+	to := r.URL.Query().Get("to")
+	if from == "" || to == "" {
+		log.Error("Missing from or to revision in diff request", log.Fields{})
+		http.Error(w, "Missing from or to revision in diff request", http.StatusBadRequest)
+		return
+	}
+
+	diff, iErr := h.client.RevisionDiff(p, ca, v, di, from, to)
+	if iErr != nil {
+		log.Error(iErr.Error(), log.Fields{})
+		if strings.Contains(iErr.Error(), "Invalid revision") {
+			http.Error(w, iErr.Error(), http.StatusBadRequest)
+		} else if strings.Contains(iErr.Error(), "Revision not found") ||
+			strings.Contains(iErr.Error(), "no state info") {
+			http.Error(w, iErr.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, iErr.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	err := json.NewEncoder(w).Encode(diff)
+	if err != nil {
+		log.Error(err.Error(), log.Fields{})
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -5,11 +5,16 @@ package api
 
 import (
 	"bytes"
+	"context"
 	moduleLib "github.com/open-ness/EMCO/src/orchestrator/pkg/module"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+
+	pkgerrors "github.com/pkg/errors"
 )
 
 //Creating an embedded interface via anonymous variable
@@ -31,7 +36,7 @@ func (m mockInstantiationManager) Migrate(p string, ca string, v string, tCav st
 }
 
 
-func (m mockInstantiationManager) Update(p string, ca string, v string, di string) (int64, error) {
+func (m mockInstantiationManager) Update(ctx context.Context, p string, ca string, v string, di string) (int64, error) {
 	if m.Err != nil {
 		return -1,m.Err
 	}
@@ -48,6 +53,26 @@ func (m mockInstantiationManager) Rollback(p string, ca string, v string, di str
 }
 
 
+func (m mockInstantiationManager) RevisionDiff(p string, ca string, v string, di string, fromRev string, toRev string) (moduleLib.AppContextDiff, error) {
+	if m.Err != nil {
+		return moduleLib.AppContextDiff{}, m.Err
+	}
+
+	return moduleLib.AppContextDiff{From: fromRev, To: toRev}, nil
+}
+
+func (m mockInstantiationManager) Events(ctx context.Context, p string, ca string, v string, di string) (<-chan moduleLib.DigEvent, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+
+	events := make(chan moduleLib.DigEvent, 2)
+	events <- moduleLib.DigEvent{Type: moduleLib.DigEventTypeEnum.State, Status: "Instantiated", Previous: "Approved"}
+	events <- moduleLib.DigEvent{Type: moduleLib.DigEventTypeEnum.AppContext, Status: "Instantiated"}
+	close(events)
+	return events, nil
+}
+
 func init() {
 	migrateJSONFile = "../json-schemas/migrate.json"
 	rollbackJSONFile = "../json-schemas/rollback.json"
@@ -110,7 +135,7 @@ func Test_updateHandler_migrate(t *testing.T) {
 	for _, testCase := range testCases {
 		t.Run(testCase.label, func(t *testing.T) {
 			request := httptest.NewRequest("POST", "/v2/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/migrate", testCase.reader)
-			resp := executeRequest(request, NewRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, testCase.uClient))
+			resp := executeRequest(request, NewRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, testCase.uClient, nil, nil, nil))
 
 			//Check returned code
 			if resp.StatusCode != testCase.expectedCode {
@@ -137,7 +162,7 @@ func Test_updateHandler_update(t *testing.T) {
 	for _, testCase := range testCases {
 		t.Run(testCase.label, func(t *testing.T) {
 			request := httptest.NewRequest("POST", "/v2/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/update", testCase.reader)
-			resp := executeRequest(request, NewRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, testCase.uClient))
+			resp := executeRequest(request, NewRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, testCase.uClient, nil, nil, nil))
 
 			//Check returned code
 			if resp.StatusCode != testCase.expectedCode {
@@ -188,7 +213,7 @@ func Test_updateHandler_rollback(t *testing.T) {
 	for _, testCase := range testCases {
 		t.Run(testCase.label, func(t *testing.T) {
 			request := httptest.NewRequest("POST", "/v2/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/rollback", testCase.reader)
-			resp := executeRequest(request, NewRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, testCase.uClient))
+			resp := executeRequest(request, NewRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, testCase.uClient, nil, nil, nil))
 
 			//Check returned code
 			if resp.StatusCode != testCase.expectedCode {
@@ -197,4 +222,81 @@ func Test_updateHandler_rollback(t *testing.T) {
 		})
 	}
 
-}
\ No newline at end of file
+}
+
+func Test_updateHandler_revisionDiff(t *testing.T) {
+	testCases := []struct {
+		label        string
+		query        string
+		expectedCode int
+		uClient      mockInstantiationManager
+	}{
+		{
+			label:        "Missing to revision",
+			query:        "?from=0",
+			expectedCode: http.StatusBadRequest,
+			uClient:      mockInstantiationManager{},
+		},
+		{
+			label:        "Diff revisions",
+			query:        "?from=0&to=1",
+			expectedCode: http.StatusOK,
+			uClient:      mockInstantiationManager{},
+		},
+		{
+			label:        "Unknown revision",
+			query:        "?from=0&to=5",
+			expectedCode: http.StatusNotFound,
+			uClient:      mockInstantiationManager{Err: pkgerrors.New("Revision not found: 5")},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			request := httptest.NewRequest("GET", "/v2/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/revisions/diff"+testCase.query, nil)
+			resp := executeRequest(request, NewRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, testCase.uClient, nil, nil, nil))
+
+			//Check returned code
+			if resp.StatusCode != testCase.expectedCode {
+				t.Fatalf("Expected %d; Got: %d", testCase.expectedCode, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func Test_instantiationHandler_events(t *testing.T) {
+	testCases := []struct {
+		label        string
+		expectedCode int
+		expectedBody string
+		uClient      mockInstantiationManager
+	}{
+		{
+			label:        "Stream events",
+			expectedCode: http.StatusOK,
+			expectedBody: "event: state\ndata: {\"type\":\"state\",\"status\":\"Instantiated\",\"previous\":\"Approved\"",
+			uClient:      mockInstantiationManager{},
+		},
+		{
+			label:        "Unknown DeploymentIntentGroup",
+			expectedCode: http.StatusNotFound,
+			uClient:      mockInstantiationManager{Err: pkgerrors.New("deploymentIntentGroup state not found: dig1")},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			request := httptest.NewRequest("GET", "/v2/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}/events", nil)
+			resp := executeRequest(request, NewRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, testCase.uClient, nil, nil, nil))
+
+			//Check returned code
+			if resp.StatusCode != testCase.expectedCode {
+				t.Fatalf("Expected %d; Got: %d", testCase.expectedCode, resp.StatusCode)
+			}
+			if testCase.expectedBody != "" {
+				body, _ := ioutil.ReadAll(resp.Body)
+				if !strings.Contains(string(body), testCase.expectedBody) || !strings.Contains(string(body), "event: appcontext") {
+					t.Errorf("Unexpected events %s", body)
+				}
+			}
+		})
+	}
+}
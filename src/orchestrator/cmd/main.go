@@ -12,19 +12,24 @@ import (
 	"os/signal"
 	"time"
 
-	"github.com/gorilla/handlers"
 	"github.com/open-ness/EMCO/src/orchestrator/api"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/auth"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/config"
 	contextDb "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/contextdb"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/metrics"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/middleware"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/tracing"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/rpc"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/module"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/module/controller"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/module/subscription"
 )
 
 func main() {
 
 	rand.Seed(time.Now().UnixNano())
+	tracing.Init("orchestrator")
 
 	err := db.InitializeDatabaseConnection("mco")
 	if err != nil {
@@ -39,16 +44,20 @@ func main() {
 		log.Fatalln("Exiting...")
 	}
 
-	httpRouter := api.NewRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
-	loggedRouter := handlers.LoggingHandler(os.Stdout, httpRouter)
+	httpRouter := api.NewRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	loggedRouter := middleware.Handler("orchestrator", httpRouter)
 	log.Println("Starting Kubernetes Multicloud API")
 
+	go metrics.Serve(config.GetConfiguration().MetricsPort)
+
 	httpServer := &http.Server{
 		Handler: loggedRouter,
 		Addr:    ":" + config.GetConfiguration().ServicePort,
 	}
 
 	controller.NewControllerClient("controller", "controllermetadata").InitControllers()
+	module.ResumeAutoRollbacks()
+	subscription.ResumeDeliveries()
 
 	connectionsClose := make(chan struct{})
 	go func() {
@@ -56,6 +65,7 @@ func main() {
 		signal.Notify(c, os.Interrupt)
 		<-c
 		httpServer.Shutdown(context.Background())
+		tracing.Shutdown()
 		rpc.CloseAllRpcConn()
 		close(connectionsClose)
 	}()
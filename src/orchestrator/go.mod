@@ -1,29 +1,42 @@
 module github.com/open-ness/EMCO/src/orchestrator
 
 require (
+	github.com/DATA-DOG/go-sqlmock v1.5.0
+	github.com/dgrijalva/jwt-go v3.2.0+incompatible
 	github.com/ghodss/yaml v1.0.0
 	github.com/golang/protobuf v1.4.2
+	github.com/googleapis/gnostic v0.4.1
 	github.com/gorilla/handlers v1.3.0
 	github.com/gorilla/mux v1.7.3
+	github.com/lib/pq v1.9.0
 	github.com/onsi/ginkgo v1.12.0
 	github.com/onsi/gomega v1.9.0
 	github.com/open-ness/EMCO/src/clm v0.0.0-00010101000000-000000000000
 	github.com/open-ness/EMCO/src/monitor v0.0.0-00010101000000-000000000000
 	github.com/open-ness/EMCO/src/rsync v0.0.0-00010101000000-000000000000
 	github.com/pkg/errors v0.9.1
+	github.com/pmezard/go-difflib v1.0.0
+	github.com/prometheus/client_golang v1.7.1
 	github.com/sirupsen/logrus v1.7.0
 	github.com/stretchr/testify v1.7.0
 	github.com/xeipuuv/gojsonschema v1.2.0
+	go.etcd.io/bbolt v1.3.5
 	go.etcd.io/etcd v3.3.25+incompatible
 	go.mongodb.org/mongo-driver v1.5.1
+	go.opentelemetry.io/otel v1.0.1
+	go.opentelemetry.io/otel/exporters/stdout/stdouttrace v1.0.1
+	go.opentelemetry.io/otel/sdk v1.0.1
+	go.opentelemetry.io/otel/trace v1.0.1
 	golang.org/x/net v0.0.0-20200707034311-ab3426394381
 	google.golang.org/grpc v1.28.0
 	google.golang.org/protobuf v1.24.0
-	gopkg.in/yaml.v2 v2.3.0
+	gopkg.in/yaml.v2 v2.4.0
 	gopkg.in/yaml.v3 v3.0.0-20200615113413-eeeca48fe776
 	helm.sh/helm/v3 v3.5.3
 	k8s.io/apimachinery v0.20.2
-
+	k8s.io/cli-runtime v0.20.2
+	k8s.io/kubectl v0.20.2
+	sigs.k8s.io/kustomize/api v0.8.5
 )
 
 replace (
@@ -4,6 +4,7 @@
 package appcontext
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -95,6 +96,155 @@ type CompositeAppMeta struct {
 	Namespace             string   `json:"Namespace"`
 	Level                 string   `json:"Level"`
 	ChildContextIDs       []string `json:"ChildContextIDs"`
+	ParentContextID       string   `json:"ParentContextID,omitempty"`
+}
+
+// RolloutKey is the key of the rollout waves of an AppContext
+const RolloutKey = "rollout"
+
+// RolloutStatusKey is the key of the rollout progress of an AppContext
+const RolloutStatusKey = "rolloutstatus"
+
+// Rollout holds the waves of clusters an update is rolled out in.
+// The clusters of a wave are only updated once the clusters of the
+// previous wave are ready.
+type Rollout struct {
+	Waves [][]string `json:"waves"`
+	// Seconds to wait for the clusters of a wave to be ready
+	ReadyTimeout int `json:"readyTimeout,omitempty"`
+}
+
+// RolloutStatus reports the progress of a rollout
+//	Wave - index of the wave being rolled out
+//	Waves - number of waves
+type RolloutStatus struct {
+	Wave    int         `json:"wave"`
+	Waves   int         `json:"waves"`
+	Status  StatusValue `json:"status"`
+	Message string      `json:"message,omitempty"`
+}
+
+type rolloutStatuses struct {
+	InProgress StatusValue
+	Completed  StatusValue
+	Failed     StatusValue
+}
+
+var RolloutStatusEnum = &rolloutStatuses{
+	InProgress: "InProgress",
+	Completed:  "Completed",
+	Failed:     "Failed",
+}
+
+// ReconcileKey is the key of the reconcile mode of an AppContext
+const ReconcileKey = "reconcile"
+
+// ReconcileMode tells rsync what to do with resources that drifted
+// from the AppContext on the clusters
+//	Off - resources are not checked for drift
+//	Detect - drifted resources are reported in the resource status
+//	Enforce - drifted resources are reported and applied again
+type ReconcileMode = string
+
+type reconcileModes struct {
+	Off     ReconcileMode
+	Detect  ReconcileMode
+	Enforce ReconcileMode
+}
+
+var ReconcileModeEnum = &reconcileModes{
+	Off:     "off",
+	Detect:  "detect",
+	Enforce: "enforce",
+}
+
+// PruneKey is the key of the prune mode of an AppContext
+const PruneKey = "prune"
+
+// PruneStatusKey is the key of the report of the last prune of an AppContext
+const PruneStatusKey = "prunestatus"
+
+// PruneMode tells rsync what to do with the resources on the clusters that
+// carry the label of the AppContext but are not part of it
+//	Off - the resources are not looked for
+//	DryRun - the resources are reported in the prune status
+//	Enforce - the resources are reported and deleted
+type PruneMode = string
+
+type pruneModes struct {
+	Off     PruneMode
+	DryRun  PruneMode
+	Enforce PruneMode
+}
+
+var PruneModeEnum = &pruneModes{
+	Off:     "off",
+	DryRun:  "dry-run",
+	Enforce: "enforce",
+}
+
+// PruneStatus reports the orphaned resources found by the last prune
+type PruneStatus struct {
+	Mode      PruneMode        `json:"mode"`
+	Resources []PrunedResource `json:"resources,omitempty"`
+}
+
+// PrunedResource is an orphaned resource found on a cluster. Deleted is set
+// once it is deleted in enforce mode, Error if deleting it failed.
+type PrunedResource struct {
+	App        string `json:"app"`
+	Cluster    string `json:"cluster"`
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+	Deleted    bool   `json:"deleted,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ForceApplyKey is the key of the flag telling rsync to take over the fields
+// of the resources owned by other managers on the clusters
+const ForceApplyKey = "forceapply"
+
+// RetryPolicyKey is the key of the retry policy of an AppContext
+const RetryPolicyKey = "retrypolicy"
+
+// RetryPolicy tells rsync how long to retry the clusters of an AppContext.
+// The zero values keep the defaults of rsync.
+type RetryPolicy struct {
+	// Seconds to wait before checking an unreachable cluster again
+	InitialBackoff int `json:"initialBackoff,omitempty"`
+	// The wait is multiplied by BackoffFactor, 2 by default, after each
+	// check up to MaxBackoff seconds. It is not increased without MaxBackoff.
+	MaxBackoff    int     `json:"maxBackoff,omitempty"`
+	BackoffFactor float64 `json:"backoffFactor,omitempty"`
+	// Seconds a cluster can be unreachable before it fails, instead of the
+	// max retries of rsync
+	MaxUnreachableTime int `json:"maxUnreachableTime,omitempty"`
+	// Seconds to apply a resource before it fails
+	ApplyTimeout int `json:"applyTimeout,omitempty"`
+	// What to do when a cluster fails
+	OnClusterFailure ClusterFailureAction `json:"onClusterFailure,omitempty"`
+}
+
+// ClusterFailureKey is the key of the error of a cluster that failed while
+// rsync continued with the other clusters of the AppContext
+const ClusterFailureKey = "clusterfailure"
+
+// ClusterFailureAction tells rsync what to do when a cluster of an
+// AppContext fails
+//	Fail - the AppContext fails and the other clusters are stopped
+//	Continue - the other clusters are handled, the AppContext doesn't fail
+type ClusterFailureAction = string
+
+type clusterFailureActions struct {
+	Fail     ClusterFailureAction
+	Continue ClusterFailureAction
+}
+
+var ClusterFailureActionEnum = &clusterFailureActions{
+	Fail:     "fail",
+	Continue: "continue",
 }
 
 // Init app context
@@ -111,6 +261,18 @@ func (ac *AppContext) LoadAppContext(cid interface{}) (interface{}, error) {
 	return ac.rtc.RtcLoad(cid)
 }
 
+// WithContext returns a copy of the app context recording its calls to etcd
+// as children of the span of ctx, if tracing is enabled
+func (ac AppContext) WithContext(ctx context.Context) AppContext {
+	rtc, ok := ac.rtc.(*rtcontext.RunTimeContext)
+	if !ok {
+		return ac
+	}
+	c := &AppContext{initDone: ac.initDone, rtcObj: rtc.WithContext(ctx)}
+	c.rtc = &c.rtcObj
+	return *c
+}
+
 // CreateCompositeApp method returns composite app handle as interface.
 func (ac *AppContext) CreateCompositeApp() (interface{}, error) {
 	h, err := ac.rtc.RtcCreate()
@@ -650,6 +812,10 @@ func (ac *AppContext) GetCompositeAppMeta() (CompositeAppMeta, error) {
 			childCtxs = append(childCtxs, v.(string))
 		}
 	}
+	var parentCtx string
+	if datamap["ParentContextID"] != nil {
+		parentCtx = fmt.Sprintf("%v", datamap["ParentContextID"])
+	}
 
-	return CompositeAppMeta{Project: p, CompositeApp: ca, Version: v, Release: rn, DeploymentIntentGroup: dig, Namespace: namespace, Level: level, ChildContextIDs: childCtxs}, nil
+	return CompositeAppMeta{Project: p, CompositeApp: ca, Version: v, Release: rn, DeploymentIntentGroup: dig, Namespace: namespace, Level: level, ChildContextIDs: childCtxs, ParentContextID: parentCtx}, nil
 }
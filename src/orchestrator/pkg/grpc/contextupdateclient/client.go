@@ -16,11 +16,11 @@ import (
 // InvokeContextUpdate will make the grpc call to the specified controller
 // The controller will take the specified intentName and update the AppContext
 // appropriatly based on its operation as a placement or action controller.
-func InvokeContextUpdate(controllerName, intentName, appContextId string) error {
+func InvokeContextUpdate(ctx context.Context, controllerName, intentName, appContextId string) error {
 	var err error
 	var rpcClient contextpb.ContextupdateClient
 	var updateRes *contextpb.ContextUpdateResponse
-	ctx, cancel := context.WithTimeout(context.Background(), 600*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 600*time.Second)
 	defer cancel()
 
 	conn := rpc.GetRpcConn(controllerName)
@@ -64,11 +64,11 @@ func NewRsyncInfo(rName, h string, pN int) RsyncInfo {
 // or rsync controller.
 // rsync will deploy the resources in the app context to the clusters as
 // prepared in the app context.
-func InvokeInstallApp(appContextId string) error {
+func InvokeInstallApp(ctx context.Context, appContextId string) error {
 	var err error
 	var rpcClient installpb.InstallappClient
 	var installRes *installpb.InstallAppResponse
-	ctx, cancel := context.WithTimeout(context.Background(), 600*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 600*time.Second)
 	defer cancel()
 
 	// Unit test helper code
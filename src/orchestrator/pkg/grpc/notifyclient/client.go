@@ -59,7 +59,7 @@ func CallRsyncInstall(contextid interface{}) error {
 	}
 
 	appContextID := fmt.Sprintf("%v", contextid)
-	err = rsyncclient.InvokeInstallApp(appContextID)
+	err = rsyncclient.InvokeInstallApp(context.Background(), appContextID)
 	if err != nil {
 		return err
 	}
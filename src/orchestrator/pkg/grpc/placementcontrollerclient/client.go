@@ -15,13 +15,13 @@ import (
 )
 
 // InvokeFilterClusters ..  will make the grpc call to the specified controller
-func InvokeFilterClusters(plsCtrl controller.Controller, appContextId string) error {
+func InvokeFilterClusters(ctx context.Context, plsCtrl controller.Controller, appContextId string) error {
 	controllerName := plsCtrl.Metadata.Name
 	log.Info("FilterClusters .. start", log.Fields{"controllerName": controllerName, "Host": plsCtrl.Spec.Host, "Port": plsCtrl.Spec.Port, "appContextId": appContextId})
 	var err error
 	var rpcClient plsctrlclientpb.PlacementControllerClient
 	var ctrlRes *plsctrlclientpb.ResourceResponse
-	ctx, cancel := context.WithTimeout(context.Background(), 600*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 600*time.Second)
 	defer cancel()
 
 	// Fetch Grpc Connection handle
@@ -15,55 +15,71 @@ import (
 
 const rsyncName = "rsync"
 
+// updateCall makes a gRPC call of the Updateapp service of rsync and returns
+// whether it succeeded with its message
+type updateCall func(ctx context.Context, client updatepb.UpdateappClient) (bool, string, error)
 
-func InvokeUpdateApp(FromAppContextID, ToAppContextID string) error {
-	var err error
-	var rpcClient updatepb.UpdateappClient
-	var updateAppRes *updatepb.UpdateAppResponse
-	ctx, cancel := context.WithTimeout(context.Background(), 600*time.Second)
+// invokeUpdateapp makes the call on the connection to rsync and logs its result
+func invokeUpdateapp(ctx context.Context, name, FromAppContextID, ToAppContextID string, call updateCall) error {
+	ctx, cancel := context.WithTimeout(ctx, 600*time.Second)
 	defer cancel()
 
-
-
 	conn := rpc.GetRpcConn(rsyncName)
 	if conn == nil {
 		inc.InitRsyncClient()
 		conn = rpc.GetRpcConn(rsyncName)
 	}
-
-	if conn != nil {
-		rpcClient = updatepb.NewUpdateappClient(conn)
-		updateReq := new(updatepb.UpdateAppRequest)
-		updateReq.UpdateFromAppContext = FromAppContextID
-		updateReq.UpdateToAppContext = ToAppContextID
-		
-		updateAppRes, err = rpcClient.UpdateApp(ctx, updateReq)
-		if err == nil {
-			log.Info("Response from UpdateApp GRPC call", log.Fields{
-				"Succeeded": updateAppRes.AppContextUpdated,
-				"Message":   updateAppRes.AppContextUpdateMessage,
-			})
-		}
-	} else {
-		return pkgerrors.Errorf("UpdateApp Failed - Could not get InstallAppClient: %v", "rsync")
+	if conn == nil {
+		return pkgerrors.Errorf("%s Failed - Could not get UpdateAppClient: %v", name, "rsync")
 	}
 
-	if err == nil {
-		if updateAppRes.AppContextUpdated {
-			log.Info("UpdateApp Success", log.Fields{
-				"FromAppContext": FromAppContextID,
-				"ToAppContext": ToAppContextID,
-				"Message":    updateAppRes.AppContextUpdateMessage,
-			})
-			return nil
-		} else {
-			log.Info("UpdateApp Success", log.Fields{
-				"FromAppContext": FromAppContextID,
-				"ToAppContext": ToAppContextID,
-				"Message":    updateAppRes.AppContextUpdateMessage,
-			})
-			return pkgerrors.Errorf("UpdateApp Failed: %v", updateAppRes.AppContextUpdateMessage)
-		}
+	ok, msg, err := call(ctx, updatepb.NewUpdateappClient(conn))
+	if err != nil {
+		return err
+	}
+	log.Info("Response from "+name+" GRPC call", log.Fields{
+		"Succeeded": ok,
+		"Message":   msg,
+	})
+	fields := log.Fields{
+		"FromAppContext": FromAppContextID,
+		"ToAppContext":   ToAppContextID,
+		"Message":        msg,
 	}
-	return err
+	if !ok {
+		log.Info(name+" Failed", fields)
+		return pkgerrors.Errorf("%s Failed: %v", name, msg)
+	}
+	log.Info(name+" Success", fields)
+	return nil
+}
+
+// InvokeUpdateApp updates the composite app from one AppContext to another
+func InvokeUpdateApp(ctx context.Context, FromAppContextID, ToAppContextID string) error {
+	return invokeUpdateapp(ctx, "UpdateApp", FromAppContextID, ToAppContextID,
+		func(ctx context.Context, client updatepb.UpdateappClient) (bool, string, error) {
+			updateReq := new(updatepb.UpdateAppRequest)
+			updateReq.UpdateFromAppContext = FromAppContextID
+			updateReq.UpdateToAppContext = ToAppContextID
+			res, err := client.UpdateApp(ctx, updateReq)
+			if err != nil {
+				return false, "", err
+			}
+			return res.AppContextUpdated, res.AppContextUpdateMessage, nil
+		})
+}
+
+// InvokeRollbackApp rolls the composite app back from one AppContext to another
+func InvokeRollbackApp(ctx context.Context, FromAppContextID, ToAppContextID string) error {
+	return invokeUpdateapp(ctx, "RollbackApp", FromAppContextID, ToAppContextID,
+		func(ctx context.Context, client updatepb.UpdateappClient) (bool, string, error) {
+			rollbackReq := new(updatepb.RollbackAppRequest)
+			rollbackReq.RollbackFromAppContext = FromAppContextID
+			rollbackReq.RollbackToAppContext = ToAppContextID
+			res, err := client.RollbackApp(ctx, rollbackReq)
+			if err != nil {
+				return false, "", err
+			}
+			return res.AppContextRolledback, res.AppContextRollbackMessage, nil
+		})
 }
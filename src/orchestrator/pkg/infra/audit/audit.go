@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+// Package audit records the requests that change the resources of the REST
+// APIs of the EMCO services: who called what, when, and with which result.
+// The records of all the services are stored in the audit collection of the
+// database and are read with the GET /v2/audit API of the orchestrator.
+package audit
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// Record is the audit record of a request
+type Record struct {
+	ID            string    `json:"id"`
+	Time          time.Time `json:"time"`
+	Service       string    `json:"service"`
+	Principal     string    `json:"principal"`
+	Project       string    `json:"project,omitempty"`
+	Method        string    `json:"method"`
+	Path          string    `json:"path"`
+	Route         string    `json:"route"`
+	BodyHash      string    `json:"bodyHash"`
+	Code          int       `json:"code"`
+	CorrelationID string    `json:"correlationId"`
+}
+
+// RecordKey is the key structure that is used in the database
+type RecordKey struct {
+	Project string `json:"auditproject"`
+	Record  string `json:"auditrecord"`
+}
+
+// We will use json marshalling to convert to string to
+// preserve the underlying structure.
+func (k RecordKey) String() string {
+	out, err := json.Marshal(k)
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+// recordQuery are the fields of the records the filters are applied on by
+// the database. The times are in timeFormat, which orders them as strings.
+type recordQuery struct {
+	Service   string `json:"auditservice"`
+	Principal string `json:"auditprincipal"`
+	Time      string `json:"audittime"`
+}
+
+const timeFormat = "2006-01-02T15:04:05.000000000Z"
+
+// MaxRecords is the maximum number of records returned by GetRecords
+const MaxRecords = 1000
+
+// Filter selects the audit records returned by GetRecords. The zero values
+// select all the records, up to MaxRecords.
+type Filter struct {
+	Project   string
+	Service   string
+	Principal string
+	From      time.Time
+	To        time.Time
+	Limit     int
+}
+
+func (f Filter) query() db.Query {
+	q := db.Query{
+		Fields: map[string]string{},
+		Sort:   "audittime",
+		Limit:  f.Limit,
+	}
+	if f.Service != "" {
+		q.Fields["auditservice"] = f.Service
+	}
+	if f.Principal != "" {
+		q.Fields["auditprincipal"] = f.Principal
+	}
+	if !f.From.IsZero() {
+		q.From = f.From.UTC().Format(timeFormat)
+	}
+	if !f.To.IsZero() {
+		q.To = f.To.UTC().Format(timeFormat)
+	}
+	if q.Limit <= 0 || q.Limit > MaxRecords {
+		q.Limit = MaxRecords
+	}
+	return q
+}
+
+// AuditManager is an interface exposes the audit log functionality
+type AuditManager interface {
+	CreateRecord(r Record) error
+	GetRecords(f Filter) ([]Record, error)
+}
+
+// AuditClient implements the AuditManager
+type AuditClient struct {
+	storeName string
+	tagRecord string
+}
+
+// NewAuditClient returns an instance of the AuditClient
+func NewAuditClient() *AuditClient {
+	return &AuditClient{
+		storeName: "audit",
+		tagRecord: "record",
+	}
+}
+
+// CreateRecord appends a record to the audit log
+func (c *AuditClient) CreateRecord(r Record) error {
+	key := RecordKey{
+		Project: r.Project,
+		Record:  r.ID,
+	}
+	query := recordQuery{
+		Service:   r.Service,
+		Principal: r.Principal,
+		Time:      r.Time.UTC().Format(timeFormat),
+	}
+	err := db.DBconn.Insert(c.storeName, key, query, c.tagRecord, r)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Creating audit record")
+	}
+	return nil
+}
+
+// GetRecords returns the audit records selected by the filter, oldest first.
+// The records are filtered by the database.
+func (c *AuditClient) GetRecords(f Filter) ([]Record, error) {
+	key := RecordKey{
+		Project: f.Project,
+	}
+	values, err := db.DBconn.FindQuery(c.storeName, key, c.tagRecord, f.query())
+	if err != nil {
+		return []Record{}, pkgerrors.Wrap(err, "Get audit records")
+	}
+
+	records := []Record{}
+	for _, value := range values {
+		r := Record{}
+		err = db.DBconn.Unmarshal(value, &r)
+		if err != nil {
+			return []Record{}, pkgerrors.Wrap(err, "Unmarshalling audit record")
+		}
+		records = append(records, r)
+	}
+	return records, nil
+}
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/auth"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+)
+
+func TestHandler(t *testing.T) {
+	db.DBconn = &db.NewMockDB{}
+	client := NewAuditClient()
+
+	router := mux.NewRouter()
+	router.HandleFunc("/v2/projects/{project-name}/composite-apps", func(w http.ResponseWriter, r *http.Request) {
+		// Only part of the body is read
+		b := make([]byte, 4)
+		r.Body.Read(b)
+		w.WriteHeader(http.StatusCreated)
+	}).Methods("POST")
+	router.HandleFunc("/v2/projects/{project-name}", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	}).Methods("GET", "DELETE")
+	// The principal comes from the authentication, which comes after the
+	// audit log and rejects the requests without credentials
+	h := handler(client, "orchestrator", router, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		ctx := auth.NewContext(r.Context(), auth.Identity{Subject: "alice", Method: "jwt"})
+		router.ServeHTTP(w, r.WithContext(ctx))
+	}))
+	authorized := func(r *http.Request) *http.Request {
+		r.Header.Set("Authorization", "Bearer token")
+		return r
+	}
+
+	start := time.Now().UTC().Add(-time.Second)
+	body := `{"metadata":{"name":"app1"}}`
+	req := authorized(httptest.NewRequest(http.MethodPost, "/v2/projects/p1/composite-apps", strings.NewReader(body)))
+	req.Header.Set(CorrelationHeader, "req-1")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Header().Get(CorrelationHeader) != "req-1" {
+		t.Errorf("Correlation ID not returned")
+	}
+	h.ServeHTTP(httptest.NewRecorder(), authorized(httptest.NewRequest(http.MethodGet, "/v2/projects/p2", nil)))
+	h.ServeHTTP(httptest.NewRecorder(), authorized(httptest.NewRequest(http.MethodDelete, "/v2/projects/p2", nil)))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/v2/projects/p3", nil))
+
+	records, err := client.GetRecords(Filter{})
+	if err != nil {
+		t.Fatalf("GetRecords returned an error (%s)", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Expected the 3 changes to be recorded, got %+v", records)
+	}
+	if records[0].Project != "p1" || records[2].Project != "p3" {
+		t.Errorf("Records not sorted by time %+v", records)
+	}
+	records, _ = client.GetRecords(Filter{Limit: 2})
+	if len(records) != 2 || records[0].Project != "p1" {
+		t.Errorf("Expected the 2 oldest records, got %+v", records)
+	}
+	records, _ = client.GetRecords(Filter{Principal: "alice"})
+	if len(records) != 2 {
+		t.Errorf("Expected the 2 records of alice, got %+v", records)
+	}
+
+	records, err = client.GetRecords(Filter{Project: "p1", From: start, To: time.Now().UTC().Add(time.Second)})
+	if err != nil || len(records) != 1 {
+		t.Fatalf("Expected 1 record of the project, got %+v (%v)", records, err)
+	}
+	sum := sha256.Sum256([]byte(body))
+	r := records[0]
+	if r.Principal != "alice" || r.Service != "orchestrator" || r.Method != http.MethodPost ||
+		r.Path != "/v2/projects/p1/composite-apps" || r.Route != "/v2/projects/{project-name}/composite-apps" ||
+		r.Code != http.StatusCreated || r.CorrelationID != "req-1" || r.BodyHash != hex.EncodeToString(sum[:]) {
+		t.Errorf("Unexpected record %+v", r)
+	}
+
+	records, _ = client.GetRecords(Filter{Project: "p2"})
+	if len(records) != 1 || records[0].Code != http.StatusNotFound || records[0].CorrelationID == "" {
+		t.Errorf("Unexpected records %+v", records)
+	}
+	// The rejected request is recorded without principal
+	records, _ = client.GetRecords(Filter{Project: "p3"})
+	if len(records) != 1 || records[0].Code != http.StatusUnauthorized || records[0].Principal != "" {
+		t.Errorf("Unexpected records %+v", records)
+	}
+	records, _ = client.GetRecords(Filter{To: start})
+	if len(records) != 0 {
+		t.Errorf("Expected no record before the requests, got %+v", records)
+	}
+}
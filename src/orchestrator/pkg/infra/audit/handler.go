@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package audit
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/auth"
+	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/utils"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CorrelationHeader carries the correlation ID of a request. It is set by the
+// caller or generated, and returned in the response.
+const CorrelationHeader = "X-Request-ID"
+
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Handler records the requests to next that are not reads in the audit log of
+// the service, including those rejected by auth.Handler, which comes after it.
+// The principal is the identity authenticated by auth.Handler, or the client
+// certificate. The route and the project of the requests are those of the
+// route of router they match.
+func Handler(service string, router *mux.Router, next http.Handler) http.Handler {
+	return handler(NewAuditClient(), service, router, next)
+}
+
+func handler(client AuditManager, service string, router *mux.Router, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := Record{
+			ID:            newID(),
+			Time:          time.Now().UTC(),
+			Service:       service,
+			Method:        r.Method,
+			Path:          r.URL.Path,
+			Route:         r.URL.Path,
+			CorrelationID: r.Header.Get(CorrelationHeader),
+		}
+		if rec.CorrelationID == "" {
+			// The trace ID links the record to the trace of the request
+			if sc := trace.SpanContextFromContext(r.Context()); sc.TraceID().IsValid() {
+				rec.CorrelationID = sc.TraceID().String()
+			} else {
+				rec.CorrelationID = newID()
+			}
+		}
+		w.Header().Set(CorrelationHeader, rec.CorrelationID)
+		ctx, caller := auth.WithCaller(r.Context())
+		r = r.WithContext(ctx)
+		var match mux.RouteMatch
+		if router.Match(r, &match) && match.Route != nil {
+			if t, err := match.Route.GetPathTemplate(); err == nil {
+				rec.Route = t
+			}
+			rec.Project = match.Vars["project-name"]
+			if rec.Project == "" {
+				rec.Project = match.Vars["project"]
+			}
+		}
+
+		// The body is hashed as the handler reads it
+		var body *hashReader
+		if r.Body != nil {
+			body = &hashReader{ReadCloser: r.Body, hash: sha256.New()}
+			r.Body = body
+		}
+		sw := utils.NewStatusWriter(w)
+		next.ServeHTTP(sw, r)
+		if body != nil {
+			// Hash the rest of the body the handler didn't read
+			io.Copy(ioutil.Discard, body)
+			rec.BodyHash = hex.EncodeToString(body.hash.Sum(nil))
+		}
+		rec.Code = sw.Code
+		if id, ok := caller(); ok {
+			rec.Principal = id.Subject
+		} else if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+			// Without the built-in authentication, the client certificate
+			// still identifies the caller
+			rec.Principal = r.TLS.VerifiedChains[0][0].Subject.CommonName
+		}
+
+		if err := client.CreateRecord(rec); err != nil {
+			log.Error("Failed to record the request in the audit log", log.Fields{"error": err,
+				"method": rec.Method, "path": rec.Path, "principal": rec.Principal, "code": rec.Code})
+		}
+	})
+}
+
+// hashReader hashes the body of a request as it is read
+type hashReader struct {
+	io.ReadCloser
+	hash hash.Hash
+}
+
+func (h *hashReader) Read(p []byte) (int, error) {
+	n, err := h.ReadCloser.Read(p)
+	h.hash.Write(p[:n])
+	return n, err
+}
@@ -11,6 +11,7 @@ import (
 	"io/ioutil"
 	"log"
 
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/config"
 	pkgerrors "github.com/pkg/errors"
 )
 
@@ -28,9 +29,14 @@ func GetTLSConfig(caCertFile string, certFile string, keyFile string) (*tls.Conf
 	caCertPool := x509.NewCertPool()
 	caCertPool.AppendCertsFromPEM(caCert)
 
+	// Client certificates are mandatory if they are the only way to authenticate
+	clientAuth := tls.VerifyClientCertIfGiven
+	if config.GetConfiguration().AuthClientCert == "require" {
+		clientAuth = tls.RequireAndVerifyClientCert
+	}
+
 	tlsConfig := &tls.Config{
-		// Change to RequireAndVerify once we have mandatory certs
-		ClientAuth: tls.VerifyClientCertIfGiven,
+		ClientAuth: clientAuth,
 		ClientCAs:  caCertPool,
 		MinVersion: tls.VersionTLS12,
 	}
@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/config"
+	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+)
+
+// Authenticator identifies the callers of the REST APIs, with a bearer token
+// or a client certificate, and authorizes them with the role bindings
+type Authenticator struct {
+	Policy Policy
+	// Verifier of the bearer tokens, nil if they are not accepted
+	tokens *tokenVerifier
+	// Client certificates are accepted
+	certs bool
+}
+
+// NewAuthenticator returns the authenticator configured by the auth-* values of
+// the configuration, nil if no role bindings are configured. Authorization is
+// then left to the service mesh in front of the services.
+func NewAuthenticator() (*Authenticator, error) {
+	c := config.GetConfiguration()
+	if c.AuthRoleBindings == "" {
+		return nil, nil
+	}
+	policy, err := LoadPolicy(c.AuthRoleBindings)
+	if err != nil {
+		return nil, err
+	}
+	a := &Authenticator{Policy: policy, certs: c.AuthClientCert != ""}
+	if c.AuthOIDCIssuer != "" || c.AuthJWTKeyFile != "" {
+		a.tokens = &tokenVerifier{
+			issuer:       c.AuthOIDCIssuer,
+			audience:     c.AuthAudience,
+			subjectClaim: c.AuthSubjectClaim,
+			groupsClaim:  c.AuthGroupsClaim,
+			client:       &http.Client{Timeout: 10 * time.Second},
+		}
+		if c.AuthJWTKeyFile != "" {
+			a.tokens.key, err = readPublicKey(c.AuthJWTKeyFile)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return a, nil
+}
+
+// Authenticate returns the identity of the caller of the request
+func (a *Authenticator) Authenticate(r *http.Request) (Identity, bool) {
+	if h := r.Header.Get("Authorization"); a.tokens != nil && strings.HasPrefix(h, "Bearer ") {
+		id, err := a.tokens.Verify(strings.TrimPrefix(h, "Bearer "))
+		if err != nil {
+			log.Warn("Rejected bearer token", log.Fields{"error": err})
+			return Identity{}, false
+		}
+		return id, true
+	}
+	// Only the certificates verified against the CA identify the caller
+	if a.certs && r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		return certIdentity(r.TLS.VerifiedChains[0][0]), true
+	}
+	return Identity{}, false
+}
+
+// Handler authenticates and authorizes the requests to next, as configured by
+// NewAuthenticator. The project and the path template of the requests are
+// those of the route of router they match. Callers without an identity get
+// 401 Unauthorized, callers without the role 403 Forbidden.
+func Handler(router *mux.Router, next http.Handler) http.Handler {
+	a, err := NewAuthenticator()
+	if err != nil {
+		log.Error("Error configuring authentication, all requests are rejected", log.Fields{"error": err})
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		})
+	}
+	if a == nil {
+		return next
+	}
+	return a.Handler(router, next)
+}
+
+// Handler authenticates and authorizes the requests to next
+func (a *Authenticator) Handler(router *mux.Router, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := a.Authenticate(r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		setCaller(r.Context(), id)
+
+		route, project := r.URL.Path, ""
+		var match mux.RouteMatch
+		if router.Match(r, &match) && match.Route != nil {
+			if t, err := match.Route.GetPathTemplate(); err == nil {
+				route = t
+			}
+			project = match.Vars["project-name"]
+			if project == "" {
+				project = match.Vars["project"]
+			}
+		}
+		if !a.Policy.Authorize(id, project, r.Method, route) {
+			log.Warn("Request not authorized", log.Fields{"subject": id.Subject, "project": project,
+				"method": r.Method, "route": route})
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+		ctx := context.WithValue(NewContext(r.Context(), id), projectScopeKey{}, a.Policy.projects(id))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,216 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/gorilla/mux"
+)
+
+const digRoute = "/v2/projects/{project-name}/composite-apps/{composite-app-name}/{composite-app-version}/deployment-intent-groups/{deployment-intent-group-name}"
+
+var testPolicy = Policy{RoleBindings: []RoleBinding{
+	{Subject: "root", Role: RoleAdmin},
+	{Subject: "alice", Project: "p1", Role: RoleProjectAdmin},
+	{Group: "ci", Project: "p1", Role: RoleDeployer},
+	{Subject: "bob", Project: "*", Role: RoleViewer},
+}}
+
+func TestAuthorize(t *testing.T) {
+	testCases := []struct {
+		id      Identity
+		project string
+		method  string
+		route   string
+		allowed bool
+	}{
+		{Identity{Subject: "root"}, "", http.MethodPost, "/v2/projects", true},
+		{Identity{Subject: "alice"}, "", http.MethodPost, "/v2/projects", false},
+		{Identity{Subject: "alice"}, "", http.MethodGet, "/v2/projects", true},
+		{Identity{Subject: "alice"}, "p1", http.MethodDelete, digRoute, true},
+		{Identity{Subject: "alice"}, "p2", http.MethodGet, digRoute, false},
+		{Identity{Subject: "alice"}, "p1", http.MethodGet, projectRoute, true},
+		{Identity{Subject: "alice"}, "p1", http.MethodPut, projectRoute, false},
+		{Identity{Subject: "alice"}, "p1", http.MethodDelete, projectRoute, false},
+		{Identity{Subject: "root"}, "p1", http.MethodPut, projectRoute, true},
+		{Identity{Subject: "ci-bot", Groups: []string{"ci"}}, "p1", http.MethodPost, digRoute + "/instantiate", true},
+		{Identity{Subject: "ci-bot", Groups: []string{"ci"}}, "p1", http.MethodPost, digRoute + "/terminate", true},
+		{Identity{Subject: "ci-bot", Groups: []string{"ci"}}, "p1", http.MethodPost, digRoute + "/plan", true},
+		{Identity{Subject: "ci-bot", Groups: []string{"ci"}}, "p1", http.MethodPost, digRoute + "/subscriptions", false},
+		{Identity{Subject: "ci-bot", Groups: []string{"ci"}}, "p1", http.MethodDelete, digRoute, false},
+		{Identity{Subject: "bob"}, "p2", http.MethodGet, digRoute, true},
+		{Identity{Subject: "bob"}, "p2", http.MethodPost, digRoute + "/terminate", false},
+		{Identity{Subject: "eve"}, "", http.MethodGet, "/v2/projects", false},
+		{Identity{Subject: "alice"}, "", http.MethodGet, "/v2/audit", false},
+		{Identity{Subject: "root"}, "", http.MethodGet, "/v2/audit", true},
+	}
+	for _, tc := range testCases {
+		if got := testPolicy.Authorize(tc.id, tc.project, tc.method, tc.route); got != tc.allowed {
+			t.Errorf("Authorize(%v, %s, %s, %s) = %v, expected %v", tc.id, tc.project, tc.method, tc.route, got, tc.allowed)
+		}
+	}
+}
+
+func TestProjectAllowed(t *testing.T) {
+	testCases := []struct {
+		id       Identity
+		project  string
+		expected bool
+	}{
+		{Identity{Subject: "root"}, "p2", true},
+		{Identity{Subject: "alice"}, "p1", true},
+		{Identity{Subject: "alice"}, "p2", false},
+		{Identity{Subject: "ci-bot", Groups: []string{"ci"}}, "p1", true},
+		{Identity{Subject: "ci-bot", Groups: []string{"ci"}}, "p2", false},
+		{Identity{Subject: "bob"}, "p2", true},
+	}
+	for _, tc := range testCases {
+		ctx := context.WithValue(context.Background(), projectScopeKey{}, testPolicy.projects(tc.id))
+		if got := ProjectAllowed(ctx, tc.project); got != tc.expected {
+			t.Errorf("ProjectAllowed(%v, %s) = %v, expected %v", tc.id, tc.project, got, tc.expected)
+		}
+	}
+	// All the projects are listed without authorization
+	if !ProjectAllowed(context.Background(), "p2") {
+		t.Errorf("Expected all the projects without authorization")
+	}
+}
+
+// newIssuer serves the discovery document and the keys of an OIDC issuer
+func newIssuer(t *testing.T, key *rsa.PrivateKey) *httptest.Server {
+	var server *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"issuer": server.URL, "jwks_uri": server.URL + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kid": "k1",
+			"kty": "RSA",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	server = httptest.NewServer(mux)
+	return server
+}
+
+func sign(t *testing.T, key interface{}, method jwt.SigningMethod, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = "k1"
+	s, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("Error signing token: %s", err)
+	}
+	return s
+}
+
+func TestHandler(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Error generating key: %s", err)
+	}
+	issuer := newIssuer(t, key)
+	defer issuer.Close()
+
+	a := &Authenticator{
+		Policy: testPolicy,
+		tokens: &tokenVerifier{issuer: issuer.URL, audience: "emco", subjectClaim: "sub", groupsClaim: "groups",
+			client: issuer.Client()},
+		certs: true,
+	}
+	var caller Identity
+	router := mux.NewRouter()
+	router.HandleFunc(digRoute+"/instantiate", func(w http.ResponseWriter, r *http.Request) {
+		caller, _ = IdentityFromContext(r.Context())
+		w.WriteHeader(http.StatusAccepted)
+	}).Methods("POST")
+	h := a.Handler(router, router)
+
+	exp := time.Now().Add(time.Hour).Unix()
+	path := "/v2/projects/p1/composite-apps/app/v1/deployment-intent-groups/dig/instantiate"
+	testCases := []struct {
+		label string
+		token string
+		cert  *x509.Certificate
+		code  int
+	}{
+		{
+			label: "No credentials",
+			code:  http.StatusUnauthorized,
+		},
+		{
+			label: "Deployer token",
+			token: sign(t, key, jwt.SigningMethodRS256, jwt.MapClaims{"iss": issuer.URL, "aud": []string{"emco"}, "exp": exp,
+				"sub": "ci-bot", "groups": []string{"ci"}}),
+			code: http.StatusAccepted,
+		},
+		{
+			label: "Viewer token",
+			token: sign(t, key, jwt.SigningMethodRS256, jwt.MapClaims{"iss": issuer.URL, "aud": "emco", "exp": exp, "sub": "bob"}),
+			code:  http.StatusForbidden,
+		},
+		{
+			label: "Expired token",
+			token: sign(t, key, jwt.SigningMethodRS256, jwt.MapClaims{"iss": issuer.URL, "aud": "emco",
+				"exp": time.Now().Add(-time.Hour).Unix(), "sub": "root"}),
+			code: http.StatusUnauthorized,
+		},
+		{
+			label: "Token without expiration",
+			token: sign(t, key, jwt.SigningMethodRS256, jwt.MapClaims{"iss": issuer.URL, "aud": "emco", "sub": "root"}),
+			code:  http.StatusUnauthorized,
+		},
+		{
+			label: "Token of another audience",
+			token: sign(t, key, jwt.SigningMethodRS256, jwt.MapClaims{"iss": issuer.URL, "aud": "other", "exp": exp, "sub": "root"}),
+			code:  http.StatusUnauthorized,
+		},
+		{
+			label: "Token signed with a shared secret",
+			token: sign(t, []byte("secret"), jwt.SigningMethodHS256, jwt.MapClaims{"iss": issuer.URL, "aud": "emco", "exp": exp, "sub": "root"}),
+			code:  http.StatusUnauthorized,
+		},
+		{
+			label: "Client certificate",
+			cert:  &x509.Certificate{Subject: pkix.Name{CommonName: "alice"}},
+			code:  http.StatusAccepted,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.label, func(t *testing.T) {
+			caller = Identity{}
+			req := httptest.NewRequest(http.MethodPost, path, nil)
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+			if tc.cert != nil {
+				req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{tc.cert}}}
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if w.Code != tc.code {
+				t.Fatalf("Expected %d, got %d: %s", tc.code, w.Code, w.Body.String())
+			}
+			if tc.code == http.StatusAccepted && caller.Subject == "" {
+				t.Errorf("Identity not passed to the handler")
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+)
+
+// Identity is the authenticated caller of a request
+type Identity struct {
+	// Subject is the user or service name
+	Subject string `json:"subject"`
+	// Groups the subject belongs to
+	Groups []string `json:"groups,omitempty"`
+	// Method used to authenticate: jwt or cert
+	Method string `json:"method"`
+}
+
+type identityKey struct{}
+
+// NewContext returns ctx with the identity of the caller
+func NewContext(ctx context.Context, id Identity) context.Context {
+	setCaller(ctx, id)
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+type callerKey struct{}
+
+// caller is the identity recorded for WithCaller
+type caller struct {
+	id Identity
+	ok bool
+}
+
+// WithCaller returns ctx in which Handler records the identity of the caller
+// once authenticated, even if the request is then rejected, and the function
+// returning it. The handlers wrapping Handler know the caller this way.
+func WithCaller(ctx context.Context) (context.Context, func() (Identity, bool)) {
+	c := &caller{}
+	return context.WithValue(ctx, callerKey{}, c), func() (Identity, bool) {
+		return c.id, c.ok
+	}
+}
+
+func setCaller(ctx context.Context, id Identity) {
+	if c, ok := ctx.Value(callerKey{}).(*caller); ok {
+		c.id, c.ok = id, true
+	}
+}
+
+// IdentityFromContext returns the identity of the caller stored by Handler,
+// false if the request was not authenticated
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
+
+// projectScope holds the projects the caller of a request has a role in
+type projectScope struct {
+	all      bool
+	projects map[string]bool
+}
+
+type projectScopeKey struct{}
+
+// ProjectAllowed checks if the caller of the request has a role in the
+// project, to leave the other projects out of the lists. All the projects are
+// allowed if the requests are not authorized.
+func ProjectAllowed(ctx context.Context, project string) bool {
+	scope, ok := ctx.Value(projectScopeKey{}).(projectScope)
+	return !ok || scope.all || scope.projects[project]
+}
+
+// certIdentity returns the identity of a verified client certificate: the
+// common name is the subject and the organizations are the groups
+func certIdentity(cert *x509.Certificate) Identity {
+	return Identity{
+		Subject: cert.Subject.CommonName,
+		Groups:  cert.Subject.Organization,
+		Method:  "cert",
+	}
+}
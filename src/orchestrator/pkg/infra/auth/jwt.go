@@ -0,0 +1,239 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// The keys of the issuer are fetched again at most once a minute when a token
+// is signed with an unknown key
+const jwksRefreshInterval = time.Minute
+
+// tokenVerifier verifies the bearer tokens signed by an OIDC issuer, with the
+// keys published at its jwks_uri, or by a static public key
+type tokenVerifier struct {
+	issuer       string
+	audience     string
+	subjectClaim string
+	groupsClaim  string
+	client       *http.Client
+
+	sync.Mutex
+	// Static public key, used for all the tokens if set
+	key     crypto.PublicKey
+	keys    map[string]crypto.PublicKey
+	fetched time.Time
+}
+
+// jwk is a JSON web key of the RSA or EC type, see RFC 7517
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, pkgerrors.Errorf("Unsupported curve %s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, pkgerrors.Errorf("Unsupported key type %s", k.Kty)
+}
+
+// readPublicKey reads a PEM encoded RSA or ECDSA public key
+func readPublicKey(file string) (crypto.PublicKey, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Read JWT key file")
+	}
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+	key, err := jwt.ParseECPublicKeyFromPEM(data)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Parse JWT key file")
+	}
+	return key, nil
+}
+
+func (v *tokenVerifier) getJSON(url string, out interface{}) error {
+	resp, err := v.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return pkgerrors.Errorf("%s returned %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// fetchKeys reads the keys of the issuer from the jwks_uri of its discovery
+// document. It is called with the lock held.
+func (v *tokenVerifier) fetchKeys() error {
+	v.fetched = time.Now()
+	var discovery struct {
+		JwksURI string `json:"jwks_uri"`
+	}
+	err := v.getJSON(strings.TrimSuffix(v.issuer, "/")+"/.well-known/openid-configuration", &discovery)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Get OIDC discovery document")
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	err = v.getJSON(discovery.JwksURI, &set)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Get OIDC issuer keys")
+	}
+	keys := map[string]crypto.PublicKey{}
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			log.Warn("Skipping OIDC issuer key", log.Fields{"kid": k.Kid, "error": err})
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	v.keys = keys
+	return nil
+}
+
+// keyFunc returns the key the token was signed with. Only the asymmetric
+// signing methods are accepted.
+func (v *tokenVerifier) keyFunc(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA:
+	default:
+		return nil, pkgerrors.Errorf("Unexpected signing method %v", token.Header["alg"])
+	}
+
+	v.Lock()
+	defer v.Unlock()
+	if v.key != nil {
+		return v.key, nil
+	}
+	kid, _ := token.Header["kid"].(string)
+	key, ok := v.keys[kid]
+	if !ok && time.Since(v.fetched) > jwksRefreshInterval {
+		if err := v.fetchKeys(); err != nil {
+			return nil, err
+		}
+		key, ok = v.keys[kid]
+	}
+	if !ok {
+		return nil, pkgerrors.Errorf("Unknown signing key %s", kid)
+	}
+	return key, nil
+}
+
+func hasAudience(claims jwt.MapClaims, audience string) bool {
+	switch aud := claims["aud"].(type) {
+	case string:
+		return aud == audience
+	case []interface{}:
+		for _, a := range aud {
+			if s, ok := a.(string); ok && s == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Verify checks the signature and the claims of the token and returns the
+// identity of its subject
+func (v *tokenVerifier) Verify(raw string) (Identity, error) {
+	claims := jwt.MapClaims{}
+	// Valid checks the expiration and not before times
+	_, err := jwt.ParseWithClaims(raw, claims, v.keyFunc)
+	if err != nil {
+		return Identity{}, pkgerrors.Wrap(err, "Invalid token")
+	}
+	// Tokens that never expire are not accepted
+	if _, ok := claims["exp"]; !ok {
+		return Identity{}, pkgerrors.New("Token has no exp claim")
+	}
+	if v.issuer != "" && !claims.VerifyIssuer(v.issuer, true) {
+		return Identity{}, pkgerrors.New("Invalid token issuer")
+	}
+	if v.audience != "" && !hasAudience(claims, v.audience) {
+		return Identity{}, pkgerrors.New("Invalid token audience")
+	}
+
+	id := Identity{Method: "jwt"}
+	id.Subject, _ = claims[v.subjectClaim].(string)
+	if id.Subject == "" {
+		return Identity{}, pkgerrors.Errorf("Token has no %s claim", v.subjectClaim)
+	}
+	if groups, ok := claims[v.groupsClaim].([]interface{}); ok {
+		for _, g := range groups {
+			if s, ok := g.(string); ok {
+				id.Groups = append(id.Groups, s)
+			}
+		}
+	}
+	return id, nil
+}
@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package auth
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// Role grants permissions on the resources of a project
+type Role string
+
+// The roles of the role bindings
+const (
+	// RoleAdmin can do everything, including managing the projects and the
+	// resources outside of the projects like clusters and controllers
+	RoleAdmin Role = "admin"
+	// RoleProjectAdmin can do everything in the project, except changing or
+	// deleting the project itself, which holds the quotas of the project
+	RoleProjectAdmin Role = "project-admin"
+	// RoleDeployer can read the project and run the lifecycle operations of
+	// the deployment intent groups and logical clouds
+	RoleDeployer Role = "deployer"
+	// RoleViewer can read the project
+	RoleViewer Role = "viewer"
+)
+
+// lifecycleOperations are the last path elements of the routes of the
+// lifecycle operations a deployer can run
+var lifecycleOperations = map[string]bool{
+	"approve":     true,
+	"instantiate": true,
+	"terminate":   true,
+	"stop":        true,
+	"update":      true,
+	"rollback":    true,
+	"migrate":     true,
+	"plan":        true,
+}
+
+// projectRoute is the route of a project, only the admins change it
+const projectRoute = "/v2/projects/{project-name}"
+
+// adminRoutes are the routes outside of the projects only the admins can read
+var adminRoutes = map[string]bool{
+	"/v2/audit": true,
+}
+
+func isRead(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// allows checks if the role grants the method on the route, within a project
+func (r Role) allows(method, route string) bool {
+	switch r {
+	case RoleAdmin:
+		return true
+	case RoleProjectAdmin:
+		return route != projectRoute || isRead(method)
+	case RoleDeployer:
+		if method == http.MethodPost {
+			return lifecycleOperations[route[strings.LastIndex(route, "/")+1:]]
+		}
+		return isRead(method)
+	case RoleViewer:
+		return isRead(method)
+	}
+	return false
+}
+
+// RoleBinding grants a role to a subject or to the members of a group, in a
+// project or in all the projects if the project is empty or "*"
+type RoleBinding struct {
+	Subject string `json:"subject,omitempty"`
+	Group   string `json:"group,omitempty"`
+	Project string `json:"project,omitempty"`
+	Role    Role   `json:"role"`
+}
+
+// Policy is the content of the role bindings file
+type Policy struct {
+	RoleBindings []RoleBinding `json:"role-bindings"`
+}
+
+// LoadPolicy reads the role bindings file
+func LoadPolicy(file string) (Policy, error) {
+	var p Policy
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return p, pkgerrors.Wrap(err, "Read role bindings file")
+	}
+	err = json.Unmarshal(data, &p)
+	if err != nil {
+		return p, pkgerrors.Wrap(err, "Parse role bindings file")
+	}
+	for _, b := range p.RoleBindings {
+		switch b.Role {
+		case RoleAdmin, RoleProjectAdmin, RoleDeployer, RoleViewer:
+		default:
+			return p, pkgerrors.Errorf("Unknown role %s in role bindings file", b.Role)
+		}
+		if (b.Subject == "") == (b.Group == "") {
+			return p, pkgerrors.New("A role binding needs either a subject or a group")
+		}
+	}
+	return p, nil
+}
+
+func (b RoleBinding) binds(id Identity) bool {
+	if b.Subject != "" {
+		return b.Subject == id.Subject
+	}
+	for _, g := range id.Groups {
+		if g == b.Group {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorize checks if the identity may call the method on the route. The
+// project is empty for the routes outside of the projects, which can be read
+// by any identity with a role binding, except the audit log, and changed by the
+// admins only. The list of the projects only has the projects of the role
+// bindings of the identity, see ProjectAllowed.
+func (p Policy) Authorize(id Identity, project, method, route string) bool {
+	for _, b := range p.RoleBindings {
+		if !b.binds(id) {
+			continue
+		}
+		if b.Role == RoleAdmin {
+			return true
+		}
+		if project == "" {
+			if isRead(method) && !adminRoutes[route] {
+				return true
+			}
+			continue
+		}
+		if (b.Project == "" || b.Project == "*" || b.Project == project) && b.Role.allows(method, route) {
+			return true
+		}
+	}
+	return false
+}
+
+// projects returns the projects the role bindings of the identity grant a
+// role in, all of them if a role binding is for all the projects
+func (p Policy) projects(id Identity) projectScope {
+	scope := projectScope{projects: make(map[string]bool)}
+	for _, b := range p.RoleBindings {
+		if !b.binds(id) {
+			continue
+		}
+		if b.Role == RoleAdmin || b.Project == "" || b.Project == "*" {
+			scope.all = true
+		}
+		scope.projects[b.Project] = true
+	}
+	return scope
+}
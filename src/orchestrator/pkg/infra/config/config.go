@@ -19,20 +19,39 @@ type Configuration struct {
 	Password               string `json:"password"`
 	DatabaseIP             string `json:"database-ip"`
 	DatabaseType           string `json:"database-type"`
+	PostgresPort           string `json:"postgres-port"`
+	PostgresSSLMode        string `json:"postgres-sslmode"`
 	PluginDir              string `json:"plugin-dir"`
 	EtcdIP                 string `json:"etcd-ip"`
 	EtcdCert               string `json:"etcd-cert"`
 	EtcdKey                string `json:"etcd-key"`
 	EtcdCAFile             string `json:"etcd-ca-file"`
+	ContextDbType          string `json:"contextdb-type"`
+	ContextDbPath          string `json:"contextdb-path"`
 	GrpcServerCert         string `json:"grpc-server-cert"`
 	GrpcServerKey          string `json:"grpc-server-key"`
 	GrpcCAFile             string `json:"grpc-ca-file"`
 	GrpcEnableTLS          string `json:"grpc-enable-tls"`
 	GrpcServerNameOverride string `json:"grpc-server-name-override"`
 	ServicePort            string `json:"service-port"`
+	MetricsPort            string `json:"metrics-port"`
+	TracingExporter        string `json:"tracing-exporter"`
+	TracingEndpoint        string `json:"tracing-endpoint"`
+	AuthRoleBindings       string `json:"auth-role-bindings"`
+	AuthOIDCIssuer         string `json:"auth-oidc-issuer"`
+	AuthJWTKeyFile         string `json:"auth-jwt-key-file"`
+	AuthAudience           string `json:"auth-audience"`
+	AuthSubjectClaim       string `json:"auth-subject-claim"`
+	AuthGroupsClaim        string `json:"auth-groups-claim"`
+	AuthClientCert         string `json:"auth-client-cert"`
 	KubernetesLabelName    string `json:"kubernetes-label-name"`
 	LogLevel               string `json:"log-level"`
 	MaxRetries             string `json:"max-retries"`
+	ReconcileInterval      string `json:"reconcile-interval"`
+	AgentToken             string `json:"agent-token"`
+	KubernetesVersion      string `json:"kubernetes-version"`
+	KubernetesSchemaDir    string `json:"kubernetes-schema-dir"`
+	ChartCredentialKeyFile string `json:"chart-credential-key-file"`
 }
 
 // Config is the structure that stores the configuration
@@ -75,20 +94,39 @@ func defaultConfiguration() *Configuration {
 		Password:               "",
 		DatabaseIP:             "127.0.0.1",
 		DatabaseType:           "mongo",
+		PostgresPort:           "5432",
+		PostgresSSLMode:        "require", // disable, require, verify-ca or verify-full
 		PluginDir:              cwd,
 		EtcdIP:                 "127.0.0.1",
 		EtcdCert:               "",
 		EtcdKey:                "",
 		EtcdCAFile:             "",
+		ContextDbType:          "etcd",
+		ContextDbPath:          "contextdb.db",
 		GrpcServerCert:         "",
 		GrpcServerKey:          "",
 		GrpcCAFile:             "",
 		GrpcEnableTLS:          "disable",
 		GrpcServerNameOverride: "",
 		ServicePort:            "9015",
+		MetricsPort:            "9090",
+		TracingExporter:        "", // otlp or stdout, tracing is disabled by default
+		TracingEndpoint:        "http://localhost:4318/v1/traces",
+		AuthRoleBindings:       "", // role bindings file, the APIs are only authorized if it is set
+		AuthOIDCIssuer:         "",
+		AuthJWTKeyFile:         "",
+		AuthAudience:           "",
+		AuthSubjectClaim:       "sub",
+		AuthGroupsClaim:        "groups",
+		AuthClientCert:         "", // enable or require to identify the callers with their certificates
 		KubernetesLabelName:    "orchestrator.io/rb-instance-id",
 		LogLevel:               "warn", // default log-level of all modules
 		MaxRetries:             "",
+		ReconcileInterval:      "60", // seconds between drift checks in rsync, 0 disables them
+		AgentToken:             "",   // key of the tokens the agents of pull-mode clusters send to rsync, no agent connects if empty
+		KubernetesVersion:      "",   // the apps are validated against <kubernetes-schema-dir>/<kubernetes-version>.json
+		KubernetesSchemaDir:    "",
+		ChartCredentialKeyFile: "", // base64 AES-256 key the chart passwords are encrypted with in the database
 	}
 }
 
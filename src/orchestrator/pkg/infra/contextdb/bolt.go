@@ -0,0 +1,195 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package contextdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+	"time"
+
+	pkgerrors "github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Bucket holding all the keys of the context database
+var boltBucket = []byte("contextdb")
+
+// BoltConfig Configuration values needed for the embedded Bolt database
+type BoltConfig struct {
+	// Path of the database file
+	Path string
+	// How long to wait for the lock of the database file
+	Timeout time.Duration
+}
+
+// BoltClient for the embedded Bolt database
+// Bolt locks the database file while it is open, so the file is only opened
+// for the duration of a transaction. The services of a single node deployment
+// share the file, the transactions of the other services wait for the lock.
+type BoltClient struct {
+	path    string
+	timeout time.Duration
+	// The lock of the file is held by the open file, the transactions of
+	// the client are serialized here instead of polling the lock
+	mu sync.RWMutex
+}
+
+// NewBoltClient function initializes the embedded Bolt database
+func NewBoltClient(c BoltConfig) (ContextDb, error) {
+	if c.Path == "" {
+		return nil, pkgerrors.Errorf("Bolt database path is empty")
+	}
+	b := &BoltClient{
+		path:    c.Path,
+		timeout: c.Timeout,
+	}
+	// Create the database file and bucket if they don't exist
+	err := b.update(func(bkt *bolt.Bucket) error {
+		return nil
+	})
+	if err != nil {
+		return nil, pkgerrors.Errorf("Error creating bolt database: %s", err.Error())
+	}
+	return b, nil
+}
+
+// update runs fn in a read-write transaction
+func (b *BoltClient) update(fn func(*bolt.Bucket) error) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	db, err := bolt.Open(b.path, 0600, &bolt.Options{Timeout: b.timeout})
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return db.Update(func(tx *bolt.Tx) error {
+		bkt, err := tx.CreateBucketIfNotExists(boltBucket)
+		if err != nil {
+			return err
+		}
+		return fn(bkt)
+	})
+}
+
+// view runs fn in a read-only transaction, the other readers share the lock
+func (b *BoltClient) view(fn func(*bolt.Bucket) error) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	db, err := bolt.Open(b.path, 0600, &bolt.Options{Timeout: b.timeout, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return db.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(boltBucket)
+		if bkt == nil {
+			return pkgerrors.Errorf("Bucket %s not found", boltBucket)
+		}
+		return fn(bkt)
+	})
+}
+
+// Put values in Bolt DB
+func (b *BoltClient) Put(key string, value interface{}) error {
+	if key == "" {
+		return pkgerrors.Errorf("Key is null")
+	}
+	if value == nil {
+		return pkgerrors.Errorf("Value is nil")
+	}
+	v, err := json.Marshal(value)
+	if err != nil {
+		return pkgerrors.Errorf("Json Marshal error: %s", err.Error())
+	}
+	err = b.update(func(bkt *bolt.Bucket) error {
+		return bkt.Put([]byte(key), v)
+	})
+	if err != nil {
+		return pkgerrors.Errorf("Error creating bolt entry: %s", err.Error())
+	}
+	return nil
+}
+
+// Get values from Bolt DB and decodes from json
+func (b *BoltClient) Get(key string, value interface{}) error {
+	if key == "" {
+		return pkgerrors.Errorf("Key is null")
+	}
+	if value == nil {
+		return pkgerrors.Errorf("Value is nil")
+	}
+	var v []byte
+	err := b.view(func(bkt *bolt.Bucket) error {
+		// The value is only valid during the transaction
+		if val := bkt.Get([]byte(key)); val != nil {
+			v = append([]byte{}, val...)
+		}
+		return nil
+	})
+	if err != nil {
+		return pkgerrors.Errorf("Error getting bolt entry: %s", err.Error())
+	}
+	if v == nil {
+		return pkgerrors.Errorf("Key doesn't exist")
+	}
+	return json.Unmarshal(v, value)
+}
+
+// GetAllKeys returns all the keys with the prefix from Bolt DB
+func (b *BoltClient) GetAllKeys(key string) ([]string, error) {
+	var keys []string
+	err := b.view(func(bkt *bolt.Bucket) error {
+		prefix := []byte(key)
+		c := bkt.Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			keys = append(keys, string(k))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, pkgerrors.Errorf("Error getting bolt entry: %s", err.Error())
+	}
+	if len(keys) == 0 {
+		return nil, pkgerrors.Errorf("Key doesn't exist")
+	}
+	return keys, nil
+}
+
+// DeleteAll keys with the prefix from Bolt DB
+func (b *BoltClient) DeleteAll(key string) error {
+	err := b.update(func(bkt *bolt.Bucket) error {
+		prefix := []byte(key)
+		c := bkt.Cursor()
+		// Deleting with the cursor moves it to the next key
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Seek(prefix) {
+			if err := c.Delete(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return pkgerrors.Errorf("Delete failed bolt entry: %s", err.Error())
+	}
+	return nil
+}
+
+// Delete values from Bolt DB
+func (b *BoltClient) Delete(key string) error {
+	err := b.update(func(bkt *bolt.Bucket) error {
+		return bkt.Delete([]byte(key))
+	})
+	if err != nil {
+		return pkgerrors.Errorf("Delete failed bolt entry: %s", err.Error())
+	}
+	return nil
+}
+
+// HealthCheck for checking health of the Bolt database
+func (b *BoltClient) HealthCheck() error {
+	return b.view(func(bkt *bolt.Bucket) error {
+		return nil
+	})
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package contextdb
+
+import (
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/config"
+)
+
+func TestBoltPrefix(t *testing.T) {
+	cli, err := NewBoltClient(BoltConfig{Path: filepath.Join(t.TempDir(), "contextdb.db")})
+	if err != nil {
+		t.Fatalf("Error creating bolt client %s", err)
+	}
+	for _, key := range []string{"/context/1/", "/context/1/app/a1/", "/context/1/app/a2/", "/context/12/", "/context/2/"} {
+		if err := cli.Put(key, key); err != nil {
+			t.Fatalf("Put failed %s", err)
+		}
+	}
+
+	keys, err := cli.GetAllKeys("/context/1")
+	if err != nil {
+		t.Fatalf("GetAllKeys failed %s", err)
+	}
+	sort.Strings(keys)
+	expected := []string{"/context/1/", "/context/1/app/a1/", "/context/1/app/a2/", "/context/12/"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("GetAllKeys returned %v, expected %v", keys, expected)
+	}
+
+	if err := cli.Delete("/context/1/app/a2/"); err != nil {
+		t.Fatalf("Delete failed %s", err)
+	}
+	if err := cli.DeleteAll("/context/1/"); err != nil {
+		t.Fatalf("DeleteAll failed %s", err)
+	}
+	keys, err = cli.GetAllKeys("")
+	if err != nil {
+		t.Fatalf("GetAllKeys failed %s", err)
+	}
+	expected = []string{"/context/12/", "/context/2/"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("GetAllKeys returned %v, expected %v", keys, expected)
+	}
+}
+
+// TestBoltShared test that the services share the Bolt database file
+func TestBoltShared(t *testing.T) {
+	c := config.GetConfiguration()
+	dbType, path := c.ContextDbType, c.ContextDbPath
+	defer func() { c.ContextDbType, c.ContextDbPath = dbType, path }()
+	c.ContextDbType = "bolt"
+	c.ContextDbPath = filepath.Join(t.TempDir(), "contextdb.db")
+
+	if err := InitializeContextDatabase(); err != nil {
+		t.Fatalf("Error initializing the context database %s", err)
+	}
+	// Another service opening the same file
+	other, err := NewBoltClient(BoltConfig{Path: c.ContextDbPath})
+	if err != nil {
+		t.Fatalf("Error creating bolt client %s", err)
+	}
+
+	done := make(chan error)
+	for i := 0; i < 10; i++ {
+		go func(i int) {
+			done <- Db.Put("/context/1/app/a"+strconv.Itoa(i)+"/", i)
+		}(i)
+		go func(i int) {
+			done <- other.Put("/context/2/app/a"+strconv.Itoa(i)+"/", i)
+		}(i)
+	}
+	for i := 0; i < 20; i++ {
+		if err := <-done; err != nil {
+			t.Fatalf("Put failed %s", err)
+		}
+	}
+	keys, err := other.GetAllKeys("/context/1/")
+	if err != nil || len(keys) != 10 {
+		t.Fatalf("Expected the keys of the other service, got %v (%v)", keys, err)
+	}
+	if err := other.DeleteAll("/context/1/"); err != nil {
+		t.Fatalf("DeleteAll failed %s", err)
+	}
+	_, err = Db.GetAllKeys("/context/1/")
+	if err == nil || !strings.Contains(err.Error(), "Key doesn't exist") {
+		t.Errorf("Expected the keys to be deleted, got %v", err)
+	}
+}
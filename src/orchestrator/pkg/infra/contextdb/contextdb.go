@@ -4,6 +4,8 @@
 package contextdb
 
 import (
+	"time"
+
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/config"
 	pkgerrors "github.com/pkg/errors"
 )
@@ -42,6 +44,15 @@ func createContextDBClient(dbType string) error {
 		if err != nil {
 			pkgerrors.Wrap(err, "Etcd Client Initialization failed with error")
 		}
+	case "bolt":
+		c := BoltConfig{
+			Path:    config.GetConfiguration().ContextDbPath,
+			Timeout: 5 * time.Second,
+		}
+		Db, err = NewBoltClient(c)
+		if err != nil {
+			return pkgerrors.Wrap(err, "Bolt Client Initialization failed with error")
+		}
 	default:
 		return pkgerrors.New(dbType + "DB not supported")
 	}
@@ -51,11 +62,11 @@ func createContextDBClient(dbType string) error {
 // InitializeContextDatabase sets up the connection to the
 // configured database to allow the application to talk to it.
 func InitializeContextDatabase() error {
-	// Only support Etcd for now
-	err := createContextDBClient("etcd")
+	err := createContextDBClient(config.GetConfiguration().ContextDbType)
 	if err != nil {
 		return pkgerrors.Cause(err)
 	}
+	Db = tracedContextDb{ContextDb: Db, system: config.GetConfiguration().ContextDbType}
 	err = Db.HealthCheck()
 	if err != nil {
 		return pkgerrors.Cause(err)
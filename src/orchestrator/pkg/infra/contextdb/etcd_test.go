@@ -8,6 +8,7 @@ import (
 	pkgerrors "github.com/pkg/errors"
 	"go.etcd.io/etcd/clientv3"
 	mvccpb "go.etcd.io/etcd/mvcc/mvccpb"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -48,6 +49,24 @@ func (e *MockEtcdClient) Delete(ctx context.Context, key string, opts ...clientv
 	return &clientv3.DeleteResponse{}, e.Err
 }
 
+// testClients returns the clients the test cases run on, etcd with the mock
+// client and the Bolt database unless the mock client returns an error
+func testClients(t *testing.T, mockEtcd *MockEtcdClient) map[string]ContextDb {
+	clients := make(map[string]ContextDb)
+	clients["etcd"], _ = NewEtcdClient(&clientv3.Client{}, EtcdConfig{})
+	getEtcd = func(e *EtcdClient) Etcd {
+		return mockEtcd
+	}
+	if mockEtcd.Err == nil {
+		cli, err := NewBoltClient(BoltConfig{Path: filepath.Join(t.TempDir(), "contextdb.db")})
+		if err != nil {
+			t.Fatalf("Error creating bolt client %s", err)
+		}
+		clients["bolt"] = cli
+	}
+	return clients
+}
+
 type testStruct struct {
 	Name string `json:"name"`
 	Num  int    `json:"num"`
@@ -90,22 +109,19 @@ func TestPut(t *testing.T) {
 		},
 	}
 	for _, testCase := range testCases {
-		t.Run(testCase.label, func(t *testing.T) {
-			cli, _ := NewEtcdClient(&clientv3.Client{}, EtcdConfig{})
-			getEtcd = func(e *EtcdClient) Etcd {
-				return testCase.mockEtcd
-			}
-			err := cli.Put(testCase.key, testCase.value)
-			if err != nil {
-				if testCase.expectedError == "" {
-					t.Fatalf("Method returned an un-expected (%s)", err)
+		for name, cli := range testClients(t, testCase.mockEtcd) {
+			t.Run(testCase.label+" on "+name, func(t *testing.T) {
+				err := cli.Put(testCase.key, testCase.value)
+				if err != nil {
+					if testCase.expectedError == "" {
+						t.Fatalf("Method returned an un-expected (%s)", err)
+					}
+					if !strings.Contains(string(err.Error()), testCase.expectedError) {
+						t.Fatalf("Method returned an error (%s)", err)
+					}
 				}
-				if !strings.Contains(string(err.Error()), testCase.expectedError) {
-					t.Fatalf("Method returned an error (%s)", err)
-				}
-			}
-
-		})
+			})
+		}
 	}
 }
 
@@ -140,22 +156,19 @@ func TestGet(t *testing.T) {
 		},
 	}
 	for _, testCase := range testCases {
-		t.Run(testCase.label, func(t *testing.T) {
-			cli, _ := NewEtcdClient(&clientv3.Client{}, EtcdConfig{})
-			getEtcd = func(e *EtcdClient) Etcd {
-				return testCase.mockEtcd
-			}
-			err := cli.Get(testCase.key, testCase.value)
-			if err != nil {
-				if testCase.expectedError == "" {
-					t.Fatalf("Method returned an un-expected (%s)", err)
-				}
-				if !strings.Contains(string(err.Error()), testCase.expectedError) {
-					t.Fatalf("Method returned an error (%s)", err)
+		for name, cli := range testClients(t, testCase.mockEtcd) {
+			t.Run(testCase.label+" on "+name, func(t *testing.T) {
+				err := cli.Get(testCase.key, testCase.value)
+				if err != nil {
+					if testCase.expectedError == "" {
+						t.Fatalf("Method returned an un-expected (%s)", err)
+					}
+					if !strings.Contains(string(err.Error()), testCase.expectedError) {
+						t.Fatalf("Method returned an error (%s)", err)
+					}
 				}
-			}
-
-		})
+			})
+		}
 	}
 }
 
@@ -172,24 +185,22 @@ func TestGetString(t *testing.T) {
 		},
 	}
 	for _, testCase := range testCases {
-		t.Run(testCase.label, func(t *testing.T) {
-			cli, _ := NewEtcdClient(&clientv3.Client{}, EtcdConfig{})
-			getEtcd = func(e *EtcdClient) Etcd {
-				return testCase.mockEtcd
-			}
-			err := cli.Put("test", "test1")
-			if err != nil {
-				t.Error("Test failed", err)
-			}
-			var s string
-			err = cli.Get("test", &s)
-			if err != nil {
-				t.Error("Test failed", err)
-			}
-			if "test1" != s {
-				t.Error("Get Failed")
-			}
-		})
+		for name, cli := range testClients(t, testCase.mockEtcd) {
+			t.Run(testCase.label+" on "+name, func(t *testing.T) {
+				err := cli.Put("test", "test1")
+				if err != nil {
+					t.Error("Test failed", err)
+				}
+				var s string
+				err = cli.Get("test", &s)
+				if err != nil {
+					t.Error("Test failed", err)
+				}
+				if "test1" != s {
+					t.Error("Get Failed")
+				}
+			})
+		}
 	}
 }
 
@@ -210,22 +221,19 @@ func TestDelete(t *testing.T) {
 		},
 	}
 	for _, testCase := range testCases {
-		t.Run(testCase.label, func(t *testing.T) {
-			cli, _ := NewEtcdClient(&clientv3.Client{}, EtcdConfig{})
-			getEtcd = func(e *EtcdClient) Etcd {
-				return testCase.mockEtcd
-			}
-			err := cli.Delete("test")
-			if err != nil {
-				if testCase.expectedError == "" {
-					t.Fatalf("Method returned an un-expected (%s)", err)
+		for name, cli := range testClients(t, testCase.mockEtcd) {
+			t.Run(testCase.label+" on "+name, func(t *testing.T) {
+				err := cli.Delete("test")
+				if err != nil {
+					if testCase.expectedError == "" {
+						t.Fatalf("Method returned an un-expected (%s)", err)
+					}
+					if !strings.Contains(string(err.Error()), testCase.expectedError) {
+						t.Fatalf("Method returned an error (%s)", err)
+					}
 				}
-				if !strings.Contains(string(err.Error()), testCase.expectedError) {
-					t.Fatalf("Method returned an error (%s)", err)
-				}
-			}
-
-		})
+			})
+		}
 	}
 }
 
@@ -247,20 +255,18 @@ func TestGetAll(t *testing.T) {
 		},
 	}
 	for _, testCase := range testCases {
-		t.Run(testCase.label, func(t *testing.T) {
-			cli, _ := NewEtcdClient(&clientv3.Client{}, EtcdConfig{})
-			getEtcd = func(e *EtcdClient) Etcd {
-				return testCase.mockEtcd
-			}
-			_, err := cli.GetAllKeys("test")
-			if err != nil {
-				if testCase.expectedError == "" {
-					t.Fatalf("Method returned an un-expected (%s)", err)
-				}
-				if !strings.Contains(string(err.Error()), testCase.expectedError) {
-					t.Fatalf("Method returned an error (%s)", err)
+		for name, cli := range testClients(t, testCase.mockEtcd) {
+			t.Run(testCase.label+" on "+name, func(t *testing.T) {
+				_, err := cli.GetAllKeys("test")
+				if err != nil {
+					if testCase.expectedError == "" {
+						t.Fatalf("Method returned an un-expected (%s)", err)
+					}
+					if !strings.Contains(string(err.Error()), testCase.expectedError) {
+						t.Fatalf("Method returned an error (%s)", err)
+					}
 				}
-			}
-		})
+			})
+		}
 	}
 }
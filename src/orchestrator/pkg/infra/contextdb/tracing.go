@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package contextdb
+
+import (
+	"context"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+)
+
+// tracedContextDb records a span for each call to the ContextDb. The ContextDb
+// interface doesn't take a context, so the spans are only recorded for the
+// ContextDb returned by WithContext, as children of the span of its context.
+type tracedContextDb struct {
+	ContextDb
+	system string
+	ctx    context.Context
+}
+
+// WithContext returns the ContextDb recording its calls as children of the
+// span of ctx, if tracing is enabled
+func WithContext(ctx context.Context) ContextDb {
+	if c, ok := Db.(tracedContextDb); ok {
+		c.ctx = ctx
+		return c
+	}
+	return Db
+}
+
+func (c tracedContextDb) start(op, key string) func(error) {
+	if !tracing.Active(c.ctx) {
+		return func(error) {}
+	}
+	_, span := tracing.Start(c.ctx, c.system+"."+op,
+		semconv.DBSystemKey.String(c.system), semconv.DBOperationKey.String(op), semconv.DBStatementKey.String(key))
+	return func(err error) { tracing.End(span, err) }
+}
+
+func (c tracedContextDb) Put(key string, value interface{}) (err error) {
+	end := c.start("Put", key)
+	defer func() { end(err) }()
+	return c.ContextDb.Put(key, value)
+}
+
+func (c tracedContextDb) Delete(key string) (err error) {
+	end := c.start("Delete", key)
+	defer func() { end(err) }()
+	return c.ContextDb.Delete(key)
+}
+
+func (c tracedContextDb) DeleteAll(key string) (err error) {
+	end := c.start("DeleteAll", key)
+	defer func() { end(err) }()
+	return c.ContextDb.DeleteAll(key)
+}
+
+func (c tracedContextDb) Get(key string, value interface{}) (err error) {
+	end := c.start("Get", key)
+	defer func() { end(err) }()
+	return c.ContextDb.Get(key, value)
+}
+
+func (c tracedContextDb) GetAllKeys(path string) (keys []string, err error) {
+	end := c.start("GetAllKeys", path)
+	defer func() { end(err) }()
+	return c.ContextDb.GetAllKeys(path)
+}
@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package db
+
+import (
+	"errors"
+	"time"
+)
+
+// Lease is held on a key by one request or instance of a microservice at a
+// time, until it is released or it ends. The end of the lease, in nanoseconds,
+// is the version of the document of the key, set with UpdateVersion, so a
+// microservice stopped while holding the lease doesn't hold it forever.
+type Lease struct {
+	coll string
+	key  Key
+	end  int64
+}
+
+// NewLease returns the lease on the key of the collection, not held yet
+func NewLease(coll string, key Key) *Lease {
+	return &Lease{coll: coll, key: key}
+}
+
+// Take takes the lease until end if it is not held or it ended. Returns
+// ErrVersionConflict if it is held by someone else.
+func (l *Lease) Take(end time.Time) error {
+	current, err := DBconn.FindVersion(l.coll, l.key)
+	if err != nil {
+		return err
+	}
+	if current > time.Now().UnixNano() {
+		return ErrVersionConflict
+	}
+	l.end = current
+	return l.Renew(end)
+}
+
+// Hold waits until the lease is not held, checking every wait, and takes it
+// for d
+func (l *Lease) Hold(d time.Duration, wait time.Duration) error {
+	for {
+		err := l.Take(time.Now().Add(d))
+		if !errors.Is(err, ErrVersionConflict) {
+			return err
+		}
+		time.Sleep(wait)
+	}
+}
+
+// Renew extends the lease held until end, or takes it if it never was.
+// Returns ErrVersionConflict if it was taken by someone else once it ended.
+func (l *Lease) Renew(end time.Time) error {
+	err := DBconn.UpdateVersion(l.coll, l.key, l.end, end.UnixNano())
+	if err != nil {
+		return err
+	}
+	l.end = end.UnixNano()
+	return nil
+}
+
+// Release ends the lease held now, unless it was taken by someone else once
+// it ended. The document is kept, as the version 0 is only for the documents
+// not created yet.
+func (l *Lease) Release() {
+	l.Renew(time.Now())
+}
@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLease(t *testing.T) {
+	DBconn = &NewMockDB{}
+	key := map[string]string{"lease": "l1"}
+
+	l := NewLease("leases", key)
+	if err := l.Hold(time.Minute, time.Millisecond); err != nil {
+		t.Fatalf("Hold returned an error %s", err)
+	}
+	if err := NewLease("leases", key).Take(time.Now().Add(time.Minute)); err != ErrVersionConflict {
+		t.Fatalf("Take of a held lease returned %v", err)
+	}
+	l.Release()
+	start := time.Now()
+	if err := l.Hold(time.Minute, time.Millisecond); err != nil || time.Since(start) > time.Second {
+		t.Fatalf("Hold of a released lease returned %v after %s", err, time.Since(start))
+	}
+	l.Release()
+
+	// A lease not released is held until it ends
+	stopped := NewLease("leases", key)
+	stopped.Take(time.Now().Add(20 * time.Millisecond))
+	start = time.Now()
+	l = NewLease("leases", key)
+	if err := l.Hold(time.Minute, time.Millisecond); err != nil {
+		t.Fatalf("Hold returned an error %s", err)
+	}
+	if time.Since(start) < 20*time.Millisecond {
+		t.Errorf("Lease held before it ended")
+	}
+	// and can't be renewed or released once taken over
+	if err := stopped.Renew(time.Now().Add(time.Minute)); err != ErrVersionConflict {
+		t.Errorf("Renew of a lease taken over returned %v", err)
+	}
+	stopped.Release()
+	if err := NewLease("leases", key).Take(time.Now().Add(time.Minute)); err != ErrVersionConflict {
+		t.Errorf("Lease released by the lease taken over")
+	}
+	if err := l.Renew(time.Now().Add(time.Minute)); err != nil {
+		t.Errorf("Renew returned an error %s", err)
+	}
+}
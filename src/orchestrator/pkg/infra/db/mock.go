@@ -14,6 +14,8 @@ type MockDB struct {
 	Items      []map[string]map[string][]byte
 	Err        error
 	MarshalErr error
+	// Versions of the documents by key
+	Versions map[string]int64
 }
 
 func (m *MockDB) HealthCheck() error {
@@ -109,9 +111,15 @@ func (m *MockDB) Find(table string, key Key, tag string) ([][]byte, error) {
 	}
 }
 
+// FindQuery ignores the query, as the query fields are not stored by Insert
+func (m *MockDB) FindQuery(table string, key Key, tag string, q Query) ([][]byte, error) {
+	return m.Find(table, key, tag)
+}
+
 func (m *MockDB) Remove(table string, key Key) error {
 	jkey, _ := json.Marshal(key)
 	str := (string(jkey))
+	delete(m.Versions, str)
 	for i, item := range m.Items {
 		for k, _ := range item {
 			if k == str {
@@ -131,3 +139,57 @@ func (m *MockDB) RemoveAll(table string, key Key) error {
 func (m *MockDB) RemoveTag(table string, key Key, tag string) error {
 	return m.Err
 }
+
+func (m *MockDB) UpdateVersion(table string, key Key, current int64, version int64) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	jkey, _ := json.Marshal(key)
+	if m.Versions[string(jkey)] != current {
+		return ErrVersionConflict
+	}
+	if m.Versions == nil {
+		m.Versions = make(map[string]int64)
+	}
+	m.Versions[string(jkey)] = version
+	return nil
+}
+
+func (m *MockDB) FindVersion(table string, key Key) (int64, error) {
+	jkey, _ := json.Marshal(key)
+	return m.Versions[string(jkey)], m.Err
+}
+
+func (m *MockDB) InsertVersion(table string, key Key, query interface{}, tag string, data interface{}, pre *Precondition) (int64, error) {
+	jkey, _ := json.Marshal(key)
+	if !pre.matches(m.Versions[string(jkey)]) {
+		return 0, ErrVersionConflict
+	}
+	if err := m.Insert(table, key, query, tag, data); err != nil {
+		return 0, err
+	}
+	if m.Versions == nil {
+		m.Versions = make(map[string]int64)
+	}
+	version := newVersion()
+	m.Versions[string(jkey)] = version
+	return version, nil
+}
+
+func (m *MockDB) FindVersions(table string, key Key, tag string) ([][]byte, []int64, error) {
+	values, err := m.Find(table, key, tag)
+	versions := make([]int64, len(values))
+	if len(values) == 1 {
+		jkey, _ := json.Marshal(key)
+		versions[0] = m.Versions[string(jkey)]
+	}
+	return values, versions, err
+}
+
+func (m *MockDB) RemoveVersion(table string, key Key, pre *Precondition) error {
+	jkey, _ := json.Marshal(key)
+	if !pre.matches(m.Versions[string(jkey)]) {
+		return ErrVersionConflict
+	}
+	return m.Remove(table, key)
+}
@@ -236,8 +236,15 @@ func (m *MongoStore) createKeyField(key interface{}) (string, error) {
 
 // Insert is used to insert/add element to a document
 func (m *MongoStore) Insert(coll string, key Key, query interface{}, tag string, data interface{}) error {
+	_, err := m.InsertVersion(coll, key, query, tag, data, nil)
+	return err
+}
+
+// InsertVersion inserts like Insert if the version of the document matches
+// and returns its new version
+func (m *MongoStore) InsertVersion(coll string, key Key, query interface{}, tag string, data interface{}, pre *Precondition) (int64, error) {
 	if data == nil || !m.validateParams(coll, key, tag) {
-		return pkgerrors.New("No Data to store")
+		return 0, pkgerrors.New("No Data to store")
 	}
 
 	c := getCollection(coll, m)
@@ -245,13 +252,24 @@ func (m *MongoStore) Insert(coll string, key Key, query interface{}, tag string,
 
 	filter, err := m.findFilter(key)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	// Create and add key tag
 	s, err := m.createKeyField(key)
 	if err != nil {
-		return err
+		return 0, err
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	if pre != nil {
+		// Only the existing document of the key with a matching version
+		fields := filter["$and"].([]bson.M)[0]
+		fields["key"] = s
+		if len(pre.Versions) > 0 {
+			fields["resourceVersion"] = bson.M{"$in": pre.Versions}
+		}
+		opts.SetUpsert(false)
 	}
+	version := newVersion()
 	_, err = decodeBytes(
 		c.FindOneAndUpdate(
 			ctx,
@@ -260,21 +278,25 @@ func (m *MongoStore) Insert(coll string, key Key, query interface{}, tag string,
 				{"$set", bson.D{
 					{tag, data},
 					{"key", s},
+					{"resourceVersion", version},
 				}},
 			},
-			options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)))
+			opts))
 
+	if err == mongo.ErrNoDocuments && pre != nil {
+		return 0, ErrVersionConflict
+	}
 	if err != nil {
-		return pkgerrors.Errorf("Error updating master table: %s", err.Error())
+		return 0, pkgerrors.Errorf("Error updating master table: %s", err.Error())
 	}
 	if query == nil {
-		return nil
+		return version, nil
 	}
 
 	// Update to add Query fields
 	update, err := m.updateFilter(query)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	_, err = c.UpdateOne(
 		ctx,
@@ -282,9 +304,9 @@ func (m *MongoStore) Insert(coll string, key Key, query interface{}, tag string,
 		update)
 
 	if err != nil {
-		return pkgerrors.Errorf("Error updating Query fields: %s", err.Error())
+		return 0, pkgerrors.Errorf("Error updating Query fields: %s", err.Error())
 	}
-	return nil
+	return version, nil
 }
 
 // Find method returns the data stored for this key and for this particular tag
@@ -292,8 +314,25 @@ func (m *MongoStore) Find(coll string, key Key, tag string) ([][]byte, error) {
 
 	//result, err := m.findInternal(coll, key, tag, "")
 	//return result, err
+	return m.FindQuery(coll, key, tag, Query{})
+}
+
+// FindQuery returns the data stored for this key and for this particular tag
+// in the documents selected by the query
+func (m *MongoStore) FindQuery(coll string, key Key, tag string, q Query) ([][]byte, error) {
+	result, _, err := m.find(coll, key, tag, q)
+	return result, err
+}
+
+// FindVersions returns the data stored for this key and for this particular
+// tag, and the versions of the documents
+func (m *MongoStore) FindVersions(coll string, key Key, tag string) ([][]byte, []int64, error) {
+	return m.find(coll, key, tag, Query{})
+}
+
+func (m *MongoStore) find(coll string, key Key, tag string, q Query) ([][]byte, []int64, error) {
 	if !m.validateParams(coll, key, tag) {
-		return nil, pkgerrors.New("Mandatory fields are missing")
+		return nil, nil, pkgerrors.New("Mandatory fields are missing")
 	}
 
 	c := getCollection(coll, m)
@@ -301,21 +340,44 @@ func (m *MongoStore) Find(coll string, key Key, tag string) ([][]byte, error) {
 
 	filter, err := m.findFilterWithKey(key)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	fields := filter["$and"].([]bson.M)[0]
+	for k, v := range q.Fields {
+		fields[k] = v
+	}
+	if q.Sort != "" && (q.From != "" || q.To != "") {
+		r := bson.M{}
+		if q.From != "" {
+			r["$gte"] = q.From
+		}
+		if q.To != "" {
+			r["$lt"] = q.To
+		}
+		fields[q.Sort] = r
 	}
 	// Find only the field requested
 	projection := bson.D{
 		{tag, 1},
+		{"resourceVersion", 1},
 		{"_id", 0},
 	}
+	opts := options.Find().SetProjection(projection)
+	if q.Sort != "" {
+		opts.SetSort(bson.D{{Key: q.Sort, Value: 1}})
+	}
+	if q.Limit > 0 {
+		opts.SetLimit(int64(q.Limit))
+	}
 
-	cursor, err := c.Find(context.Background(), filter, options.Find().SetProjection(projection))
+	cursor, err := c.Find(context.Background(), filter, opts)
 	if err != nil {
-		return nil, pkgerrors.Errorf("Error finding element: %s", err.Error())
+		return nil, nil, pkgerrors.Errorf("Error finding element: %s", err.Error())
 	}
 	defer cursorClose(ctx, cursor)
 	var data []byte
 	var result [][]byte
+	var versions []int64
 	for cursorNext(ctx, cursor) {
 		d := cursor.Current
 		switch d.Lookup(tag).Type {
@@ -330,8 +392,11 @@ func (m *MongoStore) Find(coll string, key Key, tag string) ([][]byte, error) {
 			data = r.Value
 		}
 		result = append(result, data)
+		// 0 for the documents not inserted since they have a version
+		version, _ := d.Lookup("resourceVersion").Int64OK()
+		versions = append(versions, version)
 	}
-	return result, nil
+	return result, versions, nil
 }
 
 // RemoveAll method to removes all the documet matching key
@@ -354,6 +419,11 @@ func (m *MongoStore) RemoveAll(coll string, key Key) error {
 
 // Remove method to remove the documet by key if no child references
 func (m *MongoStore) Remove(coll string, key Key) error {
+	return m.RemoveVersion(coll, key, nil)
+}
+
+// RemoveVersion removes like Remove if the version of the document matches
+func (m *MongoStore) RemoveVersion(coll string, key Key, pre *Precondition) error {
 	if !m.validateParams(coll, key) {
 		return pkgerrors.New("Mandatory fields are missing")
 	}
@@ -373,10 +443,17 @@ func (m *MongoStore) Remove(coll string, key Key) error {
 	if count > 1 {
 		return pkgerrors.Errorf("Can't delete parent without deleting child references first")
 	}
-	_, err = c.DeleteOne(ctx, filter)
+	if pre != nil && len(pre.Versions) > 0 {
+		fields := filter["$and"].([]bson.M)[0]
+		fields["resourceVersion"] = bson.M{"$in": pre.Versions}
+	}
+	res, err := c.DeleteOne(ctx, filter)
 	if err != nil {
 		return pkgerrors.Errorf("Error Deleting from database: %s", err.Error())
 	}
+	if pre != nil && res.DeletedCount == 0 {
+		return ErrVersionConflict
+	}
 	return nil
 }
 
@@ -398,6 +475,9 @@ func (m *MongoStore) RemoveTag(coll string, key Key, tag string) error {
 				{"$unset", bson.D{
 					{tag, ""},
 				}},
+				{"$set", bson.D{
+					{"resourceVersion", newVersion()},
+				}},
 			},
 			options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)))
 
@@ -407,3 +487,89 @@ func (m *MongoStore) RemoveTag(coll string, key Key, tag string) error {
 
 	return nil
 }
+
+// versionID returns the _id of the document of the key holding a version, so
+// that the document can only be created once
+func (m *MongoStore) versionID(key Key) (string, error) {
+	n, err := keyMap(key)
+	if err != nil {
+		return "", err
+	}
+	// The fields are marshalled in the order of their names
+	id, err := json.Marshal(n)
+	if err != nil {
+		return "", pkgerrors.Errorf("Error Marshalling key: %s", err.Error())
+	}
+	return string(id), nil
+}
+
+// UpdateVersion sets the version of the document matching the key if its
+// version is current
+func (m *MongoStore) UpdateVersion(coll string, key Key, current int64, version int64) error {
+	if !m.validateParams(coll, key) {
+		return pkgerrors.New("Mandatory fields are missing")
+	}
+	c := getCollection(coll, m)
+	ctx := context.Background()
+
+	id, err := m.versionID(key)
+	if err != nil {
+		return err
+	}
+	if current == 0 {
+		// The document is created with the version
+		doc, err := keyMap(key)
+		if err != nil {
+			return err
+		}
+		doc["_id"] = id
+		doc["key"], err = m.createKeyField(key)
+		if err != nil {
+			return err
+		}
+		doc["resourceVersion"] = version
+		_, err = c.InsertOne(ctx, doc)
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrVersionConflict
+		}
+		if err != nil {
+			return pkgerrors.Errorf("Error updating version: %s", err.Error())
+		}
+		return nil
+	}
+	res, err := c.UpdateOne(ctx, bson.M{"_id": id, "resourceVersion": current},
+		bson.M{"$set": bson.M{"resourceVersion": version}})
+	if err != nil {
+		return pkgerrors.Errorf("Error updating version: %s", err.Error())
+	}
+	if res.MatchedCount == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// FindVersion returns the version of the document matching the key
+func (m *MongoStore) FindVersion(coll string, key Key) (int64, error) {
+	if !m.validateParams(coll, key) {
+		return 0, pkgerrors.New("Mandatory fields are missing")
+	}
+	c := getCollection(coll, m)
+	ctx := context.Background()
+
+	id, err := m.versionID(key)
+	if err != nil {
+		return 0, err
+	}
+	doc, err := decodeBytes(c.FindOne(ctx, bson.M{"_id": id}))
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, pkgerrors.Errorf("Error finding version: %s", err.Error())
+	}
+	version, ok := doc.Lookup("resourceVersion").Int64OK()
+	if !ok {
+		return 0, nil
+	}
+	return version, nil
+}
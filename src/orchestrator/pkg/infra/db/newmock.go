@@ -14,6 +14,8 @@ type NewMockDB struct {
 	Items      []map[string]map[string][]byte
 	Err        error
 	MarshalErr error
+	// Versions of the documents by key
+	Versions map[string]int64
 }
 
 func (m *NewMockDB) HealthCheck() error {
@@ -90,10 +92,12 @@ func (m *NewMockDB) Unmarshal(inp []byte, out interface{}) error {
 }
 
 func (m *NewMockDB) Find(table string, key Key, tag string) ([][]byte, error) {
+	return m.FindQuery(table, key, tag, Query{})
+}
 
-	tkey, _ := createKeyField(key)
+func (m *NewMockDB) FindQuery(table string, key Key, tag string, q Query) ([][]byte, error) {
 
-	newr := make([][]byte, 0)
+	tkey, _ := createKeyField(key)
 
 	// Make match key
 	matchkey := make(map[string]string)
@@ -111,8 +115,15 @@ func (m *NewMockDB) Find(table string, key Key, tag string) ([][]byte, error) {
 	if wildmatch > 0 {
 		matchkey["key"] = tkey
 	}
+	for k, v := range q.Fields {
+		matchkey[k] = v
+	}
 
-	cnt := 0
+	type found struct {
+		sort string
+		data []byte
+	}
+	var result []found
 	for _, item := range m.Items {
 		for _, v := range item {
 			// check if matchkey matches this item
@@ -134,28 +145,39 @@ func (m *NewMockDB) Find(table string, key Key, tag string) ([][]byte, error) {
 			if notfound {
 				break
 			}
+			var sv string
+			if q.Sort != "" {
+				json.Unmarshal(v[q.Sort], &sv)
+				if q.From != "" && sv < q.From || q.To != "" && sv >= q.To {
+					break
+				}
+			}
 
 			// this items key matches - add to the return list if tag is present
 			if _, ok := v[tag]; ok {
-				newr = append(newr, v[tag])
-				cnt++
+				result = append(result, found{sort: sv, data: v[tag]})
 			}
 		}
 	}
-	if cnt > 0 {
-		return newr, m.Err
-	} else {
-		if m.Err != nil {
-			return newr, m.Err
-		} else {
-			return newr, nil
-		}
+	if q.Sort != "" {
+		sort.SliceStable(result, func(i, j int) bool {
+			return result[i].sort < result[j].sort
+		})
 	}
+	if q.Limit > 0 && len(result) > q.Limit {
+		result = result[:q.Limit]
+	}
+	newr := make([][]byte, 0, len(result))
+	for _, f := range result {
+		newr = append(newr, f.data)
+	}
+	return newr, m.Err
 }
 
 func (m *NewMockDB) Remove(table string, key Key) error {
 	jkey, _ := json.Marshal(key)
 	str := (string(jkey))
+	delete(m.Versions, str)
 	for i, item := range m.Items {
 		for k, _ := range item {
 			if k == str {
@@ -175,3 +197,68 @@ func (m *NewMockDB) RemoveAll(table string, key Key) error {
 func (m *NewMockDB) RemoveTag(table string, key Key, tag string) error {
 	return m.Err
 }
+
+func (m *NewMockDB) UpdateVersion(table string, key Key, current int64, version int64) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	jkey, _ := json.Marshal(key)
+	if m.Versions[string(jkey)] != current {
+		return ErrVersionConflict
+	}
+	if m.Versions == nil {
+		m.Versions = make(map[string]int64)
+	}
+	m.Versions[string(jkey)] = version
+	return nil
+}
+
+func (m *NewMockDB) FindVersion(table string, key Key) (int64, error) {
+	jkey, _ := json.Marshal(key)
+	return m.Versions[string(jkey)], m.Err
+}
+
+func (m *NewMockDB) InsertVersion(table string, key Key, query interface{}, tag string, data interface{}, pre *Precondition) (int64, error) {
+	jkey, _ := json.Marshal(key)
+	if !pre.matches(m.Versions[string(jkey)]) {
+		return 0, ErrVersionConflict
+	}
+	// Like the databases, update the tag of an existing document
+	updated := false
+	for _, item := range m.Items {
+		if v, ok := item[string(jkey)]; ok {
+			v[tag], _ = json.Marshal(data)
+			updated = true
+			break
+		}
+	}
+	if !updated {
+		if err := m.Insert(table, key, query, tag, data); err != nil {
+			return 0, err
+		}
+	}
+	if m.Versions == nil {
+		m.Versions = make(map[string]int64)
+	}
+	version := newVersion()
+	m.Versions[string(jkey)] = version
+	return version, nil
+}
+
+func (m *NewMockDB) FindVersions(table string, key Key, tag string) ([][]byte, []int64, error) {
+	values, err := m.Find(table, key, tag)
+	versions := make([]int64, len(values))
+	if len(values) == 1 {
+		jkey, _ := json.Marshal(key)
+		versions[0] = m.Versions[string(jkey)]
+	}
+	return values, versions, err
+}
+
+func (m *NewMockDB) RemoveVersion(table string, key Key, pre *Precondition) error {
+	jkey, _ := json.Marshal(key)
+	if !pre.matches(m.Versions[string(jkey)]) {
+		return ErrVersionConflict
+	}
+	return m.Remove(table, key)
+}
@@ -0,0 +1,484 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/config"
+
+	"github.com/lib/pq"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// PostgresStore is an implementation of the db.Store interface
+// Every collection is a table with one jsonb document per row. The documents
+// have the same layout as in MongoDB: the key fields, the query fields, the
+// "key" field and one field per tag. The doc_key column holds the key fields
+// of the document so that a document is only created once for a key.
+type PostgresStore struct {
+	db *sql.DB
+	// Tables already created
+	tables sync.Map
+}
+
+// NewPostgresStore initializes a PostgreSQL Database with the name provided
+func NewPostgresStore(name string, store *sql.DB) (Store, error) {
+	if store == nil {
+		conn := fmt.Sprintf("host=%s port=%s dbname=%s sslmode=%s",
+			config.GetConfiguration().DatabaseIP, config.GetConfiguration().PostgresPort,
+			name, config.GetConfiguration().PostgresSSLMode)
+		if len(os.Getenv("DB_EMCO_USERNAME")) > 0 && len(os.Getenv("DB_EMCO_PASSWORD")) > 0 {
+			conn = fmt.Sprintf("%s user=%s password=%s", conn,
+				os.Getenv("DB_EMCO_USERNAME"), os.Getenv("DB_EMCO_PASSWORD"))
+		}
+		var err error
+		store, err = sql.Open("postgres", conn)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &PostgresStore{
+		db: store,
+	}, nil
+}
+
+// HealthCheck verifies if the database is up and running
+func (p *PostgresStore) HealthCheck() error {
+	err := p.db.Ping()
+	if err != nil {
+		return pkgerrors.Wrap(err, "Error getting server status")
+	}
+	return nil
+}
+
+// validateParams checks to see if any parameters are empty
+func (p *PostgresStore) validateParams(args ...interface{}) bool {
+	for _, v := range args {
+		val, ok := v.(string)
+		if ok {
+			if val == "" {
+				return false
+			}
+		} else {
+			if v == nil {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Unmarshal implements an unmarshaler for the json data
+// that is produced from the postgres database
+func (p *PostgresStore) Unmarshal(inp []byte, out interface{}) error {
+	err := json.Unmarshal(inp, out)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Unmarshaling json")
+	}
+	return nil
+}
+
+// table creates the table for the collection if it doesn't exist
+// and returns the quoted table name
+func (p *PostgresStore) table(coll string) (string, error) {
+	t := pq.QuoteIdentifier(coll)
+	if _, ok := p.tables.Load(coll); ok {
+		return t, nil
+	}
+	_, err := p.db.Exec("CREATE TABLE IF NOT EXISTS " + t + " (id BIGSERIAL PRIMARY KEY, doc_key TEXT NOT NULL UNIQUE, doc JSONB NOT NULL)")
+	if err != nil {
+		return "", pkgerrors.Errorf("Error creating table: %s", err.Error())
+	}
+	p.tables.Store(coll, true)
+	return t, nil
+}
+
+// keyMap returns the fields of the key
+func keyMap(key interface{}) (map[string]interface{}, error) {
+	var n map[string]interface{}
+	st, err := json.Marshal(key)
+	if err != nil {
+		return nil, pkgerrors.Errorf("Error Marshalling key: %s", err.Error())
+	}
+	err = json.Unmarshal([]byte(st), &n)
+	if err != nil {
+		return nil, pkgerrors.Errorf("Error Unmarshalling key to Map: %s", err.Error())
+	}
+	return n, nil
+}
+
+// findFilter returns a document matching all the fields of the key
+// for the jsonb containment operator. Same as MongoStore.findFilter.
+func (p *PostgresStore) findFilter(key Key) ([]byte, error) {
+	n, err := keyMap(key)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(n)
+}
+
+// findFilterWithKey is like findFilter but the fields of the key with an empty
+// value match any value of the same type of key. Same as MongoStore.findFilterWithKey.
+func (p *PostgresStore) findFilterWithKey(key Key) ([]byte, error) {
+	n, err := keyMap(key)
+	if err != nil {
+		return nil, err
+	}
+	filter := make(map[string]interface{})
+	for k, v := range n {
+		if v == "" {
+			if _, ok := filter["key"]; !ok {
+				// add type of key to filter
+				s, err := createKeyField(key)
+				if err != nil {
+					return nil, err
+				}
+				filter["key"] = s
+			}
+		} else {
+			filter[k] = v
+		}
+	}
+	return json.Marshal(filter)
+}
+
+// Insert is used to insert/add element to a document
+func (p *PostgresStore) Insert(coll string, key Key, query interface{}, tag string, data interface{}) error {
+	_, err := p.InsertVersion(coll, key, query, tag, data, nil)
+	return err
+}
+
+// InsertVersion inserts like Insert if the version of the document matches
+// and returns its new version
+func (p *PostgresStore) InsertVersion(coll string, key Key, query interface{}, tag string, data interface{}, pre *Precondition) (int64, error) {
+	if data == nil || !p.validateParams(coll, key, tag) {
+		return 0, pkgerrors.New("No Data to store")
+	}
+	t, err := p.table(coll)
+	if err != nil {
+		return 0, err
+	}
+	filter, err := p.findFilter(key)
+	if err != nil {
+		return 0, err
+	}
+	// Fields to set in the document: key, query fields, key type, tag and version
+	update, err := keyMap(key)
+	if err != nil {
+		return 0, err
+	}
+	if query != nil {
+		q, err := keyMap(query)
+		if err != nil {
+			return 0, err
+		}
+		for k, v := range q {
+			update[k] = v
+		}
+	}
+	update["key"], err = createKeyField(key)
+	if err != nil {
+		return 0, err
+	}
+	update[tag] = data
+	version := newVersion()
+	update["resourceVersion"] = version
+	u, err := json.Marshal(update)
+	if err != nil {
+		return 0, pkgerrors.Errorf("Error Marshalling data: %s", err.Error())
+	}
+
+	if pre != nil {
+		// Only the existing document with a matching version
+		cond, args := p.versionCondition(pre, 3)
+		res, err := p.db.Exec("UPDATE "+t+" SET doc = doc || $2::jsonb WHERE doc_key = $1"+cond,
+			append([]interface{}{string(filter), string(u)}, args...)...)
+		if err != nil {
+			return 0, pkgerrors.Errorf("Error updating master table: %s", err.Error())
+		}
+		if count, err := res.RowsAffected(); err != nil || count == 0 {
+			return 0, ErrVersionConflict
+		}
+		return version, nil
+	}
+	// The key fields are marshalled in the order of their names, so the
+	// filter is the same for all the inserts of a key
+	_, err = p.db.Exec("INSERT INTO "+t+" (doc_key, doc) VALUES ($1, $2::jsonb) "+
+		"ON CONFLICT (doc_key) DO UPDATE SET doc = "+t+".doc || EXCLUDED.doc",
+		string(filter), string(u))
+	if err != nil {
+		return 0, pkgerrors.Errorf("Error updating master table: %s", err.Error())
+	}
+	return version, nil
+}
+
+// versionCondition returns the condition on the version of the document
+// matching the precondition, and its arguments numbered from n
+func (p *PostgresStore) versionCondition(pre *Precondition, n int) (string, []interface{}) {
+	if len(pre.Versions) == 0 {
+		return "", nil
+	}
+	return fmt.Sprintf(" AND (doc ->> 'resourceVersion')::bigint = ANY($%d)", n), []interface{}{pq.Array(pre.Versions)}
+}
+
+// Find method returns the data stored for this key and for this particular tag
+func (p *PostgresStore) Find(coll string, key Key, tag string) ([][]byte, error) {
+	return p.FindQuery(coll, key, tag, Query{})
+}
+
+// FindQuery returns the data stored for this key and for this particular tag
+// in the documents selected by the query
+func (p *PostgresStore) FindQuery(coll string, key Key, tag string, q Query) ([][]byte, error) {
+	result, _, err := p.find(coll, key, tag, q)
+	return result, err
+}
+
+// FindVersions returns the data stored for this key and for this particular
+// tag, and the versions of the documents
+func (p *PostgresStore) FindVersions(coll string, key Key, tag string) ([][]byte, []int64, error) {
+	return p.find(coll, key, tag, Query{})
+}
+
+func (p *PostgresStore) find(coll string, key Key, tag string, q Query) ([][]byte, []int64, error) {
+	if !p.validateParams(coll, key, tag) {
+		return nil, nil, pkgerrors.New("Mandatory fields are missing")
+	}
+	t, err := p.table(coll)
+	if err != nil {
+		return nil, nil, err
+	}
+	filter, err := p.findFilterWithKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(q.Fields) > 0 {
+		var f map[string]interface{}
+		json.Unmarshal(filter, &f)
+		for k, v := range q.Fields {
+			f[k] = v
+		}
+		filter, err = json.Marshal(f)
+		if err != nil {
+			return nil, nil, pkgerrors.Errorf("Error Marshalling query: %s", err.Error())
+		}
+	}
+	query := "SELECT doc -> $2, (doc ->> 'resourceVersion')::bigint FROM " + t + " WHERE doc @> $1::jsonb AND doc ? $2"
+	args := []interface{}{string(filter), tag}
+	order := "id"
+	if q.Sort != "" {
+		// The values are compared as strings, in byte order like in MongoStore
+		args = append(args, q.Sort)
+		field := "(doc ->> $3) COLLATE \"C\""
+		if q.From != "" {
+			args = append(args, q.From)
+			query += fmt.Sprintf(" AND %s >= $%d", field, len(args))
+		}
+		if q.To != "" {
+			args = append(args, q.To)
+			query += fmt.Sprintf(" AND %s < $%d", field, len(args))
+		}
+		order = field + ", id"
+	}
+	query += " ORDER BY " + order
+	if q.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", q.Limit)
+	}
+	rows, err := p.db.Query(query, args...)
+	if err != nil {
+		return nil, nil, pkgerrors.Errorf("Error finding element: %s", err.Error())
+	}
+	defer rows.Close()
+	var result [][]byte
+	var versions []int64
+	for rows.Next() {
+		var data []byte
+		// NULL for the documents not inserted since they have a version
+		var version sql.NullInt64
+		if err := rows.Scan(&data, &version); err != nil {
+			return nil, nil, pkgerrors.Errorf("Error finding element: %s", err.Error())
+		}
+		// Strings are returned as is like in MongoStore
+		var s string
+		if json.Unmarshal(data, &s) == nil {
+			data = []byte(s)
+		}
+		result = append(result, data)
+		versions = append(versions, version.Int64)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, pkgerrors.Errorf("Error finding element: %s", err.Error())
+	}
+	return result, versions, nil
+}
+
+// RemoveAll method to removes all the documet matching key
+func (p *PostgresStore) RemoveAll(coll string, key Key) error {
+	if !p.validateParams(coll, key) {
+		return pkgerrors.New("Mandatory fields are missing")
+	}
+	t, err := p.table(coll)
+	if err != nil {
+		return err
+	}
+	filter, err := p.findFilterWithKey(key)
+	if err != nil {
+		return err
+	}
+	_, err = p.db.Exec("DELETE FROM "+t+" WHERE doc @> $1::jsonb", string(filter))
+	if err != nil {
+		return pkgerrors.Errorf("Error Deleting from database: %s", err.Error())
+	}
+	return nil
+}
+
+// Remove method to remove the documet by key if no child references
+func (p *PostgresStore) Remove(coll string, key Key) error {
+	return p.RemoveVersion(coll, key, nil)
+}
+
+// RemoveVersion removes like Remove if the version of the document matches
+func (p *PostgresStore) RemoveVersion(coll string, key Key, pre *Precondition) error {
+	if !p.validateParams(coll, key) {
+		return pkgerrors.New("Mandatory fields are missing")
+	}
+	t, err := p.table(coll)
+	if err != nil {
+		return err
+	}
+	filter, err := p.findFilter(key)
+	if err != nil {
+		return err
+	}
+	tx, err := p.db.Begin()
+	if err != nil {
+		return pkgerrors.Errorf("Error Deleting from database: %s", err.Error())
+	}
+	defer tx.Rollback()
+	// Child documents contain all the fields of the parent key
+	var count int64
+	err = tx.QueryRow("SELECT COUNT(*) FROM "+t+" WHERE doc @> $1::jsonb", string(filter)).Scan(&count)
+	if err != nil {
+		return pkgerrors.Errorf("Error finding: %s", err.Error())
+	}
+	if count == 0 {
+		return pkgerrors.Errorf("key not found")
+	}
+	if count > 1 {
+		return pkgerrors.Errorf("Can't delete parent without deleting child references first")
+	}
+	query := "DELETE FROM " + t + " WHERE doc @> $1::jsonb"
+	args := []interface{}{string(filter)}
+	if pre != nil {
+		cond, condArgs := p.versionCondition(pre, 2)
+		query += cond
+		args = append(args, condArgs...)
+	}
+	res, err := tx.Exec(query, args...)
+	if err != nil {
+		return pkgerrors.Errorf("Error Deleting from database: %s", err.Error())
+	}
+	if count, err := res.RowsAffected(); pre != nil && (err != nil || count == 0) {
+		return ErrVersionConflict
+	}
+	err = tx.Commit()
+	if err != nil {
+		return pkgerrors.Errorf("Error Deleting from database: %s", err.Error())
+	}
+	return nil
+}
+
+// RemoveTag is used to remove an element from a document
+func (p *PostgresStore) RemoveTag(coll string, key Key, tag string) error {
+	t, err := p.table(coll)
+	if err != nil {
+		return err
+	}
+	filter, err := p.findFilter(key)
+	if err != nil {
+		return err
+	}
+	_, err = p.db.Exec("UPDATE "+t+" SET doc = (doc - $2) || jsonb_build_object('resourceVersion', $3::bigint) WHERE doc @> $1::jsonb",
+		string(filter), tag, newVersion())
+	if err != nil {
+		return pkgerrors.Errorf("Error removing tag: %s", err.Error())
+	}
+	return nil
+}
+
+// UpdateVersion sets the version of the document matching the key if its
+// version is current
+func (p *PostgresStore) UpdateVersion(coll string, key Key, current int64, version int64) error {
+	if !p.validateParams(coll, key) {
+		return pkgerrors.New("Mandatory fields are missing")
+	}
+	t, err := p.table(coll)
+	if err != nil {
+		return err
+	}
+	filter, err := p.findFilter(key)
+	if err != nil {
+		return err
+	}
+	var res sql.Result
+	if current == 0 {
+		// The document is created with the version
+		doc, err := keyMap(key)
+		if err != nil {
+			return err
+		}
+		doc["key"], err = createKeyField(key)
+		if err != nil {
+			return err
+		}
+		doc["resourceVersion"] = version
+		d, err := json.Marshal(doc)
+		if err != nil {
+			return pkgerrors.Errorf("Error Marshalling data: %s", err.Error())
+		}
+		res, err = p.db.Exec("INSERT INTO "+t+" (doc_key, doc) VALUES ($1, $2::jsonb) ON CONFLICT (doc_key) DO NOTHING",
+			string(filter), string(d))
+	} else {
+		res, err = p.db.Exec("UPDATE "+t+" SET doc = jsonb_set(doc, '{resourceVersion}', to_jsonb($3::bigint)) "+
+			"WHERE doc_key = $1 AND doc -> 'resourceVersion' = to_jsonb($2::bigint)",
+			string(filter), current, version)
+	}
+	if err != nil {
+		return pkgerrors.Errorf("Error updating version: %s", err.Error())
+	}
+	if count, err := res.RowsAffected(); err != nil || count == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// FindVersion returns the version of the document matching the key
+func (p *PostgresStore) FindVersion(coll string, key Key) (int64, error) {
+	if !p.validateParams(coll, key) {
+		return 0, pkgerrors.New("Mandatory fields are missing")
+	}
+	t, err := p.table(coll)
+	if err != nil {
+		return 0, err
+	}
+	filter, err := p.findFilter(key)
+	if err != nil {
+		return 0, err
+	}
+	var version sql.NullInt64
+	err = p.db.QueryRow("SELECT (doc ->> 'resourceVersion')::bigint FROM "+t+" WHERE doc_key = $1", string(filter)).Scan(&version)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, pkgerrors.Errorf("Error finding version: %s", err.Error())
+	}
+	return version.Int64, nil
+}
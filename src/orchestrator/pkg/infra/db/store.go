@@ -6,6 +6,7 @@ package db
 import (
 	"encoding/json"
 	"reflect"
+	"time"
 
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/config"
 
@@ -30,19 +31,99 @@ type Store interface {
 	Unmarshal(inp []byte, out interface{}) error
 
 	// Inserts and Updates a tag with key and also adds query fields if provided
+	// The document gets a new version
 	Insert(coll string, key Key, query interface{}, tag string, data interface{}) error
 
+	// Inserts like Insert if the version of the document matches the
+	// precondition, or unconditionally if it is nil, and returns the new
+	// version of the document. Returns ErrVersionConflict otherwise
+	InsertVersion(coll string, key Key, query interface{}, tag string, data interface{}, pre *Precondition) (int64, error)
+
 	// Find the document(s) with key and get the tag values from the document(s)
 	Find(coll string, key Key, tag string) ([][]byte, error)
 
+	// Finds like Find and also returns the version of each document
+	FindVersions(coll string, key Key, tag string) ([][]byte, []int64, error)
+
+	// Find the document(s) with key selected by the query and get the tag
+	// values from the document(s)
+	FindQuery(coll string, key Key, tag string, q Query) ([][]byte, error)
+
 	// Removes the document(s) matching the key if no child reference in collection
 	Remove(coll string, key Key) error
 
+	// Removes like Remove if the version of the document matches the
+	// precondition, or unconditionally if it is nil. Returns
+	// ErrVersionConflict otherwise
+	RemoveVersion(coll string, key Key, pre *Precondition) error
+
 	// Remove all the document(s) matching the key
 	RemoveAll(coll string, key Key) error
 
 	// Remove the specifiec tag from the document matching the key
+	// The document gets a new version
 	RemoveTag(coll string, key Key, tag string) error
+
+	// Sets the version of the document matching the key to version if its
+	// version is current, 0 meaning that the document doesn't exist yet.
+	// Returns ErrVersionConflict otherwise. Used for the documents only
+	// holding a counter, not inserted with Insert.
+	UpdateVersion(coll string, key Key, current int64, version int64) error
+
+	// Returns the version of the document matching the key, 0 if it doesn't exist
+	FindVersion(coll string, key Key) (int64, error)
+}
+
+// Precondition is the versions a document must have to be changed
+type Precondition struct {
+	// Versions matching. Any version of an existing document matches if
+	// there is none.
+	Versions []int64
+}
+
+// matches checks if the version of a document matches the precondition, 0
+// meaning that the document doesn't exist
+func (p *Precondition) matches(version int64) bool {
+	if p == nil {
+		return true
+	}
+	if version == 0 {
+		return false
+	}
+	if len(p.Versions) == 0 {
+		return true
+	}
+	for _, v := range p.Versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// Query selects the documents returned by FindQuery with their query fields,
+// those passed to Insert. The zero values select all the documents.
+type Query struct {
+	// Values the query fields must have
+	Fields map[string]string
+	// Query field the documents are sorted by, its values ordered as strings
+	Sort string
+	// Range of the values of the Sort field, From inclusive and To exclusive
+	From string
+	To   string
+	// Maximum number of documents returned
+	Limit int
+}
+
+// ErrVersionConflict is returned if the version of the document doesn't
+// match, as it was changed by someone else
+var ErrVersionConflict = pkgerrors.New("Version of the document was changed")
+
+// newVersion returns the version of a document changed now. The versions are
+// only compared for equality, so a document created again after it was
+// removed doesn't get a version it had before.
+var newVersion = func() int64 {
+	return time.Now().UnixNano()
 }
 
 // CreateDBClient creates the DB client
@@ -52,6 +133,9 @@ func createDBClient(dbType string, dbName string) error {
 	case "mongo":
 		// create a mongodb database with orchestrator as the name
 		DBconn, err = NewMongoStore(dbName, nil)
+	case "postgres":
+		// create a postgres database client with orchestrator as the name
+		DBconn, err = NewPostgresStore(dbName, nil)
 	default:
 		return pkgerrors.New(dbType + "DB not supported")
 	}
@@ -83,6 +167,7 @@ func InitializeDatabaseConnection(dbName string) error {
 	if err != nil {
 		return pkgerrors.Cause(err)
 	}
+	DBconn = tracedStore{Store: DBconn, system: config.GetConfiguration().DatabaseType}
 
 	err = DBconn.HealthCheck()
 	if err != nil {
@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package db
+
+import (
+	"context"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+)
+
+// tracedStore records a span for each call to the Store. The Store interface
+// doesn't take a context, so the spans are only recorded for the Store
+// returned by WithContext, as children of the span of its context.
+type tracedStore struct {
+	Store
+	system string
+	ctx    context.Context
+}
+
+// WithContext returns the Store recording its calls as children of the span
+// of ctx, if tracing is enabled. If ctx is the context of a request returned
+// by WithVersion, the Store also compares and sets the version of the
+// document of the request, see versionedStore.
+func WithContext(ctx context.Context) Store {
+	s := DBconn
+	if t, ok := s.(tracedStore); ok {
+		t.ctx = ctx
+		s = t
+	}
+	if v := requestVersionFrom(ctx); v != nil {
+		return versionedStore{Store: s, v: v}
+	}
+	return s
+}
+
+func (s tracedStore) start(op, coll string) func(error) {
+	if !tracing.Active(s.ctx) {
+		return func(error) {}
+	}
+	_, span := tracing.Start(s.ctx, s.system+"."+op,
+		semconv.DBSystemKey.String(s.system), semconv.DBOperationKey.String(op), attribute.String("db.collection", coll))
+	return func(err error) { tracing.End(span, err) }
+}
+
+func (s tracedStore) Insert(coll string, key Key, query interface{}, tag string, data interface{}) (err error) {
+	end := s.start("Insert", coll)
+	defer func() { end(err) }()
+	return s.Store.Insert(coll, key, query, tag, data)
+}
+
+func (s tracedStore) InsertVersion(coll string, key Key, query interface{}, tag string, data interface{}, pre *Precondition) (version int64, err error) {
+	end := s.start("InsertVersion", coll)
+	defer func() { end(err) }()
+	return s.Store.InsertVersion(coll, key, query, tag, data, pre)
+}
+
+func (s tracedStore) Find(coll string, key Key, tag string) (values [][]byte, err error) {
+	end := s.start("Find", coll)
+	defer func() { end(err) }()
+	return s.Store.Find(coll, key, tag)
+}
+
+func (s tracedStore) FindVersions(coll string, key Key, tag string) (values [][]byte, versions []int64, err error) {
+	end := s.start("FindVersions", coll)
+	defer func() { end(err) }()
+	return s.Store.FindVersions(coll, key, tag)
+}
+
+func (s tracedStore) FindQuery(coll string, key Key, tag string, q Query) (values [][]byte, err error) {
+	end := s.start("FindQuery", coll)
+	defer func() { end(err) }()
+	return s.Store.FindQuery(coll, key, tag, q)
+}
+
+func (s tracedStore) Remove(coll string, key Key) (err error) {
+	end := s.start("Remove", coll)
+	defer func() { end(err) }()
+	return s.Store.Remove(coll, key)
+}
+
+func (s tracedStore) RemoveVersion(coll string, key Key, pre *Precondition) (err error) {
+	end := s.start("RemoveVersion", coll)
+	defer func() { end(err) }()
+	return s.Store.RemoveVersion(coll, key, pre)
+}
+
+func (s tracedStore) RemoveAll(coll string, key Key) (err error) {
+	end := s.start("RemoveAll", coll)
+	defer func() { end(err) }()
+	return s.Store.RemoveAll(coll, key)
+}
+
+func (s tracedStore) RemoveTag(coll string, key Key, tag string) (err error) {
+	end := s.start("RemoveTag", coll)
+	defer func() { end(err) }()
+	return s.Store.RemoveTag(coll, key, tag)
+}
+
+func (s tracedStore) UpdateVersion(coll string, key Key, current int64, version int64) (err error) {
+	end := s.start("UpdateVersion", coll)
+	defer func() { end(err) }()
+	return s.Store.UpdateVersion(coll, key, current, version)
+}
+
+func (s tracedStore) FindVersion(coll string, key Key) (version int64, err error) {
+	end := s.start("FindVersion", coll)
+	defer func() { end(err) }()
+	return s.Store.FindVersion(coll, key)
+}
@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package db
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// RequestVersion is the version of the document of a REST API request, the
+// resource it reads or changes. The Store returned by WithContext for the
+// context of the request only changes the document if its version matches the
+// precondition of the request, and records the version of the document found
+// or changed for the response.
+type RequestVersion struct {
+	mu           sync.Mutex
+	precondition *Precondition
+	version      int64
+	conflict     bool
+}
+
+type requestVersionKey struct{}
+
+// WithVersion returns the context of a request comparing and setting the
+// version of its document with pre, or unconditionally if pre is nil, and
+// the RequestVersion recording it
+func WithVersion(ctx context.Context, pre *Precondition) (context.Context, *RequestVersion) {
+	v := &RequestVersion{precondition: pre}
+	return context.WithValue(ctx, requestVersionKey{}, v), v
+}
+
+func requestVersionFrom(ctx context.Context) *RequestVersion {
+	if ctx == nil {
+		return nil
+	}
+	v, _ := ctx.Value(requestVersionKey{}).(*RequestVersion)
+	return v
+}
+
+// Version returns the version of the document found or changed by the
+// request, 0 if there is none
+func (v *RequestVersion) Version() int64 {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.version
+}
+
+// Conflict reports if the document was not changed as its version didn't
+// match the precondition
+func (v *RequestVersion) Conflict() bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.conflict
+}
+
+func (v *RequestVersion) get() *Precondition {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.precondition
+}
+
+// changed records the new version of the document. The next changes of the
+// request only match this version, so the request can change the document
+// several times, but not once it was changed by someone else.
+func (v *RequestVersion) changed(version int64, err error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if errors.Is(err, ErrVersionConflict) {
+		v.conflict = true
+	}
+	if err != nil {
+		return
+	}
+	if v.precondition != nil {
+		v.precondition = &Precondition{Versions: []int64{version}}
+	}
+	v.version = version
+}
+
+func (v *RequestVersion) found(version int64) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.version = version
+}
+
+// versionedStore compares and sets the version of the document of a request
+type versionedStore struct {
+	Store
+	v *RequestVersion
+}
+
+func (s versionedStore) Insert(coll string, key Key, query interface{}, tag string, data interface{}) error {
+	version, err := s.Store.InsertVersion(coll, key, query, tag, data, s.v.get())
+	s.v.changed(version, err)
+	return err
+}
+
+func (s versionedStore) Find(coll string, key Key, tag string) ([][]byte, error) {
+	values, versions, err := s.Store.FindVersions(coll, key, tag)
+	// Only the version of a single document is the version of the request
+	if err == nil && len(versions) == 1 {
+		s.v.found(versions[0])
+	}
+	return values, err
+}
+
+func (s versionedStore) Remove(coll string, key Key) error {
+	err := s.Store.RemoveVersion(coll, key, s.v.get())
+	s.v.changed(0, err)
+	return err
+}
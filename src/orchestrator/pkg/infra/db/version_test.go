@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package db
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRequestVersion(t *testing.T) {
+	DBconn = &NewMockDB{}
+	key := map[string]string{"project": "p1"}
+
+	// Without a precondition the document is changed and its version recorded
+	ctx, v := WithVersion(context.Background(), nil)
+	if err := WithContext(ctx).Insert("coll", key, nil, "data", "d1"); err != nil {
+		t.Fatalf("Insert returned an error %s", err)
+	}
+	created := v.Version()
+	if created == 0 {
+		t.Fatalf("Insert recorded no version")
+	}
+	ctx, v = WithVersion(context.Background(), nil)
+	if _, err := WithContext(ctx).Find("coll", key, "data"); err != nil || v.Version() != created {
+		t.Fatalf("Find recorded version %d %v, expected %d", v.Version(), err, created)
+	}
+
+	// A precondition of another version doesn't change the document
+	ctx, v = WithVersion(context.Background(), &Precondition{Versions: []int64{created + 1}})
+	err := WithContext(ctx).Insert("coll", key, nil, "data", "d2")
+	if err != ErrVersionConflict || !v.Conflict() {
+		t.Fatalf("Insert of another version returned %v, conflict %t", err, v.Conflict())
+	}
+	if err := WithContext(ctx).Remove("coll", key); err != ErrVersionConflict {
+		t.Fatalf("Remove of another version returned %v", err)
+	}
+
+	// The request can change the document several times once it matched
+	ctx, v = WithVersion(context.Background(), &Precondition{Versions: []int64{created}})
+	store := WithContext(ctx)
+	if err := store.Insert("coll", key, nil, "data", "d2"); err != nil {
+		t.Fatalf("Insert returned an error %s", err)
+	}
+	if v.Version() == created {
+		t.Fatalf("Insert didn't change the version")
+	}
+	if err := store.Insert("coll", key, nil, "status", "s2"); err != nil {
+		t.Fatalf("Second Insert returned an error %s", err)
+	}
+
+	// The Store of a context without version is unconditional
+	if err := WithContext(context.Background()).Remove("coll", key); err != nil {
+		t.Fatalf("Remove returned an error %s", err)
+	}
+}
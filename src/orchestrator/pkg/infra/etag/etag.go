@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+// Package etag adds optimistic concurrency to the REST APIs of the
+// microservices. Every document of the database has a version, changed by
+// each change of the document, whether made by a request or not. The version
+// of the resource of a request is returned as its ETag, and the requests with
+// an If-Match header only change the resource if its version matches. They
+// are rejected with 412 Precondition Failed otherwise.
+//
+// The versions are compared and set by the database with the changes, see
+// db.WithVersion. The handlers make the database calls of the resource of a
+// request with the Store of db.WithContext for the context of the request,
+// through the WithContext method of the module clients.
+package etag
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/utils"
+)
+
+// Format returns the ETag of a version
+func Format(version int64) string {
+	return "\"" + strconv.FormatInt(version, 10) + "\""
+}
+
+// Precondition returns the precondition of the value of an If-Match header.
+// The ETags that aren't the versions of documents match no document.
+func Precondition(ifMatch string) *db.Precondition {
+	pre := &db.Precondition{}
+	for _, v := range strings.Split(ifMatch, ",") {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "W/")
+		if v == "*" {
+			return &db.Precondition{}
+		}
+		version, err := strconv.ParseInt(strings.Trim(v, "\""), 10, 64)
+		if err != nil || version <= 0 {
+			// No document has the version 0
+			version = 0
+		}
+		pre.Versions = append(pre.Versions, version)
+	}
+	return pre
+}
+
+// Handler adds the ETag header to the successful responses of the requests
+// reading or changing a resource, and only lets the requests with an If-Match
+// header change the resource if its version matches
+func Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Websocket connections are not wrapped
+		if r.Header.Get("Upgrade") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		var pre *db.Precondition
+		if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+			pre = Precondition(ifMatch)
+		}
+		ctx, v := db.WithVersion(r.Context(), pre)
+		sw := utils.NewStatusWriter(w)
+		sw.BeforeHeader = func(code int) {
+			if v.Conflict() {
+				// The error of the handler is a precondition failure
+				sw.Code = http.StatusPreconditionFailed
+				return
+			}
+			if code >= 200 && code < 300 && r.Method != http.MethodDelete && v.Version() > 0 {
+				w.Header().Set("ETag", Format(v.Version()))
+			}
+		}
+		next.ServeHTTP(sw, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,178 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package etag
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// resourceKey is the key of the resource of a path in the database
+type resourceKey struct {
+	Path string `json:"path"`
+}
+
+// testStore is a handler storing one resource per path in the database, like
+// the handlers of the microservices
+func testStore(w http.ResponseWriter, r *http.Request) {
+	key := resourceKey{Path: r.URL.Path}
+	store := db.WithContext(r.Context())
+	switch r.Method {
+	case http.MethodGet:
+		v, err := store.Find("resources", key, "data")
+		if err != nil || len(v) == 0 {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		w.Write(v[0])
+	case http.MethodPut:
+		b, _ := ioutil.ReadAll(r.Body)
+		if err := store.Insert("resources", key, nil, "data", json.RawMessage(b)); err != nil {
+			http.Error(w, pkgerrors.Wrap(err, "db Insert error").Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(b)
+	case http.MethodDelete:
+		if err := store.Remove("resources", key); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func do(h http.Handler, method, path, body, ifMatch string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	if ifMatch != "" {
+		r.Header.Set("If-Match", ifMatch)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestHandler(t *testing.T) {
+	db.DBconn = &db.NewMockDB{}
+	h := Handler(http.HandlerFunc(testStore))
+
+	// A created resource has a version
+	w := do(h, http.MethodPut, "/v2/projects/p1", `"p1"`, "")
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" {
+		t.Fatalf("PUT returned %d with ETag %s", w.Code, etag)
+	}
+	if e := do(h, http.MethodGet, "/v2/projects/p1", "", "").Header().Get("ETag"); e != etag {
+		t.Fatalf("GET returned ETag %s, PUT returned %s", e, etag)
+	}
+
+	// Update without If-Match is not checked
+	w = do(h, http.MethodPut, "/v2/projects/p1", `"d1"`, "")
+	if w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Fatalf("PUT returned %d with ETag %s", w.Code, w.Header().Get("ETag"))
+	}
+
+	// The resource was modified since the first PUT
+	w = do(h, http.MethodPut, "/v2/projects/p1", `"d2"`, etag)
+	if w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("PUT with old ETag returned %d", w.Code)
+	}
+	if b := do(h, http.MethodGet, "/v2/projects/p1", "", "").Body.String(); b != `"d1"` {
+		t.Fatalf("PUT with old ETag changed the resource to %s", b)
+	}
+
+	etag = do(h, http.MethodGet, "/v2/projects/p1", "", "").Header().Get("ETag")
+	w = do(h, http.MethodPut, "/v2/projects/p1", `"d2"`, etag)
+	if w.Code != http.StatusOK || w.Header().Get("ETag") == etag ||
+		w.Header().Get("ETag") != do(h, http.MethodGet, "/v2/projects/p1", "", "").Header().Get("ETag") {
+		t.Fatalf("PUT with current ETag returned %d with ETag %s", w.Code, w.Header().Get("ETag"))
+	}
+
+	// Only one of two updates of the same version succeeds
+	etag = w.Header().Get("ETag")
+	if w := do(h, http.MethodPut, "/v2/projects/p1", `"d3"`, etag); w.Code != http.StatusOK {
+		t.Fatalf("First PUT returned %d", w.Code)
+	}
+	if w := do(h, http.MethodPut, "/v2/projects/p1", `"d4"`, etag); w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("Second PUT returned %d", w.Code)
+	}
+
+	w = do(h, http.MethodDelete, "/v2/projects/p1", "", etag)
+	if w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("DELETE with old ETag returned %d", w.Code)
+	}
+	w = do(h, http.MethodDelete, "/v2/projects/p1", "", "*")
+	if w.Code != http.StatusNoContent || w.Header().Get("ETag") != "" {
+		t.Fatalf("DELETE with If-Match * returned %d with ETag %s", w.Code, w.Header().Get("ETag"))
+	}
+	w = do(h, http.MethodGet, "/v2/projects/p1", "", "")
+	if w.Code != http.StatusNotFound || w.Header().Get("ETag") != "" {
+		t.Errorf("GET of deleted resource returned %d with ETag %s", w.Code, w.Header().Get("ETag"))
+	}
+
+	// Created again, the resource gets a new version
+	if w := do(h, http.MethodPut, "/v2/projects/p1", `"p1"`, "*"); w.Code != http.StatusPreconditionFailed {
+		t.Errorf("PUT of a missing resource with If-Match * returned %d", w.Code)
+	}
+	if e := do(h, http.MethodPut, "/v2/projects/p1", `"p1"`, "").Header().Get("ETag"); e == "" || e == etag {
+		t.Errorf("PUT of the resource created again returned ETag %s", e)
+	}
+}
+
+func TestHandlerChangesOutsideRequests(t *testing.T) {
+	db.DBconn = &db.NewMockDB{}
+	h := Handler(http.HandlerFunc(testStore))
+	etag := do(h, http.MethodPut, "/v2/projects/p1", `"p1"`, "").Header().Get("ETag")
+
+	// Like the state of a resource stored by a background task
+	_, err := db.DBconn.InsertVersion("resources", resourceKey{Path: "/v2/projects/p1"}, nil, "state", "s1", nil)
+	if err != nil {
+		t.Fatalf("Insert returned an error %s", err)
+	}
+	if e := do(h, http.MethodGet, "/v2/projects/p1", "", "").Header().Get("ETag"); e == etag {
+		t.Errorf("GET returned the ETag %s of the resource before it changed", e)
+	}
+	if w := do(h, http.MethodPut, "/v2/projects/p1", `"d1"`, etag); w.Code != http.StatusPreconditionFailed {
+		t.Errorf("PUT with the ETag read before the change returned %d", w.Code)
+	}
+}
+
+func TestHandlerStream(t *testing.T) {
+	db.DBconn = &db.NewMockDB{}
+	h := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("event: state\n\n"))
+		w.(http.Flusher).Flush()
+		w.Write([]byte("event: appcontext\n\n"))
+	}))
+	w := do(h, http.MethodGet, "/v2/projects/p1/events", "", "")
+	if !w.Flushed || w.Body.String() != "event: state\n\nevent: appcontext\n\n" || w.Header().Get("ETag") != "" {
+		t.Errorf("Unexpected stream response %v %s", w.Header(), w.Body.String())
+	}
+}
+
+func TestPrecondition(t *testing.T) {
+	testCases := []struct {
+		ifMatch  string
+		expected []int64
+	}{
+		{`"5"`, []int64{5}},
+		{`"5", W/"7"`, []int64{5, 7}},
+		{`"5", *`, nil},
+		{`"abc"`, []int64{0}},
+	}
+	for _, testCase := range testCases {
+		pre := Precondition(testCase.ifMatch)
+		if !reflect.DeepEqual(pre.Versions, testCase.expected) {
+			t.Errorf("Precondition of %s returned %v, expected %v", testCase.ifMatch, pre.Versions, testCase.expected)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	grpcClientDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "grpc_client_handling_seconds",
+		Help:      "Latency of the gRPC calls to other services. For streams, the time to open the stream.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	grpcServerDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "grpc_server_handling_seconds",
+		Help:      "Latency of the gRPC calls handled by the service. For streams, the lifetime of the stream.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+)
+
+// ServerOptions returns the options recording the latency of the gRPC server
+func ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unaryServerInterceptor),
+		grpc.ChainStreamInterceptor(streamServerInterceptor),
+	}
+}
+
+// DialOptions returns the options recording the latency of the gRPC client
+func DialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(unaryClientInterceptor),
+		grpc.WithChainStreamInterceptor(streamClientInterceptor),
+	}
+}
+
+func observe(h *prometheus.HistogramVec, method string, start time.Time, err error) {
+	h.WithLabelValues(method, status.Code(err).String()).Observe(time.Since(start).Seconds())
+}
+
+func unaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	observe(grpcServerDuration, info.FullMethod, start, err)
+	return resp, err
+}
+
+func streamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	observe(grpcServerDuration, info.FullMethod, start, err)
+	return err
+}
+
+func unaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	observe(grpcClientDuration, method, start, err)
+	return err
+}
+
+func streamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	start := time.Now()
+	s, err := streamer(ctx, desc, cc, method, opts...)
+	observe(grpcClientDuration, method, start, err)
+	return s, err
+}
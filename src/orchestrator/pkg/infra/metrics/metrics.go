@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+// Package metrics exposes the Prometheus metrics of the EMCO services. The
+// services serve them with Serve on their metrics port, apart from the REST
+// API so they are not exposed to its callers. Handler records the metrics of
+// the REST API requests.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/utils"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Namespace of the metrics of all the EMCO services
+const Namespace = "emco"
+
+// Path the metrics are served at
+const Path = "/metrics"
+
+var (
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of the REST API requests per route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "code"})
+
+	httpRequestErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "http_request_errors_total",
+		Help:      "Number of REST API requests per route that returned an error status.",
+	}, []string{"method", "route", "code"})
+)
+
+func init() {
+	prometheus.MustRegister(httpRequestDuration, httpRequestErrors, grpcClientDuration, grpcServerDuration)
+}
+
+// Handler records the latency and the errors of the requests to next. The
+// requests are identified by the path template of the route of router they
+// match.
+func Handler(router *mux.Router, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := "unmatched"
+		var match mux.RouteMatch
+		if router.Match(r, &match) && match.Route != nil {
+			if t, err := match.Route.GetPathTemplate(); err == nil {
+				route = t
+			}
+		}
+
+		start := time.Now()
+		sw := utils.NewStatusWriter(w)
+		next.ServeHTTP(sw, r)
+
+		code := strconv.Itoa(sw.Code)
+		httpRequestDuration.WithLabelValues(r.Method, route, code).Observe(time.Since(start).Seconds())
+		if sw.Code >= http.StatusBadRequest {
+			httpRequestErrors.WithLabelValues(r.Method, route, code).Inc()
+		}
+	})
+}
+
+// Serve serves the metrics at /metrics on the port. It only returns if the
+// port can't be listened on.
+func Serve(port string) {
+	if port == "" {
+		return
+	}
+	mux := http.NewServeMux()
+	mux.Handle(Path, promhttp.Handler())
+	log.Info("Serving metrics", log.Fields{"port": port})
+	err := http.ListenAndServe(":"+port, mux)
+	log.Error("Metrics server stopped", log.Fields{"port": port, "error": err})
+}
@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestHandler(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc("/v2/projects/{project-name}", func(w http.ResponseWriter, r *http.Request) {
+		if mux.Vars(r)["project-name"] == "missing" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"metadata":{"name":"p1"}}`))
+	}).Methods("GET")
+	h := Handler(router, router)
+
+	for _, path := range []string{"/v2/projects/p1", "/v2/projects/p2", "/v2/projects/missing"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	route := "/v2/projects/{project-name}"
+	if n := testutil.ToFloat64(httpRequestErrors.WithLabelValues("GET", route, "404")); n != 1 {
+		t.Errorf("Expected 1 error for the route, got %v", n)
+	}
+	if n := testutil.CollectAndCount(httpRequestDuration); n != 2 {
+		t.Errorf("Expected latency for 2 status codes of the route, got %v", n)
+	}
+
+	// The metrics are not served with the API
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, Path, nil))
+	if w.Code != http.StatusNotFound || strings.Contains(w.Body.String(), "emco_http_request_duration_seconds") {
+		t.Errorf("Metrics returned %d with the API: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+// Package middleware chains the handlers wrapping the REST API of the EMCO
+// services.
+package middleware
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/gorilla/handlers"
+	"github.com/gorilla/mux"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/audit"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/auth"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/etag"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/metrics"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/tracing"
+)
+
+// Handler returns the handler of the REST API of the service: router wrapped
+// with the request log, the metrics, the tracing, the audit log, the
+// authorization and the ETags, in that order. The audit log records the
+// requests the authorization rejects.
+func Handler(service string, router *mux.Router) http.Handler {
+	h := etag.Handler(router)
+	h = auth.Handler(router, h)
+	h = audit.Handler(service, router, h)
+	h = tracing.Handler(router, h)
+	h = metrics.Handler(router, h)
+	return handlers.LoggingHandler(os.Stdout, h)
+}
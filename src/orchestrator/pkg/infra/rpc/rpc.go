@@ -13,6 +13,8 @@ import (
 
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/config"
 	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/metrics"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/tracing"
 	pkgerrors "github.com/pkg/errors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
@@ -216,6 +218,9 @@ func createClientConn(Host string, Port int) (*grpc.ClientConn, error) {
 		opts = append(opts, grpc.WithInsecure())
 	}
 
+	opts = append(opts, metrics.DialOptions()...)
+	opts = append(opts, tracing.DialOptions()...)
+
 	conn, err := grpc.Dial(serverAddr, opts...)
 	if err != nil {
 		pkgerrors.Wrap(err, "Grpc Client Initialization failed with error")
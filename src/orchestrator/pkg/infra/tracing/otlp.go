@@ -0,0 +1,212 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	pkgerrors "github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// otlpExporter sends the spans to an OTLP/HTTP endpoint, like the OpenTelemetry
+// collector, in the JSON encoding of OTLP. The gRPC and protobuf OTLP exporters
+// need newer gRPC releases than the ones the EMCO services are built with.
+type otlpExporter struct {
+	endpoint string
+	client   *http.Client
+}
+
+func newOTLPExporter(endpoint string) *otlpExporter {
+	// The traces path is the default if only the collector address is given
+	if u, err := url.Parse(endpoint); err == nil && (u.Path == "" || u.Path == "/") {
+		u.Path = "/v1/traces"
+		endpoint = u.String()
+	}
+	return &otlpExporter{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// The OTLP JSON types, see opentelemetry/proto/trace/v1/trace.proto
+type otlpTraces struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceId           string         `json:"traceId"`
+	SpanId            string         `json:"spanId"`
+	ParentSpanId      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Events            []otlpEvent    `json:"events,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano string         `json:"timeUnixNano"`
+	Name         string         `json:"name"`
+	Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string                 `json:"key"`
+	Value map[string]interface{} `json:"value"`
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func otlpAttributes(attrs []attribute.KeyValue) []otlpKeyValue {
+	kvs := make([]otlpKeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		var v map[string]interface{}
+		switch a.Value.Type() {
+		case attribute.BOOL:
+			v = map[string]interface{}{"boolValue": a.Value.AsBool()}
+		case attribute.INT64:
+			// 64 bit integers are strings in the JSON encoding
+			v = map[string]interface{}{"intValue": strconv.FormatInt(a.Value.AsInt64(), 10)}
+		case attribute.FLOAT64:
+			v = map[string]interface{}{"doubleValue": a.Value.AsFloat64()}
+		default:
+			v = map[string]interface{}{"stringValue": a.Value.Emit()}
+		}
+		kvs = append(kvs, otlpKeyValue{Key: string(a.Key), Value: v})
+	}
+	return kvs
+}
+
+func otlpSpanStatus(s sdktrace.Status) otlpStatus {
+	// The OTLP status codes are Unset, Ok, Error
+	switch s.Code {
+	case codes.Ok:
+		return otlpStatus{Code: 1}
+	case codes.Error:
+		return otlpStatus{Code: 2, Message: s.Description}
+	}
+	return otlpStatus{}
+}
+
+func toOTLP(spans []sdktrace.ReadOnlySpan) otlpTraces {
+	traces := otlpTraces{ResourceSpans: []otlpResourceSpans{}}
+	resources := map[string]int{}
+	scopes := map[string]map[string]int{}
+	for _, s := range spans {
+		res := ""
+		var attrs []attribute.KeyValue
+		if s.Resource() != nil {
+			res = s.Resource().Encoded(attribute.DefaultEncoder())
+			attrs = s.Resource().Attributes()
+		}
+		ri, ok := resources[res]
+		if !ok {
+			ri = len(traces.ResourceSpans)
+			resources[res] = ri
+			scopes[res] = map[string]int{}
+			traces.ResourceSpans = append(traces.ResourceSpans, otlpResourceSpans{
+				Resource: otlpResource{Attributes: otlpAttributes(attrs)},
+			})
+		}
+		rs := &traces.ResourceSpans[ri]
+		lib := s.InstrumentationLibrary()
+		si, ok := scopes[res][lib.Name]
+		if !ok {
+			si = len(rs.ScopeSpans)
+			scopes[res][lib.Name] = si
+			rs.ScopeSpans = append(rs.ScopeSpans, otlpScopeSpans{Scope: otlpScope{Name: lib.Name, Version: lib.Version}})
+		}
+
+		span := otlpSpan{
+			TraceId:           s.SpanContext().TraceID().String(),
+			SpanId:            s.SpanContext().SpanID().String(),
+			Name:              s.Name(),
+			Kind:              int(s.SpanKind()),
+			StartTimeUnixNano: unixNano(s.StartTime()),
+			EndTimeUnixNano:   unixNano(s.EndTime()),
+			Attributes:        otlpAttributes(s.Attributes()),
+			Status:            otlpSpanStatus(s.Status()),
+		}
+		if s.Parent().IsValid() {
+			span.ParentSpanId = s.Parent().SpanID().String()
+		}
+		for _, e := range s.Events() {
+			span.Events = append(span.Events, otlpEvent{
+				TimeUnixNano: unixNano(e.Time),
+				Name:         e.Name,
+				Attributes:   otlpAttributes(e.Attributes),
+			})
+		}
+		rs.ScopeSpans[si].Spans = append(rs.ScopeSpans[si].Spans, span)
+	}
+	return traces
+}
+
+// ExportSpans sends the spans to the endpoint
+func (e *otlpExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	b, err := json.Marshal(toOTLP(spans))
+	if err != nil {
+		return pkgerrors.Wrap(err, "Error encoding spans")
+	}
+	req, err := http.NewRequest(http.MethodPost, e.endpoint, bytes.NewReader(b))
+	if err != nil {
+		return pkgerrors.Wrap(err, "Error creating OTLP request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return pkgerrors.Wrap(err, "Error sending spans")
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return pkgerrors.New(fmt.Sprintf("OTLP endpoint returned %s", resp.Status))
+	}
+	return nil
+}
+
+// Shutdown has nothing to release
+func (e *otlpExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
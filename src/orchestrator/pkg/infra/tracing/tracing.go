@@ -0,0 +1,138 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+// Package tracing records the OpenTelemetry traces of the EMCO services. The
+// trace context is propagated over the REST API with Handler and over gRPC with
+// ServerOptions and DialOptions, using the W3C Trace Context headers.
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/config"
+	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/tracing"
+
+var (
+	tracer     = otel.Tracer(instrumentationName)
+	propagator = propagation.TraceContext{}
+	provider   *sdktrace.TracerProvider
+)
+
+// Init sets up the export of the traces of the service, as configured by
+// tracing-exporter: otlp sends them to the OTLP/HTTP tracing-endpoint, stdout
+// prints them. Spans are not recorded if no exporter is configured, but the
+// trace context is still propagated.
+func Init(service string) {
+	otel.SetTextMapPropagator(propagator)
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch t := config.GetConfiguration().TracingExporter; t {
+	case "":
+		return
+	case "otlp":
+		exporter = newOTLPExporter(config.GetConfiguration().TracingEndpoint)
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	default:
+		log.Error("Unknown tracing exporter, tracing is disabled", log.Fields{"exporter": t})
+		return
+	}
+	if err != nil {
+		log.Error("Failed to create tracing exporter, tracing is disabled", log.Fields{"error": err})
+		return
+	}
+
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(service))),
+	)
+	otel.SetTracerProvider(provider)
+	log.Info("Tracing enabled", log.Fields{"service": service, "exporter": config.GetConfiguration().TracingExporter})
+}
+
+// Shutdown exports the remaining spans
+func Shutdown() {
+	if provider == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := provider.Shutdown(ctx); err != nil {
+		log.Error("Failed to export the remaining spans", log.Fields{"error": err})
+	}
+}
+
+// Start starts a span, child of the span of ctx if any
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// Active reports whether ctx carries a span, which the spans started from ctx
+// are children of
+func Active(ctx context.Context) bool {
+	return ctx != nil && trace.SpanContextFromContext(ctx).IsValid()
+}
+
+// End ends the span, recording the error if the operation failed
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Detach returns a context carrying the span of ctx, which is not canceled with
+// ctx. It is used for the operations that outlive the request they started from.
+func Detach(ctx context.Context) context.Context {
+	return trace.ContextWithSpan(context.Background(), trace.SpanFromContext(ctx))
+}
+
+// Inject returns the trace context of ctx, to be stored with work that is
+// handled later
+func Inject(ctx context.Context) map[string]string {
+	carrier := mapCarrier{}
+	propagator.Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// Extract returns ctx with the trace context returned by Inject
+func Extract(ctx context.Context, carrier map[string]string) context.Context {
+	return propagator.Extract(ctx, mapCarrier(carrier))
+}
+
+// mapCarrier adapts a map to the propagator
+type mapCarrier map[string]string
+
+func (c mapCarrier) Get(key string) string {
+	return c[key]
+}
+
+func (c mapCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+func (c mapCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
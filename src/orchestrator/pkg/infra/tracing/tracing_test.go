@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	pkgerrors "github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const (
+	traceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	parentID    = "00f067aa0ba902b7"
+	traceparent = "00-" + traceID + "-" + parentID + "-01"
+)
+
+// The global tracer keeps the first provider set, the tests record with their
+// own span processor
+var testProvider = sdktrace.NewTracerProvider()
+
+func init() {
+	otel.SetTracerProvider(testProvider)
+}
+
+func record(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	testProvider.RegisterSpanProcessor(recorder)
+	t.Cleanup(func() { testProvider.UnregisterSpanProcessor(recorder) })
+	return recorder
+}
+
+func TestHandler(t *testing.T) {
+	recorder := record(t)
+
+	var propagated map[string]string
+	router := mux.NewRouter()
+	router.HandleFunc("/v2/projects/{project-name}", func(w http.ResponseWriter, r *http.Request) {
+		propagated = Inject(r.Context())
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}).Methods("GET")
+
+	req := httptest.NewRequest(http.MethodGet, "/v2/projects/p1", nil)
+	req.Header.Set("traceparent", traceparent)
+	Handler(router, router).ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	s := spans[0]
+	if s.Name() != "GET /v2/projects/{project-name}" {
+		t.Errorf("Unexpected span name %s", s.Name())
+	}
+	if s.SpanContext().TraceID().String() != traceID || s.Parent().SpanID().String() != parentID {
+		t.Errorf("Span does not continue the trace of the caller: %v", s.Parent())
+	}
+	if s.Status().Code.String() != "Error" {
+		t.Errorf("Expected the span of a 500 response to fail, got %v", s.Status())
+	}
+	if !strings.Contains(propagated["traceparent"], traceID+"-"+s.SpanContext().SpanID().String()) {
+		t.Errorf("Trace context not propagated from the handler: %v", propagated)
+	}
+}
+
+func TestInjectExtract(t *testing.T) {
+	record(t)
+
+	if carrier := Inject(context.Background()); carrier != nil {
+		t.Errorf("Expected no trace context, got %v", carrier)
+	}
+
+	ctx, span := Start(Extract(context.Background(), map[string]string{"traceparent": traceparent}), "test")
+	defer span.End()
+	// The detached context keeps the span once the original is canceled
+	cctx, cancel := context.WithCancel(ctx)
+	dctx := Detach(cctx)
+	cancel()
+	if dctx.Err() != nil {
+		t.Errorf("Detached context was canceled")
+	}
+	if carrier := Inject(dctx); !strings.Contains(carrier["traceparent"], traceID) {
+		t.Errorf("Trace context lost, got %v", carrier)
+	}
+}
+
+func TestActive(t *testing.T) {
+	record(t)
+
+	if Active(nil) || Active(context.Background()) {
+		t.Errorf("Context without span reported active")
+	}
+	ctx, span := Start(Extract(context.Background(), map[string]string{"traceparent": traceparent}), "test")
+	defer span.End()
+	if !Active(ctx) {
+		t.Errorf("Context with span not reported active")
+	}
+}
+
+func TestOTLPExporter(t *testing.T) {
+	recorder := record(t)
+	_, span := Start(Extract(context.Background(), map[string]string{"traceparent": traceparent}), "MakeAppContext")
+	End(span, pkgerrors.New("Error in making AppContext"))
+
+	var path string
+	var traces otlpTraces
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&traces); err != nil {
+			t.Errorf("Error decoding the spans: %s", err)
+		}
+	}))
+	defer server.Close()
+
+	err := newOTLPExporter(server.URL).ExportSpans(context.Background(), recorder.Ended())
+	if err != nil {
+		t.Fatalf("ExportSpans returned an error (%s)", err)
+	}
+	if path != "/v1/traces" {
+		t.Errorf("Spans sent to %s", path)
+	}
+	if len(traces.ResourceSpans) != 1 || len(traces.ResourceSpans[0].ScopeSpans) != 1 ||
+		len(traces.ResourceSpans[0].ScopeSpans[0].Spans) != 1 {
+		t.Fatalf("Unexpected spans %+v", traces)
+	}
+	s := traces.ResourceSpans[0].ScopeSpans[0].Spans[0]
+	if s.Name != "MakeAppContext" || s.TraceId != traceID || s.ParentSpanId != parentID {
+		t.Errorf("Unexpected span %+v", s)
+	}
+	if s.Status.Code != 2 || s.Status.Message != "Error in making AppContext" || len(s.Events) != 1 {
+		t.Errorf("Error not recorded in span %+v", s)
+	}
+}
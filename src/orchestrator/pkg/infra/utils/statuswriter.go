@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package utils
+
+import "net/http"
+
+// StatusWriter records the status code of a response for the handlers
+// wrapping the REST API
+type StatusWriter struct {
+	http.ResponseWriter
+	// Code is the status code of the response, 200 until the header is written
+	Code        int
+	WroteHeader bool
+	// BeforeHeader, if set, is called with the status code before the header
+	// is written, to add headers to the response. It may change Code to
+	// change the status code written.
+	BeforeHeader func(code int)
+}
+
+// NewStatusWriter returns the StatusWriter of w
+func NewStatusWriter(w http.ResponseWriter) *StatusWriter {
+	return &StatusWriter{ResponseWriter: w, Code: http.StatusOK}
+}
+
+func (w *StatusWriter) WriteHeader(code int) {
+	if !w.WroteHeader {
+		w.Code = code
+		w.WroteHeader = true
+		if w.BeforeHeader != nil {
+			w.BeforeHeader(code)
+		}
+		code = w.Code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *StatusWriter) Write(b []byte) (int, error) {
+	if !w.WroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush lets the streamed responses through
+func (w *StatusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
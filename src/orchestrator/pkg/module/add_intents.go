@@ -9,6 +9,7 @@ genericPlacementIntents to deployementIntentGroup
 */
 
 import (
+	"context"
 	"encoding/json"
 	"reflect"
 	"strings"
@@ -49,6 +50,7 @@ type IntentManager interface {
 	GetAllIntents(p, ca, v, di string) (ListOfIntents, error)
 	GetIntentByName(i, p, ca, v, di string) (IntentSpecData, error)
 	DeleteIntent(i string, p string, ca string, v string, di string) error
+	WithContext(ctx context.Context) IntentManager
 }
 
 // IntentKey consists of Name if the intent, Project name, CompositeApp name,
@@ -75,6 +77,8 @@ func (ik IntentKey) String() string {
 type IntentClient struct {
 	storeName   string
 	tagMetaData string
+	// ctx is the context of the request using the client
+	ctx context.Context
 }
 
 // NewIntentClient returns an instance of AddIntentClient
@@ -85,6 +89,14 @@ func NewIntentClient() *IntentClient {
 	}
 }
 
+// WithContext returns a copy of the client making the database calls of
+// the intent of a request with its context
+func (c *IntentClient) WithContext(ctx context.Context) IntentManager {
+	n := *c
+	n.ctx = ctx
+	return &n
+}
+
 /*
 AddIntent adds a given intent to the deployment-intent-group and stores in the db.
 Other input parameters for it - projectName, compositeAppName, version, DeploymentIntentgroupName
@@ -123,7 +135,7 @@ func (c *IntentClient) AddIntent(a Intent, p string, ca string, v string, di str
 		DeploymentIntentGroup: di,
 	}
 
-	err = db.DBconn.Insert(c.storeName, akey, nil, c.tagMetaData, a)
+	err = db.WithContext(c.ctx).Insert(c.storeName, akey, nil, c.tagMetaData, a)
 	if err != nil {
 		return Intent{}, pkgerrors.Wrap(err, "Create DB entry error")
 	}
@@ -144,7 +156,7 @@ func (c *IntentClient) GetIntent(i string, p string, ca string, v string, di str
 		DeploymentIntentGroup: di,
 	}
 
-	result, err := db.DBconn.Find(c.storeName, k, c.tagMetaData)
+	result, err := db.WithContext(c.ctx).Find(c.storeName, k, c.tagMetaData)
 	if err != nil {
 		return Intent{}, pkgerrors.Wrap(err, "db Find error")
 	}
@@ -173,7 +185,7 @@ func (c IntentClient) GetIntentByName(i string, p string, ca string, v string, d
 		Version:               v,
 		DeploymentIntentGroup: di,
 	}
-	result, err := db.DBconn.Find(c.storeName, k, c.tagMetaData)
+	result, err := db.WithContext(c.ctx).Find(c.storeName, k, c.tagMetaData)
 	if err != nil {
 		return IntentSpecData{}, pkgerrors.Wrap(err, "db Find error")
 	}
@@ -229,7 +241,7 @@ func (c IntentClient) DeleteIntent(i string, p string, ca string, v string, di s
 		DeploymentIntentGroup: di,
 	}
 
-	err := db.DBconn.Remove(c.storeName, k)
+	err := db.WithContext(c.ctx).Remove(c.storeName, k)
 	if err != nil {
 		if strings.Contains(err.Error(), "Error finding:") {
 			return pkgerrors.Wrap(err, "db Remove error - not found")
@@ -4,10 +4,12 @@
 package module
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+	"github.com/open-ness/EMCO/src/orchestrator/utils/helm"
 
 	pkgerrors "github.com/pkg/errors"
 )
@@ -15,6 +17,13 @@ import (
 // App contains metadata for Apps
 type App struct {
 	Metadata AppMetaData `json:"metadata"`
+	Spec     AppSpec     `json:"spec,omitempty"`
+}
+
+// AppSpec contains the chart of an App fetched from a repository, instead of
+// the uploaded file
+type AppSpec struct {
+	Chart *ChartReference `json:"chart,omitempty"`
 }
 
 //AppMetaData contains the parameters needed for Apps
@@ -23,6 +32,9 @@ type AppMetaData struct {
 	Description string `json:"description"`
 	UserData1   string `json:"userData1"`
 	UserData2   string `json:"userData2"`
+	// Type tells how the file of the App is rendered: helm (the default),
+	// kustomize or manifests
+	Type string `json:"type,omitempty"`
 }
 
 //AppContent contains fileContent
@@ -55,6 +67,7 @@ type AppManager interface {
 	GetAppContent(name string, p string, cN string, cV string) (AppContent, error)
 	GetApps(p string, cN string, cV string) ([]App, error)
 	DeleteApp(name string, p string, cN string, cV string) error
+	WithContext(ctx context.Context) AppManager
 }
 
 // AppClient implements the AppManager
@@ -62,6 +75,8 @@ type AppManager interface {
 type AppClient struct {
 	storeName           string
 	tagMeta, tagContent string
+	// ctx is the context of the request using the client
+	ctx context.Context
 }
 
 // NewAppClient returns an instance of the AppClient
@@ -74,6 +89,14 @@ func NewAppClient() *AppClient {
 	}
 }
 
+// WithContext returns a copy of the client making the database calls of
+// the app of a request with its context
+func (v *AppClient) WithContext(ctx context.Context) AppManager {
+	c := *v
+	c.ctx = ctx
+	return &c
+}
+
 // CreateApp creates a new collection based on the App
 func (v *AppClient) CreateApp(a App, ac AppContent, p string, cN string, cV string, exists bool) (App, error) {
 
@@ -97,18 +120,47 @@ func (v *AppClient) CreateApp(a App, ac AppContent, p string, cN string, cV stri
 		return App{}, pkgerrors.New("Unable to find the project")
 	}
 
+	switch a.Metadata.Type {
+	case "", helm.AppTypeHelm, helm.AppTypeKustomize, helm.AppTypeManifests:
+	default:
+		return App{}, pkgerrors.Errorf("Invalid app type %s", a.Metadata.Type)
+	}
+
+	stored := a
+	if a.Spec.Chart != nil {
+		if a.Metadata.Type != "" && a.Metadata.Type != helm.AppTypeHelm {
+			return App{}, pkgerrors.New("Invalid chart reference: only helm apps reference a chart")
+		}
+		err = a.Spec.Chart.validate()
+		if err != nil {
+			return App{}, err
+		}
+		// The password of the chart is only decrypted to fetch the chart
+		chart, err := a.Spec.Chart.sealPassword()
+		if err != nil {
+			return App{}, err
+		}
+		stored.Spec.Chart = &chart
+	}
+
 	//check if CompositeApp with version exists
 	_, err = NewCompositeAppClient().GetCompositeApp(cN, cV, p)
 	if err != nil {
 		return App{}, pkgerrors.New("Unable to find the composite app with version")
 	}
 
-	err = db.DBconn.Insert(v.storeName, key, nil, v.tagMeta, a)
+	release, err := checkChartStorageQuota(p, key, ac)
+	if err != nil {
+		return App{}, err
+	}
+	defer release()
+
+	err = db.WithContext(v.ctx).Insert(v.storeName, key, nil, v.tagMeta, stored)
 	if err != nil {
 		return App{}, pkgerrors.Wrap(err, "Creating DB Entry")
 	}
 
-	err = db.DBconn.Insert(v.storeName, key, nil, v.tagContent, ac)
+	err = db.WithContext(v.ctx).Insert(v.storeName, key, nil, v.tagContent, ac)
 	if err != nil {
 		return App{}, pkgerrors.Wrap(err, "Creating DB Entry")
 	}
@@ -126,7 +178,7 @@ func (v *AppClient) GetApp(name string, p string, cN string, cV string) (App, er
 		CompositeApp:        cN,
 		CompositeAppVersion: cV,
 	}
-	value, err := db.DBconn.Find(v.storeName, key, v.tagMeta)
+	value, err := db.WithContext(v.ctx).Find(v.storeName, key, v.tagMeta)
 	if err != nil {
 		return App{}, pkgerrors.Wrap(err, "db Find error")
 	} else if len(value) == 0 {
@@ -156,7 +208,7 @@ func (v *AppClient) GetAppContent(name string, p string, cN string, cV string) (
 		CompositeApp:        cN,
 		CompositeAppVersion: cV,
 	}
-	value, err := db.DBconn.Find(v.storeName, key, v.tagContent)
+	value, err := db.WithContext(v.ctx).Find(v.storeName, key, v.tagContent)
 	if err != nil {
 		return AppContent{}, pkgerrors.Wrap(err, "db Find error")
 	} else if len(value) == 0 {
@@ -216,7 +268,7 @@ func (v *AppClient) DeleteApp(name string, p string, cN string, cV string) error
 		CompositeApp:        cN,
 		CompositeAppVersion: cV,
 	}
-	err := db.DBconn.Remove(v.storeName, key)
+	err := db.WithContext(v.ctx).Remove(v.storeName, key)
 	if err != nil {
 		if strings.Contains(err.Error(), "Error finding:") {
 			return pkgerrors.Wrap(err, "db Remove error - not found")
@@ -4,8 +4,13 @@
 package module
 
 import (
+	"context"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
 	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/tracing"
 	pkgerrors "github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type Instantiator struct {
@@ -17,7 +22,19 @@ type Instantiator struct {
 }
 
 // MakeAppContext shall make an app context and store the app context into etcd. This shall return contextForCompositeApp
-func (i *Instantiator) MakeAppContext() (contextForCompositeApp, error) {
+func (i *Instantiator) MakeAppContext(ctx context.Context) (contextForCompositeApp, error) {
+	sctx, span := tracing.Start(ctx, "MakeAppContext", attribute.String("deploymentIntentGroup", i.deploymentIntent))
+	cca, err := i.makeAppContext(sctx)
+	tracing.End(span, err)
+	if err != nil {
+		return cca, err
+	}
+	// The next calls to etcd are traced in the span of the caller
+	cca.context = cca.context.WithContext(ctx)
+	return cca, nil
+}
+
+func (i *Instantiator) makeAppContext(ctx context.Context) (contextForCompositeApp, error) {
 
 	rName := i.deploymentIntenetGrp.Spec.Version //rName is releaseName
 	overrideValues := i.deploymentIntenetGrp.Spec.OverrideValuesObj
@@ -41,15 +58,67 @@ func (i *Instantiator) MakeAppContext() (contextForCompositeApp, error) {
 		return contextForCompositeApp{}, err
 	}
 
-	cca, err := makeAppContextForCompositeApp(i.project, i.compositeApp, i.compAppVersion, rName, i.deploymentIntent, namespace, level)
+	cca, err := makeAppContextForCompositeApp(ctx, i.project, i.compositeApp, i.compAppVersion, rName, i.deploymentIntent, namespace, level)
 	if err != nil {
 		return contextForCompositeApp{}, err
 	}
 
-	err = storeAppContextIntoRunTimeDB(allApps, cca, overrideValues, dcmClusters, i.project, i.compositeApp, i.compAppVersion, rName, cp, gIntent, i.deploymentIntent, namespace)
+	// The AppContext is of no use if it can't be completed
+	err = i.completeAppContext(cca, allApps, overrideValues, dcmClusters, gIntent, namespace, rName, cp)
 	if err != nil {
-		return contextForCompositeApp{}, pkgerrors.Wrap(err, "Error in storeAppContextIntoETCd")
+		deleteAppContext(cca.context)
+		return contextForCompositeApp{}, err
 	}
 
 	return cca, nil
 }
+
+// completeAppContext adds the settings for rsync and the resources of the apps to the AppContext
+func (i *Instantiator) completeAppContext(cca contextForCompositeApp, allApps []App, overrideValues []OverrideValues, dcmClusters []Cluster, gIntent, namespace, rName, cp string) error {
+	// Let rsync know what to do with resources that drift on the clusters
+	reconcile := i.deploymentIntenetGrp.Spec.Reconcile
+	if reconcile == "" {
+		reconcile = appcontext.ReconcileModeEnum.Off
+	}
+	_, err := cca.context.AddLevelValue(cca.compositeAppHandle, appcontext.ReconcileKey, reconcile)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Error adding the reconcile mode")
+	}
+
+	// Let rsync know what to do with orphaned resources on the clusters
+	prune := i.deploymentIntenetGrp.Spec.Prune
+	if prune == "" {
+		prune = appcontext.PruneModeEnum.DryRun
+	}
+	_, err = cca.context.AddLevelValue(cca.compositeAppHandle, appcontext.PruneKey, prune)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Error adding the prune mode")
+	}
+
+	// Let rsync know to take over the fields owned by other managers
+	if i.deploymentIntenetGrp.Spec.ForceApply {
+		_, err = cca.context.AddLevelValue(cca.compositeAppHandle, appcontext.ForceApplyKey, true)
+		if err != nil {
+			return pkgerrors.Wrap(err, "Error adding the force apply flag")
+		}
+	}
+
+	// Let rsync know how long to retry the clusters
+	var lcRetryPolicy *RetryPolicy
+	if lc, err := NewLogicalCloudClient().Get(i.project, i.deploymentIntenetGrp.Spec.LogicalCloud); err == nil {
+		lcRetryPolicy = lc.Specification.RetryPolicy
+	}
+	if rp := getRetryPolicy(i.deploymentIntenetGrp.Spec.RetryPolicy, lcRetryPolicy); rp != nil {
+		_, err = cca.context.AddLevelValue(cca.compositeAppHandle, appcontext.RetryPolicyKey, *rp)
+		if err != nil {
+			return pkgerrors.Wrap(err, "Error adding the retry policy")
+		}
+	}
+
+	err = storeAppContextIntoRunTimeDB(allApps, cca, overrideValues, dcmClusters, i.project, i.compositeApp, i.compAppVersion, rName, cp, gIntent, i.deploymentIntent, namespace)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Error in storeAppContextIntoETCd")
+	}
+
+	return nil
+}
@@ -0,0 +1,243 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package module
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// Operational status an app can wait for on the app it depends on
+const (
+	// AppOpStatusDeployed - all resources of the app are applied on all clusters
+	AppOpStatusDeployed = "Deployed"
+	// AppOpStatusReady - all resources of the app are reported ready on all clusters
+	AppOpStatusReady = "Ready"
+)
+
+// AppDependency declares that an app of a composite app depends on another app
+type AppDependency struct {
+	MetaData AppDependencyMetaData `json:"metadata"`
+	Spec     AppDependencySpec     `json:"spec"`
+}
+
+// AppDependencyMetaData has Name, description, userdata1, userdata2
+type AppDependencyMetaData struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	UserData1   string `json:"userData1"`
+	UserData2   string `json:"userData2"`
+}
+
+// AppDependencySpec has the app depended upon, the status to wait for
+// and an optional wait time in seconds after the status is reached
+type AppDependencySpec struct {
+	AppName  string `json:"app"`
+	OpStatus string `json:"opStatus"`
+	Wait     int    `json:"wait,omitempty"`
+}
+
+// AppDependencyKey is the key structure that is used in the database
+type AppDependencyKey struct {
+	App                 string `json:"app"`
+	Project             string `json:"project"`
+	CompositeApp        string `json:"compositeapp"`
+	CompositeAppVersion string `json:"compositeappversion"`
+	Name                string `json:"appdependency"`
+}
+
+// We will use json marshalling to convert to string to
+// preserve the underlying structure.
+func (k AppDependencyKey) String() string {
+	out, err := json.Marshal(k)
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+// AppDependencyManager is an interface exposes the AppDependency functionality
+type AppDependencyManager interface {
+	CreateAppDependency(d AppDependency, p string, ca string, v string, app string, exists bool) (AppDependency, error)
+	GetAppDependency(name string, p string, ca string, v string, app string) (AppDependency, error)
+	GetAllAppDependency(p string, ca string, v string, app string) ([]AppDependency, error)
+	DeleteAppDependency(name string, p string, ca string, v string, app string) error
+	WithContext(ctx context.Context) AppDependencyManager
+}
+
+// AppDependencyClient implements the AppDependencyManager
+type AppDependencyClient struct {
+	storeName string
+	tagMeta   string
+	// ctx is the context of the request using the client
+	ctx context.Context
+}
+
+// NewAppDependencyClient returns an instance of the AppDependencyClient
+func NewAppDependencyClient() *AppDependencyClient {
+	return &AppDependencyClient{
+		storeName: "orchestrator",
+		tagMeta:   "appdependency",
+	}
+}
+
+// WithContext returns a copy of the client making the database calls of
+// the app dependency of a request with its context
+func (c *AppDependencyClient) WithContext(ctx context.Context) AppDependencyManager {
+	n := *c
+	n.ctx = ctx
+	return &n
+}
+
+// CreateAppDependency creates or updates the dependency of an app on another app of the same composite app
+func (c *AppDependencyClient) CreateAppDependency(d AppDependency, p string, ca string, v string, app string, exists bool) (AppDependency, error) {
+
+	key := AppDependencyKey{
+		Name:                d.MetaData.Name,
+		App:                 app,
+		Project:             p,
+		CompositeApp:        ca,
+		CompositeAppVersion: v,
+	}
+
+	_, err := c.GetAppDependency(d.MetaData.Name, p, ca, v, app)
+	if err == nil && !exists {
+		return AppDependency{}, pkgerrors.New("AppDependency already exists")
+	}
+
+	_, err = NewAppClient().GetApp(app, p, ca, v)
+	if err != nil {
+		return AppDependency{}, pkgerrors.New("Unable to find the app")
+	}
+
+	if d.Spec.AppName == app {
+		return AppDependency{}, pkgerrors.New("An app can't depend on itself")
+	}
+	_, err = NewAppClient().GetApp(d.Spec.AppName, p, ca, v)
+	if err != nil {
+		return AppDependency{}, pkgerrors.New("Unable to find the dependent app")
+	}
+
+	if d.Spec.OpStatus != AppOpStatusDeployed && d.Spec.OpStatus != AppOpStatusReady {
+		return AppDependency{}, pkgerrors.New("Invalid opStatus, must be Deployed or Ready")
+	}
+
+	// A dependency loop would block the apps forever
+	if err := c.checkDependencyLoop(d, p, ca, v, app); err != nil {
+		return AppDependency{}, err
+	}
+
+	err = db.WithContext(c.ctx).Insert(c.storeName, key, nil, c.tagMeta, d)
+	if err != nil {
+		return AppDependency{}, pkgerrors.Wrap(err, "Create DB entry error")
+	}
+
+	return d, nil
+}
+
+// checkDependencyLoop walks the dependencies of the app depended upon to make sure they
+// don't lead back to the app
+func (c *AppDependencyClient) checkDependencyLoop(d AppDependency, p string, ca string, v string, app string) error {
+	visited := map[string]bool{}
+	pending := []string{d.Spec.AppName}
+	for len(pending) > 0 {
+		next := pending[0]
+		pending = pending[1:]
+		if next == app {
+			return pkgerrors.New("AppDependency creates a dependency loop")
+		}
+		if visited[next] {
+			continue
+		}
+		visited[next] = true
+		deps, err := c.GetAllAppDependency(p, ca, v, next)
+		if err != nil {
+			return err
+		}
+		for _, dep := range deps {
+			pending = append(pending, dep.Spec.AppName)
+		}
+	}
+	return nil
+}
+
+// GetAppDependency returns the AppDependency for the app
+func (c *AppDependencyClient) GetAppDependency(name string, p string, ca string, v string, app string) (AppDependency, error) {
+	key := AppDependencyKey{
+		Name:                name,
+		App:                 app,
+		Project:             p,
+		CompositeApp:        ca,
+		CompositeAppVersion: v,
+	}
+
+	value, err := db.WithContext(c.ctx).Find(c.storeName, key, c.tagMeta)
+	if err != nil {
+		return AppDependency{}, pkgerrors.Wrap(err, "db Find error")
+	} else if len(value) == 0 {
+		return AppDependency{}, pkgerrors.New("AppDependency not found")
+	}
+
+	d := AppDependency{}
+	err = db.DBconn.Unmarshal(value[0], &d)
+	if err != nil {
+		return AppDependency{}, pkgerrors.Wrap(err, "Unmarshaling Value")
+	}
+	return d, nil
+}
+
+// GetAllAppDependency returns all the AppDependency for the app
+func (c *AppDependencyClient) GetAllAppDependency(p string, ca string, v string, app string) ([]AppDependency, error) {
+	key := AppDependencyKey{
+		Name:                "",
+		App:                 app,
+		Project:             p,
+		CompositeApp:        ca,
+		CompositeAppVersion: v,
+	}
+
+	values, err := db.DBconn.Find(c.storeName, key, c.tagMeta)
+	if err != nil {
+		return []AppDependency{}, pkgerrors.Wrap(err, "db Find error")
+	}
+
+	resp := []AppDependency{}
+	for _, value := range values {
+		d := AppDependency{}
+		err = db.DBconn.Unmarshal(value, &d)
+		if err != nil {
+			return []AppDependency{}, pkgerrors.Wrap(err, "Unmarshaling Value")
+		}
+		resp = append(resp, d)
+	}
+	return resp, nil
+}
+
+// DeleteAppDependency deletes the AppDependency from the database
+func (c *AppDependencyClient) DeleteAppDependency(name string, p string, ca string, v string, app string) error {
+	key := AppDependencyKey{
+		Name:                name,
+		App:                 app,
+		Project:             p,
+		CompositeApp:        ca,
+		CompositeAppVersion: v,
+	}
+
+	err := db.WithContext(c.ctx).Remove(c.storeName, key)
+	if err != nil {
+		if strings.Contains(err.Error(), "Error finding:") {
+			return pkgerrors.Wrap(err, "db Remove error - not found")
+		} else if strings.Contains(err.Error(), "Can't delete parent without deleting child") {
+			return pkgerrors.Wrap(err, "db Remove error - conflict")
+		} else {
+			return pkgerrors.Wrap(err, "db Remove error - general")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package module
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+)
+
+// appDependencyMockDB returns an empty result instead of an error when
+// nothing is found, like the real database does
+type appDependencyMockDB struct {
+	*db.MockDB
+}
+
+func (m appDependencyMockDB) Find(table string, key db.Key, tag string) ([][]byte, error) {
+	r, err := m.MockDB.Find(table, key, tag)
+	if err != nil && strings.Contains(err.Error(), "Record not found") {
+		return nil, nil
+	}
+	return r, err
+}
+
+func appDependencyTestDB() appDependencyMockDB {
+	items := map[string]map[string][]byte{}
+	for _, app := range []string{"app1", "app2", "app3"} {
+		items[AppKey{App: app, Project: "testProject", CompositeApp: "testCompositeApp", CompositeAppVersion: "v1"}.String()] = map[string][]byte{
+			"appmetadata": []byte("{\"metadata\":{\"name\":\"" + app + "\"}}"),
+		}
+	}
+	items[AppDependencyKey{Name: "dep1", App: "app2", Project: "testProject", CompositeApp: "testCompositeApp", CompositeAppVersion: "v1"}.String()] = map[string][]byte{
+		"appdependency": []byte("{\"metadata\":{\"name\":\"dep1\"},\"spec\":{\"app\":\"app1\",\"opStatus\":\"Ready\"}}"),
+	}
+	return appDependencyMockDB{&db.MockDB{Items: []map[string]map[string][]byte{items}}}
+}
+
+func TestCreateAppDependency(t *testing.T) {
+	testCases := []struct {
+		label         string
+		app           string
+		inp           AppDependency
+		expectedError string
+	}{
+		{
+			label: "Create AppDependency",
+			app:   "app3",
+			inp: AppDependency{
+				MetaData: AppDependencyMetaData{Name: "dep2"},
+				Spec:     AppDependencySpec{AppName: "app2", OpStatus: AppOpStatusDeployed, Wait: 10},
+			},
+		},
+		{
+			label: "AppDependency already exists",
+			app:   "app2",
+			inp: AppDependency{
+				MetaData: AppDependencyMetaData{Name: "dep1"},
+				Spec:     AppDependencySpec{AppName: "app1", OpStatus: AppOpStatusReady},
+			},
+			expectedError: "already exists",
+		},
+		{
+			label: "Unknown dependent app",
+			app:   "app3",
+			inp: AppDependency{
+				MetaData: AppDependencyMetaData{Name: "dep2"},
+				Spec:     AppDependencySpec{AppName: "app4", OpStatus: AppOpStatusReady},
+			},
+			expectedError: "Unable to find the dependent app",
+		},
+		{
+			label: "App depends on itself",
+			app:   "app3",
+			inp: AppDependency{
+				MetaData: AppDependencyMetaData{Name: "dep2"},
+				Spec:     AppDependencySpec{AppName: "app3", OpStatus: AppOpStatusReady},
+			},
+			expectedError: "itself",
+		},
+		{
+			label: "Invalid opStatus",
+			app:   "app3",
+			inp: AppDependency{
+				MetaData: AppDependencyMetaData{Name: "dep2"},
+				Spec:     AppDependencySpec{AppName: "app1", OpStatus: "Running"},
+			},
+			expectedError: "Invalid opStatus",
+		},
+		{
+			label: "Dependency loop",
+			app:   "app1",
+			inp: AppDependency{
+				MetaData: AppDependencyMetaData{Name: "dep2"},
+				Spec:     AppDependencySpec{AppName: "app2", OpStatus: AppOpStatusDeployed},
+			},
+			expectedError: "dependency loop",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			db.DBconn = appDependencyTestDB()
+			got, err := NewAppDependencyClient().CreateAppDependency(testCase.inp, "testProject", "testCompositeApp", "v1", testCase.app, false)
+			if err != nil {
+				if testCase.expectedError == "" {
+					t.Fatalf("CreateAppDependency returned an unexpected error %s", err)
+				}
+				if strings.Contains(err.Error(), testCase.expectedError) == false {
+					t.Fatalf("CreateAppDependency returned an unexpected error %s", err)
+				}
+			} else {
+				if testCase.expectedError != "" {
+					t.Fatalf("CreateAppDependency didn't return the expected error %s", testCase.expectedError)
+				}
+				if reflect.DeepEqual(testCase.inp, got) == false {
+					t.Errorf("CreateAppDependency returned unexpected body: got %v; expected %v", got, testCase.inp)
+				}
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ Adding/Querying AppIntents for each application in the composite-app
 */
 
 import (
+	"context"
 	"encoding/json"
 	"reflect"
 	"strings"
@@ -46,6 +47,7 @@ type AppIntentManager interface {
 	GetAllIntentsByApp(aN, p, ca, v, i, digName string) (SpecData, error)
 	GetAllAppIntents(p, ca, v, i, digName string) ([]AppIntent, error)
 	DeleteAppIntent(ai string, p string, ca string, v string, i string, digName string) error
+	WithContext(ctx context.Context) AppIntentManager
 }
 
 //AppIntentQueryKey required for query
@@ -100,6 +102,8 @@ func (ak AppIntentKey) String() string {
 type AppIntentClient struct {
 	storeName   string
 	tagMetaData string
+	// ctx is the context of the request using the client
+	ctx context.Context
 }
 
 // NewAppIntentClient returns an instance of AppIntentClient
@@ -110,6 +114,14 @@ func NewAppIntentClient() *AppIntentClient {
 	}
 }
 
+// WithContext returns a copy of the client making the database calls of
+// the app intent of a request with its context
+func (c *AppIntentClient) WithContext(ctx context.Context) AppIntentManager {
+	n := *c
+	n.ctx = ctx
+	return &n
+}
+
 // CreateAppIntent creates an entry for AppIntent in the db.
 // Other input parameters for it - projectName, compositeAppName, version, intentName and deploymentIntentGroupName.
 func (c *AppIntentClient) CreateAppIntent(a AppIntent, p string, ca string, v string, i string, digName string) (AppIntent, error) {
@@ -157,7 +169,7 @@ func (c *AppIntentClient) CreateAppIntent(a AppIntent, p string, ca string, v st
 		AppName: a.Spec.AppName,
 	}
 
-	err = db.DBconn.Insert(c.storeName, akey, qkey, c.tagMetaData, a)
+	err = db.WithContext(c.ctx).Insert(c.storeName, akey, qkey, c.tagMetaData, a)
 	if err != nil {
 		return AppIntent{}, pkgerrors.Wrap(err, "Create DB entry error")
 	}
@@ -177,7 +189,7 @@ func (c *AppIntentClient) GetAppIntent(ai string, p string, ca string, v string,
 		DeploymentIntentGroupName: digName,
 	}
 
-	result, err := db.DBconn.Find(c.storeName, k, c.tagMetaData)
+	result, err := db.WithContext(c.ctx).Find(c.storeName, k, c.tagMetaData)
 	if err != nil {
 		return AppIntent{}, pkgerrors.Wrap(err, "db Find error")
 	}
@@ -270,7 +282,7 @@ func (c *AppIntentClient) DeleteAppIntent(ai string, p string, ca string, v stri
 		DeploymentIntentGroupName: digName,
 	}
 
-	err := db.DBconn.Remove(c.storeName, k)
+	err := db.WithContext(c.ctx).Remove(c.storeName, k)
 	if err != nil {
 		if strings.Contains(err.Error(), "Error finding:") {
 			return pkgerrors.Wrap(err, "db Remove error - not found")
@@ -4,6 +4,7 @@
 package module
 
 import (
+	"context"
 	"strings"
 
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
@@ -65,6 +66,7 @@ type AppProfileManager interface {
 	GetAppProfileContent(project, compositeApp, compositeAppVersion, compositeProfile, profile string) (AppProfileContent, error)
 	GetAppProfileContentByApp(project, compositeApp, compositeAppVersion, compositeProfile, appName string) (AppProfileContent, error)
 	DeleteAppProfile(project, compositeApp, compositeAppVersion, compositeProfile, profile string) error
+	WithContext(ctx context.Context) AppProfileManager
 }
 
 // AppProfileClient implements the Manager
@@ -73,6 +75,8 @@ type AppProfileClient struct {
 	storeName  string
 	tagMeta    string
 	tagContent string
+	// ctx is the context of the request using the client
+	ctx context.Context
 }
 
 // NewAppProfileClient returns an instance of the AppProfileClient
@@ -85,6 +89,14 @@ func NewAppProfileClient() *AppProfileClient {
 	}
 }
 
+// WithContext returns a copy of the client making the database calls of
+// the app profile of a request with its context
+func (c *AppProfileClient) WithContext(ctx context.Context) AppProfileManager {
+	n := *c
+	n.ctx = ctx
+	return &n
+}
+
 // CreateAppProfile creates an entry for AppProfile in the database.
 func (c *AppProfileClient) CreateAppProfile(project, compositeApp, compositeAppVersion, compositeProfile string, ap AppProfile, ac AppProfileContent, exists bool) (AppProfile, error) {
 	key := AppProfileKey{
@@ -116,11 +128,11 @@ func (c *AppProfileClient) CreateAppProfile(project, compositeApp, compositeAppV
 
 	// TODO: (after app api is ready) check that the app Spec.AppName exists as part of the composite app
 
-	err = db.DBconn.Insert(c.storeName, key, qkey, c.tagMeta, ap)
+	err = db.WithContext(c.ctx).Insert(c.storeName, key, qkey, c.tagMeta, ap)
 	if err != nil {
 		return AppProfile{}, pkgerrors.Wrap(err, "Creating DB Entry")
 	}
-	err = db.DBconn.Insert(c.storeName, key, qkey, c.tagContent, ac)
+	err = db.WithContext(c.ctx).Insert(c.storeName, key, qkey, c.tagContent, ac)
 	if err != nil {
 		return AppProfile{}, pkgerrors.Wrap(err, "Creating DB Entry")
 	}
@@ -138,7 +150,7 @@ func (c *AppProfileClient) GetAppProfile(project, compositeApp, compositeAppVers
 		Profile:             profile,
 	}
 
-	value, err := db.DBconn.Find(c.storeName, key, c.tagMeta)
+	value, err := db.WithContext(c.ctx).Find(c.storeName, key, c.tagMeta)
 	if err != nil {
 		return AppProfile{}, pkgerrors.Wrap(err, "db Find error")
 	} else if len(value) == 0 {
@@ -200,7 +212,7 @@ func (c *AppProfileClient) GetAppProfileByApp(project, compositeApp, compositeAp
 		AppName:             appName,
 	}
 
-	value, err := db.DBconn.Find(c.storeName, key, c.tagMeta)
+	value, err := db.WithContext(c.ctx).Find(c.storeName, key, c.tagMeta)
 	if err != nil {
 		return AppProfile{}, pkgerrors.Wrap(err, "db Find error")
 	} else if len(value) == 0 {
@@ -228,7 +240,7 @@ func (c *AppProfileClient) GetAppProfileContent(project, compositeApp, composite
 		Profile:             profile,
 	}
 
-	value, err := db.DBconn.Find(c.storeName, key, c.tagContent)
+	value, err := db.WithContext(c.ctx).Find(c.storeName, key, c.tagContent)
 	if err != nil {
 		return AppProfileContent{}, pkgerrors.Wrap(err, "db Find error")
 	} else if len(value) == 0 {
@@ -257,7 +269,7 @@ func (c *AppProfileClient) GetAppProfileContentByApp(project, compositeApp, comp
 		AppName:             appName,
 	}
 
-	value, err := db.DBconn.Find(c.storeName, key, c.tagContent)
+	value, err := db.WithContext(c.ctx).Find(c.storeName, key, c.tagContent)
 	if err != nil {
 		return AppProfileContent{}, pkgerrors.Wrap(err, "db Find error")
 	} else if len(value) == 0 {
@@ -287,7 +299,7 @@ func (c *AppProfileClient) DeleteAppProfile(project, compositeApp, compositeAppV
 		Profile:             profile,
 	}
 
-	err := db.DBconn.Remove(c.storeName, key)
+	err := db.WithContext(c.ctx).Remove(c.storeName, key)
 	if err != nil {
 		if strings.Contains(err.Error(), "Error finding:") {
 			return pkgerrors.Wrap(err, "db Remove error - not found")
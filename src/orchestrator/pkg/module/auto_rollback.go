@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package module
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/state"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// AutoRollback rolls an update of the DeploymentIntentGroup back to the previous
+// revision when the update fails or isn't ready within the deadline
+type AutoRollback struct {
+	Enabled bool `json:"enabled"`
+	// Seconds for the update to be ready
+	Deadline int `json:"deadline,omitempty"`
+}
+
+// pendingRollback is an update of a DeploymentIntentGroup watched for an
+// automatic rollback. It is stored with the DeploymentIntentGroup, so the
+// watch resumes when the orchestrator restarts.
+type pendingRollback struct {
+	Project      string `json:"project"`
+	CompositeApp string `json:"composite-app"`
+	Version      string `json:"composite-app-version"`
+	DigName      string `json:"deployment-intent-group"`
+	// AppContext of the update, empty once the watch is done
+	ContextId string `json:"instance"`
+	// Revision to roll back to
+	Revision int64     `json:"revision"`
+	Deadline int       `json:"deadline,omitempty"`
+	Start    time.Time `json:"start"`
+}
+
+// Tag of the pending rollback of a DeploymentIntentGroup
+const pendingRollbackTag = "pendingRollback"
+
+// Interval at which an update is checked for failures
+var autoRollbackInterval = 5 * time.Second
+
+// watchUpdate stores the update as pending a rollback and watches it
+func (c InstantiationClient) watchUpdate(pr pendingRollback) {
+	err := storePendingRollback(pr)
+	if err != nil {
+		log.Error(":: Error storing the pending rollback ::", log.Fields{"dep-group": pr.DigName, "error": err})
+	}
+	go c.autoRollback(pr)
+}
+
+// ResumeAutoRollbacks resumes watching the updates that were pending an
+// automatic rollback when the orchestrator stopped
+func ResumeAutoRollbacks() {
+	values, err := db.DBconn.Find(NewInstantiationClient().db.storeName, DeploymentIntentGroupKey{}, pendingRollbackTag)
+	if err != nil {
+		log.Error(":: Error finding the pending rollbacks ::", log.Fields{"error": err})
+		return
+	}
+	for _, value := range values {
+		var pr pendingRollback
+		if len(value) == 0 || db.DBconn.Unmarshal(value, &pr) != nil || pr.ContextId == "" {
+			continue
+		}
+		log.Info(":: Resuming the watch of an update ::", log.Fields{"dep-group": pr.DigName, "context": pr.ContextId})
+		go NewInstantiationClient().autoRollback(pr)
+	}
+}
+
+func (pr pendingRollback) key() DeploymentIntentGroupKey {
+	return DeploymentIntentGroupKey{Name: pr.DigName, Project: pr.Project, CompositeApp: pr.CompositeApp, Version: pr.Version}
+}
+
+func storePendingRollback(pr pendingRollback) error {
+	return db.DBconn.Insert(NewInstantiationClient().db.storeName, pr.key(), nil, pendingRollbackTag, pr)
+}
+
+// getPendingRollback returns the update of the DeploymentIntentGroup pending a rollback
+func getPendingRollback(key DeploymentIntentGroupKey) (pendingRollback, error) {
+	var pr pendingRollback
+	values, err := db.DBconn.Find(NewInstantiationClient().db.storeName, key, pendingRollbackTag)
+	if err != nil {
+		return pr, err
+	}
+	if len(values) == 0 || len(values[0]) == 0 {
+		return pr, pkgerrors.New("No pending rollback")
+	}
+	err = db.DBconn.Unmarshal(values[0], &pr)
+	return pr, err
+}
+
+// clearPendingRollback marks the watch of the update done, unless the
+// DeploymentIntentGroup was deleted or another update is watched
+func clearPendingRollback(pr pendingRollback) {
+	current, err := getPendingRollback(pr.key())
+	if err != nil || current.ContextId != pr.ContextId {
+		return
+	}
+	if _, err := NewDeploymentIntentGroupClient().GetDeploymentIntentGroup(pr.DigName, pr.Project, pr.CompositeApp, pr.Version); err != nil {
+		return
+	}
+	done := pr
+	done.ContextId = ""
+	if err := storePendingRollback(done); err != nil {
+		log.Error(":: Error clearing the pending rollback ::", log.Fields{"dep-group": pr.DigName, "error": err})
+	}
+}
+
+// autoRollback watches the update of the DeploymentIntentGroup to the AppContext and
+// rolls the DeploymentIntentGroup back to the given revision if the update fails or
+// isn't ready within the deadline. Watching stops once the update is done or the
+// DeploymentIntentGroup changes.
+func (c InstantiationClient) autoRollback(pr pendingRollback) {
+	p, ca, v, di := pr.Project, pr.CompositeApp, pr.Version, pr.DigName
+	ctxId, revision, deadline, start := pr.ContextId, pr.Revision, pr.Deadline, pr.Start
+	defer clearPendingRollback(pr)
+	for {
+		time.Sleep(autoRollbackInterval)
+
+		s, err := NewDeploymentIntentGroupClient().GetDeploymentIntentGroupState(di, p, ca, v)
+		if err != nil {
+			log.Error(":: Error getting the state of the DeploymentIntentGroup ::", log.Fields{"dep-group": di, "error": err})
+			return
+		}
+		stateVal, err := state.GetCurrentStateFromStateInfo(s)
+		if err != nil || stateVal != state.StateEnum.Instantiated || state.GetLastContextIdFromStateInfo(s) != ctxId {
+			return
+		}
+
+		done, reason := checkUpdate(ctxId, start, deadline)
+		if !done {
+			continue
+		}
+		if reason != "" {
+			log.Warn(":: Update failed, rolling back ::", log.Fields{"dep-group": di, "context": ctxId,
+				"revision": revision, "reason": reason})
+			reason = fmt.Sprintf("Automatic rollback of revision %d: %s", revision+1, reason)
+			if err := c.rollback(p, ca, v, di, strconv.FormatInt(revision, 10), reason); err != nil {
+				log.Error(":: Error rolling back the DeploymentIntentGroup ::", log.Fields{"dep-group": di, "error": err})
+			}
+		}
+		return
+	}
+}
+
+// checkUpdate checks whether the update to the AppContext is done. If the update
+// needs to be rolled back, the reason is returned.
+func checkUpdate(ctxId string, start time.Time, deadline int) (bool, string) {
+	// Status isn't set until rsync starts handling the AppContext
+	acStatus, err := state.GetAppContextStatus(ctxId)
+	if err == nil {
+		switch acStatus.Status {
+		case appcontext.AppContextStatusEnum.Instantiated:
+			return true, ""
+		case appcontext.AppContextStatusEnum.InstantiateFailed:
+			reason := "update failed"
+			if rs, err := state.GetAppContextRolloutStatus(ctxId); err == nil && rs.Message != "" {
+				reason = reason + ": " + rs.Message
+			}
+			return true, reason
+		}
+	}
+	if deadline > 0 && time.Since(start) > time.Duration(deadline)*time.Second {
+		return true, fmt.Sprintf("update not ready within %d seconds", deadline)
+	}
+	return false, ""
+}
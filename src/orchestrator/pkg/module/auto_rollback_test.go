@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package module
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/contextdb"
+)
+
+func TestCheckUpdate(t *testing.T) {
+	contextdb.Db = &contextdb.MockConDb{}
+
+	makeContext := func(status appcontext.StatusValue, rs *appcontext.RolloutStatus) string {
+		ac := appcontext.AppContext{}
+		cid, err := ac.InitAppContext()
+		if err != nil {
+			t.Fatalf("Error initializing AppContext %v", err)
+		}
+		h, err := ac.CreateCompositeApp()
+		if err != nil {
+			t.Fatalf("Error creating composite app %v", err)
+		}
+		if status != "" {
+			ac.AddLevelValue(h, "status", appcontext.AppContextStatus{Status: status})
+		}
+		if rs != nil {
+			ac.AddLevelValue(h, appcontext.RolloutStatusKey, *rs)
+		}
+		return fmt.Sprintf("%v", cid)
+	}
+
+	testCases := []struct {
+		label          string
+		ctxId          string
+		start          time.Time
+		deadline       int
+		expectedDone   bool
+		expectedReason string
+	}{
+		{
+			label:        "Update done",
+			ctxId:        makeContext(appcontext.AppContextStatusEnum.Instantiated, nil),
+			start:        time.Now(),
+			expectedDone: true,
+		},
+		{
+			label:          "Update failed",
+			ctxId:          makeContext(appcontext.AppContextStatusEnum.InstantiateFailed, &appcontext.RolloutStatus{Message: "wave 1 not ready"}),
+			start:          time.Now(),
+			expectedDone:   true,
+			expectedReason: "update failed: wave 1 not ready",
+		},
+		{
+			label:    "Update in progress",
+			ctxId:    makeContext(appcontext.AppContextStatusEnum.Instantiating, nil),
+			start:    time.Now(),
+			deadline: 60,
+		},
+		{
+			label:          "Update past deadline",
+			ctxId:          makeContext(appcontext.AppContextStatusEnum.Instantiating, nil),
+			start:          time.Now().Add(-2 * time.Minute),
+			deadline:       60,
+			expectedDone:   true,
+			expectedReason: "update not ready within 60 seconds",
+		},
+		{
+			label: "Update not started without deadline",
+			ctxId: makeContext("", nil),
+			start: time.Now().Add(-2 * time.Minute),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			done, reason := checkUpdate(testCase.ctxId, testCase.start, testCase.deadline)
+			if done != testCase.expectedDone || !strings.Contains(reason, testCase.expectedReason) || (testCase.expectedReason == "" && reason != "") {
+				t.Errorf("checkUpdate returned %v, %q; expected %v, %q", done, reason, testCase.expectedDone, testCase.expectedReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package module
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/config"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	"github.com/open-ness/EMCO/src/orchestrator/utils/helm"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// ChartReference locates the chart of an App in a Helm chart repository or
+// in an OCI registry
+type ChartReference struct {
+	// Repository is the URL of a Helm chart repository
+	Repository string `json:"repository,omitempty"`
+	// Name is the name of the chart in the Helm chart repository
+	Name string `json:"name,omitempty"`
+	// OCI is the reference of the chart in an OCI registry, like
+	// oci://registry.example.com/charts/nginx
+	OCI      string `json:"oci,omitempty"`
+	Version  string `json:"version"`
+	Username string `json:"username,omitempty"`
+	// Password is encrypted with the chart credential key in the database
+	Password string `json:"password,omitempty"`
+}
+
+func (r ChartReference) validate() error {
+	switch {
+	case r.Version == "":
+		return pkgerrors.New("Invalid chart reference: the version is missing")
+	case r.Repository != "" && r.OCI != "":
+		return pkgerrors.New("Invalid chart reference: either a repository or an OCI reference is needed")
+	case r.Repository != "":
+		if r.Name == "" {
+			return pkgerrors.New("Invalid chart reference: the name of the chart in the repository is missing")
+		}
+	case r.OCI != "":
+		if !strings.HasPrefix(r.OCI, "oci://") {
+			return pkgerrors.New("Invalid chart reference: the OCI reference must start with oci://")
+		}
+	default:
+		return pkgerrors.New("Invalid chart reference: either a repository or an OCI reference is needed")
+	}
+	return nil
+}
+
+// String identifies the version of the chart, without the credentials
+func (r ChartReference) String() string {
+	if r.OCI != "" {
+		return r.OCI + ":" + r.Version
+	}
+	return strings.TrimSuffix(r.Repository, "/") + "/" + r.Name + ":" + r.Version
+}
+
+// chartCredentialKey reads the key of the chart-credential-key-file
+// configuration the chart passwords are encrypted with
+func chartCredentialKey() (cipher.AEAD, error) {
+	file := config.GetConfiguration().ChartCredentialKeyFile
+	if file == "" {
+		return nil, pkgerrors.New("Chart passwords need the chart-credential-key-file configuration")
+	}
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Error reading the chart credential key")
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(b)))
+	if err != nil || len(key) != 32 {
+		return nil, pkgerrors.New("The chart credential key must be 32 bytes encoded in base64")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealPassword returns the reference with its password encrypted, as it is
+// stored in the database
+func (r ChartReference) sealPassword() (ChartReference, error) {
+	if r.Password == "" {
+		return r, nil
+	}
+	aead, err := chartCredentialKey()
+	if err != nil {
+		return r, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return r, pkgerrors.Wrap(err, "Error encrypting the chart password")
+	}
+	r.Password = base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(r.Password), nil))
+	return r, nil
+}
+
+// openPassword returns the reference stored in the database with its
+// password decrypted, to fetch the chart
+func (r ChartReference) openPassword() (ChartReference, error) {
+	if r.Password == "" {
+		return r, nil
+	}
+	aead, err := chartCredentialKey()
+	if err != nil {
+		return r, err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(r.Password)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return r, pkgerrors.New("Invalid encrypted chart password")
+	}
+	password, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return r, pkgerrors.Wrap(err, "Error decrypting the chart password")
+	}
+	r.Password = string(password)
+	return r, nil
+}
+
+// ChartPin is the chart an App of a DeploymentIntentGroup was approved with
+type ChartPin struct {
+	Reference string `json:"reference"`
+	Digest    string `json:"digest"`
+}
+
+// ChartKey is the key of a chart fetched for a project in the database. The
+// charts are cached per project, so a project doesn't get a chart fetched
+// with the credentials of another one.
+type ChartKey struct {
+	Project   string `json:"chartproject"`
+	Reference string `json:"chartreference"`
+}
+
+// We will use json marshalling to convert to string to
+// preserve the underlying structure.
+func (ck ChartKey) String() string {
+	out, err := json.Marshal(ck)
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+// chart is a fetched chart, cached in the database
+type chart struct {
+	Digest  string `json:"digest"`
+	Content string `json:"content"`
+}
+
+const (
+	tagChart     = "chart"
+	tagChartPins = "chartpins"
+)
+
+// chartFetcher fetches the charts, replaced in the tests
+var chartFetcher = helm.NewChartFetcher()
+
+// fetchChart returns the chart of the reference, fetched once for the project
+// and cached in the database. The cached chart keeps the digest of a version
+// stable even if the repository publishes the version again.
+// The fetched charts count in the chart storage of the project.
+func fetchChart(p string, r ChartReference) (chart, error) {
+	c, found, err := cachedChart(p, r)
+	if err != nil || found {
+		return c, err
+	}
+
+	log.Info(":: Fetching chart ::", log.Fields{"chart": r.String()})
+	r, err = r.openPassword()
+	if err != nil {
+		return chart{}, err
+	}
+	var content []byte
+	if r.OCI != "" {
+		content, err = chartFetcher.FetchOCIChart(r.OCI, r.Version, r.Username, r.Password)
+	} else {
+		content, err = chartFetcher.FetchRepoChart(r.Repository, r.Name, r.Version, r.Username, r.Password)
+	}
+	if err != nil {
+		return chart{}, pkgerrors.Wrapf(err, "Error fetching chart %s", r.String())
+	}
+	release, err := checkFetchedChartQuota(p, int64(len(content)))
+	if err != nil {
+		return chart{}, err
+	}
+	defer release()
+	c = chart{Digest: helm.Digest(content), Content: base64.StdEncoding.EncodeToString(content)}
+	key := ChartKey{Project: p, Reference: r.String()}
+	err = db.DBconn.Insert(NewAppClient().storeName, key, nil, tagChart, c)
+	if err != nil {
+		return chart{}, pkgerrors.Wrap(err, "Error caching chart")
+	}
+	return c, nil
+}
+
+// cachedChart returns the chart of the reference cached for the project,
+// false if it was not fetched yet
+func cachedChart(p string, r ChartReference) (chart, bool, error) {
+	key := ChartKey{Project: p, Reference: r.String()}
+	values, err := db.DBconn.Find(NewAppClient().storeName, key, tagChart)
+	if err != nil {
+		return chart{}, false, pkgerrors.Wrap(err, "db Find error")
+	}
+	if len(values) == 0 {
+		return chart{}, false, nil
+	}
+	c := chart{}
+	err = db.DBconn.Unmarshal(values[0], &c)
+	if err != nil {
+		return chart{}, false, pkgerrors.Wrap(err, "Unmarshaling chart")
+	}
+	return c, true, nil
+}
+
+// deleteCharts removes the charts cached for the project
+func deleteCharts(p string) error {
+	err := db.DBconn.RemoveAll(NewAppClient().storeName, ChartKey{Project: p})
+	if err != nil {
+		return pkgerrors.Wrap(err, "Error deleting the cached charts")
+	}
+	return nil
+}
+
+func getChartPins(p, ca, v, di string) (map[string]ChartPin, error) {
+	c := NewDeploymentIntentGroupClient()
+	key := DeploymentIntentGroupKey{Name: di, Project: p, CompositeApp: ca, Version: v}
+	values, err := db.DBconn.Find(c.storeName, key, tagChartPins)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "db Find error")
+	}
+	pins := map[string]ChartPin{}
+	if len(values) > 0 {
+		err = db.DBconn.Unmarshal(values[0], &pins)
+		if err != nil {
+			return nil, pkgerrors.Wrap(err, "Unmarshaling chart pins")
+		}
+	}
+	return pins, nil
+}
+
+// pinCharts fetches the charts of the apps of the composite app referencing
+// one and pins their digests to the DeploymentIntentGroup
+func pinCharts(p, ca, v, di string) error {
+	apps, err := NewAppClient().GetApps(p, ca, v)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Not finding the apps")
+	}
+	pins := map[string]ChartPin{}
+	for _, a := range apps {
+		if a.Spec.Chart == nil {
+			continue
+		}
+		c, err := fetchChart(p, *a.Spec.Chart)
+		if err != nil {
+			return err
+		}
+		pins[a.Metadata.Name] = ChartPin{Reference: a.Spec.Chart.String(), Digest: c.Digest}
+	}
+	if len(pins) == 0 {
+		return nil
+	}
+
+	c := NewDeploymentIntentGroupClient()
+	key := DeploymentIntentGroupKey{Name: di, Project: p, CompositeApp: ca, Version: v}
+	err = db.DBconn.Insert(c.storeName, key, nil, tagChartPins, pins)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Error pinning the charts of the DeploymentIntentGroup: "+di)
+	}
+	return nil
+}
+
+// getAppFile returns the file of an App of a DeploymentIntentGroup, the
+// uploaded file or the chart pinned at approval
+func getAppFile(a App, p, ca, v, di string) ([]byte, error) {
+	appName := a.Metadata.Name
+	if a.Spec.Chart == nil {
+		aC, err := NewAppClient().GetAppContent(appName, p, ca, v)
+		if err != nil {
+			return nil, pkgerrors.Wrap(err, "Not finding the content of app:: "+appName)
+		}
+		content, err := base64.StdEncoding.DecodeString(aC.FileContent)
+		if err != nil {
+			return nil, pkgerrors.Wrap(err, "Fail to convert to byte array")
+		}
+		return content, nil
+	}
+
+	pins, err := getChartPins(p, ca, v, di)
+	if err != nil {
+		return nil, err
+	}
+	pin, ok := pins[appName]
+	if !ok || pin.Reference != a.Spec.Chart.String() {
+		return nil, pkgerrors.Errorf("The chart of app %s is not pinned, approve the DeploymentIntentGroup %s", appName, di)
+	}
+	// The charts are fetched at approval, not while the quota of the project
+	// may be held by the instantiation
+	c, found, err := cachedChart(p, *a.Spec.Chart)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, pkgerrors.Errorf("The chart of app %s is not fetched, approve the DeploymentIntentGroup %s", appName, di)
+	}
+	content, err := base64.StdEncoding.DecodeString(c.Content)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Fail to convert to byte array")
+	}
+	if helm.Digest(content) != pin.Digest {
+		return nil, pkgerrors.Errorf("The chart of app %s does not match the digest pinned at approval", appName)
+	}
+	return content, nil
+}
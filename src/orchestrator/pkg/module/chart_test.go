@@ -0,0 +1,181 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package module
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/config"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+	"github.com/open-ness/EMCO/src/orchestrator/utils/helm"
+)
+
+func TestChartPinning(t *testing.T) {
+	db.DBconn = &db.NewMockDB{}
+
+	chart := []byte("chart 1.0.0")
+	fetches := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/index.yaml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "apiVersion: v1\nentries:\n  nginx:\n  - name: nginx\n    version: 1.0.0\n    urls:\n    - nginx-1.0.0.tgz\n")
+	})
+	mux.HandleFunc("/nginx-1.0.0.tgz", func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		w.Write(chart)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	_, err := NewProjectClient().CreateProject(Project{MetaData: ProjectMetaData{Name: "testProject"}}, false)
+	if err != nil {
+		t.Fatalf("CreateProject returned an unexpected error %s", err)
+	}
+	ca := CompositeApp{Metadata: CompositeAppMetaData{Name: "ca1"}, Spec: CompositeAppSpec{Version: "v1"}}
+	_, err = NewCompositeAppClient().CreateCompositeApp(ca, "testProject", false)
+	if err != nil {
+		t.Fatalf("CreateCompositeApp returned an unexpected error %s", err)
+	}
+
+	app := App{Metadata: AppMetaData{Name: "app1"}, Spec: AppSpec{Chart: &ChartReference{Repository: server.URL, Version: "1.0.0"}}}
+	_, err = NewAppClient().CreateApp(app, AppContent{}, "testProject", "ca1", "v1", false)
+	if err == nil || !strings.Contains(err.Error(), "Invalid chart reference") {
+		t.Fatalf("Expected the chart reference without a name to be invalid, got %v", err)
+	}
+	app.Spec.Chart.Name = "nginx"
+	_, err = NewAppClient().CreateApp(app, AppContent{}, "testProject", "ca1", "v1", false)
+	if err != nil {
+		t.Fatalf("CreateApp returned an unexpected error %s", err)
+	}
+
+	_, err = getAppFile(app, "testProject", "ca1", "v1", "dig1")
+	if err == nil || !strings.Contains(err.Error(), "not pinned") {
+		t.Fatalf("Expected the chart not to be pinned before the approval, got %v", err)
+	}
+
+	err = pinCharts("testProject", "ca1", "v1", "dig1")
+	if err != nil {
+		t.Fatalf("pinCharts returned an unexpected error %s", err)
+	}
+	pins, err := getChartPins("testProject", "ca1", "v1", "dig1")
+	if err != nil {
+		t.Fatalf("getChartPins returned an unexpected error %s", err)
+	}
+	expected := ChartPin{Reference: server.URL + "/nginx:1.0.0", Digest: helm.Digest(chart)}
+	if pins["app1"] != expected {
+		t.Errorf("Unexpected chart pins %+v", pins)
+	}
+
+	// The chart is rendered from the cache, even if the repository changes
+	chart = []byte("chart 1.0.0 published again")
+	got, err := getAppFile(app, "testProject", "ca1", "v1", "dig1")
+	if err != nil {
+		t.Fatalf("getAppFile returned an unexpected error %s", err)
+	}
+	if !bytes.Equal(got, []byte("chart 1.0.0")) || fetches != 1 {
+		t.Errorf("getAppFile returned %q after %d fetches", got, fetches)
+	}
+
+	// Another project doesn't get the chart cached for this one, and the
+	// fetched chart counts in its chart storage
+	other := Project{MetaData: ProjectMetaData{Name: "otherProject"}, Spec: ProjectSpec{Limits: ProjectLimits{ChartStorage: 10}}}
+	_, err = NewProjectClient().CreateProject(other, false)
+	if err != nil {
+		t.Fatalf("CreateProject returned an unexpected error %s", err)
+	}
+	_, err = fetchChart("otherProject", *app.Spec.Chart)
+	if err == nil || !strings.Contains(err.Error(), "quota exceeded") {
+		t.Fatalf("Expected the chart storage quota to be exceeded, got %v", err)
+	}
+	other = Project{MetaData: ProjectMetaData{Name: "thirdProject"}, Spec: ProjectSpec{Limits: ProjectLimits{ChartStorage: 100}}}
+	_, err = NewProjectClient().CreateProject(other, false)
+	if err != nil {
+		t.Fatalf("CreateProject returned an unexpected error %s", err)
+	}
+	c, err := fetchChart("thirdProject", *app.Spec.Chart)
+	if err != nil {
+		t.Fatalf("fetchChart returned an unexpected error %s", err)
+	}
+	if c.Digest != helm.Digest(chart) || fetches != 3 {
+		t.Errorf("fetchChart returned the chart cached for another project")
+	}
+	u, err := NewProjectClient().GetProjectUsage("thirdProject")
+	if err != nil {
+		t.Fatalf("GetProjectUsage returned an unexpected error %s", err)
+	}
+	if u.Usage.ChartStorage != int64(len(chart)) {
+		t.Errorf("Unexpected chart storage %d", u.Usage.ChartStorage)
+	}
+}
+
+func TestChartPassword(t *testing.T) {
+	db.DBconn = &db.NewMockDB{}
+	defer func() { config.GetConfiguration().ChartCredentialKeyFile = "" }()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/index.yaml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "apiVersion: v1\nentries:\n  nginx:\n  - name: nginx\n    version: 1.0.0\n    urls:\n    - nginx-1.0.0.tgz\n")
+	})
+	mux.HandleFunc("/nginx-1.0.0.tgz", func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "u1" || password != "secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("chart 1.0.0"))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	_, err := NewProjectClient().CreateProject(Project{MetaData: ProjectMetaData{Name: "testProject"}}, false)
+	if err != nil {
+		t.Fatalf("CreateProject returned an unexpected error %s", err)
+	}
+	ca := CompositeApp{Metadata: CompositeAppMetaData{Name: "ca1"}, Spec: CompositeAppSpec{Version: "v1"}}
+	_, err = NewCompositeAppClient().CreateCompositeApp(ca, "testProject", false)
+	if err != nil {
+		t.Fatalf("CreateCompositeApp returned an unexpected error %s", err)
+	}
+
+	app := App{Metadata: AppMetaData{Name: "app1"}, Spec: AppSpec{Chart: &ChartReference{Repository: server.URL,
+		Name: "nginx", Version: "1.0.0", Username: "u1", Password: "secret"}}}
+	_, err = NewAppClient().CreateApp(app, AppContent{}, "testProject", "ca1", "v1", false)
+	if err == nil || !strings.Contains(err.Error(), "chart-credential-key-file") {
+		t.Fatalf("Expected the password to need a key, got %v", err)
+	}
+
+	keyFile := filepath.Join(t.TempDir(), "chart.key")
+	if err := ioutil.WriteFile(keyFile, []byte("MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=\n"), 0600); err != nil {
+		t.Fatalf("Error writing the key %s", err)
+	}
+	config.GetConfiguration().ChartCredentialKeyFile = keyFile
+	_, err = NewAppClient().CreateApp(app, AppContent{}, "testProject", "ca1", "v1", false)
+	if err != nil {
+		t.Fatalf("CreateApp returned an unexpected error %s", err)
+	}
+
+	// The password is encrypted in the database, and decrypted to fetch the chart
+	stored, err := NewAppClient().GetApp("app1", "testProject", "ca1", "v1")
+	if err != nil {
+		t.Fatalf("GetApp returned an unexpected error %s", err)
+	}
+	for _, item := range db.DBconn.(*db.NewMockDB).Items {
+		for _, v := range item {
+			if bytes.Contains(v["appmetadata"], []byte("secret")) {
+				t.Errorf("Password stored in plaintext %s", v["appmetadata"])
+			}
+		}
+	}
+	if stored.Spec.Chart.Password == "" {
+		t.Errorf("Password not stored")
+	}
+	if _, err := fetchChart("testProject", *stored.Spec.Chart); err != nil {
+		t.Errorf("fetchChart returned an unexpected error %s", err)
+	}
+}
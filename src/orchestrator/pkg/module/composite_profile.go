@@ -4,6 +4,7 @@
 package module
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 
@@ -55,6 +56,7 @@ type CompositeProfileManager interface {
 		version string) ([]CompositeProfile, error)
 	DeleteCompositeProfile(compositeProfileName string, projectName string,
 		compositeAppName string, version string) error
+	WithContext(ctx context.Context) CompositeProfileManager
 }
 
 // CompositeProfileClient implements the Manager
@@ -62,6 +64,8 @@ type CompositeProfileManager interface {
 type CompositeProfileClient struct {
 	storeName string
 	tagMeta   string
+	// ctx is the context of the request using the client
+	ctx context.Context
 }
 
 // NewCompositeProfileClient returns an instance of the CompositeProfileClient
@@ -73,6 +77,14 @@ func NewCompositeProfileClient() *CompositeProfileClient {
 	}
 }
 
+// WithContext returns a copy of the client making the database calls of
+// the composite profile of a request with its context
+func (c *CompositeProfileClient) WithContext(ctx context.Context) CompositeProfileManager {
+	n := *c
+	n.ctx = ctx
+	return &n
+}
+
 // CreateCompositeProfile creates an entry for CompositeProfile in the database. Other Input parameters for it - projectName, compositeAppName, version
 func (c *CompositeProfileClient) CreateCompositeProfile(cpf CompositeProfile, p string, ca string,
 	v string, exists bool) (CompositeProfile, error) {
@@ -101,7 +113,7 @@ func (c *CompositeProfileClient) CreateCompositeProfile(cpf CompositeProfile, p
 		Version:      v,
 	}
 
-	err = db.DBconn.Insert(c.storeName, cProfkey, nil, c.tagMeta, cpf)
+	err = db.WithContext(c.ctx).Insert(c.storeName, cProfkey, nil, c.tagMeta, cpf)
 	if err != nil {
 		return CompositeProfile{}, pkgerrors.Wrap(err, "Create DB entry error")
 	}
@@ -118,7 +130,7 @@ func (c *CompositeProfileClient) GetCompositeProfile(cpf string, p string, ca st
 		Version:      v,
 	}
 
-	result, err := db.DBconn.Find(c.storeName, key, c.tagMeta)
+	result, err := db.WithContext(c.ctx).Find(c.storeName, key, c.tagMeta)
 	if err != nil {
 		return CompositeProfile{}, pkgerrors.Wrap(err, "db Find error")
 	} else if len(result) == 0 {
@@ -176,7 +188,7 @@ func (c *CompositeProfileClient) DeleteCompositeProfile(cpf string, p string, ca
 		Version:      v,
 	}
 
-	err := db.DBconn.Remove(c.storeName, key)
+	err := db.WithContext(c.ctx).Remove(c.storeName, key)
 	if err != nil {
 		if strings.Contains(err.Error(), "Error finding:") {
 			return pkgerrors.Wrap(err, "db Remove error - not found")
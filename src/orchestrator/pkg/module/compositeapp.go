@@ -4,6 +4,7 @@
 package module
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 
@@ -54,6 +55,7 @@ type CompositeAppManager interface {
 	GetCompositeApp(name string, version string, p string) (CompositeApp, error)
 	GetAllCompositeApps(p string) ([]CompositeApp, error)
 	DeleteCompositeApp(name string, version string, p string) error
+	WithContext(ctx context.Context) CompositeAppManager
 }
 
 // CompositeAppClient implements the CompositeAppManager
@@ -61,6 +63,8 @@ type CompositeAppManager interface {
 type CompositeAppClient struct {
 	storeName string
 	tagMeta   string
+	// ctx is the context of the request using the client
+	ctx context.Context
 }
 
 // NewCompositeAppClient returns an instance of the CompositeAppClient
@@ -72,6 +76,14 @@ func NewCompositeAppClient() *CompositeAppClient {
 	}
 }
 
+// WithContext returns a copy of the client making the database calls of
+// the composite app of a request with its context
+func (v *CompositeAppClient) WithContext(ctx context.Context) CompositeAppManager {
+	c := *v
+	c.ctx = ctx
+	return &c
+}
+
 // CreateCompositeApp creates a new collection based on the CompositeApp
 func (v *CompositeAppClient) CreateCompositeApp(c CompositeApp, p string, exists bool) (CompositeApp, error) {
 
@@ -87,6 +99,7 @@ func (v *CompositeAppClient) CreateCompositeApp(c CompositeApp, p string, exists
 	if err == nil && !exists{
 		return CompositeApp{}, pkgerrors.New("CompositeApp already exists")
 	}
+	found := err == nil
 
 	//Check if Project exists
 	_, err = NewProjectClient().GetProject(p)
@@ -94,7 +107,15 @@ func (v *CompositeAppClient) CreateCompositeApp(c CompositeApp, p string, exists
 		return CompositeApp{}, pkgerrors.New("Unable to find the project")
 	}
 
-	err = db.DBconn.Insert(v.storeName, key, nil, v.tagMeta, c)
+	if !found {
+		release, err := checkCompositeAppQuota(p)
+		if err != nil {
+			return CompositeApp{}, err
+		}
+		defer release()
+	}
+
+	err = db.WithContext(v.ctx).Insert(v.storeName, key, nil, v.tagMeta, c)
 	if err != nil {
 		return CompositeApp{}, pkgerrors.Wrap(err, "Creating DB Entry")
 	}
@@ -111,7 +132,7 @@ func (v *CompositeAppClient) GetCompositeApp(name string, version string, p stri
 		Version:          version,
 		Project:          p,
 	}
-	value, err := db.DBconn.Find(v.storeName, key, v.tagMeta)
+	value, err := db.WithContext(v.ctx).Find(v.storeName, key, v.tagMeta)
 	if err != nil {
 		return CompositeApp{}, pkgerrors.Wrap(err, "db Find error")
 	} else if len(value) == 0 {
@@ -174,7 +195,7 @@ func (v *CompositeAppClient) DeleteCompositeApp(name string, version string, p s
 		Version:          version,
 		Project:          p,
 	}
-	err := db.DBconn.Remove(v.storeName, key)
+	err := db.WithContext(v.ctx).Remove(v.storeName, key)
 	if err != nil {
 		if strings.Contains(err.Error(), "Error finding:") {
 			return pkgerrors.Wrap(err, "db Remove error - not found")
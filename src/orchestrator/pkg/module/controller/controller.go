@@ -4,6 +4,7 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 
@@ -58,6 +59,7 @@ type ControllerManager interface {
 	GetControllers() ([]Controller, error)
 	InitControllers()
 	DeleteController(name string) error
+	WithContext(ctx context.Context) ControllerManager
 }
 
 // ControllerClient implements the Manager
@@ -65,6 +67,8 @@ type ControllerManager interface {
 type ControllerClient struct {
 	collectionName string
 	tagMeta        string
+	// ctx is the context of the request using the client
+	ctx context.Context
 }
 
 // NewControllerClient returns an instance of the ControllerClient
@@ -76,6 +80,14 @@ func NewControllerClient(name, tag string) *ControllerClient {
 	}
 }
 
+// WithContext returns a copy of the client making the database calls of
+// the controller of a request with its context
+func (mc *ControllerClient) WithContext(ctx context.Context) ControllerManager {
+	c := *mc
+	c.ctx = ctx
+	return &c
+}
+
 // CreateController a new collection based on the Controller
 func (mc *ControllerClient) CreateController(m Controller, mayExist bool) (Controller, error) {
 
@@ -92,7 +104,7 @@ func (mc *ControllerClient) CreateController(m Controller, mayExist bool) (Contr
 		return Controller{}, pkgerrors.New("Controller already exists")
 	}
 
-	err = db.DBconn.Insert(mc.collectionName, key, nil, mc.tagMeta, m)
+	err = db.WithContext(mc.ctx).Insert(mc.collectionName, key, nil, mc.tagMeta, m)
 	if err != nil {
 		return Controller{}, pkgerrors.Wrap(err, "Creating DB Entry")
 	}
@@ -111,7 +123,7 @@ func (mc *ControllerClient) GetController(name string) (Controller, error) {
 	key := ControllerKey{
 		ControllerName: name,
 	}
-	value, err := db.DBconn.Find(mc.collectionName, key, mc.tagMeta)
+	value, err := db.WithContext(mc.ctx).Find(mc.collectionName, key, mc.tagMeta)
 	if err != nil {
 		return Controller{}, pkgerrors.Wrap(err, "db Find error")
 	} else if len(value) == 0 {
@@ -164,7 +176,7 @@ func (mc *ControllerClient) DeleteController(name string) error {
 	key := ControllerKey{
 		ControllerName: name,
 	}
-	err := db.DBconn.Remove(mc.collectionName, key)
+	err := db.WithContext(mc.ctx).Remove(mc.collectionName, key)
 	if err != nil {
 		if strings.Contains(err.Error(), "Error finding:") {
 			return pkgerrors.Wrap(err, "db Remove error - not found")
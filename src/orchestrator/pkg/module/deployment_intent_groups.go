@@ -4,6 +4,7 @@
 package module
 
 import (
+	"context"
 	"encoding/json"
 	"reflect"
 	"strings"
@@ -36,12 +37,23 @@ type DepSpecData struct {
 	Version           string           `json:"version"`
 	OverrideValuesObj []OverrideValues `json:"override-values"`
 	LogicalCloud      string           `json:"logical-cloud"`
+	RolloutStrategy   *RolloutStrategy `json:"rollout-strategy,omitempty"`
+	AutoRollback      *AutoRollback    `json:"auto-rollback,omitempty"`
+	Reconcile         string           `json:"reconcile,omitempty"`
+	ForceApply        bool             `json:"force-apply,omitempty"`
+	Prune             string           `json:"prune,omitempty"`
+	RetryPolicy       *RetryPolicy     `json:"retry-policy,omitempty"`
 }
 
 // OverrideValues has appName and ValuesObj
 type OverrideValues struct {
-	AppName   string            `json:"app-name"`
+	AppName string `json:"app-name"`
+	// ValuesObj are set like helm --set, like "image.tag": "1.2.3"
 	ValuesObj map[string]string `json:"values"`
+	// StructuredValues are nested values, like a values file. They are
+	// deep-merged over the values of the chart and of the profile, and
+	// ValuesObj is set over them.
+	StructuredValues map[string]interface{} `json:"structured-values,omitempty"`
 }
 
 // Values has ImageRepository
@@ -56,6 +68,7 @@ type DeploymentIntentGroupManager interface {
 	GetDeploymentIntentGroupState(di string, p string, ca string, v string) (state.StateInfo, error)
 	DeleteDeploymentIntentGroup(di string, p string, ca string, v string) error
 	GetAllDeploymentIntentGroups(p string, ca string, v string) ([]DeploymentIntentGroup, error)
+	WithContext(ctx context.Context) DeploymentIntentGroupManager
 }
 
 // DeploymentIntentGroupKey consists of Name of the deployment group, project name, CompositeApp name, CompositeApp version
@@ -81,6 +94,8 @@ type DeploymentIntentGroupClient struct {
 	storeName   string
 	tagMetaData string
 	tagState    string
+	// ctx is the context of the request using the client
+	ctx context.Context
 }
 
 // NewDeploymentIntentGroupClient return an instance of DeploymentIntentGroupClient which implements DeploymentIntentGroupManager
@@ -92,6 +107,14 @@ func NewDeploymentIntentGroupClient() *DeploymentIntentGroupClient {
 	}
 }
 
+// WithContext returns a copy of the client making the database calls of
+// the deployment intent group of a request with its context
+func (c *DeploymentIntentGroupClient) WithContext(ctx context.Context) DeploymentIntentGroupManager {
+	n := *c
+	n.ctx = ctx
+	return &n
+}
+
 // CreateDeploymentIntentGroup creates an entry for a given  DeploymentIntentGroup in the database. Other Input parameters for it - projectName, compositeAppName, version
 func (c *DeploymentIntentGroupClient) CreateDeploymentIntentGroup(d DeploymentIntentGroup, p string, ca string,
 	v string) (DeploymentIntentGroup, error) {
@@ -113,6 +136,17 @@ func (c *DeploymentIntentGroupClient) CreateDeploymentIntentGroup(d DeploymentIn
 		return DeploymentIntentGroup{}, pkgerrors.New("Unable to find the composite-app")
 	}
 
+	release, err := checkDeploymentIntentGroupQuota(p)
+	if err != nil {
+		return DeploymentIntentGroup{}, err
+	}
+	defer release()
+
+	err = validateOverrideValues(d, p, ca, v)
+	if err != nil {
+		return DeploymentIntentGroup{}, err
+	}
+
 	gkey := DeploymentIntentGroupKey{
 		Name:         d.MetaData.Name,
 		Project:      p,
@@ -120,7 +154,7 @@ func (c *DeploymentIntentGroupClient) CreateDeploymentIntentGroup(d DeploymentIn
 		Version:      v,
 	}
 
-	err = db.DBconn.Insert(c.storeName, gkey, nil, c.tagMetaData, d)
+	err = db.WithContext(c.ctx).Insert(c.storeName, gkey, nil, c.tagMetaData, d)
 	if err != nil {
 		return DeploymentIntentGroup{}, pkgerrors.Wrap(err, "Create DB entry error")
 	}
@@ -134,7 +168,7 @@ func (c *DeploymentIntentGroupClient) CreateDeploymentIntentGroup(d DeploymentIn
 	}
 	s.Actions = append(s.Actions, a)
 
-	err = db.DBconn.Insert(c.storeName, gkey, nil, c.tagState, s)
+	err = db.WithContext(c.ctx).Insert(c.storeName, gkey, nil, c.tagState, s)
 	if err != nil {
 		return DeploymentIntentGroup{}, pkgerrors.Wrap(err, "Error updating the stateInfo of the DeploymentIntentGroup: "+d.MetaData.Name)
 	}
@@ -152,7 +186,7 @@ func (c *DeploymentIntentGroupClient) GetDeploymentIntentGroup(di string, p stri
 		Version:      v,
 	}
 
-	result, err := db.DBconn.Find(c.storeName, key, c.tagMetaData)
+	result, err := db.WithContext(c.ctx).Find(c.storeName, key, c.tagMetaData)
 	if err != nil {
 		return DeploymentIntentGroup{}, pkgerrors.Wrap(err, "db Find error")
 	} else if len(result) == 0 {
@@ -227,7 +261,7 @@ func (c *DeploymentIntentGroupClient) GetDeploymentIntentGroupState(di string, p
 		return state.StateInfo{}, pkgerrors.Wrap(err, "Get DeploymentIntentGroup StateInfo error")
 	}
 
-	if result != nil {
+	if len(result) > 0 {
 		s := state.StateInfo{}
 		err = db.DBconn.Unmarshal(result[0], &s)
 		if err != nil {
@@ -251,7 +285,7 @@ func (c *DeploymentIntentGroupClient) DeleteDeploymentIntentGroup(di string, p s
 	if err != nil {
 		// If the StateInfo cannot be found, then a proper deployment intent group record is not present.
 		// Call the DB delete to clean up any errant record without a StateInfo element that may exist.
-		err = db.DBconn.Remove(c.storeName, k)
+		err = db.WithContext(c.ctx).Remove(c.storeName, k)
 		if err != nil {
 			return pkgerrors.Wrap(err, "Error deleting DeploymentIntentGroup entry")
 		}
@@ -289,7 +323,7 @@ func (c *DeploymentIntentGroupClient) DeleteDeploymentIntentGroup(di string, p s
 		}
 	}
 
-	err = db.DBconn.Remove(c.storeName, k)
+	err = db.WithContext(c.ctx).Remove(c.storeName, k)
 	if err != nil {
 		if strings.Contains(err.Error(), "Error finding:") {
 			return pkgerrors.Wrap(err, "db Remove error - not found")
@@ -0,0 +1,270 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package module
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"time"
+
+	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/rpc"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/module/subscription"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/state"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/status"
+	readynotifypb "github.com/open-ness/EMCO/src/rsync/pkg/grpc/readynotify"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// DigEvent is a state or status change of a DeploymentIntentGroup
+type DigEvent struct {
+	Type      DigEventType `json:"type"`
+	ContextId string       `json:"instance,omitempty"`
+	App       string       `json:"app,omitempty"`
+	Cluster   string       `json:"cluster,omitempty"`
+	Resource  string       `json:"resource,omitempty"`
+	Status    string       `json:"status"`
+	Previous  string       `json:"previous,omitempty"`
+	TimeStamp time.Time    `json:"time"`
+}
+
+type DigEventType = string
+
+type digEventTypes struct {
+	State      DigEventType
+	AppContext DigEventType
+	Cluster    DigEventType
+	Resource   DigEventType
+}
+
+// DigEventTypeEnum defines the kinds of changes reported as events
+var DigEventTypeEnum = &digEventTypes{
+	State:      "state",      // StateInfo of the DeploymentIntentGroup
+	AppContext: "appcontext", // status of the current AppContext
+	Cluster:    "cluster",    // ready status of a cluster of an app
+	Resource:   "resource",   // rsync status of a resource
+}
+
+// statePollInterval is the interval between the reads of the state of a
+// watched DeploymentIntentGroup. The state is read from the database, so the
+// changes made by any instance of the orchestrator are streamed.
+var statePollInterval = 2 * time.Second
+
+// Events validates the DeploymentIntentGroup and starts watching its state and
+// status. The changes are sent on the returned channel, which is closed once
+// the context is done. The first events are the current state and status, then
+// each action added to the state is sent.
+func (c InstantiationClient) Events(ctx context.Context, p string, ca string, v string, di string) (<-chan DigEvent, error) {
+	_, err := NewDeploymentIntentGroupClient().GetDeploymentIntentGroupState(di, p, ca, v)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "deploymentIntentGroup state not found: "+di)
+	}
+	events := make(chan DigEvent)
+	go c.watchEvents(ctx, p, ca, v, di, events)
+	return events, nil
+}
+
+// watchEvents computes the snapshot again once the state is polled or rsync
+// notifies a status change, and sends the changes
+func (c InstantiationClient) watchEvents(ctx context.Context, p, ca, v, di string, events chan<- DigEvent) {
+	defer close(events)
+	poll := time.NewTicker(statePollInterval)
+	defer poll.Stop()
+
+	var prev map[string]DigEvent
+	// Number of the actions of the StateInfo already sent
+	sent := -1
+	var lastNotify, statusNotify readyNotify
+	defer lastNotify.stop()
+	defer statusNotify.stop()
+
+	for {
+		s, err := NewDeploymentIntentGroupClient().GetDeploymentIntentGroupState(di, p, ca, v)
+		if err != nil {
+			// The DeploymentIntentGroup was deleted
+			log.Info("Stop watching DeploymentIntentGroup events", log.Fields{"project": p, "deploymentIntentGroup": di, "error": err})
+			return
+		}
+		cur := getDigSnapshot(s)
+		stateEvents := getDigStateEvents(s, sent)
+		for _, e := range append(stateEvents, diffDigSnapshots(prev, cur)...) {
+			select {
+			case events <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+		prev = cur
+		sent = len(s.Actions)
+
+		// rsync notifies the status changes of the current AppContext, and of
+		// the AppContext holding the status after an update
+		lastCtxId := state.GetLastContextIdFromStateInfo(s)
+		statusCtxId := state.GetStatusContextIdFromStateInfo(s)
+		if statusCtxId == lastCtxId {
+			statusCtxId = ""
+		}
+		lastNotify.follow(lastCtxId)
+		statusNotify.follow(statusCtxId)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-poll.C:
+		case <-lastNotify.notify:
+		case <-statusNotify.notify:
+		}
+	}
+}
+
+// readyNotify is a subscription to the rsync notifications for an AppContext
+type readyNotify struct {
+	ctxId       string
+	notify      <-chan struct{}
+	unsubscribe func()
+}
+
+// follow subscribes to the notifications for the AppContext, unless already
+// subscribed. A nil channel is never ready.
+func (r *readyNotify) follow(ctxId string) {
+	if r.unsubscribe != nil && ctxId == r.ctxId {
+		return
+	}
+	r.stop()
+	r.ctxId = ctxId
+	r.notify, r.unsubscribe = subscribeReadyNotify(ctxId)
+}
+
+// stop ends the subscription
+func (r *readyNotify) stop() {
+	if r.unsubscribe != nil {
+		r.unsubscribe()
+		r.unsubscribe = nil
+	}
+}
+
+// subscribeReadyNotify subscribes to the rsync notifications for the AppContext.
+// The returned channel is nil if rsync can't be reached.
+func subscribeReadyNotify(ctxId string) (<-chan struct{}, func()) {
+	conn := rpc.GetRpcConn(rsyncName)
+	if ctxId == "" || conn == nil {
+		return nil, func() {}
+	}
+	client := readynotifypb.NewReadyNotifyClient(conn)
+	topic := &readynotifypb.Topic{
+		ClientName: fmt.Sprintf("orchestrator-events-%d", rand.Int63()),
+		AppContext: ctxId,
+	}
+	stream, err := client.Alert(context.Background(), topic)
+	if err != nil {
+		log.Info("Failed to subscribe to rsync notifications", log.Fields{"appContextId": ctxId, "error": err})
+		return nil, func() {}
+	}
+	notify := make(chan struct{}, 1)
+	go func() {
+		for {
+			if _, err := stream.Recv(); err != nil {
+				return
+			}
+			select {
+			case notify <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return notify, func() {
+		// Ends the stream of the subscription
+		client.Unsubscribe(context.Background(), topic)
+	}
+}
+
+// getDigStateEvents returns an event for each action of the StateInfo after
+// the first sent ones, or for the current state if none were sent yet
+func getDigStateEvents(s state.StateInfo, sent int) []DigEvent {
+	if (sent < 0 || sent > len(s.Actions)) && len(s.Actions) > 0 {
+		sent = len(s.Actions) - 1
+	}
+	events := []DigEvent{}
+	for i := sent; i >= 0 && i < len(s.Actions); i++ {
+		a := s.Actions[i]
+		e := DigEvent{Type: DigEventTypeEnum.State, ContextId: a.ContextId, Status: string(a.State), TimeStamp: a.TimeStamp}
+		if i > 0 {
+			e.Previous = string(s.Actions[i-1].State)
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+// getDigSnapshot returns the current status of the DeploymentIntentGroup as
+// events, identified by what they report on
+func getDigSnapshot(s state.StateInfo) map[string]DigEvent {
+	snapshot := make(map[string]DigEvent)
+	if len(s.Actions) == 0 {
+		return snapshot
+	}
+
+	result, err := status.PrepareStatusResult(s, "", "rsync", "all", nil, nil, nil)
+	if err != nil {
+		return snapshot
+	}
+	ctxId := state.GetLastContextIdFromStateInfo(s)
+	if result.Status != "" {
+		snapshot["1"] = DigEvent{Type: DigEventTypeEnum.AppContext, ContextId: ctxId, Status: string(result.Status)}
+	}
+	for _, app := range result.Apps {
+		for _, cl := range app.Clusters {
+			cluster := cl.ClusterProvider + "+" + cl.Cluster
+			snapshot["2/"+app.Name+"/"+cluster] = DigEvent{Type: DigEventTypeEnum.Cluster, ContextId: ctxId,
+				App: app.Name, Cluster: cluster, Status: cl.ReadyStatus}
+			for _, r := range cl.Resources {
+				resource := r.Name + "+" + r.Gvk.Kind
+				snapshot["3/"+app.Name+"/"+cluster+"/"+resource] = DigEvent{Type: DigEventTypeEnum.Resource, ContextId: ctxId,
+					App: app.Name, Cluster: cluster, Resource: resource, Status: r.RsyncStatus}
+			}
+		}
+	}
+	return snapshot
+}
+
+// diffDigSnapshots returns the events of cur that changed since prev, the
+// AppContext status first and then the cluster and resource status
+func diffDigSnapshots(prev, cur map[string]DigEvent) []DigEvent {
+	keys := make([]string, 0, len(cur))
+	for k := range cur {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	now := time.Now()
+	events := []DigEvent{}
+	for _, k := range keys {
+		e := cur[k]
+		p, ok := prev[k]
+		if ok && p.Status == e.Status && p.ContextId == e.ContextId {
+			continue
+		}
+		e.Previous = p.Status
+		e.TimeStamp = now
+		events = append(events, e)
+	}
+	return events
+}
+
+// publishDigState notifies the subscriptions of the project of a new state of
+// the DeploymentIntentGroup
+func publishDigState(p, ca, v, di string, a state.ActionEntry) {
+	data := map[string]string{
+		"state":    string(a.State),
+		"instance": a.ContextId,
+		"revision": strconv.FormatInt(a.Revision, 10),
+	}
+	if a.Reason != "" {
+		data["reason"] = a.Reason
+	}
+	subscription.Publish(p, subscription.EventTypeEnum.DigState, subscription.DigSource(p, ca, v, di), di, data)
+}
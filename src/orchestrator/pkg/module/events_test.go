@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package module
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/state"
+)
+
+func TestDiffDigSnapshots(t *testing.T) {
+	prev := map[string]DigEvent{
+		"1": {Type: DigEventTypeEnum.AppContext, ContextId: "1", Status: "Instantiating"},
+		"3/a1/p1+c1/r1+Deployment": {Type: DigEventTypeEnum.Resource, ContextId: "1", App: "a1", Cluster: "p1+c1",
+			Resource: "r1+Deployment", Status: "Pending"},
+	}
+	cur := map[string]DigEvent{
+		"1":          {Type: DigEventTypeEnum.AppContext, ContextId: "1", Status: "Instantiated"},
+		"2/a1/p1+c1": {Type: DigEventTypeEnum.Cluster, ContextId: "1", App: "a1", Cluster: "p1+c1", Status: "Available"},
+		"3/a1/p1+c1/r1+Deployment": {Type: DigEventTypeEnum.Resource, ContextId: "1", App: "a1", Cluster: "p1+c1",
+			Resource: "r1+Deployment", Status: "Applied"},
+	}
+
+	// All the events are reported first
+	events := diffDigSnapshots(nil, prev)
+	if len(events) != 2 || events[0].Type != DigEventTypeEnum.AppContext || events[0].Previous != "" {
+		t.Fatalf("Unexpected initial events %v", events)
+	}
+
+	events = diffDigSnapshots(prev, cur)
+	expected := []struct{ eventType, status, previous string }{
+		{DigEventTypeEnum.AppContext, "Instantiated", "Instantiating"},
+		{DigEventTypeEnum.Cluster, "Available", ""},
+		{DigEventTypeEnum.Resource, "Applied", "Pending"},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %v", len(expected), events)
+	}
+	for i, e := range expected {
+		if events[i].Type != e.eventType || events[i].Status != e.status || events[i].Previous != e.previous {
+			t.Errorf("Event %d is %v, expected %v", i, events[i], e)
+		}
+	}
+
+	if events := diffDigSnapshots(cur, cur); len(events) != 0 {
+		t.Errorf("Expected no events, got %v", events)
+	}
+}
+
+func TestGetDigStateEvents(t *testing.T) {
+	s := state.StateInfo{Actions: []state.ActionEntry{
+		{State: state.StateEnum.Created},
+		{State: state.StateEnum.Instantiated, ContextId: "1"},
+	}}
+
+	// Only the current state is reported first
+	events := getDigStateEvents(s, -1)
+	if len(events) != 1 || events[0].Status != state.StateEnum.Instantiated || events[0].Previous != state.StateEnum.Created {
+		t.Fatalf("Unexpected initial events %v", events)
+	}
+
+	// Each action added since is reported
+	s.Actions = append(s.Actions,
+		state.ActionEntry{State: state.StateEnum.Updated, ContextId: "2"},
+		state.ActionEntry{State: state.StateEnum.Instantiated, ContextId: "2"})
+	events = getDigStateEvents(s, 2)
+	expected := []struct{ status, previous string }{
+		{state.StateEnum.Updated, state.StateEnum.Instantiated},
+		{state.StateEnum.Instantiated, state.StateEnum.Updated},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %v", len(expected), events)
+	}
+	for i, e := range expected {
+		if events[i].Type != DigEventTypeEnum.State || events[i].Status != e.status || events[i].Previous != e.previous || events[i].ContextId != "2" {
+			t.Errorf("Event %d is %v, expected %v", i, events[i], e)
+		}
+	}
+
+	if events := getDigStateEvents(s, len(s.Actions)); len(events) != 0 {
+		t.Errorf("Expected no events, got %v", events)
+	}
+	if events := getDigStateEvents(state.StateInfo{}, -1); len(events) != 0 {
+		t.Errorf("Expected no events, got %v", events)
+	}
+}
+
+func TestWatchEvents(t *testing.T) {
+	db.DBconn = &db.NewMockDB{}
+	statePollInterval = 10 * time.Millisecond
+	defer func() { statePollInterval = 2 * time.Second }()
+
+	key := DeploymentIntentGroupKey{Name: "dig1", Project: "p1", CompositeApp: "ca1", Version: "v1"}
+	s := state.StateInfo{Actions: []state.ActionEntry{{State: state.StateEnum.Created, TimeStamp: time.Now()}}}
+	if err := db.DBconn.Insert("orchestrator", key, nil, "stateInfo", s); err != nil {
+		t.Fatalf("Insert returned an unexpected error %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := NewInstantiationClient().Events(ctx, "p1", "ca1", "v1", "dig1")
+	if err != nil {
+		t.Fatalf("Events returned an unexpected error %s", err)
+	}
+	e := <-events
+	if e.Type != DigEventTypeEnum.State || e.Status != string(state.StateEnum.Created) {
+		t.Errorf("Unexpected first event %v", e)
+	}
+
+	// The DeploymentIntentGroup deleted by another instance of the
+	// orchestrator ends the stream
+	if err := db.DBconn.Remove("orchestrator", key); err != nil {
+		t.Fatalf("Remove returned an unexpected error %s", err)
+	}
+	select {
+	case e, ok := <-events:
+		if ok {
+			t.Errorf("Unexpected event %v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("Expected the stream to end")
+	}
+}
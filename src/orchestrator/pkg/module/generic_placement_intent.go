@@ -4,6 +4,7 @@
 package module
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 
@@ -35,6 +36,7 @@ type GenericPlacementIntentManager interface {
 		compositeAppName string, version string, digName string) error
 
 	GetAllGenericPlacementIntents(p string, ca string, v string, digName string) ([]GenericPlacementIntent, error)
+	WithContext(ctx context.Context) GenericPlacementIntentManager
 }
 
 // GenericPlacementIntentKey is used as the primary key
@@ -60,6 +62,8 @@ func (gk GenericPlacementIntentKey) String() string {
 type GenericPlacementIntentClient struct {
 	storeName   string
 	tagMetaData string
+	// ctx is the context of the request using the client
+	ctx context.Context
 }
 
 // NewGenericPlacementIntentClient return an instance of GenericPlacementIntentClient which implements GenericPlacementIntentManager
@@ -70,6 +74,14 @@ func NewGenericPlacementIntentClient() *GenericPlacementIntentClient {
 	}
 }
 
+// WithContext returns a copy of the client making the database calls of
+// the generic placement intent of a request with its context
+func (c *GenericPlacementIntentClient) WithContext(ctx context.Context) GenericPlacementIntentManager {
+	n := *c
+	n.ctx = ctx
+	return &n
+}
+
 // CreateGenericPlacementIntent creates an entry for GenericPlacementIntent in the database. Other Input parameters for it - projectName, compositeAppName, version and deploymentIntentGroupName
 func (c *GenericPlacementIntentClient) CreateGenericPlacementIntent(g GenericPlacementIntent, p string, ca string,
 	v string, digName string) (GenericPlacementIntent, error) {
@@ -106,7 +118,7 @@ func (c *GenericPlacementIntentClient) CreateGenericPlacementIntent(g GenericPla
 		DigName:      digName,
 	}
 
-	err = db.DBconn.Insert(c.storeName, gkey, nil, c.tagMetaData, g)
+	err = db.WithContext(c.ctx).Insert(c.storeName, gkey, nil, c.tagMetaData, g)
 	if err != nil {
 		return GenericPlacementIntent{}, pkgerrors.Wrap(err, "Create DB entry error")
 	}
@@ -124,7 +136,7 @@ func (c *GenericPlacementIntentClient) GetGenericPlacementIntent(i string, p str
 		DigName:      digName,
 	}
 
-	result, err := db.DBconn.Find(c.storeName, key, c.tagMetaData)
+	result, err := db.WithContext(c.ctx).Find(c.storeName, key, c.tagMetaData)
 	if err != nil {
 		return GenericPlacementIntent{}, pkgerrors.Wrap(err, "db Find error")
 	}
@@ -194,7 +206,7 @@ func (c *GenericPlacementIntentClient) DeleteGenericPlacementIntent(i string, p
 		DigName:      digName,
 	}
 
-	err := db.DBconn.Remove(c.storeName, key)
+	err := db.WithContext(c.ctx).Remove(c.storeName, key)
 	if err != nil {
 		if strings.Contains(err.Error(), "Error finding:") {
 			return pkgerrors.Wrap(err, "db Remove error - not found")
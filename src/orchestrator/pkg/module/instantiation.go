@@ -4,15 +4,18 @@
 package module
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
 	gpic "github.com/open-ness/EMCO/src/orchestrator/pkg/gpic"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/tracing"
 	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/state"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/status"
@@ -93,7 +96,7 @@ type InstantiationKey struct {
 // InstantiationManager functionalities
 type InstantiationManager interface {
 	Approve(p string, ca string, v string, di string) error
-	Instantiate(p string, ca string, v string, di string) error
+	Instantiate(ctx context.Context, p string, ca string, v string, di string) error
 	Status(p, ca, v, di, qInstance, qType, qOutput string, fApps, fClusters, fResources []string) (DeploymentStatus, error)
 	StatusAppsList(p, ca, v, di, qInstance string) (DeploymentAppsListStatus, error)
 	StatusClustersByApp(p, ca, v, di, qInstance string, fApps []string) (DeploymentClustersByAppStatus, error)
@@ -101,8 +104,12 @@ type InstantiationManager interface {
 	Terminate(p string, ca string, v string, di string) error
 	Stop(p string, ca string, v string, di string) error
 	Migrate(p string, ca string, v string, tCav string, di string, tDi string) error
-	Update(p string, ca string, v string, di string) (int64, error)
+	Update(ctx context.Context, p string, ca string, v string, di string) (int64, error)
 	Rollback(p string, ca string, v string, di string, rbRev string) error
+	Plan(p string, ca string, v string, di string) (DeploymentPlan, error)
+	Revisions(p string, ca string, v string, di string) ([]DeploymentRevision, error)
+	RevisionDiff(p string, ca string, v string, di string, fromRev string, toRev string) (AppContextDiff, error)
+	Events(ctx context.Context, p string, ca string, v string, di string) (<-chan DigEvent, error)
 }
 
 // InstantiationClientDbInfo consists of storeName and tagState
@@ -150,6 +157,16 @@ func (c InstantiationClient) Approve(p string, ca string, v string, di string) e
 		return pkgerrors.Errorf("DeploymentIntentGroup is in an unknown state" + stateVal)
 	}
 
+	// The DeploymentIntentGroup is instantiated with the charts approved
+	err = pinCharts(p, ca, v, di)
+	if err != nil {
+		return err
+	}
+	err = validateDeploymentIntentGroup(p, ca, v, di)
+	if err != nil {
+		return err
+	}
+
 	key := DeploymentIntentGroupKey{
 		Name:         di,
 		Project:      p,
@@ -167,17 +184,29 @@ func (c InstantiationClient) Approve(p string, ca string, v string, di string) e
 	if err != nil {
 		return pkgerrors.Wrap(err, "Error updating the stateInfo of the DeploymentIntentGroup: "+di)
 	}
+	publishDigState(p, ca, v, di, a)
 
 	return nil
 }
 
-func getOverrideValuesByAppName(ov []OverrideValues, a string) map[string]string {
+func getOverrideValuesByAppName(ov []OverrideValues, a string) OverrideValues {
 	for _, eachOverrideVal := range ov {
 		if eachOverrideVal.AppName == a {
-			return eachOverrideVal.ValuesObj
+			return eachOverrideVal
 		}
 	}
-	return map[string]string{}
+	return OverrideValues{AppName: a}
+}
+
+// setValues returns the ValuesObj in the following format, sorted
+// foo=bar
+func (o OverrideValues) setValues() []string {
+	values := []string{}
+	for k, v := range o.ValuesObj {
+		values = append(values, k+"="+v)
+	}
+	sort.Strings(values)
+	return values
 }
 
 /*
@@ -201,20 +230,20 @@ func findGenericPlacementIntent(p, ca, v, di string) (string, error) {
 }
 
 // GetSortedTemplateForApp returns the sorted templates.
-//It takes in arguments - appName, project, compositeAppName, releaseName, compositeProfileName, array of override values
-func GetSortedTemplateForApp(appName, p, ca, v, rName, cp, namespace string, overrideValues []OverrideValues) ([]helm.KubernetesResourceTemplate, error) {
+//It takes in arguments - appName, project, compositeAppName, releaseName, compositeProfileName, deploymentIntentGroupName, array of override values
+func GetSortedTemplateForApp(appName, p, ca, v, rName, cp, di, namespace string, overrideValues []OverrideValues) ([]helm.KubernetesResourceTemplate, error) {
 
 	log.Info(":: Processing App ::", log.Fields{"appName": appName})
 
 	var sortedTemplates []helm.KubernetesResourceTemplate
 
-	aC, err := NewAppClient().GetAppContent(appName, p, ca, v)
+	app, err := NewAppClient().GetApp(appName, p, ca, v)
 	if err != nil {
-		return sortedTemplates, pkgerrors.Wrap(err, fmt.Sprint("Not finding the content of app:: ", appName))
+		return sortedTemplates, pkgerrors.Wrap(err, fmt.Sprint("Not finding the app:: ", appName))
 	}
-	appContent, err := base64.StdEncoding.DecodeString(aC.FileContent)
+	appContent, err := getAppFile(app, p, ca, v, di)
 	if err != nil {
-		return sortedTemplates, pkgerrors.Wrap(err, "Fail to convert to byte array")
+		return sortedTemplates, err
 	}
 
 	log.Info(":: Got the app content.. ::", log.Fields{"appName": appName})
@@ -230,20 +259,8 @@ func GetSortedTemplateForApp(appName, p, ca, v, rName, cp, namespace string, ove
 
 	log.Info(":: Got the app Profile content .. ::", log.Fields{"appName": appName})
 
-	overrideValuesOfApp := getOverrideValuesByAppName(overrideValues, appName)
-	//Convert override values from map to array of strings of the following format
-	//foo=bar
-	overrideValuesOfAppStr := []string{}
-	if overrideValuesOfApp != nil {
-		for k, v := range overrideValuesOfApp {
-			overrideValuesOfAppStr = append(overrideValuesOfAppStr, k+"="+v)
-		}
-	}
-
-	sortedTemplates, err = helm.NewTemplateClient("", namespace, rName,
-		ManifestFileName).Resolve(appContent,
-		appProfileContent, overrideValuesOfAppStr,
-		appName)
+	sortedTemplates, err = renderApp(app, appContent, appProfileContent,
+		getOverrideValuesByAppName(overrideValues, appName), namespace, rName)
 
 	log.Info(":: Total no. of sorted templates ::", log.Fields{"len(sortedTemplates):": len(sortedTemplates)})
 
@@ -385,7 +402,9 @@ Instantiate methods takes in projectName, compositeAppName, compositeAppVersion,
 DeploymentIntentName. This method is responsible for template resolution, intent
 resolution, creation and saving of context for saving into etcd.
 */
-func (c InstantiationClient) Instantiate(p string, ca string, v string, di string) error {
+func (c InstantiationClient) Instantiate(ctx context.Context, p string, ca string, v string, di string) error {
+	// The calls to the controllers and rsync go on if the request is canceled
+	ctx = tracing.Detach(ctx)
 
 	log.Info(":: Orchestrator Instantiate ::", log.Fields{"project": p, "composite-app": ca, "composite-app-ver": v, "dep-group": di})
 
@@ -403,28 +422,35 @@ func (c InstantiationClient) Instantiate(p string, ca string, v string, di strin
 
 	// BEGIN : Make app context
 	instantiator := Instantiator{p, ca, v, di, dIGrp}
-	cca, err := instantiator.MakeAppContext()
+	cca, err := instantiator.MakeAppContext(ctx)
 	if err != nil {
 		return pkgerrors.Wrap(err, "Error in making AppContext")
 	}
 	// END : Make app context
 
 	// BEGIN : callScheduler
-	err = callScheduler(cca.context, cca.ctxval, p, ca, v, di)
+	err = callScheduler(ctx, cca.context, cca.ctxval, p, ca, v, di)
 	if err != nil {
 		return pkgerrors.Wrap(err, "Error in callScheduler")
 	}
 	// END : callScheduler
 
+	release, err := checkDeploymentQuota(p, ca, v, di, cca.context)
+	if err != nil {
+		deleteAppContext(cca.context)
+		return err
+	}
+	defer release()
+
 	// BEGIN : Rsync code
-	err = callRsyncInstall(cca.ctxval)
+	err = callRsyncInstall(ctx, cca.ctxval)
 	if err != nil {
 		deleteAppContext(cca.context)
 		return pkgerrors.Wrap(err, "Error calling rsync")
 	}
 	// END : Rsync code
 
-	err = storeAppContextIntoMetaDB(cca.ctxval, c.db.storeName, c.db.tagState, s, p, ca, v, di)
+	err = storeAppContextIntoMetaDB(ctx, cca.ctxval, c.db.storeName, c.db.tagState, s, p, ca, v, di)
 
 	log.Info(":: Done with instantiation call to rsync... ::", log.Fields{"CompositeAppName": ca})
 	return err
@@ -591,28 +617,8 @@ func (c InstantiationClient) Terminate(p string, ca string, v string, di string)
 		}
 	}
 
-	var ac appcontext.AppContext
-	_, err = ac.LoadAppContext(currentCtxId)
-	if err != nil {
-		return pkgerrors.Wrapf(err, "Error getting AppContext with Id: %v", currentCtxId)
-	}
-
-	// Get the composite app meta
-	m, err := ac.GetCompositeAppMeta()
-	if err != nil {
-		return pkgerrors.Wrap(err, "Error getting CompositeAppMeta")
-	}
-	if len(m.ChildContextIDs) > 0 {
-		// Uninstall the resources associated to the child contexts
-		for _, childContextID := range m.ChildContextIDs {
-			err = callRsyncUninstall(childContextID)
-			if err != nil {
-				log.Warn("Unable to uninstall the resources associated to the child context", log.Fields{"childContext": childContextID})
-				continue
-			}
-		}
-	}
-	// Uninstall the resources associated to the parent contexts
+	// Uninstall the resources associated to the context, rsync uninstalls
+	// the resources of its child contexts with them
 	err = callRsyncUninstall(currentCtxId)
 	if err != nil {
 		return err
@@ -635,6 +641,7 @@ func (c InstantiationClient) Terminate(p string, ca string, v string, di string)
 	if err != nil {
 		return pkgerrors.Wrap(err, "Error updating the stateInfo of the DeploymentIntentGroup: "+di)
 	}
+	publishDigState(p, ca, v, di, a)
 
 	return nil
 }
@@ -709,6 +716,7 @@ func (c InstantiationClient) Stop(p string, ca string, v string, di string) erro
 	if err != nil {
 		return pkgerrors.Wrap(err, "Error updating the stateInfo of the DeploymentIntentGroup: "+di)
 	}
+	publishDigState(p, ca, v, di, a)
 
 	return nil
 }
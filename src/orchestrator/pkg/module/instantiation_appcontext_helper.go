@@ -9,6 +9,7 @@ It contains methods for creating appContext, saving cluster and resource details
 
 */
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"time"
@@ -49,17 +50,25 @@ type appOrderInstr struct {
 	Apporder []string `json:"apporder"`
 }
 
+// appDepInstr maps each app to "go" if it has no dependency, or to the
+// apps it depends on along with the criteria to wait for
 type appDepInstr struct {
-	AppDepMap map[string]string `json:"appdependency"`
+	AppDepMap map[string]interface{} `json:"appdependency"`
+}
+
+type appDepCriteria struct {
+	OpStatus string `json:"opstatus,omitempty"`
+	Wait     int    `json:"wait,omitempty"`
 }
 
 // makeAppContext creates an appContext for a compositeApp and returns the output as contextForCompositeApp
-func makeAppContextForCompositeApp(p, ca, v, rName, dig string, namespace string, level string) (contextForCompositeApp, error) {
+func makeAppContextForCompositeApp(ctx context.Context, p, ca, v, rName, dig string, namespace string, level string) (contextForCompositeApp, error) {
 	context := appcontext.AppContext{}
 	ctxval, err := context.InitAppContext()
 	if err != nil {
 		return contextForCompositeApp{}, pkgerrors.Wrap(err, "Error creating AppContext CompositeApp")
 	}
+	context = context.WithContext(ctx)
 	compositeHandle, err := context.CreateCompositeApp()
 	if err != nil {
 		return contextForCompositeApp{}, pkgerrors.Wrap(err, "Error creating CompositeApp handle")
@@ -273,13 +282,27 @@ func storeAppContextIntoRunTimeDB(allApps []App, cxtForCApp contextForCompositeA
 	var appOrdInsStr appOrderInstr
 	// for recording the app dependency
 	var appDepStr appDepInstr
-	appDepStr.AppDepMap = make(map[string]string)
+	appDepStr.AppDepMap = make(map[string]interface{})
 
 	for _, eachApp := range allApps {
 		appOrdInsStr.Apporder = append(appOrdInsStr.Apporder, eachApp.Metadata.Name)
-		appDepStr.AppDepMap[eachApp.Metadata.Name] = "go"
 
-		sortedTemplates, err := GetSortedTemplateForApp(eachApp.Metadata.Name, p, ca, v, rName, cp, namespace, overrideValues)
+		appDeps, err := NewAppDependencyClient().GetAllAppDependency(p, ca, v, eachApp.Metadata.Name)
+		if err != nil {
+			deleteAppContext(context)
+			return pkgerrors.Wrapf(err, "Unable to get the dependencies for app :: %s", eachApp.Metadata.Name)
+		}
+		if len(appDeps) == 0 {
+			appDepStr.AppDepMap[eachApp.Metadata.Name] = "go"
+		} else {
+			depMap := make(map[string]appDepCriteria)
+			for _, d := range appDeps {
+				depMap[d.Spec.AppName] = appDepCriteria{OpStatus: d.Spec.OpStatus, Wait: d.Spec.Wait}
+			}
+			appDepStr.AppDepMap[eachApp.Metadata.Name] = depMap
+		}
+
+		sortedTemplates, err := GetSortedTemplateForApp(eachApp.Metadata.Name, p, ca, v, rName, cp, di, namespace, overrideValues)
 
 		if err != nil {
 			deleteAppContext(context)
@@ -345,7 +368,7 @@ func storeAppContextIntoRunTimeDB(allApps []App, cxtForCApp contextForCompositeA
 		return pkgerrors.Wrap(err, "Error marshalling app order instruction")
 	}
 
-	jappDepInstr, err := json.Marshal(appDepStr.AppDepMap)
+	jappDepInstr, err := json.Marshal(appDepStr)
 	if err != nil {
 		deleteAppContext(context)
 		return pkgerrors.Wrap(err, "Error marshalling app dependency instruction")
@@ -353,7 +376,7 @@ func storeAppContextIntoRunTimeDB(allApps []App, cxtForCApp contextForCompositeA
 	_, err = context.AddInstruction(cxtForCApp.compositeAppHandle, "app", "order", string(jappOrderInstr))
 	if err != nil {
 		deleteAppContext(context)
-		return pkgerrors.Wrap(err, "Error adding app dependency instruction")
+		return pkgerrors.Wrap(err, "Error adding app order instruction")
 	}
 	_, err = context.AddInstruction(cxtForCApp.compositeAppHandle, "app", "dependency", string(jappDepInstr))
 	if err != nil {
@@ -365,7 +388,7 @@ func storeAppContextIntoRunTimeDB(allApps []App, cxtForCApp contextForCompositeA
 	return nil
 }
 
-func storeAppContextIntoMetaDB(ctxval interface{}, storeName string, colName string, s state.StateInfo, p, ca, v, di string) error {
+func storeAppContextIntoMetaDB(ctx context.Context, ctxval interface{}, storeName string, colName string, s state.StateInfo, p, ca, v, di string) error {
 
 	// BEGIN:: save the context in the orchestrator db record
 	key := DeploymentIntentGroupKey{
@@ -382,12 +405,13 @@ func storeAppContextIntoMetaDB(ctxval interface{}, storeName string, colName str
 	}
 	s.StatusContextId = ctxval.(string)
 	s.Actions = append(s.Actions, a)
-	err := db.DBconn.Insert(storeName, key, nil, colName, s)
+	err := db.WithContext(ctx).Insert(storeName, key, nil, colName, s)
 	if err != nil {
 		log.Warn(":: Error updating DeploymentIntentGroup state in DB ::", log.Fields{"Error": err.Error(), "DeploymentIntentGroup": di, "CompositeApp": ca, "CompositeAppVersion": v, "Project": p, "AppContext": ctxval.(string)})
 		return pkgerrors.Wrap(err, "Error adding DeploymentIntentGroup state to DB")
 	}
 	// END:: save the context in the orchestrator db record
+	publishDigState(p, ca, v, di, a)
 	return nil
 }
 
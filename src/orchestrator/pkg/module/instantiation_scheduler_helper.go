@@ -5,6 +5,7 @@ package module
 
 import (
 	"container/heap"
+	"context"
 
 	"fmt"
 
@@ -13,9 +14,11 @@ import (
 	rsyncclient "github.com/open-ness/EMCO/src/orchestrator/pkg/grpc/installappclient"
 	plsGrpcClient "github.com/open-ness/EMCO/src/orchestrator/pkg/grpc/placementcontrollerclient"
 	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/tracing"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/module/controller"
 	mtypes "github.com/open-ness/EMCO/src/orchestrator/pkg/module/types"
 	pkgerrors "github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // ControllerTypePlacement denotes "placement" Controller Type
@@ -173,14 +176,16 @@ func getPrioritizedControllerList(p, ca, v, di string) (PrioritizedControlList,
 callGrpcForControllerList method shall take in a list of controllers, a map of contollers to controllerIntentNames and contextID. It invokes the context
 updation through the grpc client for the given list of controllers.
 */
-func callGrpcForControllerList(cl []controller.Controller, mc map[string]string, contextid interface{}) error {
+func callGrpcForControllerList(ctx context.Context, cl []controller.Controller, mc map[string]string, contextid interface{}) error {
 	for _, c := range cl {
 		controller := c.Metadata.Name
 		controllerIntentName := mc[controller]
 		appContextID := fmt.Sprintf("%v", contextid)
 		log.Info("callGrpcForControllerList .. Invoking action-controller.", log.Fields{
 			"controller": controller, "controllerIntentName": controllerIntentName, "appContextID": appContextID})
-		err := client.InvokeContextUpdate(controller, controllerIntentName, appContextID)
+		cctx, span := tracing.Start(ctx, "UpdateAppContext", attribute.String("controller", controller))
+		err := client.InvokeContextUpdate(cctx, controller, controllerIntentName, appContextID)
+		tracing.End(span, err)
 		if err != nil {
 			return err
 		}
@@ -192,13 +197,15 @@ func callGrpcForControllerList(cl []controller.Controller, mc map[string]string,
 callGrpcForPlacementControllerList method shall take in a list of placement controllers, a map of contollers to controllerIntentNames and contextID.
 It invokes the filter clusters through the grpc client for the given list of controllers.
 */
-func callGrpcForPlacementControllerList(cl []controller.Controller, contextid interface{}) error {
+func callGrpcForPlacementControllerList(ctx context.Context, cl []controller.Controller, contextid interface{}) error {
 	for _, c := range cl {
 		controller := c.Metadata.Name
 		appContextID := fmt.Sprintf("%v", contextid)
 		log.Info("callGrpcForControllerList .. Invoking placement-controller.", log.Fields{
 			"controller": controller, "appContextID": appContextID})
-		err := plsGrpcClient.InvokeFilterClusters(c, appContextID)
+		cctx, span := tracing.Start(ctx, "FilterClusters", attribute.String("controller", controller))
+		err := plsGrpcClient.InvokeFilterClusters(cctx, c, appContextID)
+		tracing.End(span, err)
 		if err != nil {
 			return pkgerrors.Wrapf(err, "Placement-controller returned error. failed-placement-controller[%v] appContextID[%v]", controller, appContextID)
 		}
@@ -228,7 +235,7 @@ func queryDBAndSetRsyncInfo() (rsyncclient.RsyncInfo, error) {
 /*
 callRsyncInstall method shall take in the app context id and invokes the rsync service via grpc
 */
-func callRsyncInstall(contextid interface{}) error {
+func callRsyncInstall(ctx context.Context, contextid interface{}) error {
 	rsyncInfo, err := queryDBAndSetRsyncInfo()
 	log.Info("Calling the Rsync ", log.Fields{
 		"RsyncName": rsyncInfo.RsyncName,
@@ -238,7 +245,7 @@ func callRsyncInstall(contextid interface{}) error {
 	}
 
 	appContextID := fmt.Sprintf("%v", contextid)
-	err = rsyncclient.InvokeInstallApp(appContextID)
+	err = rsyncclient.InvokeInstallApp(ctx, appContextID)
 	if err != nil {
 		return err
 	}
@@ -303,7 +310,7 @@ func deleteExtraClusters(apps []App, ct appcontext.AppContext) error {
 }
 
 // callScheduler instantiates based on the controller priority list
-func callScheduler(context appcontext.AppContext, ctxval interface{}, p, ca, v, di string) (error) {
+func callScheduler(ctx context.Context, context appcontext.AppContext, ctxval interface{}, p, ca, v, di string) (error) {
 	// BEGIN: scheduler code
 
 	allApps, err := NewAppClient().GetApps(p, ca, v)
@@ -319,7 +326,7 @@ func callScheduler(context appcontext.AppContext, ctxval interface{}, p, ca, v,
 	log.Info("Orchestrator Instantiate .. Priority Based List ", log.Fields{"PlacementControllers::": pl.pPlaCont,
 		"ActionControllers::": pl.pActCont, "mapOfControllers::": mapOfControllers})
 	// Invoke all Placement Controllers communication interface in loop
-	err = callGrpcForPlacementControllerList(pl.pPlaCont, ctxval)
+	err = callGrpcForPlacementControllerList(ctx, pl.pPlaCont, ctxval)
 	if err != nil {
 		deleteAppContext(context)
 		log.Error("Orchestrator Instantiate .. Error calling PlacementController gRPC.", log.Fields{"all-placement-controllers": pl.pPlaCont, "err": err})
@@ -334,7 +341,7 @@ func callScheduler(context appcontext.AppContext, ctxval interface{}, p, ca, v,
 	}
 
 	// Invoke all Action Controllers communication interface
-	err = callGrpcForControllerList(pl.pActCont, mapOfControllers, ctxval)
+	err = callGrpcForControllerList(ctx, pl.pActCont, mapOfControllers, ctxval)
 	log.Warn("", log.Fields{"pl.pActCont::": pl.pActCont})
 	log.Warn("", log.Fields{"mapOfControllers::": mapOfControllers})
 	log.Warn("", log.Fields{"ctxval::": ctxval})
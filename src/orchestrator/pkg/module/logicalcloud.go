@@ -34,9 +34,10 @@ type MetaDataList struct {
 
 // Spec contains the parameters needed for spec
 type Spec struct {
-	NameSpace string   `json:"namespace"`
-	Level     string   `json:"level"`
-	User      UserData `json:"user"`
+	NameSpace   string       `json:"namespace"`
+	Level       string       `json:"level"`
+	User        UserData     `json:"user"`
+	RetryPolicy *RetryPolicy `json:"retry-policy,omitempty"`
 }
 
 // UserData contains the parameters needed for user
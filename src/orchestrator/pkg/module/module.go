@@ -4,7 +4,9 @@
 package module
 
 import (
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/audit"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/module/controller"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/module/subscription"
 )
 
 // Client for using the services in the orchestrator
@@ -12,6 +14,7 @@ type Client struct {
 	Project                *ProjectClient
 	CompositeApp           *CompositeAppClient
 	App                    *AppClient
+	AppDependency          *AppDependencyClient
 	Controller             *controller.ControllerClient
 	GenericPlacementIntent *GenericPlacementIntentClient
 	AppIntent              *AppIntentClient
@@ -19,6 +22,8 @@ type Client struct {
 	Intent                 *IntentClient
 	CompositeProfile       *CompositeProfileClient
 	AppProfile             *AppProfileClient
+	Subscription           *subscription.SubscriptionClient
+	Audit                  *audit.AuditClient
 	// Add Clients for API's here
 	Instantiation *InstantiationClient
 }
@@ -29,6 +34,7 @@ func NewClient() *Client {
 	c.Project = NewProjectClient()
 	c.CompositeApp = NewCompositeAppClient()
 	c.App = NewAppClient()
+	c.AppDependency = NewAppDependencyClient()
 	c.Controller = controller.NewControllerClient("controller", "controllermetadata")
 	c.GenericPlacementIntent = NewGenericPlacementIntentClient()
 	c.AppIntent = NewAppIntentClient()
@@ -36,6 +42,8 @@ func NewClient() *Client {
 	c.Intent = NewIntentClient()
 	c.CompositeProfile = NewCompositeProfileClient()
 	c.AppProfile = NewAppProfileClient()
+	c.Subscription = subscription.NewSubscriptionClient()
+	c.Audit = audit.NewAuditClient()
 	// Add Client API handlers here
 	c.Instantiation = NewInstantiationClient()
 	return c
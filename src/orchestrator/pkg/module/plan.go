@@ -0,0 +1,354 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package module
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
+	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/state"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Kinds of change reported when comparing two AppContexts
+const (
+	DiffAdded    = "added"
+	DiffRemoved  = "removed"
+	DiffModified = "modified"
+)
+
+// DeploymentPlan is the result of a dry run of the instantiation of a
+// DeploymentIntentGroup. It holds the resources, as resolved by the placement
+// and action controllers, that would be deployed on each cluster.
+type DeploymentPlan struct {
+	Project               string          `json:"project,omitempty"`
+	CompositeAppName      string          `json:"composite-app-name,omitempty"`
+	CompositeAppVersion   string          `json:"composite-app-version,omitempty"`
+	DeploymentIntentGroup string          `json:"deployment-intent-group,omitempty"`
+	Apps                  []PlanApp       `json:"apps"`
+	Diff                  *AppContextDiff `json:"diff,omitempty"`
+}
+
+// PlanApp holds the clusters an app is placed on
+type PlanApp struct {
+	Name     string        `json:"name"`
+	Clusters []PlanCluster `json:"clusters"`
+}
+
+// PlanCluster holds the resources of an app on a cluster
+type PlanCluster struct {
+	Name      string         `json:"name"`
+	Resources []PlanResource `json:"resources"`
+}
+
+// PlanResource is a rendered resource
+type PlanResource struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+// AppContextDiff reports the apps, clusters and resources that changed
+// between two AppContexts
+type AppContextDiff struct {
+	From string    `json:"from,omitempty"`
+	To   string    `json:"to,omitempty"`
+	Apps []AppDiff `json:"apps"`
+}
+
+// AppDiff reports the change of an app
+type AppDiff struct {
+	Name     string        `json:"name"`
+	Change   string        `json:"change"`
+	Clusters []ClusterDiff `json:"clusters,omitempty"`
+}
+
+// ClusterDiff reports the change of an app on a cluster
+type ClusterDiff struct {
+	Name      string         `json:"name"`
+	Change    string         `json:"change"`
+	Resources []ResourceDiff `json:"resources,omitempty"`
+}
+
+// ResourceDiff reports the change of a resource
+// Diff is the unified diff of the YAML of a modified resource
+type ResourceDiff struct {
+	Name   string `json:"name"`
+	Change string `json:"change"`
+	Diff   string `json:"diff,omitempty"`
+}
+
+/*
+Plan takes in projectName, compositeAppName, compositeAppVersion,
+DeploymentIntentName. It makes the AppContext and calls the placement and
+action controllers like Instantiate does, but doesn't call rsync. The resolved
+resources are returned and the AppContext is deleted. Like Instantiate, the
+DeploymentIntentGroup must be approved. If it is instantiated, the plan also
+includes the difference with the current AppContext.
+*/
+func (c InstantiationClient) Plan(p string, ca string, v string, di string) (DeploymentPlan, error) {
+
+	log.Info(":: Orchestrator Plan ::", log.Fields{"project": p, "composite-app": ca, "composite-app-ver": v, "dep-group": di})
+
+	dIGrp, err := NewDeploymentIntentGroupClient().GetDeploymentIntentGroup(di, p, ca, v)
+	if err != nil {
+		return DeploymentPlan{}, pkgerrors.Wrap(err, "Not finding the deploymentIntentGroup")
+	}
+
+	s, err := NewDeploymentIntentGroupClient().GetDeploymentIntentGroupState(di, p, ca, v)
+	if err != nil {
+		return DeploymentPlan{}, pkgerrors.Wrap(err, "DeploymentIntentGroup has no state info: "+di)
+	}
+	stateVal, err := state.GetCurrentStateFromStateInfo(s)
+	if err != nil {
+		return DeploymentPlan{}, pkgerrors.Errorf("Error getting current state from DeploymentIntentGroup stateInfo: " + di)
+	}
+	deployed, err := checkPlanState(stateVal, di)
+	if err != nil {
+		return DeploymentPlan{}, err
+	}
+
+	instantiator := Instantiator{p, ca, v, di, dIGrp}
+	ctx := context.Background()
+	cca, err := instantiator.MakeAppContext(ctx)
+	if err != nil {
+		return DeploymentPlan{}, pkgerrors.Wrap(err, "Error in making AppContext")
+	}
+	// The AppContext is only needed to build the plan
+	defer deleteAppContext(cca.context)
+
+	err = callScheduler(ctx, cca.context, cca.ctxval, p, ca, v, di)
+	if err != nil {
+		return DeploymentPlan{}, pkgerrors.Wrap(err, "Error in callScheduler")
+	}
+
+	apps, err := readAppContextPlan(cca.context)
+	if err != nil {
+		return DeploymentPlan{}, pkgerrors.Wrap(err, "Error reading the AppContext")
+	}
+
+	plan := DeploymentPlan{
+		Project:               p,
+		CompositeAppName:      ca,
+		CompositeAppVersion:   v,
+		DeploymentIntentGroup: di,
+		Apps:                  apps,
+	}
+
+	if !deployed {
+		return plan, nil
+	}
+
+	currentCtxId := state.GetLastContextIdFromStateInfo(s)
+	currentAc, err := state.GetAppContextFromId(currentCtxId)
+	if err != nil {
+		return DeploymentPlan{}, pkgerrors.Wrapf(err, "Error getting AppContext with Id: %v", currentCtxId)
+	}
+	currentApps, err := readAppContextPlan(currentAc)
+	if err != nil {
+		return DeploymentPlan{}, pkgerrors.Wrap(err, "Error reading the current AppContext")
+	}
+	diff := diffPlanApps(currentApps, apps)
+	diff.From = currentCtxId
+	plan.Diff = &diff
+
+	return plan, nil
+}
+
+// checkPlanState returns an error if the DeploymentIntentGroup can't be
+// planned in its state, and whether its current AppContext is deployed, in
+// which case the plan is compared with it
+func checkPlanState(stateVal string, di string) (bool, error) {
+	switch stateVal {
+	case state.StateEnum.Approved, state.StateEnum.Terminated, state.StateEnum.TerminateStopped:
+		return false, nil
+	case state.StateEnum.Instantiated, state.StateEnum.InstantiateStopped, state.StateEnum.Updated:
+		return true, nil
+	case state.StateEnum.Created:
+		return false, pkgerrors.Errorf("DeploymentIntentGroup must be Approved before planning: " + di)
+	default:
+		return false, pkgerrors.Errorf("DeploymentIntentGroup is in an invalid state for planning: " + stateVal)
+	}
+}
+
+// readAppContextPlan reads the apps, clusters and resources of an AppContext
+// Apps and resources are listed in the order they are deployed
+func readAppContextPlan(ac appcontext.AppContext) ([]PlanApp, error) {
+	apps := []PlanApp{}
+
+	appOrder, err := ac.GetAppInstruction(appcontext.OrderInstruction)
+	if err != nil {
+		return apps, err
+	}
+	var aov map[string][]string
+	err = json.Unmarshal([]byte(fmt.Sprintf("%v", appOrder)), &aov)
+	if err != nil {
+		return apps, pkgerrors.Wrap(err, "Error unmarshalling app order instruction")
+	}
+
+	for _, app := range aov["apporder"] {
+		clusters, err := ac.GetClusterNames(app)
+		if err != nil {
+			// Apps not placed on any cluster have no resources
+			log.Info(":: No clusters for app ::", log.Fields{"app": app, "error": err})
+			continue
+		}
+		sort.Strings(clusters)
+
+		planApp := PlanApp{Name: app, Clusters: []PlanCluster{}}
+		for _, cluster := range clusters {
+			resources, err := getResourceOrder(ac, app, cluster)
+			if err != nil {
+				return apps, err
+			}
+			planCluster := PlanCluster{Name: cluster, Resources: []PlanResource{}}
+			for _, res := range resources {
+				rh, err := ac.GetResourceHandle(app, cluster, res)
+				if err != nil {
+					return apps, pkgerrors.Wrapf(err, "Error getting resource handle for resource :: %s, app:: %s, cluster :: %s", res, app, cluster)
+				}
+				value, err := ac.GetValue(rh)
+				if err != nil {
+					return apps, pkgerrors.Wrapf(err, "Error getting resource value for resource :: %s, app:: %s, cluster :: %s", res, app, cluster)
+				}
+				planCluster.Resources = append(planCluster.Resources, PlanResource{Name: res, Content: fmt.Sprintf("%v", value)})
+			}
+			planApp.Clusters = append(planApp.Clusters, planCluster)
+		}
+		apps = append(apps, planApp)
+	}
+	return apps, nil
+}
+
+// getResourceOrder returns the resources of an app on a cluster in the order
+// of the resource order instruction, falling back to the sorted resource names
+func getResourceOrder(ac appcontext.AppContext, app, cluster string) ([]string, error) {
+	resOrder, err := ac.GetResourceInstruction(app, cluster, appcontext.OrderInstruction)
+	if err == nil {
+		var rov map[string][]string
+		err = json.Unmarshal([]byte(fmt.Sprintf("%v", resOrder)), &rov)
+		if err == nil {
+			return rov["resorder"], nil
+		}
+	}
+	resources, err := ac.GetResourceNames(app, cluster)
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "Error getting resources for app:: %s, cluster :: %s", app, cluster)
+	}
+	sort.Strings(resources)
+	return resources, nil
+}
+
+// diffPlanApps compares the apps of two AppContexts
+func diffPlanApps(from, to []PlanApp) AppContextDiff {
+	diff := AppContextDiff{Apps: []AppDiff{}}
+
+	fromApps := make(map[string]PlanApp)
+	for _, a := range from {
+		fromApps[a.Name] = a
+	}
+	toApps := make(map[string]PlanApp)
+	for _, a := range to {
+		toApps[a.Name] = a
+	}
+
+	for _, a := range to {
+		fa, ok := fromApps[a.Name]
+		if !ok {
+			diff.Apps = append(diff.Apps, AppDiff{Name: a.Name, Change: DiffAdded, Clusters: diffPlanClusters(nil, a.Clusters)})
+			continue
+		}
+		clusters := diffPlanClusters(fa.Clusters, a.Clusters)
+		if len(clusters) > 0 {
+			diff.Apps = append(diff.Apps, AppDiff{Name: a.Name, Change: DiffModified, Clusters: clusters})
+		}
+	}
+	for _, a := range from {
+		if _, ok := toApps[a.Name]; !ok {
+			diff.Apps = append(diff.Apps, AppDiff{Name: a.Name, Change: DiffRemoved, Clusters: diffPlanClusters(a.Clusters, nil)})
+		}
+	}
+	return diff
+}
+
+// diffPlanClusters compares the clusters of an app, only changed clusters are returned
+func diffPlanClusters(from, to []PlanCluster) []ClusterDiff {
+	var diff []ClusterDiff
+
+	fromClusters := make(map[string]PlanCluster)
+	for _, c := range from {
+		fromClusters[c.Name] = c
+	}
+	toClusters := make(map[string]PlanCluster)
+	for _, c := range to {
+		toClusters[c.Name] = c
+	}
+
+	for _, c := range to {
+		fc, ok := fromClusters[c.Name]
+		if !ok {
+			diff = append(diff, ClusterDiff{Name: c.Name, Change: DiffAdded, Resources: diffPlanResources(nil, c.Resources)})
+			continue
+		}
+		resources := diffPlanResources(fc.Resources, c.Resources)
+		if len(resources) > 0 {
+			diff = append(diff, ClusterDiff{Name: c.Name, Change: DiffModified, Resources: resources})
+		}
+	}
+	for _, c := range from {
+		if _, ok := toClusters[c.Name]; !ok {
+			diff = append(diff, ClusterDiff{Name: c.Name, Change: DiffRemoved, Resources: diffPlanResources(c.Resources, nil)})
+		}
+	}
+	return diff
+}
+
+// diffPlanResources compares the resources of an app on a cluster, only changed resources are returned
+func diffPlanResources(from, to []PlanResource) []ResourceDiff {
+	var diff []ResourceDiff
+
+	fromResources := make(map[string]PlanResource)
+	for _, r := range from {
+		fromResources[r.Name] = r
+	}
+	toResources := make(map[string]PlanResource)
+	for _, r := range to {
+		toResources[r.Name] = r
+	}
+
+	for _, r := range to {
+		fr, ok := fromResources[r.Name]
+		if !ok {
+			diff = append(diff, ResourceDiff{Name: r.Name, Change: DiffAdded})
+		} else if fr.Content != r.Content {
+			diff = append(diff, ResourceDiff{Name: r.Name, Change: DiffModified, Diff: unifiedDiff(r.Name, fr.Content, r.Content)})
+		}
+	}
+	for _, r := range from {
+		if _, ok := toResources[r.Name]; !ok {
+			diff = append(diff, ResourceDiff{Name: r.Name, Change: DiffRemoved})
+		}
+	}
+	return diff
+}
+
+// unifiedDiff returns the unified diff between two versions of a resource
+func unifiedDiff(name, from, to string) string {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(from),
+		B:        difflib.SplitLines(to),
+		FromFile: "a/" + name,
+		ToFile:   "b/" + name,
+		Context:  3,
+	})
+	if err != nil {
+		log.Warn(":: Error computing the diff of resource ::", log.Fields{"resource": name, "error": err})
+		return ""
+	}
+	return diff
+}
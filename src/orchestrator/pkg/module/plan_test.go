@@ -0,0 +1,190 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package module
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/contextdb"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/state"
+)
+
+func makePlanTestAppContext(t *testing.T, resources map[string]map[string]string) appcontext.AppContext {
+	ac := appcontext.AppContext{}
+	_, err := ac.InitAppContext()
+	if err != nil {
+		t.Fatalf("Error initializing AppContext %v", err)
+	}
+	cah, err := ac.CreateCompositeApp()
+	if err != nil {
+		t.Fatalf("Error creating composite app %v", err)
+	}
+	order := []string{}
+	for _, app := range []string{"app1", "app2"} {
+		res, ok := resources[app]
+		if !ok {
+			continue
+		}
+		order = append(order, "\""+app+"\"")
+		ah, _ := ac.AddApp(cah, app)
+		ch, _ := ac.AddCluster(ah, "provider1+cluster1")
+		for name, content := range res {
+			ac.AddResource(ch, name, content)
+		}
+	}
+	ac.AddInstruction(cah, "app", "order", "{\"apporder\":["+strings.Join(order, ",")+"]}")
+	return ac
+}
+
+func TestPlanDiff(t *testing.T) {
+	contextdb.Db = &contextdb.MockConDb{}
+
+	current := makePlanTestAppContext(t, map[string]map[string]string{
+		"app1": {"r1+Deployment": "replicas: 1", "r2+Service": "port: 80"},
+	})
+	planned := makePlanTestAppContext(t, map[string]map[string]string{
+		"app1": {"r1+Deployment": "replicas: 2", "r3+ConfigMap": "a: b"},
+		"app2": {"r4+Deployment": "replicas: 1"},
+	})
+
+	currentApps, err := readAppContextPlan(current)
+	if err != nil {
+		t.Fatalf("Error reading current AppContext %v", err)
+	}
+	plannedApps, err := readAppContextPlan(planned)
+	if err != nil {
+		t.Fatalf("Error reading planned AppContext %v", err)
+	}
+	if len(plannedApps) != 2 || len(plannedApps[1].Clusters) != 1 || plannedApps[1].Clusters[0].Resources[0].Content != "replicas: 1" {
+		t.Fatalf("Unexpected plan %v", plannedApps)
+	}
+
+	diff := diffPlanApps(currentApps, plannedApps)
+	expected := []AppDiff{
+		{Name: "app1", Change: DiffModified, Clusters: []ClusterDiff{
+			{Name: "provider1+cluster1", Change: DiffModified, Resources: []ResourceDiff{
+				{Name: "r1+Deployment", Change: DiffModified, Diff: "--- a/r1+Deployment\n+++ b/r1+Deployment\n@@ -1 +1 @@\n-replicas: 1\n+replicas: 2\n"},
+				{Name: "r3+ConfigMap", Change: DiffAdded},
+				{Name: "r2+Service", Change: DiffRemoved},
+			}},
+		}},
+		{Name: "app2", Change: DiffAdded, Clusters: []ClusterDiff{
+			{Name: "provider1+cluster1", Change: DiffAdded, Resources: []ResourceDiff{
+				{Name: "r4+Deployment", Change: DiffAdded},
+			}},
+		}},
+	}
+	if !reflect.DeepEqual(diff.Apps, expected) {
+		t.Errorf("Unexpected diff: got %v; expected %v", diff.Apps, expected)
+	}
+
+	diff = diffPlanApps(currentApps, currentApps)
+	if len(diff.Apps) != 0 {
+		t.Errorf("Expected no diff, got %v", diff.Apps)
+	}
+}
+
+func TestRevisionsFromStateInfo(t *testing.T) {
+	s := state.StateInfo{Actions: []state.ActionEntry{
+		{State: state.StateEnum.Approved},
+		{State: state.StateEnum.Instantiated, ContextId: "100", Revision: 0},
+		{State: state.StateEnum.Updated, ContextId: "100", Revision: 0},
+		{State: state.StateEnum.Instantiated, ContextId: "200", Revision: 1},
+		{State: state.StateEnum.Updated, ContextId: "200", Revision: 1},
+		{State: state.StateEnum.Instantiated, ContextId: "100", Revision: 2},
+	}}
+
+	revisions := getRevisionsFromStateInfo(s)
+	if len(revisions) != 3 {
+		t.Fatalf("Expected 3 revisions, got %v", revisions)
+	}
+	for i, expected := range []string{"100", "200", "100"} {
+		if revisions[i].Revision != int64(i) || revisions[i].ContextId != expected {
+			t.Errorf("Unexpected revision %v", revisions[i])
+		}
+	}
+	if revisions[0].Current || revisions[1].Current || !revisions[2].Current {
+		t.Errorf("Expected only the last revision to be current %v", revisions)
+	}
+
+	if _, err := readRevisionPlan(revisions, "3"); err == nil || !strings.Contains(err.Error(), "Revision not found") {
+		t.Errorf("Expected revision not found error, got %v", err)
+	}
+	if _, err := readRevisionPlan(revisions, "x"); err == nil || !strings.Contains(err.Error(), "Invalid revision") {
+		t.Errorf("Expected invalid revision error, got %v", err)
+	}
+}
+
+func TestPlanRequiresApproval(t *testing.T) {
+	key, _ := json.Marshal(DeploymentIntentGroupKey{Name: "dig1", Project: "p1", CompositeApp: "ca1", Version: "v1"})
+	created, _ := json.Marshal(state.StateInfo{Actions: []state.ActionEntry{{State: state.StateEnum.Created}}})
+	dig, _ := json.Marshal(DeploymentIntentGroup{MetaData: DepMetaData{Name: "dig1"}})
+	db.DBconn = &db.MockDB{Items: []map[string]map[string][]byte{
+		{string(key): {"deploymentintentgroupmetadata": dig, "stateInfo": created}},
+	}}
+
+	_, err := NewInstantiationClient().Plan("p1", "ca1", "v1", "dig1")
+	if err == nil || !strings.Contains(err.Error(), "must be Approved before planning") {
+		t.Errorf("Expected the plan of a DeploymentIntentGroup not approved to fail, got %v", err)
+	}
+}
+
+func TestCheckPlanState(t *testing.T) {
+	testCases := []struct {
+		state    string
+		deployed bool
+		err      string
+	}{
+		{state: state.StateEnum.Created, err: "must be Approved before planning"},
+		{state: state.StateEnum.Approved},
+		{state: state.StateEnum.Instantiated, deployed: true},
+		{state: state.StateEnum.InstantiateStopped, deployed: true},
+		{state: state.StateEnum.Updated, deployed: true},
+		{state: state.StateEnum.Terminated},
+		{state: state.StateEnum.Applied, err: "invalid state for planning"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.state, func(t *testing.T) {
+			deployed, err := checkPlanState(testCase.state, "dig1")
+			if testCase.err != "" {
+				if err == nil || !strings.Contains(err.Error(), testCase.err) {
+					t.Fatalf("Expected error %s, got %v", testCase.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("checkPlanState returned an unexpected error %s", err)
+			}
+			if deployed != testCase.deployed {
+				t.Errorf("checkPlanState returned deployed %v, expected %v", deployed, testCase.deployed)
+			}
+		})
+	}
+}
+
+func TestRevisionsAfterReinstantiate(t *testing.T) {
+	contextdb.Db = &contextdb.MockConDb{}
+
+	s := state.StateInfo{Actions: []state.ActionEntry{
+		{State: state.StateEnum.Approved},
+		{State: state.StateEnum.Instantiated, ContextId: "100", Revision: 0},
+		{State: state.StateEnum.Terminated, ContextId: "100", Revision: 0},
+		{State: state.StateEnum.Instantiated, ContextId: "300", Revision: 0},
+	}}
+
+	revisions := getRevisionsFromStateInfo(s)
+	if len(revisions) != 1 || revisions[0].ContextId != "300" || !revisions[0].Current {
+		t.Fatalf("Expected revision 0 to be the last instantiated AppContext, got %v", revisions)
+	}
+
+	// The AppContext of the revision doesn't exist
+	if _, err := readRevisionPlan(revisions, "0"); err == nil || !strings.Contains(err.Error(), "Revision not found") {
+		t.Errorf("Expected revision not found error, got %v", err)
+	}
+}
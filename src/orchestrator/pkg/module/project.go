@@ -4,10 +4,12 @@
 package module
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
 
 	pkgerrors "github.com/pkg/errors"
 )
@@ -15,6 +17,7 @@ import (
 // Project contains the metaData for Projects
 type Project struct {
 	MetaData ProjectMetaData `json:"metadata"`
+	Spec     ProjectSpec     `json:"spec,omitempty"`
 }
 
 // ProjectMetaData contains the parameters for creating a project
@@ -25,6 +28,20 @@ type ProjectMetaData struct {
 	UserData2   string `json:"userData2"`
 }
 
+// ProjectSpec contains the settings of a project
+type ProjectSpec struct {
+	Limits ProjectLimits `json:"limits,omitempty"`
+}
+
+// ProjectLimits are the quotas of a project. A zero limit is unlimited.
+type ProjectLimits struct {
+	CompositeApps                      int   `json:"compositeApps,omitempty"`
+	DeploymentIntentGroups             int   `json:"deploymentIntentGroups,omitempty"`
+	InstantiatedDeploymentIntentGroups int   `json:"instantiatedDeploymentIntentGroups,omitempty"`
+	Clusters                           int   `json:"clusters,omitempty"`
+	ChartStorage                       int64 `json:"chartStorage,omitempty"`
+}
+
 // ProjectKey is the key structure that is used in the database
 type ProjectKey struct {
 	ProjectName string `json:"project"`
@@ -47,6 +64,8 @@ type ProjectManager interface {
 	GetProject(name string) (Project, error)
 	DeleteProject(name string) error
 	GetAllProjects() ([]Project, error)
+	GetProjectUsage(name string) (ProjectUsage, error)
+	WithContext(ctx context.Context) ProjectManager
 }
 
 // ProjectClient implements the ProjectManager
@@ -54,6 +73,8 @@ type ProjectManager interface {
 type ProjectClient struct {
 	storeName           string
 	tagMeta, tagContent string
+	// ctx is the context of the request using the client
+	ctx context.Context
 }
 
 // NewProjectClient returns an instance of the ProjectClient
@@ -65,6 +86,14 @@ func NewProjectClient() *ProjectClient {
 	}
 }
 
+// WithContext returns a copy of the client making the database calls of
+// the project of a request with its context
+func (v *ProjectClient) WithContext(ctx context.Context) ProjectManager {
+	c := *v
+	c.ctx = ctx
+	return &c
+}
+
 // CreateProject a new collection based on the project
 func (v *ProjectClient) CreateProject(p Project, exists bool) (Project, error) {
 
@@ -79,7 +108,7 @@ func (v *ProjectClient) CreateProject(p Project, exists bool) (Project, error) {
 		return Project{}, pkgerrors.New("Project already exists")
 	}
 
-	err = db.DBconn.Insert(v.storeName, key, nil, v.tagMeta, p)
+	err = db.WithContext(v.ctx).Insert(v.storeName, key, nil, v.tagMeta, p)
 	if err != nil {
 		return Project{}, pkgerrors.Wrap(err, "Creating DB Entry")
 	}
@@ -94,7 +123,7 @@ func (v *ProjectClient) GetProject(name string) (Project, error) {
 	key := ProjectKey{
 		ProjectName: name,
 	}
-	value, err := db.DBconn.Find(v.storeName, key, v.tagMeta)
+	value, err := db.WithContext(v.ctx).Find(v.storeName, key, v.tagMeta)
 	if err != nil {
 		return Project{}, pkgerrors.Wrap(err, "db Find error")
 	} else if len(value) == 0 {
@@ -144,7 +173,7 @@ func (v *ProjectClient) DeleteProject(name string) error {
 	key := ProjectKey{
 		ProjectName: name,
 	}
-	err := db.DBconn.Remove(v.storeName, key)
+	err := db.WithContext(v.ctx).Remove(v.storeName, key)
 	if err != nil {
 		if strings.Contains(err.Error(), "Error finding:") {
 			return pkgerrors.Wrap(err, "db Remove error - not found")
@@ -155,6 +184,11 @@ func (v *ProjectClient) DeleteProject(name string) error {
 		}
 	}
 
+	err = deleteCharts(name)
+	if err != nil {
+		log.Error("Error deleting the charts of the project", log.Fields{"project": name, "error": err})
+	}
+
 	//TODO: Delete the collection when the project is deleted
 	return nil
 }
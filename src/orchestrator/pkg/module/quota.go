@@ -0,0 +1,496 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package module
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/open-ness/EMCO/src/clm/pkg/cluster"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/state"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// ProjectResources counts the resources a project uses
+type ProjectResources struct {
+	CompositeApps                      int   `json:"compositeApps"`
+	DeploymentIntentGroups             int   `json:"deploymentIntentGroups"`
+	InstantiatedDeploymentIntentGroups int   `json:"instantiatedDeploymentIntentGroups"`
+	Clusters                           int   `json:"clusters"`
+	ChartStorage                       int64 `json:"chartStorage"`
+}
+
+// ProjectUsage is the consumption of a project against its limits
+type ProjectUsage struct {
+	Project string           `json:"project"`
+	Limits  ProjectLimits    `json:"limits"`
+	Usage   ProjectResources `json:"usage"`
+}
+
+// digRef identifies a deployment intent group of a project
+type digRef struct {
+	compositeApp, version, name string
+}
+
+// GetProjectUsage returns the resources the project uses and its limits
+func (v *ProjectClient) GetProjectUsage(name string) (ProjectUsage, error) {
+	p, err := v.GetProject(name)
+	if err != nil {
+		return ProjectUsage{}, err
+	}
+	u := ProjectUsage{Project: name, Limits: p.Spec.Limits}
+
+	cas, err := projectCompositeApps(name)
+	if err != nil {
+		return ProjectUsage{}, err
+	}
+	u.Usage.CompositeApps = len(cas)
+
+	digs, err := projectDeploymentIntentGroups(name)
+	if err != nil {
+		return ProjectUsage{}, err
+	}
+	u.Usage.DeploymentIntentGroups = len(digs)
+
+	instantiated, err := instantiatedDeploymentIntentGroups(name, digs)
+	if err != nil {
+		return ProjectUsage{}, err
+	}
+	u.Usage.InstantiatedDeploymentIntentGroups = len(instantiated)
+
+	clusters, err := projectClusters(name, instantiated)
+	if err != nil {
+		return ProjectUsage{}, err
+	}
+	u.Usage.Clusters = len(clusters)
+
+	u.Usage.ChartStorage, err = chartStorage(name)
+	if err != nil {
+		return ProjectUsage{}, err
+	}
+	return u, nil
+}
+
+// The quota checks of a limit of a project and the changes they allow are
+// serialized by a lease on the limit, so concurrent requests don't exceed it.
+// The requests only wait for the others changing the usage of the same limit.
+const quotaLeaseCollection = "projectquotaleases"
+
+var (
+	// quotaLeaseDuration is how long a request holds the lease at most
+	quotaLeaseDuration = time.Minute
+	// quotaLeaseWait is the wait before checking again whether the lease is
+	// still held
+	quotaLeaseWait = 20 * time.Millisecond
+)
+
+// quotaLeaseKey is the key of the lease on a limit of a project
+type quotaLeaseKey struct {
+	Project string `json:"quotaproject"`
+	Limit   string `json:"quotalimit"`
+}
+
+// holdQuota waits until no other request holds the limit of the project and
+// holds it. The function returned releases it.
+func holdQuota(p, limit string) (func(), error) {
+	l := db.NewLease(quotaLeaseCollection, quotaLeaseKey{Project: p, Limit: limit})
+	err := l.Hold(quotaLeaseDuration, quotaLeaseWait)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Error holding the quota lease")
+	}
+	return l.Release, nil
+}
+
+// noRelease is returned by the quota checks of the projects without the limit
+func noRelease() {}
+
+// quotaError reports the limit of the project an operation would exceed
+func quotaError(p string, limit int64, resource string) error {
+	return pkgerrors.Errorf("Project %s quota exceeded: limit of %d %s", p, limit, resource)
+}
+
+func projectLimits(p string) (ProjectLimits, error) {
+	proj, err := NewProjectClient().GetProject(p)
+	if err != nil {
+		return ProjectLimits{}, pkgerrors.New("Unable to find the project")
+	}
+	return proj.Spec.Limits, nil
+}
+
+// projectCompositeApps returns the composite apps of the project, an empty
+// list if there is none
+func projectCompositeApps(p string) ([]CompositeApp, error) {
+	c := NewCompositeAppClient()
+	values, err := db.DBconn.Find(c.storeName, CompositeAppKey{Project: p}, c.tagMeta)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "db Find error")
+	}
+	cas := make([]CompositeApp, 0, len(values))
+	for _, value := range values {
+		ca := CompositeApp{}
+		err = db.DBconn.Unmarshal(value, &ca)
+		if err != nil {
+			return nil, pkgerrors.Wrap(err, "Unmarshaling CompositeApp")
+		}
+		cas = append(cas, ca)
+	}
+	return cas, nil
+}
+
+// projectDeploymentIntentGroups returns the deployment intent groups of all
+// the composite apps of the project
+func projectDeploymentIntentGroups(p string) ([]digRef, error) {
+	cas, err := projectCompositeApps(p)
+	if err != nil {
+		return nil, err
+	}
+	var digs []digRef
+	for _, ca := range cas {
+		list, err := NewDeploymentIntentGroupClient().GetAllDeploymentIntentGroups(p, ca.Metadata.Name, ca.Spec.Version)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range list {
+			digs = append(digs, digRef{ca.Metadata.Name, ca.Spec.Version, d.MetaData.Name})
+		}
+	}
+	return digs, nil
+}
+
+// instantiatedDeploymentIntentGroups returns the deployment intent groups
+// with resources deployed, mapped to their current AppContext ID
+func instantiatedDeploymentIntentGroups(p string, digs []digRef) (map[digRef]string, error) {
+	instantiated := map[digRef]string{}
+	for _, d := range digs {
+		s, err := NewDeploymentIntentGroupClient().GetDeploymentIntentGroupState(d.name, p, d.compositeApp, d.version)
+		if err != nil {
+			return nil, err
+		}
+		current, err := state.GetCurrentStateFromStateInfo(s)
+		if err != nil {
+			continue
+		}
+		switch current {
+		case state.StateEnum.Instantiated, state.StateEnum.InstantiateStopped, state.StateEnum.Updated:
+			instantiated[d] = state.GetLastContextIdFromStateInfo(s)
+		}
+	}
+	return instantiated, nil
+}
+
+// appContextClusters adds the clusters the apps of the AppContext are placed
+// on to clusters
+func appContextClusters(ac appcontext.AppContext, clusters map[string]bool) error {
+	appOrder, err := ac.GetAppInstruction(appcontext.OrderInstruction)
+	if err != nil {
+		return err
+	}
+	var aov map[string][]string
+	err = json.Unmarshal([]byte(fmt.Sprintf("%v", appOrder)), &aov)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Error unmarshalling app order instruction")
+	}
+	for _, app := range aov["apporder"] {
+		names, err := ac.GetClusterNames(app)
+		if err != nil {
+			// Apps not placed on any cluster
+			continue
+		}
+		for _, c := range names {
+			clusters[c] = true
+		}
+	}
+	return nil
+}
+
+// targetClusters returns the clusters the deployment intent groups deploy to
+func targetClusters(digs map[digRef]string) (map[string]bool, error) {
+	clusters := map[string]bool{}
+	for d, ctxID := range digs {
+		if ctxID == "" {
+			continue
+		}
+		ac, err := state.GetAppContextFromId(ctxID)
+		if err != nil {
+			log.Warn(":: Error loading the AppContext of the DeploymentIntentGroup ::", log.Fields{"dig": d.name, "error": err})
+			continue
+		}
+		err = appContextClusters(ac, clusters)
+		if err != nil {
+			return nil, pkgerrors.Wrapf(err, "Error reading the clusters of DeploymentIntentGroup %s", d.name)
+		}
+	}
+	return clusters, nil
+}
+
+// dedicatedClusters adds the clusters of the cluster providers dedicated to
+// the project to clusters
+func dedicatedClusters(p string, clusters map[string]bool) error {
+	c := cluster.NewClusterClient()
+	providers, err := c.GetClusterProviders()
+	if err != nil {
+		return pkgerrors.Wrap(err, "Error reading the cluster providers")
+	}
+	for _, cp := range providers {
+		if cp.Spec.Project != p {
+			continue
+		}
+		list, err := c.GetClusters(cp.Metadata.Name)
+		if err != nil {
+			return pkgerrors.Wrapf(err, "Error reading the clusters of cluster provider %s", cp.Metadata.Name)
+		}
+		for _, cl := range list {
+			clusters[cp.Metadata.Name+cluster.SEPARATOR+cl.Metadata.Name] = true
+		}
+	}
+	return nil
+}
+
+// projectClusters returns the clusters the project uses: the clusters the
+// deployment intent groups deploy to and the clusters dedicated to the project
+func projectClusters(p string, digs map[digRef]string) (map[string]bool, error) {
+	clusters, err := targetClusters(digs)
+	if err != nil {
+		return nil, err
+	}
+	err = dedicatedClusters(p, clusters)
+	if err != nil {
+		return nil, err
+	}
+	return clusters, nil
+}
+
+// chartStorage returns the size of the app files of the project and of the
+// charts fetched for it
+func chartStorage(p string) (int64, error) {
+	c := NewAppClient()
+	values, err := db.DBconn.Find(c.storeName, AppKey{Project: p}, c.tagContent)
+	if err != nil {
+		return 0, pkgerrors.Wrap(err, "db Find error")
+	}
+
+	var size int64
+	for _, value := range values {
+		ac := AppContent{}
+		err = db.DBconn.Unmarshal(value, &ac)
+		if err != nil {
+			return 0, pkgerrors.Wrap(err, "Unmarshaling AppContent")
+		}
+		size += contentSize(ac)
+	}
+
+	values, err = db.DBconn.Find(c.storeName, ChartKey{Project: p}, tagChart)
+	if err != nil {
+		return 0, pkgerrors.Wrap(err, "db Find error")
+	}
+	for _, value := range values {
+		ch := chart{}
+		err = db.DBconn.Unmarshal(value, &ch)
+		if err != nil {
+			return 0, pkgerrors.Wrap(err, "Unmarshaling chart")
+		}
+		size += contentSize(AppContent{FileContent: ch.Content})
+	}
+	return size, nil
+}
+
+// contentSize is the size of the decoded file of an app
+func contentSize(ac AppContent) int64 {
+	data, err := base64.StdEncoding.DecodeString(ac.FileContent)
+	if err != nil {
+		return int64(len(ac.FileContent))
+	}
+	return int64(len(data))
+}
+
+// checkCompositeAppQuota checks a composite app can be added to the project.
+// The function returned releases the quota once the composite app is added.
+func checkCompositeAppQuota(p string) (func(), error) {
+	limits, err := projectLimits(p)
+	if err != nil || limits.CompositeApps == 0 {
+		return noRelease, err
+	}
+	release, err := holdQuota(p, "compositeApps")
+	if err != nil {
+		return nil, err
+	}
+	cas, err := projectCompositeApps(p)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	if len(cas) >= limits.CompositeApps {
+		release()
+		return nil, quotaError(p, int64(limits.CompositeApps), "composite apps")
+	}
+	return release, nil
+}
+
+// checkDeploymentIntentGroupQuota checks a deployment intent group can be
+// added to the project. The function returned releases the quota once the
+// deployment intent group is added.
+func checkDeploymentIntentGroupQuota(p string) (func(), error) {
+	limits, err := projectLimits(p)
+	if err != nil || limits.DeploymentIntentGroups == 0 {
+		return noRelease, err
+	}
+	release, err := holdQuota(p, "deploymentIntentGroups")
+	if err != nil {
+		return nil, err
+	}
+	digs, err := projectDeploymentIntentGroups(p)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	if len(digs) >= limits.DeploymentIntentGroups {
+		release()
+		return nil, quotaError(p, int64(limits.DeploymentIntentGroups), "deployment intent groups")
+	}
+	return release, nil
+}
+
+// checkChartStorageQuota checks the file of an app fits in the storage of the
+// project, replacing the current file of the app if any. The function
+// returned releases the quota once the file is stored.
+func checkChartStorageQuota(p string, key AppKey, ac AppContent) (func(), error) {
+	limits, err := projectLimits(p)
+	if err != nil || limits.ChartStorage == 0 {
+		return noRelease, err
+	}
+	release, err := holdQuota(p, "chartStorage")
+	if err != nil {
+		return nil, err
+	}
+	size, err := chartStorage(p)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	current, err := NewAppClient().GetAppContent(key.App, p, key.CompositeApp, key.CompositeAppVersion)
+	if err == nil {
+		size -= contentSize(current)
+	}
+	if size+contentSize(ac) > limits.ChartStorage {
+		release()
+		return nil, quotaError(p, limits.ChartStorage, "bytes of chart storage")
+	}
+	return release, nil
+}
+
+// checkFetchedChartQuota checks a chart fetched from a repository fits in the
+// storage of the project. The function returned releases the quota once the
+// chart is cached.
+func checkFetchedChartQuota(p string, chartSize int64) (func(), error) {
+	limits, err := projectLimits(p)
+	if err != nil || limits.ChartStorage == 0 {
+		return noRelease, err
+	}
+	release, err := holdQuota(p, "chartStorage")
+	if err != nil {
+		return nil, err
+	}
+	size, err := chartStorage(p)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	if size+chartSize > limits.ChartStorage {
+		release()
+		return nil, quotaError(p, limits.ChartStorage, "bytes of chart storage")
+	}
+	return release, nil
+}
+
+// checkDeploymentQuota checks the deployment intent group can deploy the
+// AppContext ac, given the other deployment intent groups of the project
+// deployed. The function returned releases the quota once the state of the
+// deployment intent group is stored.
+func checkDeploymentQuota(p, ca, v, di string, ac appcontext.AppContext) (func(), error) {
+	limits, err := projectLimits(p)
+	if err != nil || (limits.InstantiatedDeploymentIntentGroups == 0 && limits.Clusters == 0) {
+		return noRelease, err
+	}
+	release, err := holdQuota(p, "deployments")
+	if err != nil {
+		return nil, err
+	}
+	err = deploymentQuota(p, ca, v, di, ac, limits)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
+}
+
+func deploymentQuota(p, ca, v, di string, ac appcontext.AppContext, limits ProjectLimits) error {
+	digs, err := projectDeploymentIntentGroups(p)
+	if err != nil {
+		return err
+	}
+	instantiated, err := instantiatedDeploymentIntentGroups(p, digs)
+	if err != nil {
+		return err
+	}
+	delete(instantiated, digRef{ca, v, di})
+
+	if limits.InstantiatedDeploymentIntentGroups > 0 && len(instantiated) >= limits.InstantiatedDeploymentIntentGroups {
+		return quotaError(p, int64(limits.InstantiatedDeploymentIntentGroups), "instantiated deployment intent groups")
+	}
+	if limits.Clusters > 0 {
+		clusters, err := projectClusters(p, instantiated)
+		if err != nil {
+			return err
+		}
+		err = appContextClusters(ac, clusters)
+		if err != nil {
+			return err
+		}
+		if len(clusters) > limits.Clusters {
+			return quotaError(p, int64(limits.Clusters), "clusters")
+		}
+	}
+	return nil
+}
+
+// CheckClusterQuota checks a cluster can be added to a cluster provider
+// dedicated to the project. The function returned releases the quota once the
+// cluster is added.
+func CheckClusterQuota(p, provider, name string) (func(), error) {
+	limits, err := projectLimits(p)
+	if err != nil || limits.Clusters == 0 {
+		return noRelease, err
+	}
+	// The clusters used also change with the deployments
+	release, err := holdQuota(p, "deployments")
+	if err != nil {
+		return nil, err
+	}
+	digs, err := projectDeploymentIntentGroups(p)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	instantiated, err := instantiatedDeploymentIntentGroups(p, digs)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	clusters, err := projectClusters(p, instantiated)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	clusters[provider+cluster.SEPARATOR+name] = true
+	if len(clusters) > limits.Clusters {
+		release()
+		return nil, quotaError(p, int64(limits.Clusters), "clusters")
+	}
+	return release, nil
+}
@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package module
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-ness/EMCO/src/clm/pkg/cluster"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+)
+
+func appFile(size int) AppContent {
+	return AppContent{FileContent: base64.StdEncoding.EncodeToString(make([]byte, size))}
+}
+
+func TestProjectQuotas(t *testing.T) {
+	db.DBconn = &db.NewMockDB{}
+
+	p := Project{
+		MetaData: ProjectMetaData{Name: "testProject"},
+		Spec: ProjectSpec{Limits: ProjectLimits{
+			CompositeApps:          1,
+			DeploymentIntentGroups: 1,
+			ChartStorage:           10,
+		}},
+	}
+	_, err := NewProjectClient().CreateProject(p, false)
+	if err != nil {
+		t.Fatalf("CreateProject returned an unexpected error %s", err)
+	}
+
+	ca := CompositeApp{Metadata: CompositeAppMetaData{Name: "ca1"}, Spec: CompositeAppSpec{Version: "v1"}}
+	_, err = NewCompositeAppClient().CreateCompositeApp(ca, "testProject", false)
+	if err != nil {
+		t.Fatalf("CreateCompositeApp returned an unexpected error %s", err)
+	}
+	ca1 := ca
+	ca.Metadata.Name = "ca2"
+	_, err = NewCompositeAppClient().CreateCompositeApp(ca, "testProject", false)
+	if err == nil || !strings.Contains(err.Error(), "quota exceeded") {
+		t.Fatalf("Expected the composite app quota to be exceeded, got %v", err)
+	}
+
+	dig := DeploymentIntentGroup{MetaData: DepMetaData{Name: "dig1"}}
+	_, err = NewDeploymentIntentGroupClient().CreateDeploymentIntentGroup(dig, "testProject", "ca1", "v1")
+	if err != nil {
+		t.Fatalf("CreateDeploymentIntentGroup returned an unexpected error %s", err)
+	}
+	dig.MetaData.Name = "dig2"
+	_, err = NewDeploymentIntentGroupClient().CreateDeploymentIntentGroup(dig, "testProject", "ca1", "v1")
+	if err == nil || !strings.Contains(err.Error(), "quota exceeded") {
+		t.Fatalf("Expected the deployment intent group quota to be exceeded, got %v", err)
+	}
+
+	app := App{Metadata: AppMetaData{Name: "app1"}}
+	_, err = NewAppClient().CreateApp(app, appFile(8), "testProject", "ca1", "v1", false)
+	if err != nil {
+		t.Fatalf("CreateApp returned an unexpected error %s", err)
+	}
+	app1 := app
+	app.Metadata.Name = "app2"
+	_, err = NewAppClient().CreateApp(app, appFile(3), "testProject", "ca1", "v1", false)
+	if err == nil || !strings.Contains(err.Error(), "quota exceeded") {
+		t.Fatalf("Expected the chart storage quota to be exceeded, got %v", err)
+	}
+
+	u, err := NewProjectClient().GetProjectUsage("testProject")
+	if err != nil {
+		t.Fatalf("GetProjectUsage returned an unexpected error %s", err)
+	}
+	expected := ProjectResources{CompositeApps: 1, DeploymentIntentGroups: 1, ChartStorage: 8}
+	if u.Usage != expected || u.Limits != p.Spec.Limits {
+		t.Errorf("GetProjectUsage returned unexpected usage %+v", u)
+	}
+
+	// Updating a composite app doesn't add one
+	_, err = NewCompositeAppClient().CreateCompositeApp(ca1, "testProject", true)
+	if err != nil {
+		t.Fatalf("CreateCompositeApp returned an unexpected error %s", err)
+	}
+	// The new file of an app replaces the old one
+	_, err = NewAppClient().CreateApp(app1, appFile(10), "testProject", "ca1", "v1", true)
+	if err != nil {
+		t.Fatalf("CreateApp returned an unexpected error %s", err)
+	}
+}
+
+func TestClusterQuota(t *testing.T) {
+	db.DBconn = &db.NewMockDB{}
+
+	p := Project{MetaData: ProjectMetaData{Name: "testProject"}, Spec: ProjectSpec{Limits: ProjectLimits{Clusters: 1}}}
+	_, err := NewProjectClient().CreateProject(p, false)
+	if err != nil {
+		t.Fatalf("CreateProject returned an unexpected error %s", err)
+	}
+	cc := cluster.NewClusterClient()
+	cp := cluster.ClusterProvider{Spec: cluster.ClusterProviderSpec{Project: "testProject"}}
+	cp.Metadata.Name = "provider1"
+	_, err = cc.CreateClusterProvider(cp, false)
+	if err != nil {
+		t.Fatalf("CreateClusterProvider returned an unexpected error %s", err)
+	}
+
+	release, err := CheckClusterQuota("testProject", "provider1", "cluster1")
+	if err != nil {
+		t.Fatalf("CheckClusterQuota returned an unexpected error %s", err)
+	}
+	// Added like CreateCluster, without the cloud config
+	c := cluster.Cluster{}
+	c.Metadata.Name = "cluster1"
+	err = db.DBconn.Insert("cluster", cluster.ClusterKey{ClusterProviderName: "provider1", ClusterName: "cluster1"}, nil, "clustermetadata", c)
+	release()
+	if err != nil {
+		t.Fatalf("Insert returned an unexpected error %s", err)
+	}
+
+	_, err = CheckClusterQuota("testProject", "provider1", "cluster2")
+	if err == nil || !strings.Contains(err.Error(), "quota exceeded") {
+		t.Fatalf("Expected the cluster quota to be exceeded, got %v", err)
+	}
+	u, err := NewProjectClient().GetProjectUsage("testProject")
+	if err != nil {
+		t.Fatalf("GetProjectUsage returned an unexpected error %s", err)
+	}
+	if u.Usage.Clusters != 1 {
+		t.Errorf("Unexpected cluster usage %d", u.Usage.Clusters)
+	}
+}
+
+func TestHoldQuota(t *testing.T) {
+	db.DBconn = &db.NewMockDB{}
+	quotaLeaseWait = time.Millisecond
+	quotaLeaseDuration = 20 * time.Millisecond
+	defer func() { quotaLeaseDuration = time.Minute }()
+
+	release, err := holdQuota("testProject", "compositeApps")
+	if err != nil {
+		t.Fatalf("holdQuota returned an unexpected error %s", err)
+	}
+	// The other limits of the project are not held
+	start := time.Now()
+	other, err := holdQuota("testProject", "deploymentIntentGroups")
+	if err != nil || time.Since(start) >= quotaLeaseDuration {
+		t.Fatalf("holdQuota of another limit returned %v after %s", err, time.Since(start))
+	}
+	other()
+	release()
+
+	start = time.Now()
+	release, err = holdQuota("testProject", "compositeApps")
+	if err != nil || time.Since(start) >= quotaLeaseDuration {
+		t.Fatalf("holdQuota of a released limit returned %v after %s", err, time.Since(start))
+	}
+	release()
+}
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package module
+
+import (
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// RetryPolicy tells rsync how long to retry the clusters of a
+// DeploymentIntentGroup, or of the DeploymentIntentGroups of a LogicalCloud.
+// The fields not set keep the defaults of rsync.
+type RetryPolicy struct {
+	// Seconds to wait before checking an unreachable cluster again
+	InitialBackoff int `json:"initial-backoff,omitempty"`
+	// The wait is multiplied by BackoffFactor, 2 by default, after each
+	// check up to MaxBackoff seconds
+	MaxBackoff    int     `json:"max-backoff,omitempty"`
+	BackoffFactor float64 `json:"backoff-factor,omitempty"`
+	// Seconds a cluster can be unreachable before it fails
+	MaxUnreachableTime int `json:"max-unreachable-time,omitempty"`
+	// Seconds to apply a resource before it fails
+	ApplyTimeout int `json:"apply-timeout,omitempty"`
+	// fail (default) or continue with the other clusters when a cluster fails
+	OnClusterFailure string `json:"on-cluster-failure,omitempty"`
+}
+
+// Validate checks the retry policy like the schema of the
+// DeploymentIntentGroup, for the LogicalClouds that have no schema
+func (p *RetryPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	if p.InitialBackoff < 0 || p.MaxBackoff < 0 || p.MaxUnreachableTime < 0 || p.ApplyTimeout < 0 {
+		return pkgerrors.New("Invalid retry policy, the times must be positive")
+	}
+	if p.BackoffFactor != 0 && p.BackoffFactor < 1 {
+		return pkgerrors.New("Invalid retry policy, the backoff factor must be at least 1")
+	}
+	switch p.OnClusterFailure {
+	case "", appcontext.ClusterFailureActionEnum.Fail, appcontext.ClusterFailureActionEnum.Continue:
+	default:
+		return pkgerrors.Errorf("Invalid retry policy, unknown on-cluster-failure: %s", p.OnClusterFailure)
+	}
+	return nil
+}
+
+// getRetryPolicy returns the retry policy of a DeploymentIntentGroup for
+// rsync. The fields the DeploymentIntentGroup doesn't set are taken from the
+// retry policy of its LogicalCloud. It returns nil if neither has one.
+func getRetryPolicy(dig, lc *RetryPolicy) *appcontext.RetryPolicy {
+	if dig == nil && lc == nil {
+		return nil
+	}
+	var p RetryPolicy
+	if lc != nil {
+		p = *lc
+	}
+	if dig != nil {
+		if dig.InitialBackoff != 0 {
+			p.InitialBackoff = dig.InitialBackoff
+		}
+		if dig.MaxBackoff != 0 {
+			p.MaxBackoff = dig.MaxBackoff
+		}
+		if dig.BackoffFactor != 0 {
+			p.BackoffFactor = dig.BackoffFactor
+		}
+		if dig.MaxUnreachableTime != 0 {
+			p.MaxUnreachableTime = dig.MaxUnreachableTime
+		}
+		if dig.ApplyTimeout != 0 {
+			p.ApplyTimeout = dig.ApplyTimeout
+		}
+		if dig.OnClusterFailure != "" {
+			p.OnClusterFailure = dig.OnClusterFailure
+		}
+	}
+	return &appcontext.RetryPolicy{
+		InitialBackoff:     p.InitialBackoff,
+		MaxBackoff:         p.MaxBackoff,
+		BackoffFactor:      p.BackoffFactor,
+		MaxUnreachableTime: p.MaxUnreachableTime,
+		ApplyTimeout:       p.ApplyTimeout,
+		OnClusterFailure:   p.OnClusterFailure,
+	}
+}
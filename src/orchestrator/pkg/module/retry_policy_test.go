@@ -0,0 +1,33 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package module
+
+import (
+	"testing"
+)
+
+func TestRetryPolicyValidate(t *testing.T) {
+	testCases := []struct {
+		label  string
+		policy *RetryPolicy
+		valid  bool
+	}{
+		{label: "No retry policy", valid: true},
+		{label: "Valid retry policy", policy: &RetryPolicy{InitialBackoff: 1, MaxBackoff: 60, BackoffFactor: 1.5, OnClusterFailure: "continue"}, valid: true},
+		{label: "Negative time", policy: &RetryPolicy{ApplyTimeout: -1}},
+		{label: "Backoff factor below 1", policy: &RetryPolicy{BackoffFactor: 0.5}},
+		{label: "Unknown cluster failure action", policy: &RetryPolicy{OnClusterFailure: "retry"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.label, func(t *testing.T) {
+			err := tc.policy.Validate()
+			if tc.valid && err != nil {
+				t.Errorf("Validate returned an unexpected error %s", err)
+			}
+			if !tc.valid && err == nil {
+				t.Errorf("Validate didn't reject the retry policy")
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package module
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/state"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// DeploymentRevision is a revision of an instantiated DeploymentIntentGroup
+// Each update of the DeploymentIntentGroup creates a new revision
+type DeploymentRevision struct {
+	Revision  int64     `json:"revision"`
+	ContextId string    `json:"instance"`
+	TimeStamp time.Time `json:"time"`
+	Current   bool      `json:"current"`
+}
+
+// getRevisionsFromStateInfo returns the revisions in the stateInfo, in the order
+// they were first instantiated. A revision is identified by the AppContext it was
+// last instantiated with, as a terminated revision is instantiated again with a
+// new AppContext.
+func getRevisionsFromStateInfo(s state.StateInfo) []DeploymentRevision {
+	revisions := []DeploymentRevision{}
+	seen := make(map[int64]int)
+	for _, a := range s.Actions {
+		if a.State != state.StateEnum.Instantiated || a.ContextId == "" {
+			continue
+		}
+		revision := DeploymentRevision{
+			Revision:  a.Revision,
+			ContextId: a.ContextId,
+			TimeStamp: a.TimeStamp,
+		}
+		if i, ok := seen[a.Revision]; ok {
+			revisions[i] = revision
+			continue
+		}
+		seen[a.Revision] = len(revisions)
+		revisions = append(revisions, revision)
+	}
+
+	stateVal, err := state.GetCurrentStateFromStateInfo(s)
+	if err == nil && (stateVal == state.StateEnum.Instantiated || stateVal == state.StateEnum.InstantiateStopped) {
+		currentRevision, _ := state.GetLatestRevisionFromStateInfo(s)
+		for i := range revisions {
+			if revisions[i].Revision == currentRevision {
+				revisions[i].Current = true
+			}
+		}
+	}
+	return revisions
+}
+
+/*
+Revisions takes in projectName, compositeAppName, compositeAppVersion,
+DeploymentIntentName and returns the revisions the DeploymentIntentGroup was
+instantiated with.
+*/
+func (c InstantiationClient) Revisions(p string, ca string, v string, di string) ([]DeploymentRevision, error) {
+	s, err := NewDeploymentIntentGroupClient().GetDeploymentIntentGroupState(di, p, ca, v)
+	if err != nil {
+		return []DeploymentRevision{}, pkgerrors.Wrap(err, "DeploymentIntentGroup has no state info: "+di)
+	}
+	return getRevisionsFromStateInfo(s), nil
+}
+
+/*
+RevisionDiff takes in projectName, compositeAppName, compositeAppVersion,
+DeploymentIntentName and two revisions. It compares the AppContexts of both
+revisions and returns the added, removed and modified apps, clusters and resources.
+*/
+func (c InstantiationClient) RevisionDiff(p string, ca string, v string, di string, fromRev string, toRev string) (AppContextDiff, error) {
+	s, err := NewDeploymentIntentGroupClient().GetDeploymentIntentGroupState(di, p, ca, v)
+	if err != nil {
+		return AppContextDiff{}, pkgerrors.Wrap(err, "DeploymentIntentGroup has no state info: "+di)
+	}
+	revisions := getRevisionsFromStateInfo(s)
+
+	fromApps, err := readRevisionPlan(revisions, fromRev)
+	if err != nil {
+		return AppContextDiff{}, err
+	}
+	toApps, err := readRevisionPlan(revisions, toRev)
+	if err != nil {
+		return AppContextDiff{}, err
+	}
+
+	diff := diffPlanApps(fromApps, toApps)
+	diff.From = fromRev
+	diff.To = toRev
+	return diff, nil
+}
+
+// readRevisionPlan reads the apps, clusters and resources of the AppContext of a revision
+func readRevisionPlan(revisions []DeploymentRevision, rev string) ([]PlanApp, error) {
+	r, err := strconv.ParseInt(rev, 10, 64)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Invalid revision "+rev)
+	}
+	for _, revision := range revisions {
+		if revision.Revision != r {
+			continue
+		}
+		ac, err := state.GetAppContextFromId(revision.ContextId)
+		if err != nil {
+			// The AppContext of a revision is deleted once it is replaced
+			if strings.Contains(err.Error(), "Key doesn't exist") {
+				return nil, pkgerrors.Errorf("Revision not found: %s, its AppContext %s was deleted", rev, revision.ContextId)
+			}
+			return nil, pkgerrors.Wrapf(err, "Error getting AppContext with Id: %v", revision.ContextId)
+		}
+		apps, err := readAppContextPlan(ac)
+		if err != nil {
+			return nil, pkgerrors.Wrapf(err, "Error reading the AppContext of revision %v", rev)
+		}
+		return apps, nil
+	}
+	return nil, pkgerrors.New("Revision not found: " + rev)
+}
@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package module
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/open-ness/EMCO/src/clm/pkg/cluster"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
+	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/state"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// Rollout strategy types
+const (
+	// RolloutTypeLabel - one wave per cluster label
+	RolloutTypeLabel = "label"
+	// RolloutTypePercentage - one wave per cumulative percentage of the clusters
+	RolloutTypePercentage = "percentage"
+)
+
+// What to do when a wave fails or isn't ready in time
+const (
+	// RolloutOnFailureStop - stop the rollout, clusters of later waves are left untouched
+	RolloutOnFailureStop = "stop"
+	// RolloutOnFailureRollback - rollback the DeploymentIntentGroup to the previous revision
+	RolloutOnFailureRollback = "rollback"
+)
+
+// RolloutStrategy rolls out the updates of a DeploymentIntentGroup in waves of
+// clusters. A wave is only updated once the clusters of the previous wave are
+// ready. Clusters not part of any wave are updated in a last wave.
+type RolloutStrategy struct {
+	Type        string   `json:"type"`
+	Labels      []string `json:"labels,omitempty"`
+	Percentages []int    `json:"percentages,omitempty"`
+	// Seconds to wait for the clusters of a wave to be ready
+	ReadyTimeout int    `json:"ready-timeout,omitempty"`
+	OnFailure    string `json:"on-failure,omitempty"`
+}
+
+// setAppContextRollout computes the waves of clusters the AppContext is rolled out in
+func setAppContextRollout(ctxId string, rs RolloutStrategy) error {
+	ac, err := state.GetAppContextFromId(ctxId)
+	if err != nil {
+		return err
+	}
+	clusters, err := getAppContextClusters(ac)
+	if err != nil {
+		return err
+	}
+	waves, err := computeRolloutWaves(clusters, rs, cluster.NewClusterClient().GetClustersWithLabel)
+	if err != nil {
+		return err
+	}
+	log.Info(":: Rollout waves ::", log.Fields{"context": ctxId, "waves": waves})
+	return state.UpdateAppContextRollout(ctxId, appcontext.Rollout{Waves: waves, ReadyTimeout: rs.ReadyTimeout})
+}
+
+// getAppContextClusters returns the sorted clusters of all the apps of the AppContext
+func getAppContextClusters(ac appcontext.AppContext) ([]string, error) {
+	apps, err := readAppContextPlan(ac)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]bool)
+	clusters := []string{}
+	for _, app := range apps {
+		for _, c := range app.Clusters {
+			if !found[c.Name] {
+				found[c.Name] = true
+				clusters = append(clusters, c.Name)
+			}
+		}
+	}
+	sort.Strings(clusters)
+	return clusters, nil
+}
+
+// computeRolloutWaves splits the clusters, of the form provider+cluster, in waves
+func computeRolloutWaves(clusters []string, rs RolloutStrategy,
+	clustersWithLabel func(provider, label string) ([]string, error)) ([][]string, error) {
+
+	var waves [][]string
+	assigned := make(map[string]bool)
+
+	switch rs.Type {
+	case RolloutTypeLabel:
+		providers := []string{}
+		for _, c := range clusters {
+			provider := strings.Split(c, "+")[0]
+			if len(providers) == 0 || providers[len(providers)-1] != provider {
+				providers = append(providers, provider)
+			}
+		}
+		for _, label := range rs.Labels {
+			labeled := make(map[string]bool)
+			for _, provider := range providers {
+				names, err := clustersWithLabel(provider, label)
+				if err != nil {
+					return nil, pkgerrors.Wrap(err, "Error getting clusters with label "+label)
+				}
+				for _, name := range names {
+					labeled[provider+"+"+name] = true
+				}
+			}
+			var wave []string
+			for _, c := range clusters {
+				if labeled[c] && !assigned[c] {
+					assigned[c] = true
+					wave = append(wave, c)
+				}
+			}
+			if len(wave) > 0 {
+				waves = append(waves, wave)
+			}
+		}
+	case RolloutTypePercentage:
+		done := 0
+		for _, pct := range rs.Percentages {
+			if pct < 1 || pct > 100 {
+				return nil, pkgerrors.Errorf("Invalid rollout percentage %d", pct)
+			}
+			upto := int(math.Ceil(float64(len(clusters)*pct) / 100))
+			if upto <= done {
+				continue
+			}
+			waves = append(waves, clusters[done:upto])
+			for _, c := range clusters[done:upto] {
+				assigned[c] = true
+			}
+			done = upto
+		}
+	default:
+		return nil, pkgerrors.Errorf("Invalid rollout strategy type %s", rs.Type)
+	}
+
+	var last []string
+	for _, c := range clusters {
+		if !assigned[c] {
+			last = append(last, c)
+		}
+	}
+	if len(last) > 0 {
+		waves = append(waves, last)
+	}
+	return waves, nil
+}
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package module
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestComputeRolloutWaves(t *testing.T) {
+	clusters := []string{"p1+c1", "p1+c2", "p1+c3", "p2+c1"}
+	labels := map[string]map[string][]string{
+		"p1": {"canary": {"c2"}, "edge": {"c1", "c2"}},
+		"p2": {"edge": {"c1"}},
+	}
+	clustersWithLabel := func(provider, label string) ([]string, error) {
+		return labels[provider][label], nil
+	}
+
+	testCases := []struct {
+		label         string
+		rs            RolloutStrategy
+		expected      [][]string
+		expectedError string
+	}{
+		{
+			label:    "Waves by label",
+			rs:       RolloutStrategy{Type: RolloutTypeLabel, Labels: []string{"canary", "edge"}},
+			expected: [][]string{{"p1+c2"}, {"p1+c1", "p2+c1"}, {"p1+c3"}},
+		},
+		{
+			label:    "Unknown label",
+			rs:       RolloutStrategy{Type: RolloutTypeLabel, Labels: []string{"core"}},
+			expected: [][]string{{"p1+c1", "p1+c2", "p1+c3", "p2+c1"}},
+		},
+		{
+			label:    "Waves by percentage",
+			rs:       RolloutStrategy{Type: RolloutTypePercentage, Percentages: []int{10, 20, 50}},
+			expected: [][]string{{"p1+c1"}, {"p1+c2"}, {"p1+c3", "p2+c1"}},
+		},
+		{
+			label:         "Invalid percentage",
+			rs:            RolloutStrategy{Type: RolloutTypePercentage, Percentages: []int{0}},
+			expectedError: "Invalid rollout percentage",
+		},
+		{
+			label:         "Invalid type",
+			rs:            RolloutStrategy{Type: "random"},
+			expectedError: "Invalid rollout strategy type",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			waves, err := computeRolloutWaves(clusters, testCase.rs, clustersWithLabel)
+			if testCase.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), testCase.expectedError) {
+					t.Fatalf("Expected error %s, got %v", testCase.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+			if !reflect.DeepEqual(waves, testCase.expected) {
+				t.Errorf("Unexpected waves: got %v; expected %v", waves, testCase.expected)
+			}
+		})
+	}
+}
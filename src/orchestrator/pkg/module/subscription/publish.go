@@ -0,0 +1,405 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package subscription
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// Event is a CloudEvents 1.0 event in structured mode
+type Event struct {
+	SpecVersion     string            `json:"specversion"`
+	Id              string            `json:"id"`
+	Source          string            `json:"source"`
+	Type            EventType         `json:"type"`
+	Subject         string            `json:"subject,omitempty"`
+	Time            time.Time         `json:"time"`
+	DataContentType string            `json:"datacontenttype,omitempty"`
+	Data            map[string]string `json:"data,omitempty"`
+}
+
+type EventType = string
+
+type eventTypes struct {
+	DigState           EventType
+	DigInstantiated    EventType
+	DigTerminated      EventType
+	DigFailed          EventType
+	ClusterUnreachable EventType
+	ClusterAvailable   EventType
+	LogicalCloudReady  EventType
+	ResourceDrifted    EventType
+}
+
+// EventTypeEnum defines the types of events that can be subscribed to
+var EventTypeEnum = &eventTypes{
+	DigState:           "emco.deploymentintentgroup.state",
+	DigInstantiated:    "emco.deploymentintentgroup.instantiated",
+	DigTerminated:      "emco.deploymentintentgroup.terminated",
+	DigFailed:          "emco.deploymentintentgroup.failed",
+	ClusterUnreachable: "emco.cluster.unreachable",
+	ClusterAvailable:   "emco.cluster.available",
+	LogicalCloudReady:  "emco.logicalcloud.ready",
+	ResourceDrifted:    "emco.resource.drifted",
+}
+
+func isEventType(t string) bool {
+	switch t {
+	case EventTypeEnum.DigState, EventTypeEnum.DigInstantiated, EventTypeEnum.DigTerminated,
+		EventTypeEnum.DigFailed, EventTypeEnum.ClusterUnreachable, EventTypeEnum.ClusterAvailable,
+		EventTypeEnum.LogicalCloudReady, EventTypeEnum.ResourceDrifted:
+		return true
+	}
+	return false
+}
+
+// Delivery records the delivery of an event to the endpoint of a subscription
+type Delivery struct {
+	Id           string         `json:"id"`
+	Event        Event          `json:"event"`
+	Status       DeliveryStatus `json:"status"`
+	Attempts     int            `json:"attempts"`
+	ResponseCode int            `json:"responseCode,omitempty"`
+	Error        string         `json:"error,omitempty"`
+	TimeStamp    time.Time      `json:"time"`
+	NextAttempt  *time.Time     `json:"nextAttempt,omitempty"`
+}
+
+type DeliveryStatus = string
+
+type deliveryStatuses struct {
+	Pending   DeliveryStatus
+	Delivered DeliveryStatus
+	Failed    DeliveryStatus
+}
+
+// DeliveryStatusEnum defines the status of a delivery
+var DeliveryStatusEnum = &deliveryStatuses{
+	Pending:   "Pending",
+	Delivered: "Delivered",
+	Failed:    "Failed",
+}
+
+// Delivery attempts and backoff between attempts, which doubles after each attempt
+var (
+	maxAttempts    = 5
+	initialBackoff = 2 * time.Second
+	maxDeliveries  = 100
+	httpClient     = &http.Client{Timeout: 10 * time.Second}
+)
+
+// The deliveries not done yet are queued in the database, so they are retried
+// after a restart. The microservice delivering an event holds a lease on it
+// until its next attempt, and the deliveries of a stopped microservice are
+// taken over once their lease expired.
+const (
+	queueCollection    = "subscriptionqueue"
+	leaseCollection    = "subscriptionleases"
+	sequenceCollection = "subscriptionsequences"
+	queueTag           = "queueddelivery"
+)
+
+var (
+	// leaseGrace is how long after its next attempt a delivery is taken over
+	leaseGrace = time.Minute
+	// resumeInterval is how often the expired leases are checked
+	resumeInterval = 5 * time.Minute
+)
+
+// queueKey is the key of a queued delivery
+type queueKey struct {
+	Project      string `json:"project"`
+	Subscription string `json:"subscription"`
+	Delivery     string `json:"queuedDelivery"`
+}
+
+// queuedDelivery is a delivery not done yet
+type queuedDelivery struct {
+	Project      string   `json:"project"`
+	Subscription string   `json:"subscription"`
+	Delivery     Delivery `json:"delivery"`
+}
+
+// DigSource returns the source of the events of a DeploymentIntentGroup
+func DigSource(p, ca, v, di string) string {
+	return fmt.Sprintf("/projects/%s/composite-apps/%s/%s/deployment-intent-groups/%s", p, ca, v, di)
+}
+
+// LogicalCloudSource returns the source of the events of a logical cloud
+func LogicalCloudSource(p, lc string) string {
+	return fmt.Sprintf("/projects/%s/logical-clouds/%s", p, lc)
+}
+
+func newId() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Publish sends the event to the endpoints of the subscriptions of the project
+// that include the event type. Events are delivered in the background.
+func Publish(p string, t EventType, source string, subject string, data map[string]string) {
+	if p == "" || db.DBconn == nil {
+		return
+	}
+	c := NewSubscriptionClient()
+	subs, err := c.GetAllSubscriptions(p)
+	if err != nil {
+		log.Error("Error getting subscriptions", log.Fields{"project": p, "error": err})
+		return
+	}
+	e := Event{
+		SpecVersion:     "1.0",
+		Id:              newId(),
+		Source:          source,
+		Type:            t,
+		Subject:         subject,
+		Time:            time.Now().UTC(),
+		DataContentType: "application/json",
+		Data:            data,
+	}
+	for _, s := range subs {
+		if !s.wants(t) {
+			continue
+		}
+		seq, err := c.nextSequence(p, s.Metadata.Name)
+		if err != nil {
+			log.Error("Error numbering delivery", log.Fields{"project": p, "subscription": s.Metadata.Name, "error": err})
+			continue
+		}
+		now := time.Now()
+		d := Delivery{
+			Id:          strconv.FormatInt(seq, 10),
+			Event:       e,
+			Status:      DeliveryStatusEnum.Pending,
+			TimeStamp:   now,
+			NextAttempt: &now,
+		}
+		lease := db.NewLease(leaseCollection, queueKey{Project: p, Subscription: s.Metadata.Name, Delivery: d.Id})
+		if err := c.enqueue(p, s.Metadata.Name, d, lease); err != nil {
+			log.Error("Error queueing delivery", log.Fields{"project": p, "subscription": s.Metadata.Name, "error": err})
+			continue
+		}
+		if err := c.updateDelivery(p, s.Metadata.Name, d); err != nil {
+			log.Error("Error recording delivery", log.Fields{"project": p, "subscription": s.Metadata.Name, "error": err})
+		}
+		c.trimDeliveries(p, s.Metadata.Name, seq)
+		go c.deliver(p, s.Metadata.Name, d, lease)
+	}
+}
+
+// ResumeDeliveries takes over the queued deliveries whose lease expired, when
+// the orchestrator starts and then periodically
+func ResumeDeliveries() {
+	go func() {
+		for {
+			NewSubscriptionClient().resumeDeliveries()
+			time.Sleep(resumeInterval)
+		}
+	}()
+}
+
+func (c *SubscriptionClient) resumeDeliveries() {
+	values, err := db.DBconn.Find(queueCollection, queueKey{}, queueTag)
+	if err != nil {
+		log.Error("Error finding the queued deliveries", log.Fields{"error": err})
+		return
+	}
+	for _, value := range values {
+		var q queuedDelivery
+		if len(value) == 0 || db.DBconn.Unmarshal(value, &q) != nil || q.Delivery.Id == "" {
+			continue
+		}
+		key := queueKey{Project: q.Project, Subscription: q.Subscription, Delivery: q.Delivery.Id}
+		lease := db.NewLease(leaseCollection, key)
+		if err := lease.Take(leaseUntil(q.Delivery)); err != nil {
+			// Not ended, or taken over by another instance
+			continue
+		}
+		log.Info("Resuming event delivery", log.Fields{"project": q.Project, "subscription": q.Subscription, "delivery": q.Delivery.Id})
+		go c.deliver(q.Project, q.Subscription, q.Delivery, lease)
+	}
+}
+
+// leaseUntil returns the end of the lease on a delivery until its next attempt
+func leaseUntil(d Delivery) time.Time {
+	next := time.Now()
+	if d.NextAttempt != nil && d.NextAttempt.After(next) {
+		next = *d.NextAttempt
+	}
+	return next.Add(httpClient.Timeout + leaseGrace)
+}
+
+// wants checks if the event type is included in the subscription
+func (s Subscription) wants(t EventType) bool {
+	if len(s.Spec.Events) == 0 {
+		return true
+	}
+	for _, e := range s.Spec.Events {
+		if e == t {
+			return true
+		}
+	}
+	return false
+}
+
+// deliver posts the event to the endpoint until it is accepted or the attempts
+// are exhausted, recording the outcome of each attempt. It stops if the
+// subscription is deleted or the delivery is taken over by another instance.
+func (c *SubscriptionClient) deliver(p string, name string, d Delivery, lease *db.Lease) {
+	key := queueKey{Project: p, Subscription: name, Delivery: d.Id}
+	for {
+		if d.NextAttempt != nil {
+			time.Sleep(time.Until(*d.NextAttempt))
+		}
+		// The endpoint may have changed since the event was published
+		s, err := c.GetSubscription(name, p)
+		if err != nil {
+			log.Info("Subscription deleted, stopping event delivery", log.Fields{"project": p, "subscription": name, "delivery": d.Id})
+			c.dequeue(key)
+			return
+		}
+		d.Attempts++
+		d.ResponseCode, d.Error = post(s.Spec.Endpoint, d.Event)
+		d.TimeStamp = time.Now()
+		if d.Error == "" {
+			d.Status = DeliveryStatusEnum.Delivered
+		} else if d.Attempts >= maxAttempts {
+			d.Status = DeliveryStatusEnum.Failed
+		}
+		if d.Status != DeliveryStatusEnum.Pending {
+			d.NextAttempt = nil
+			c.recordDelivery(p, name, d)
+			c.dequeue(key)
+			return
+		}
+		next := d.TimeStamp.Add(initialBackoff << uint(d.Attempts-1))
+		d.NextAttempt = &next
+		if err := c.enqueue(p, name, d, lease); err != nil {
+			log.Info("Event delivery taken over", log.Fields{"subscription": name, "delivery": d.Id, "error": err})
+			return
+		}
+		c.recordDelivery(p, name, d)
+		log.Info("Event delivery failed, retrying", log.Fields{"subscription": name, "attempts": d.Attempts, "error": d.Error})
+	}
+}
+
+// post sends the event and returns the response code and the error if the event
+// wasn't accepted
+func post(endpoint string, e Event) (int, string) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return 0, err.Error()
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(b))
+	if err != nil {
+		return 0, err.Error()
+	}
+	req.Header.Set("Content-Type", "application/cloudevents+json; charset=UTF-8")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err.Error()
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Sprintf("Endpoint returned %s", resp.Status)
+	}
+	return resp.StatusCode, ""
+}
+
+func (c *SubscriptionClient) updateDelivery(p string, name string, d Delivery) error {
+	key := DeliveryKey{
+		Project:      p,
+		Subscription: name,
+		Delivery:     d.Id,
+	}
+	err := db.DBconn.Insert(c.storeName, key, nil, c.tagDelivery, d)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Create DB entry error")
+	}
+	return nil
+}
+
+// recordDelivery records the outcome of an attempt, unless the subscription
+// was deleted meanwhile
+func (c *SubscriptionClient) recordDelivery(p string, name string, d Delivery) {
+	if err := c.updateDelivery(p, name, d); err != nil {
+		log.Error("Error recording delivery", log.Fields{"project": p, "subscription": name, "error": err})
+		return
+	}
+	if _, err := c.GetSubscription(name, p); err != nil {
+		// Deleted during the attempt, don't leave its delivery behind
+		db.DBconn.Remove(c.storeName, DeliveryKey{Project: p, Subscription: name, Delivery: d.Id})
+	}
+}
+
+// enqueue queues the delivery once its lease is renewed until its next
+// attempt. Fails if the delivery was taken over by another instance.
+func (c *SubscriptionClient) enqueue(p string, name string, d Delivery, lease *db.Lease) error {
+	key := queueKey{Project: p, Subscription: name, Delivery: d.Id}
+	if err := lease.Renew(leaseUntil(d)); err != nil {
+		return err
+	}
+	q := queuedDelivery{Project: p, Subscription: name, Delivery: d}
+	if err := db.DBconn.Insert(queueCollection, key, nil, queueTag, q); err != nil {
+		return pkgerrors.Wrap(err, "Create DB entry error")
+	}
+	return nil
+}
+
+// dequeue removes a delivery done from the queue
+func (c *SubscriptionClient) dequeue(key queueKey) {
+	if err := db.DBconn.Remove(queueCollection, key); err != nil {
+		log.Error("Error removing queued delivery", log.Fields{"subscription": key.Subscription, "delivery": key.Delivery, "error": err})
+	}
+	db.DBconn.Remove(leaseCollection, key)
+}
+
+// nextSequence returns the number of the next delivery of the subscription
+func (c *SubscriptionClient) nextSequence(p string, name string) (int64, error) {
+	key := SubscriptionKey{Project: p, Subscription: name}
+	for {
+		current, err := db.DBconn.FindVersion(sequenceCollection, key)
+		if err != nil {
+			return 0, err
+		}
+		err = db.DBconn.UpdateVersion(sequenceCollection, key, current, current+1)
+		if errors.Is(err, db.ErrVersionConflict) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		return current + 1, nil
+	}
+}
+
+// trimDeliveries only keeps the latest deliveries of the subscription. The
+// deliveries are numbered, so the one just out of the kept ones is removed.
+func (c *SubscriptionClient) trimDeliveries(p string, name string, seq int64) {
+	if seq <= int64(maxDeliveries) {
+		return
+	}
+	key := DeliveryKey{
+		Project:      p,
+		Subscription: name,
+		Delivery:     strconv.FormatInt(seq-int64(maxDeliveries), 10),
+	}
+	if err := db.DBconn.Remove(c.storeName, key); err != nil && !strings.Contains(err.Error(), "not found") {
+		log.Error("Error removing delivery", log.Fields{"subscription": name, "delivery": key.Delivery, "error": err})
+	}
+}
@@ -0,0 +1,241 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package subscription
+
+import (
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+	mtypes "github.com/open-ness/EMCO/src/orchestrator/pkg/module/types"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// Subscription registers a webhook endpoint for the events of a project
+type Subscription struct {
+	Metadata mtypes.Metadata  `json:"metadata"`
+	Spec     SubscriptionSpec `json:"spec"`
+}
+
+// SubscriptionSpec has the endpoint the events are sent to and the types of
+// events to send. All the events are sent if no type is given.
+type SubscriptionSpec struct {
+	Endpoint string   `json:"endpoint"`
+	Events   []string `json:"events,omitempty"`
+}
+
+// SubscriptionKey is the key structure that is used in the database
+type SubscriptionKey struct {
+	Project      string `json:"project"`
+	Subscription string `json:"subscription"`
+}
+
+// We will use json marshalling to convert to string to
+// preserve the underlying structure.
+func (k SubscriptionKey) String() string {
+	out, err := json.Marshal(k)
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+// projectKey is the key of the project of the subscriptions
+type projectKey struct {
+	Project string `json:"project"`
+}
+
+// DeliveryKey is the key structure of the deliveries of a subscription
+type DeliveryKey struct {
+	Project      string `json:"project"`
+	Subscription string `json:"subscription"`
+	Delivery     string `json:"delivery"`
+}
+
+// SubscriptionManager is an interface exposes the Subscription functionality
+type SubscriptionManager interface {
+	CreateSubscription(s Subscription, p string, exists bool) (Subscription, error)
+	GetSubscription(name string, p string) (Subscription, error)
+	GetAllSubscriptions(p string) ([]Subscription, error)
+	DeleteSubscription(name string, p string) error
+	GetDeliveries(name string, p string) ([]Delivery, error)
+}
+
+// SubscriptionClient implements the SubscriptionManager
+type SubscriptionClient struct {
+	storeName   string
+	tagMeta     string
+	tagDelivery string
+}
+
+// NewSubscriptionClient returns an instance of the SubscriptionClient
+func NewSubscriptionClient() *SubscriptionClient {
+	return &SubscriptionClient{
+		storeName:   "orchestrator",
+		tagMeta:     "subscriptionmetadata",
+		tagDelivery: "deliveryinfo",
+	}
+}
+
+// CreateSubscription creates or updates a Subscription of the project
+func (c *SubscriptionClient) CreateSubscription(s Subscription, p string, exists bool) (Subscription, error) {
+	key := SubscriptionKey{
+		Project:      p,
+		Subscription: s.Metadata.Name,
+	}
+
+	_, err := c.GetSubscription(s.Metadata.Name, p)
+	if err == nil && !exists {
+		return Subscription{}, pkgerrors.New("Subscription already exists")
+	}
+
+	// Check if the project exists
+	value, err := db.DBconn.Find(c.storeName, projectKey{Project: p}, "projectmetadata")
+	if err != nil || len(value) == 0 {
+		return Subscription{}, pkgerrors.New("Unable to find the project")
+	}
+
+	u, err := url.Parse(s.Spec.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return Subscription{}, pkgerrors.New("Invalid endpoint, must be a http or https URL")
+	}
+	for _, e := range s.Spec.Events {
+		if !isEventType(e) {
+			return Subscription{}, pkgerrors.New("Invalid event type: " + e)
+		}
+	}
+
+	err = db.DBconn.Insert(c.storeName, key, nil, c.tagMeta, s)
+	if err != nil {
+		return Subscription{}, pkgerrors.Wrap(err, "Create DB entry error")
+	}
+	return s, nil
+}
+
+// GetSubscription returns the Subscription of the project
+func (c *SubscriptionClient) GetSubscription(name string, p string) (Subscription, error) {
+	key := SubscriptionKey{
+		Project:      p,
+		Subscription: name,
+	}
+
+	value, err := db.DBconn.Find(c.storeName, key, c.tagMeta)
+	if err != nil {
+		return Subscription{}, pkgerrors.Wrap(err, "db Find error")
+	} else if len(value) == 0 {
+		return Subscription{}, pkgerrors.New("Subscription not found")
+	}
+
+	s := Subscription{}
+	err = db.DBconn.Unmarshal(value[0], &s)
+	if err != nil {
+		return Subscription{}, pkgerrors.Wrap(err, "Unmarshaling Value")
+	}
+	return s, nil
+}
+
+// GetAllSubscriptions returns all the Subscriptions of the project
+func (c *SubscriptionClient) GetAllSubscriptions(p string) ([]Subscription, error) {
+	key := SubscriptionKey{
+		Project:      p,
+		Subscription: "",
+	}
+
+	values, err := db.DBconn.Find(c.storeName, key, c.tagMeta)
+	if err != nil {
+		return []Subscription{}, pkgerrors.Wrap(err, "db Find error")
+	}
+
+	resp := []Subscription{}
+	for _, value := range values {
+		s := Subscription{}
+		err = db.DBconn.Unmarshal(value, &s)
+		if err != nil {
+			return []Subscription{}, pkgerrors.Wrap(err, "Unmarshaling Value")
+		}
+		resp = append(resp, s)
+	}
+	return resp, nil
+}
+
+// DeleteSubscription deletes the Subscription and its deliveries from the database
+func (c *SubscriptionClient) DeleteSubscription(name string, p string) error {
+	// Deliveries are child references of the subscription
+	dkey := DeliveryKey{
+		Project:      p,
+		Subscription: name,
+		Delivery:     "",
+	}
+	err := db.DBconn.RemoveAll(c.storeName, dkey)
+	if err != nil {
+		return pkgerrors.Wrap(err, "db Remove error - general")
+	}
+	// The queued deliveries are dropped, the microservices delivering them
+	// stop once they find the subscription deleted
+	qkey := queueKey{
+		Project:      p,
+		Subscription: name,
+		Delivery:     "",
+	}
+	err = db.DBconn.RemoveAll(queueCollection, qkey)
+	if err != nil {
+		return pkgerrors.Wrap(err, "db Remove error - general")
+	}
+	err = db.DBconn.RemoveAll(leaseCollection, qkey)
+	if err != nil {
+		return pkgerrors.Wrap(err, "db Remove error - general")
+	}
+
+	key := SubscriptionKey{
+		Project:      p,
+		Subscription: name,
+	}
+	// The deliveries of a subscription created again are numbered from one
+	db.DBconn.Remove(sequenceCollection, key)
+	err = db.DBconn.Remove(c.storeName, key)
+	if err != nil {
+		if strings.Contains(err.Error(), "Error finding:") || strings.Contains(err.Error(), "key not found") {
+			return pkgerrors.Wrap(err, "db Remove error - not found")
+		} else if strings.Contains(err.Error(), "Can't delete parent without deleting child") {
+			return pkgerrors.Wrap(err, "db Remove error - conflict")
+		} else {
+			return pkgerrors.Wrap(err, "db Remove error - general")
+		}
+	}
+	return nil
+}
+
+// GetDeliveries returns the recent deliveries of the Subscription, the latest first
+func (c *SubscriptionClient) GetDeliveries(name string, p string) ([]Delivery, error) {
+	_, err := c.GetSubscription(name, p)
+	if err != nil {
+		return []Delivery{}, err
+	}
+
+	key := DeliveryKey{
+		Project:      p,
+		Subscription: name,
+		Delivery:     "",
+	}
+	values, err := db.DBconn.Find(c.storeName, key, c.tagDelivery)
+	if err != nil {
+		return []Delivery{}, pkgerrors.Wrap(err, "db Find error")
+	}
+
+	resp := []Delivery{}
+	for _, value := range values {
+		d := Delivery{}
+		err = db.DBconn.Unmarshal(value, &d)
+		if err != nil {
+			return []Delivery{}, pkgerrors.Wrap(err, "Unmarshaling Value")
+		}
+		resp = append(resp, d)
+	}
+	sort.SliceStable(resp, func(i, j int) bool {
+		return resp[i].TimeStamp.After(resp[j].TimeStamp)
+	})
+	return resp, nil
+}
@@ -0,0 +1,345 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package subscription
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/module/types"
+)
+
+func newTestDB() *db.NewMockDB {
+	mdb := &db.NewMockDB{}
+	mdb.Insert("orchestrator", projectKey{Project: "testProject"}, nil, "projectmetadata",
+		map[string]interface{}{"metadata": map[string]string{"name": "testProject"}})
+	return mdb
+}
+
+func TestCreateSubscription(t *testing.T) {
+	testCases := []struct {
+		label         string
+		project       string
+		inp           Subscription
+		expectedError string
+	}{
+		{
+			label:   "Create Subscription",
+			project: "testProject",
+			inp: Subscription{
+				Metadata: types.Metadata{Name: "testSubscription"},
+				Spec: SubscriptionSpec{
+					Endpoint: "https://example.com/events",
+					Events:   []string{EventTypeEnum.DigInstantiated, EventTypeEnum.ClusterUnreachable},
+				},
+			},
+		},
+		{
+			label:   "Create Subscription of missing project",
+			project: "missingProject",
+			inp: Subscription{
+				Metadata: types.Metadata{Name: "testSubscription"},
+				Spec:     SubscriptionSpec{Endpoint: "https://example.com/events"},
+			},
+			expectedError: "Unable to find the project",
+		},
+		{
+			label:   "Create Subscription with invalid endpoint",
+			project: "testProject",
+			inp: Subscription{
+				Metadata: types.Metadata{Name: "testSubscription"},
+				Spec:     SubscriptionSpec{Endpoint: "ftp://example.com/events"},
+			},
+			expectedError: "Invalid endpoint",
+		},
+		{
+			label:   "Create Subscription with invalid event type",
+			project: "testProject",
+			inp: Subscription{
+				Metadata: types.Metadata{Name: "testSubscription"},
+				Spec: SubscriptionSpec{
+					Endpoint: "https://example.com/events",
+					Events:   []string{"emco.unknown"},
+				},
+			},
+			expectedError: "Invalid event type",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			db.DBconn = newTestDB()
+			impl := NewSubscriptionClient()
+			_, err := impl.CreateSubscription(testCase.inp, testCase.project, false)
+			if err != nil {
+				if testCase.expectedError == "" || !strings.Contains(err.Error(), testCase.expectedError) {
+					t.Fatalf("Create returned an unexpected error %s", err)
+				}
+				return
+			}
+			if testCase.expectedError != "" {
+				t.Fatalf("Create didn't return the expected error %s", testCase.expectedError)
+			}
+			got, err := impl.GetSubscription(testCase.inp.Metadata.Name, testCase.project)
+			if err != nil || got.Spec.Endpoint != testCase.inp.Spec.Endpoint {
+				t.Errorf("Get returned %v, %v", got, err)
+			}
+			_, err = impl.CreateSubscription(testCase.inp, testCase.project, false)
+			if err == nil || !strings.Contains(err.Error(), "Subscription already exists") {
+				t.Errorf("Create of existing subscription returned %v", err)
+			}
+		})
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	defer func(a int, b time.Duration) { maxAttempts, initialBackoff = a, b }(maxAttempts, initialBackoff)
+	maxAttempts, initialBackoff = 3, time.Millisecond
+
+	testCases := []struct {
+		label            string
+		failures         int
+		expectedStatus   DeliveryStatus
+		expectedAttempts int
+	}{
+		{
+			label:            "Deliver event",
+			expectedStatus:   DeliveryStatusEnum.Delivered,
+			expectedAttempts: 1,
+		},
+		{
+			label:            "Deliver event after retries",
+			failures:         2,
+			expectedStatus:   DeliveryStatusEnum.Delivered,
+			expectedAttempts: 3,
+		},
+		{
+			label:            "Fail to deliver event",
+			failures:         3,
+			expectedStatus:   DeliveryStatusEnum.Failed,
+			expectedAttempts: 3,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			requests := 0
+			var received Event
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if !strings.HasPrefix(r.Header.Get("Content-Type"), "application/cloudevents+json") {
+					t.Errorf("Unexpected content type %s", r.Header.Get("Content-Type"))
+				}
+				if requests <= testCase.failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				json.NewDecoder(r.Body).Decode(&received)
+			}))
+			defer srv.Close()
+
+			db.DBconn = newTestDB()
+			c := NewSubscriptionClient()
+			s := Subscription{
+				Metadata: types.Metadata{Name: "testSubscription"},
+				Spec:     SubscriptionSpec{Endpoint: srv.URL},
+			}
+			if _, err := c.CreateSubscription(s, "testProject", false); err != nil {
+				t.Fatalf("Create returned an unexpected error %s", err)
+			}
+			d := Delivery{
+				Id: "d1",
+				Event: Event{SpecVersion: "1.0", Id: "e1", Type: EventTypeEnum.DigInstantiated,
+					Source: DigSource("testProject", "ca1", "v1", "dig1"), Data: map[string]string{"instance": "1234"}},
+				Status: DeliveryStatusEnum.Pending,
+			}
+			lease := db.NewLease(leaseCollection, queueKey{Project: "testProject", Subscription: "testSubscription", Delivery: d.Id})
+			if err := c.enqueue("testProject", "testSubscription", d, lease); err != nil {
+				t.Fatalf("Enqueue returned an unexpected error %s", err)
+			}
+			c.deliver("testProject", "testSubscription", d, lease)
+
+			deliveries, err := c.GetDeliveries("testSubscription", "testProject")
+			if err != nil || len(deliveries) == 0 {
+				t.Fatalf("Get deliveries returned %v, %v", deliveries, err)
+			}
+			got := deliveries[0]
+			if got.Status != testCase.expectedStatus || got.Attempts != testCase.expectedAttempts {
+				t.Errorf("Delivery is %s after %d attempts, expected %s after %d", got.Status, got.Attempts,
+					testCase.expectedStatus, testCase.expectedAttempts)
+			}
+			if testCase.expectedStatus == DeliveryStatusEnum.Delivered && received.Id != "e1" {
+				t.Errorf("Endpoint received unexpected event %v", received)
+			}
+			// The delivery is done, so it isn't resumed
+			if v, _ := db.DBconn.FindVersion(leaseCollection, queueKey{"testProject", "testSubscription", "d1"}); v != 0 {
+				t.Errorf("Delivery still leased until %d", v)
+			}
+		})
+	}
+}
+
+func TestWants(t *testing.T) {
+	s := Subscription{Spec: SubscriptionSpec{Events: []string{EventTypeEnum.ResourceDrifted}}}
+	if !s.wants(EventTypeEnum.ResourceDrifted) || s.wants(EventTypeEnum.DigFailed) {
+		t.Error("Subscription with event types matched unexpected events")
+	}
+	s.Spec.Events = nil
+	if !s.wants(EventTypeEnum.DigFailed) {
+		t.Error("Subscription without event types didn't match all events")
+	}
+}
+
+// lockedDB is the mock database, safe for the deliveries in the background
+type lockedDB struct {
+	*db.NewMockDB
+	sync.Mutex
+}
+
+func (l *lockedDB) Insert(coll string, key db.Key, query interface{}, tag string, data interface{}) error {
+	l.Lock()
+	defer l.Unlock()
+	return l.NewMockDB.Insert(coll, key, query, tag, data)
+}
+
+func (l *lockedDB) Find(coll string, key db.Key, tag string) ([][]byte, error) {
+	l.Lock()
+	defer l.Unlock()
+	return l.NewMockDB.Find(coll, key, tag)
+}
+
+func (l *lockedDB) Remove(coll string, key db.Key) error {
+	l.Lock()
+	defer l.Unlock()
+	return l.NewMockDB.Remove(coll, key)
+}
+
+func (l *lockedDB) UpdateVersion(coll string, key db.Key, current int64, version int64) error {
+	l.Lock()
+	defer l.Unlock()
+	return l.NewMockDB.UpdateVersion(coll, key, current, version)
+}
+
+func (l *lockedDB) FindVersion(coll string, key db.Key) (int64, error) {
+	l.Lock()
+	defer l.Unlock()
+	return l.NewMockDB.FindVersion(coll, key)
+}
+
+func createTestSubscription(t *testing.T, endpoint string) *SubscriptionClient {
+	c := NewSubscriptionClient()
+	s := Subscription{
+		Metadata: types.Metadata{Name: "testSubscription"},
+		Spec:     SubscriptionSpec{Endpoint: endpoint},
+	}
+	if _, err := c.CreateSubscription(s, "testProject", false); err != nil {
+		t.Fatalf("Create returned an unexpected error %s", err)
+	}
+	return c
+}
+
+func TestResumeDeliveries(t *testing.T) {
+	received := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e Event
+		json.NewDecoder(r.Body).Decode(&e)
+		received <- e.Id
+	}))
+	defer srv.Close()
+
+	db.DBconn = &lockedDB{NewMockDB: newTestDB()}
+	c := createTestSubscription(t, srv.URL)
+	now := time.Now()
+	d := Delivery{Id: "1", Event: Event{SpecVersion: "1.0", Id: "e1", Type: EventTypeEnum.DigFailed},
+		Status: DeliveryStatusEnum.Pending, Attempts: 1, NextAttempt: &now}
+
+	// Still delivered by another instance
+	key := queueKey{"testProject", "testSubscription", "1"}
+	other := db.NewLease(leaseCollection, key)
+	c.enqueue("testProject", "testSubscription", d, other)
+	c.resumeDeliveries()
+	select {
+	case id := <-received:
+		t.Fatalf("Unexpected delivery of %s", id)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// The instance delivering it stopped
+	other.Renew(time.Now().Add(-time.Second))
+	c.resumeDeliveries()
+	select {
+	case id := <-received:
+		if id != "e1" {
+			t.Errorf("Unexpected delivery of %s", id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Queued delivery was not resumed")
+	}
+	for i := 0; i < 100; i++ {
+		if v, _ := db.DBconn.FindVersion(leaseCollection, key); v == 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	deliveries, _ := c.GetDeliveries("testSubscription", "testProject")
+	if len(deliveries) == 0 || deliveries[0].Status != DeliveryStatusEnum.Delivered || deliveries[0].Attempts != 2 {
+		t.Errorf("Unexpected deliveries %v", deliveries)
+	}
+}
+
+func TestDeliverDeletedSubscription(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer srv.Close()
+
+	db.DBconn = newTestDB()
+	c := createTestSubscription(t, srv.URL)
+	d := Delivery{Id: "1", Event: Event{SpecVersion: "1.0", Id: "e1", Type: EventTypeEnum.DigFailed},
+		Status: DeliveryStatusEnum.Pending}
+	lease := db.NewLease(leaseCollection, queueKey{"testProject", "testSubscription", "1"})
+	c.enqueue("testProject", "testSubscription", d, lease)
+	if err := c.DeleteSubscription("testSubscription", "testProject"); err != nil {
+		t.Fatalf("Delete returned an unexpected error %s", err)
+	}
+
+	c.deliver("testProject", "testSubscription", d, lease)
+	if requests != 0 {
+		t.Errorf("Event of a deleted subscription was sent %d times", requests)
+	}
+	values, _ := db.DBconn.Find(c.storeName, DeliveryKey{"testProject", "testSubscription", ""}, c.tagDelivery)
+	if len(values) != 0 {
+		t.Errorf("Delivery of a deleted subscription was recorded")
+	}
+}
+
+func TestTrimDeliveries(t *testing.T) {
+	defer func(m int) { maxDeliveries = m }(maxDeliveries)
+	maxDeliveries = 2
+
+	db.DBconn = newTestDB()
+	c := createTestSubscription(t, "https://example.com/events")
+	for i := 0; i < 4; i++ {
+		seq, err := c.nextSequence("testProject", "testSubscription")
+		if err != nil || seq != int64(i+1) {
+			t.Fatalf("Unexpected sequence %d, %v", seq, err)
+		}
+		d := Delivery{Id: strconv.FormatInt(seq, 10), Status: DeliveryStatusEnum.Delivered,
+			TimeStamp: time.Now().Add(time.Duration(i) * time.Second)}
+		c.updateDelivery("testProject", "testSubscription", d)
+		c.trimDeliveries("testProject", "testSubscription", seq)
+	}
+	deliveries, _ := c.GetDeliveries("testSubscription", "testProject")
+	if len(deliveries) != 2 || deliveries[0].Id != "4" || deliveries[1].Id != "3" {
+		t.Errorf("Unexpected deliveries %v", deliveries)
+	}
+}
@@ -4,11 +4,13 @@
 package module
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"time"
 
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/tracing"
 	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/state"
 	pkgerrors "github.com/pkg/errors"
@@ -74,14 +76,15 @@ func (c InstantiationClient) Migrate(p string, ca string, v string, tCav string,
 
 	// BEGIN : Make app context
 	instantiator := Instantiator{p, ca, tCav, tDi, dIGrp}
-	cca, err := instantiator.MakeAppContext()
+	ctx := context.Background()
+	cca, err := instantiator.MakeAppContext(ctx)
 	if err != nil {
 		return pkgerrors.Wrap(err, "Error in making AppContext")
 	}
 	// END : Make app context
 
 	// BEGIN : callScheduler
-	err = callScheduler(cca.context, cca.ctxval, p, ca, tCav, tDi)
+	err = callScheduler(ctx, cca.context, cca.ctxval, p, ca, tCav, tDi)
 	if err != nil {
 		return pkgerrors.Wrap(err, "Error in callScheduler")
 	}
@@ -115,7 +118,7 @@ func (c InstantiationClient) Migrate(p string, ca string, v string, tCav string,
 		return err
 	}
 
-	err = callRsyncUpdate(sourceCtxId, targetCtxId)
+	err = callRsyncUpdate(ctx, sourceCtxId, targetCtxId)
 	if err != nil {
 		return err
 	}
@@ -158,6 +161,7 @@ func (c InstantiationClient) Migrate(p string, ca string, v string, tCav string,
 	if err != nil {
 		return pkgerrors.Wrap(err, "Error updating the stateInfo of the DeploymentIntentGroup: "+tDi)
 	}
+	publishDigState(p, ca, tCav, tDi, a)
 	return nil
 }
 
@@ -166,7 +170,9 @@ Update methods takes in projectName, compositeAppName, compositeAppVersion,
 DeploymentIntentName.
 This method is responsible for creation and saving of context into etcd and ensuring new intents are applied on DeploymentIntentGroup.
 */
-func (c InstantiationClient) Update(p string, ca string, v string, di string) (int64, error) {
+func (c InstantiationClient) Update(ctx context.Context, p string, ca string, v string, di string) (int64, error) {
+	// The calls to the controllers and rsync go on if the request is canceled
+	ctx = tracing.Detach(ctx)
 
 	log.Info("Update API", log.Fields{"profile": p, "compositeapp": ca, "version": v, "deploymentintentgroup": di})
 
@@ -198,19 +204,26 @@ func (c InstantiationClient) Update(p string, ca string, v string, di string) (i
 
 	// BEGIN : Make app context
 	instantiator := Instantiator{p, ca, v, di, dIGrp}
-	cca, err := instantiator.MakeAppContext()
+	cca, err := instantiator.MakeAppContext(ctx)
 	if err != nil {
 		return -1, pkgerrors.Wrap(err, "Error in making AppContext")
 	}
 	// END : Make app context
 
 	// BEGIN : callScheduler
-	err = callScheduler(cca.context, cca.ctxval, p, ca, v, di)
+	err = callScheduler(ctx, cca.context, cca.ctxval, p, ca, v, di)
 	if err != nil {
 		return -1, pkgerrors.Wrap(err, "Error in callScheduler")
 	}
 	// END : callScheduler
 
+	release, err := checkDeploymentQuota(p, ca, v, di, cca.context)
+	if err != nil {
+		deleteAppContext(cca.context)
+		return -1, err
+	}
+	defer release()
+
 	targetCtxId := fmt.Sprintf("%v", cca.ctxval)
 
 	// Update Status Context ID in AppContext
@@ -218,9 +231,30 @@ func (c InstantiationClient) Update(p string, ca string, v string, di string) (i
 	// Update Status context id to be source status collected in source
 	err = state.UpdateAppContextStatusContextID(targetCtxId, statusID)
 	if err != nil {
+		deleteAppContext(cca.context)
 		return -1, err
 	}
-	err = callRsyncUpdate(sourceCtxId, targetCtxId)
+	// Roll out the update in waves of clusters if requested
+	rs := dIGrp.Spec.RolloutStrategy
+	ar := dIGrp.Spec.AutoRollback
+	rollbackOnFailure := rs != nil && rs.OnFailure == RolloutOnFailureRollback
+	deadline := 0
+	if ar != nil && ar.Enabled {
+		rollbackOnFailure = true
+		deadline = ar.Deadline
+		if rs == nil {
+			// A single wave makes rsync wait for the update to be ready
+			rs = &RolloutStrategy{Type: RolloutTypePercentage, Percentages: []int{100}, ReadyTimeout: deadline}
+		}
+	}
+	if rs != nil {
+		err = setAppContextRollout(targetCtxId, *rs)
+		if err != nil {
+			deleteAppContext(cca.context)
+			return -1, pkgerrors.Wrap(err, "Error setting the rollout waves")
+		}
+	}
+	err = callRsyncUpdate(ctx, sourceCtxId, targetCtxId)
 	if err != nil {
 		return -1, err
 	}
@@ -241,7 +275,7 @@ func (c InstantiationClient) Update(p string, ca string, v string, di string) (i
 	}
 	ss.Actions = append(ss.Actions, a)
 
-	err = db.DBconn.Insert(c.db.storeName, key, nil, c.db.tagState, ss)
+	err = db.WithContext(ctx).Insert(c.db.storeName, key, nil, c.db.tagState, ss)
 	if err != nil {
 		return -1, pkgerrors.Wrap(err, "Error updating the stateInfo of the DeploymentIntentGroup: "+di)
 	}
@@ -258,13 +292,19 @@ func (c InstantiationClient) Update(p string, ca string, v string, di string) (i
 	}
 	ss.Actions = append(ss.Actions, a)
 
-	err = db.DBconn.Insert(c.db.storeName, key, nil, c.db.tagState, ss)
+	err = db.WithContext(ctx).Insert(c.db.storeName, key, nil, c.db.tagState, ss)
 	if err != nil {
 		return -1, pkgerrors.Wrap(err, "Error updating the stateInfo of the DeploymentIntentGroup: "+di)
 	}
+	publishDigState(p, ca, v, di, a)
 
 	log.Info("Updated revisionID", log.Fields{"Updated to revisionID": latestRevision})
 
+	if rollbackOnFailure {
+		c.watchUpdate(pendingRollback{Project: p, CompositeApp: ca, Version: v, DigName: di,
+			ContextId: targetCtxId, Revision: lastRevision, Deadline: deadline, Start: time.Now()})
+	}
+
 	return latestRevision, nil
 
 }
@@ -279,6 +319,13 @@ func (c InstantiationClient) Rollback(p string, ca string, v string, di string,
 	log.Info("Rollback API", log.Fields{"profile": p, "compositeapp": ca, "version": v, "deploymentintentgroup": di,
 		"rbRev": rbRev})
 
+	return c.rollback(p, ca, v, di, rbRev, "")
+}
+
+// rollback rolls the DeploymentIntentGroup back to the given revision. The reason
+// is recorded in the StateInfo for rollbacks not requested by the user.
+func (c InstantiationClient) rollback(p string, ca string, v string, di string, rbRev string, reason string) error {
+
 	ss, err := NewDeploymentIntentGroupClient().GetDeploymentIntentGroupState(di, p, ca, v)
 	if err != nil {
 		return pkgerrors.Wrap(err, "DeploymentIntentGroup has no state info: "+di)
@@ -300,7 +347,7 @@ func (c InstantiationClient) Rollback(p string, ca string, v string, di string,
 		return pkgerrors.Wrap(err, "GetMatchingContextIDforRevision error "+rbRev)
 	}
 
-	err = callRsyncUpdate(sourceCtxId, targetCtxId)
+	err = callRsyncRollback(context.Background(), sourceCtxId, targetCtxId)
 	if err != nil {
 		return err
 	}
@@ -334,6 +381,7 @@ func (c InstantiationClient) Rollback(p string, ca string, v string, di string,
 		ContextId: targetCtxId,
 		TimeStamp: time.Now(),
 		Revision:  latestRevision,
+		Reason:    reason,
 	}
 	ss.Actions = append(ss.Actions, a)
 
@@ -341,8 +389,9 @@ func (c InstantiationClient) Rollback(p string, ca string, v string, di string,
 	if err != nil {
 		return pkgerrors.Wrap(err, "Error updating the stateInfo of the DeploymentIntentGroup: "+di)
 	}
+	publishDigState(p, ca, v, di, a)
 
-	log.Info("Rollback Completed", log.Fields{"Rollback revisionID": latestRevision})
+	log.Info("Rollback Completed", log.Fields{"Rollback revisionID": latestRevision, "reason": reason})
 
 	return nil
 }
@@ -6,13 +6,14 @@ package module
 
 
 import (
+	"context"
 	"fmt"
 	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
 	rsyncclient "github.com/open-ness/EMCO/src/orchestrator/pkg/grpc/updateappclient"
 	
 )
 
-func callRsyncUpdate(FromContextid, ToContextid interface{}) error {
+func callRsyncUpdate(ctx context.Context, FromContextid, ToContextid interface{}) error {
 	rsyncInfo, err := queryDBAndSetRsyncInfo()
 	log.Info("Calling the Rsync ", log.Fields{
 		"RsyncName": rsyncInfo.RsyncName,
@@ -23,9 +24,28 @@ func callRsyncUpdate(FromContextid, ToContextid interface{}) error {
 
 	fromAppContextID := fmt.Sprintf("%v", FromContextid)
 	toAppContextID := fmt.Sprintf("%v", ToContextid)
-	err = rsyncclient.InvokeUpdateApp(fromAppContextID, toAppContextID)
+	err = rsyncclient.InvokeUpdateApp(ctx, fromAppContextID, toAppContextID)
 	if err != nil {
 		return err
 	}
 	return nil
-}
\ No newline at end of file
+}
+// callRsyncRollback asks rsync to roll the composite app back from one
+// AppContext to another
+func callRsyncRollback(ctx context.Context, FromContextid, ToContextid interface{}) error {
+	rsyncInfo, err := queryDBAndSetRsyncInfo()
+	log.Info("Calling the Rsync ", log.Fields{
+		"RsyncName": rsyncInfo.RsyncName,
+	})
+	if err != nil {
+		return err
+	}
+
+	fromAppContextID := fmt.Sprintf("%v", FromContextid)
+	toAppContextID := fmt.Sprintf("%v", ToContextid)
+	err = rsyncclient.InvokeRollbackApp(ctx, fromAppContextID, toAppContextID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
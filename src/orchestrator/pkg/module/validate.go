@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package module
+
+import (
+	"encoding/base64"
+	"path/filepath"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/config"
+	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	"github.com/open-ness/EMCO/src/orchestrator/utils/helm"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// validationReleaseName is the release name the apps are rendered with when
+// they are uploaded
+const validationReleaseName = "emco"
+
+// renderApp renders the file of an app with its profile and override values
+func renderApp(a App, appContent, appProfileContent []byte, overrideValues OverrideValues, namespace, rName string) ([]helm.KubernetesResourceTemplate, error) {
+	resolver, err := helm.NewResolver(a.Metadata.Type, config.GetConfiguration().KubernetesVersion, namespace, rName, ManifestFileName)
+	if err != nil {
+		return nil, err
+	}
+	return resolver.Resolve(appContent, appProfileContent, overrideValues.StructuredValues, overrideValues.setValues(), a.Metadata.Name)
+}
+
+// schemaValidator returns the validator of the OpenAPI schemas of the
+// configured Kubernetes version, or nil if none is configured
+func schemaValidator() (*helm.SchemaValidator, error) {
+	c := config.GetConfiguration()
+	if c.KubernetesVersion == "" || c.KubernetesSchemaDir == "" {
+		return nil, nil
+	}
+	v, err := helm.LoadSchemaValidator(filepath.Join(c.KubernetesSchemaDir, c.KubernetesVersion+".json"))
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "Error loading the schemas of Kubernetes %s", c.KubernetesVersion)
+	}
+	return v, nil
+}
+
+// validateApp renders an app and lints its manifests. It returns a
+// helm.ValidationError if the app doesn't render or a manifest is invalid.
+func validateApp(a App, appContent, appProfileContent []byte, overrideValues OverrideValues, namespace, rName string) error {
+	templates, err := renderApp(a, appContent, appProfileContent, overrideValues, namespace, rName)
+	if len(templates) > 0 {
+		defer cleanTmpfiles(templates)
+	}
+	if err != nil {
+		return helm.NewRenderError(a.Metadata.Name, err)
+	}
+	v, err := schemaValidator()
+	if err != nil {
+		return err
+	}
+	return helm.ValidateTemplates(a.Metadata.Name, templates, v)
+}
+
+// ValidateApp renders an uploaded app with its default values and lints its
+// manifests. The apps referencing a chart are validated at approval.
+func ValidateApp(a App, ac AppContent) error {
+	if a.Spec.Chart != nil {
+		return nil
+	}
+	content, err := base64.StdEncoding.DecodeString(ac.FileContent)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Fail to convert to byte array")
+	}
+	return validateApp(a, content, nil, OverrideValues{}, "default", validationReleaseName)
+}
+
+// ValidateAppProfile renders the app of an uploaded profile with the profile
+// and lints its manifests
+func ValidateAppProfile(p, ca, v string, ap AppProfile, apc AppProfileContent) error {
+	a, err := NewAppClient().GetApp(ap.Spec.AppName, p, ca, v)
+	if err != nil || a.Spec.Chart != nil {
+		// The profiles of the apps not uploaded yet and of the charts are
+		// validated at approval
+		return nil
+	}
+	appContent, err := getAppFile(a, p, ca, v, "")
+	if err != nil {
+		return err
+	}
+	profile, err := base64.StdEncoding.DecodeString(apc.Profile)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Fail to convert to byte array")
+	}
+	return validateApp(a, appContent, profile, OverrideValues{}, "default", validationReleaseName)
+}
+
+// validateDeploymentIntentGroup renders the apps of a DeploymentIntentGroup
+// with its profile and override values, and stops at the first app that
+// fails to validate
+func validateDeploymentIntentGroup(p, ca, v, di string) error {
+	dig, err := NewDeploymentIntentGroupClient().GetDeploymentIntentGroup(di, p, ca, v)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Not finding the deploymentIntentGroup")
+	}
+	namespace := "default"
+	lc, err := NewLogicalCloudClient().Get(p, dig.Spec.LogicalCloud)
+	if err == nil && lc.Specification.NameSpace != "" {
+		namespace = lc.Specification.NameSpace
+	}
+
+	apps, err := NewAppClient().GetApps(p, ca, v)
+	if err != nil {
+		return pkgerrors.Wrap(err, "Not finding the apps")
+	}
+	for _, a := range apps {
+		appName := a.Metadata.Name
+		appContent, err := getAppFile(a, p, ca, v, di)
+		if err != nil {
+			return err
+		}
+		appPC, err := NewAppProfileClient().GetAppProfileContentByApp(p, ca, v, dig.Spec.Profile, appName)
+		if err != nil {
+			return pkgerrors.Wrapf(err, "Not finding the appProfileContent for:: %s", appName)
+		}
+		profile, err := base64.StdEncoding.DecodeString(appPC.Profile)
+		if err != nil {
+			return pkgerrors.Wrap(err, "Fail to convert to byte array")
+		}
+		err = validateApp(a, appContent, profile, getOverrideValuesByAppName(dig.Spec.OverrideValuesObj, appName), namespace, dig.Spec.Version)
+		if err != nil {
+			log.Error("Invalid app", log.Fields{"DeploymentIntentGroup": di, "app": appName, "error": err.Error()})
+			return err
+		}
+	}
+	return nil
+}
+
+// validateOverrideValues validates the override values of the Helm apps of a
+// DeploymentIntentGroup against the values.schema.json of their charts. The
+// apps and profiles not uploaded yet and the charts in repositories are
+// validated at approval.
+func validateOverrideValues(d DeploymentIntentGroup, p, ca, v string) error {
+	for _, ov := range d.Spec.OverrideValuesObj {
+		a, err := NewAppClient().GetApp(ov.AppName, p, ca, v)
+		if err != nil || a.Spec.Chart != nil {
+			continue
+		}
+		if a.Metadata.Type != "" && a.Metadata.Type != helm.AppTypeHelm {
+			if len(ov.StructuredValues) > 0 || len(ov.setValues()) > 0 {
+				return pkgerrors.Errorf("Invalid override values: the %s app %s has no values", a.Metadata.Type, ov.AppName)
+			}
+			continue
+		}
+		appContent, err := getAppFile(a, p, ca, v, d.MetaData.Name)
+		if err != nil {
+			continue
+		}
+		appPC, err := NewAppProfileClient().GetAppProfileContentByApp(p, ca, v, d.Spec.Profile, ov.AppName)
+		if err != nil {
+			continue
+		}
+		profile, err := base64.StdEncoding.DecodeString(appPC.Profile)
+		if err != nil {
+			return pkgerrors.Wrap(err, "Fail to convert to byte array")
+		}
+		err = helm.ValidateValues(appContent, profile, ov.StructuredValues, ov.setValues(), ov.AppName, ManifestFileName)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
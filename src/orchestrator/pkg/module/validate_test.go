@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package module
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+	"github.com/open-ness/EMCO/src/orchestrator/utils/helm"
+)
+
+// archive returns the base64 tar.gz of the files
+func archive(t *testing.T, files map[string]string) string {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content))})
+		if err != nil {
+			t.Fatalf("Error writing archive: %s", err)
+		}
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gz.Close()
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+// chartContent returns the AppContent of a chart with a ConfigMap template
+func chartContent(t *testing.T, template string) AppContent {
+	return AppContent{FileContent: archive(t, map[string]string{
+		"app1/Chart.yaml":            "apiVersion: v2\nname: app1\nversion: 1.0.0\n",
+		"app1/values.yaml":           "name: config\n",
+		"app1/values.schema.json":    `{"properties": {"name": {"type": "string", "maxLength": 16}}}`,
+		"app1/templates/config.yaml": template,
+	})}
+}
+
+func TestValidateApp(t *testing.T) {
+	app := App{Metadata: AppMetaData{Name: "app1"}}
+	testCases := []struct {
+		label    string
+		template string
+		files    []string
+	}{
+		{label: "Valid chart", template: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Values.name }}\n"},
+		{label: "Broken template", template: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Values.name | nofunc }}\n", files: []string{"app1/templates/config.yaml"}},
+		{label: "Missing name", template: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  labels: {}\n", files: []string{"app1/templates/config.yaml"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.label, func(t *testing.T) {
+			err := ValidateApp(app, chartContent(t, tc.template))
+			if tc.files == nil {
+				if err != nil {
+					t.Fatalf("ValidateApp returned an unexpected error %s", err)
+				}
+				return
+			}
+			verr, ok := err.(*helm.ValidationError)
+			if !ok {
+				t.Fatalf("Expected a ValidationError, got %v", err)
+			}
+			if len(verr.Files) != len(tc.files) || verr.Files[0].File != tc.files[0] {
+				t.Errorf("Expected the errors of %v, got %+v", tc.files, verr.Files)
+			}
+		})
+	}
+
+	// The charts in repositories are validated at approval
+	app.Spec.Chart = &ChartReference{Repository: "https://charts.example.com", Name: "app1", Version: "1.0.0"}
+	if err := ValidateApp(app, AppContent{}); err != nil {
+		t.Errorf("ValidateApp of a chart reference returned an unexpected error %s", err)
+	}
+}
+
+func TestValidateOverrideValues(t *testing.T) {
+	db.DBconn = &db.NewMockDB{}
+
+	_, err := NewProjectClient().CreateProject(Project{MetaData: ProjectMetaData{Name: "testProject"}}, false)
+	if err != nil {
+		t.Fatalf("CreateProject returned an unexpected error %s", err)
+	}
+	ca := CompositeApp{Metadata: CompositeAppMetaData{Name: "ca1"}, Spec: CompositeAppSpec{Version: "v1"}}
+	_, err = NewCompositeAppClient().CreateCompositeApp(ca, "testProject", false)
+	if err != nil {
+		t.Fatalf("CreateCompositeApp returned an unexpected error %s", err)
+	}
+	app := App{Metadata: AppMetaData{Name: "app1"}}
+	_, err = NewAppClient().CreateApp(app, chartContent(t, "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Values.name }}\n"), "testProject", "ca1", "v1", false)
+	if err != nil {
+		t.Fatalf("CreateApp returned an unexpected error %s", err)
+	}
+	_, err = NewCompositeProfileClient().CreateCompositeProfile(CompositeProfile{Metadata: CompositeProfileMetadata{Name: "cp1"}}, "testProject", "ca1", "v1", false)
+	if err != nil {
+		t.Fatalf("CreateCompositeProfile returned an unexpected error %s", err)
+	}
+	profile := AppProfileContent{Profile: archive(t, map[string]string{
+		"manifest.yaml": "version: v1\ntype:\n  values: values.yaml\n",
+		"values.yaml":   "name: profile\n",
+	})}
+	ap := AppProfile{Metadata: AppProfileMetadata{Name: "ap1"}, Spec: AppProfileSpec{AppName: "app1"}}
+	_, err = NewAppProfileClient().CreateAppProfile("testProject", "ca1", "v1", "cp1", ap, profile, false)
+	if err != nil {
+		t.Fatalf("CreateAppProfile returned an unexpected error %s", err)
+	}
+
+	dig := DeploymentIntentGroup{
+		MetaData: DepMetaData{Name: "dig1"},
+		Spec: DepSpecData{Profile: "cp1", Version: "r1", LogicalCloud: "lc1", OverrideValuesObj: []OverrideValues{
+			{AppName: "app1", StructuredValues: map[string]interface{}{"name": "a-name-too-long-for-the-schema"}},
+		}},
+	}
+	_, err = NewDeploymentIntentGroupClient().CreateDeploymentIntentGroup(dig, "testProject", "ca1", "v1")
+	if err == nil || !strings.Contains(err.Error(), "Invalid override values of app app1") {
+		t.Fatalf("Expected the override values to be invalid, got %v", err)
+	}
+
+	dig.Spec.OverrideValuesObj[0].StructuredValues["name"] = "override"
+	_, err = NewDeploymentIntentGroupClient().CreateDeploymentIntentGroup(dig, "testProject", "ca1", "v1")
+	if err != nil {
+		t.Fatalf("CreateDeploymentIntentGroup returned an unexpected error %s", err)
+	}
+
+	// The manifests apps have no values
+	app = App{Metadata: AppMetaData{Name: "app2", Type: helm.AppTypeManifests}}
+	content := AppContent{FileContent: archive(t, map[string]string{
+		"app2/config.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\n",
+	})}
+	_, err = NewAppClient().CreateApp(app, content, "testProject", "ca1", "v1", false)
+	if err != nil {
+		t.Fatalf("CreateApp returned an unexpected error %s", err)
+	}
+	dig.MetaData.Name = "dig2"
+	dig.Spec.OverrideValuesObj = []OverrideValues{{AppName: "app2", ValuesObj: map[string]string{"name": "override"}}}
+	_, err = NewDeploymentIntentGroupClient().CreateDeploymentIntentGroup(dig, "testProject", "ca1", "v1")
+	if err == nil || !strings.Contains(err.Error(), "Invalid override values: the manifests app app2 has no values") {
+		t.Fatalf("Expected the override values of the manifests app to be rejected, got %v", err)
+	}
+}
@@ -17,6 +17,8 @@ type statusValues struct {
 	Failed   RsyncStatus
 	Retrying RsyncStatus
 	Deleted  RsyncStatus
+	Drifted  RsyncStatus
+	Conflict RsyncStatus
 }
 
 var RsyncStatusEnum = &statusValues{
@@ -25,4 +27,6 @@ var RsyncStatusEnum = &statusValues{
 	Failed:   "Failed",
 	Retrying: "Retrying",
 	Deleted:  "Deleted",
+	Drifted:  "Drifted",
+	Conflict: "Conflict",
 }
@@ -4,6 +4,7 @@
 package rtcontext
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"strings"
@@ -20,6 +21,8 @@ const prefix string = "/context/"
 type RunTimeContext struct {
 	cid  interface{}
 	meta interface{}
+	// ctx is the context the calls to the ContextDb are traced in, if set
+	ctx context.Context
 }
 
 type Rtcontext interface {
@@ -40,6 +43,21 @@ type Rtcontext interface {
 	RtcAddOneLevel(pl interface{}, level string, value interface{}) (interface{}, error)
 }
 
+// WithContext returns a copy of the RunTimeContext recording its calls to the
+// ContextDb as children of the span of ctx
+func (rtc RunTimeContext) WithContext(ctx context.Context) RunTimeContext {
+	rtc.ctx = ctx
+	return rtc
+}
+
+// db returns the ContextDb of the calls of the RunTimeContext
+func (rtc *RunTimeContext) db() contextdb.ContextDb {
+	if rtc.ctx == nil {
+		return contextdb.Db
+	}
+	return contextdb.WithContext(rtc.ctx)
+}
+
 //Intialize context by assiging a new id
 func (rtc *RunTimeContext) RtcInit() (interface{}, error) {
 	if rtc.cid != nil {
@@ -78,7 +96,7 @@ func (rtc *RunTimeContext) RtcCreate() (interface{}, error) {
 		return nil, pkgerrors.Errorf("Not a valid run time context prefix")
 	}
 	id := strings.SplitN(cid, "/", 4)[2]
-	err := contextdb.Db.Put(cid, id)
+	err := rtc.db().Put(cid, id)
 	if err != nil {
 		return nil, pkgerrors.Errorf("Error creating run time context: %s", err.Error())
 	}
@@ -98,7 +116,7 @@ func (rtc *RunTimeContext) RtcAddMeta(meta interface{}) error {
 
 	rtc.meta = meta
 	k := cid + "meta" + "/"
-	err := contextdb.Db.Put(k, rtc.meta)
+	err := rtc.db().Put(k, rtc.meta)
 	if err != nil {
 		return pkgerrors.Errorf("Error saving metadata in run time context: %s", err.Error())
 	}
@@ -114,7 +132,7 @@ func (rtc *RunTimeContext) RtcGet() (interface{}, error) {
 	}
 
 	var value string
-	err := contextdb.Db.Get(str, &value)
+	err := rtc.db().Get(str, &value)
 	if err != nil {
 		return nil, pkgerrors.Errorf("Error getting run time context metadata: %s", err.Error())
 	}
@@ -134,7 +152,7 @@ func (rtc *RunTimeContext) RtcGetMeta() (interface{}, error) {
 
 	var value interface{}
 	k := str + "meta" + "/"
-	err := contextdb.Db.Get(k, &value)
+	err := rtc.db().Get(k, &value)
 	if err != nil {
 		return nil, pkgerrors.Errorf("Error getting run time context metadata: %s", err.Error())
 	}
@@ -158,7 +176,7 @@ func (rtc *RunTimeContext) RtcAddLevel(handle interface{}, level string, value s
 	}
 
 	key := str + level + "/" + value + "/"
-	err := contextdb.Db.Put(key, value)
+	err := rtc.db().Put(key, value)
 	if err != nil {
 		return nil, pkgerrors.Errorf("Error adding run time context level: %s", err.Error())
 	}
@@ -183,7 +201,7 @@ func (rtc *RunTimeContext) RtcAddOneLevel(pl interface{}, level string, value in
 	}
 
 	key := str + level + "/"
-	err := contextdb.Db.Put(key, value)
+	err := rtc.db().Put(key, value)
 	if err != nil {
 		return nil, pkgerrors.Errorf("Error adding run time context level: %s", err.Error())
 	}
@@ -206,7 +224,7 @@ func (rtc *RunTimeContext) RtcAddResource(handle interface{}, resname string, va
 	}
 
 	k := str + "resource" + "/" + resname + "/"
-	err := contextdb.Db.Put(k, value)
+	err := rtc.db().Put(k, value)
 	if err != nil {
 		return nil, pkgerrors.Errorf("Error adding run time context resource: %s", err.Error())
 	}
@@ -231,7 +249,7 @@ func (rtc *RunTimeContext) RtcAddInstruction(handle interface{}, level string, i
 		return nil, pkgerrors.Errorf("Not a valid run time context instruction value")
 	}
 	k := str + level + "/" + "instruction" + "/" + insttype + "/"
-	err := contextdb.Db.Put(k, fmt.Sprintf("%v", value))
+	err := rtc.db().Put(k, fmt.Sprintf("%v", value))
 	if err != nil {
 		return nil, pkgerrors.Errorf("Error adding run time context instruction: %s", err.Error())
 	}
@@ -246,7 +264,7 @@ func (rtc *RunTimeContext) RtcDeletePair(handle interface{}) error {
 	if !strings.HasPrefix(str, sid) {
 		return pkgerrors.Errorf("Not a valid run time context handle")
 	}
-	err := contextdb.Db.Delete(str)
+	err := rtc.db().Delete(str)
 	if err != nil {
 		return pkgerrors.Errorf("Error deleting run time context pair: %s", err.Error())
 	}
@@ -263,7 +281,7 @@ func (rtc *RunTimeContext) RtcDeletePrefix(handle interface{}) error {
 		return pkgerrors.Errorf("Not a valid run time context handle")
 	}
 
-	err := contextdb.Db.DeleteAll(str)
+	err := rtc.db().DeleteAll(str)
 	if err != nil {
 		return pkgerrors.Errorf("Error deleting run time context with prefix: %s", err.Error())
 	}
@@ -279,7 +297,7 @@ func (rtc *RunTimeContext) RtcGetHandles(handle interface{}) ([]interface{}, err
 		return nil, pkgerrors.Errorf("Not a valid run time context handle")
 	}
 
-	s, err := contextdb.Db.GetAllKeys(str)
+	s, err := rtc.db().GetAllKeys(str)
 	if err != nil {
 
 		return nil, pkgerrors.Errorf("Error getting run time context handles: %s", err.Error())
@@ -299,7 +317,7 @@ func (rtc *RunTimeContext) RtcGetValue(handle interface{}, value interface{}) er
 		return pkgerrors.Errorf("Not a valid run time context handle")
 	}
 
-	err := contextdb.Db.Get(str, value)
+	err := rtc.db().Get(str, value)
 	if err != nil {
 		logutils.Error("contextdb str", logutils.Fields{"str": str})
 		return pkgerrors.Errorf("Error getting run time context value: %s", err.Error())
@@ -315,7 +333,7 @@ func (rtc *RunTimeContext) RtcUpdateValue(handle interface{}, value interface{})
 	if !strings.HasPrefix(str, sid) {
 		return pkgerrors.Errorf("Not a valid run time context handle")
 	}
-	err := contextdb.Db.Put(str, value)
+	err := rtc.db().Put(str, value)
 	if err != nil {
 		return pkgerrors.Errorf("Error updating run time context value: %s", err.Error())
 	}
@@ -99,7 +99,7 @@ func TestRtcInit(t *testing.T) {
 }
 
 func TestRtcLoad(t *testing.T) {
-	var rtc = RunTimeContext{"", ""}
+	var rtc = RunTimeContext{cid: "", meta: ""}
 	testCases := []struct {
 		label         string
 		mockContextDb *MockContextDb
@@ -134,7 +134,7 @@ func TestRtcLoad(t *testing.T) {
 }
 
 func TestRtcCreate(t *testing.T) {
-	var rtc = RunTimeContext{"/context/5345674458787728/", ""}
+	var rtc = RunTimeContext{cid: "/context/5345674458787728/", meta: ""}
 	testCases := []struct {
 		label         string
 		mockContextDb *MockContextDb
@@ -166,7 +166,7 @@ func TestRtcCreate(t *testing.T) {
 }
 
 func TestRtcGet(t *testing.T) {
-	var rtc = RunTimeContext{"/context/5345674458787728/", ""}
+	var rtc = RunTimeContext{cid: "/context/5345674458787728/", meta: ""}
 	testCases := []struct {
 		label         string
 		mockContextDb *MockContextDb
@@ -227,7 +227,7 @@ func TestRtcGet(t *testing.T) {
 }
 
 func TestRtcAddLevel(t *testing.T) {
-	var rtc = RunTimeContext{"/context/3528435435454354/", ""}
+	var rtc = RunTimeContext{cid: "/context/3528435435454354/", meta: ""}
 	testCases := []struct {
 		label         string
 		mockContextDb *MockContextDb
@@ -291,7 +291,7 @@ func TestRtcAddLevel(t *testing.T) {
 }
 
 func TestRtcAddResource(t *testing.T) {
-	var rtc = RunTimeContext{"/context/3528435435454354/", ""}
+	var rtc = RunTimeContext{cid: "/context/3528435435454354/", meta: ""}
 	testCases := []struct {
 		label         string
 		mockContextDb *MockContextDb
@@ -355,7 +355,7 @@ func TestRtcAddResource(t *testing.T) {
 }
 
 func TestRtcAddInstruction(t *testing.T) {
-	var rtc = RunTimeContext{"/context/3528435435454354/", ""}
+	var rtc = RunTimeContext{cid: "/context/3528435435454354/", meta: ""}
 	testCases := []struct {
 		label         string
 		mockContextDb *MockContextDb
@@ -434,7 +434,7 @@ func TestRtcAddInstruction(t *testing.T) {
 }
 
 func TestRtcGetHandles(t *testing.T) {
-	var rtc = RunTimeContext{"/context/5345674458787728/", ""}
+	var rtc = RunTimeContext{cid: "/context/5345674458787728/", meta: ""}
 	testCases := []struct {
 		label         string
 		mockContextDb *MockContextDb
@@ -477,7 +477,7 @@ func TestRtcGetHandles(t *testing.T) {
 }
 
 func TestRtcGetValue(t *testing.T) {
-	var rtc = RunTimeContext{"/context/5345674458787728/", ""}
+	var rtc = RunTimeContext{cid: "/context/5345674458787728/", meta: ""}
 	testCases := []struct {
 		label         string
 		mockContextDb *MockContextDb
@@ -521,7 +521,7 @@ func TestRtcGetValue(t *testing.T) {
 }
 
 func TestRtcUpdateValue(t *testing.T) {
-	var rtc = RunTimeContext{"/context/5345674458787728/", ""}
+	var rtc = RunTimeContext{cid: "/context/5345674458787728/", meta: ""}
 	testCases := []struct {
 		label         string
 		mockContextDb *MockContextDb
@@ -568,7 +568,7 @@ func TestRtcUpdateValue(t *testing.T) {
 }
 
 func TestRtcDeletePair(t *testing.T) {
-	var rtc = RunTimeContext{"/context/5345674458787728/", ""}
+	var rtc = RunTimeContext{cid: "/context/5345674458787728/", meta: ""}
 	testCases := []struct {
 		label         string
 		mockContextDb *MockContextDb
@@ -608,7 +608,7 @@ func TestRtcDeletePair(t *testing.T) {
 }
 
 func TestRtcDeletePrefix(t *testing.T) {
-	var rtc = RunTimeContext{"/context/5345674458787728/", ""}
+	var rtc = RunTimeContext{cid: "/context/5345674458787728/", meta: ""}
 	testCases := []struct {
 		label         string
 		mockContextDb *MockContextDb
@@ -160,3 +160,55 @@ func UpdateAppContextStatusContextID(ctxid string, sctxid string) error {
 	}
 	return nil
 }
+
+// UpdateAppContextRollout sets the waves the AppContext is rolled out in
+func UpdateAppContextRollout(ctxid string, r appcontext.Rollout) error {
+	ac, err := GetAppContextFromId(ctxid)
+	if err != nil {
+		return err
+	}
+	hc, err := ac.GetCompositeAppHandle()
+	if err != nil {
+		return err
+	}
+	rh, err := ac.GetLevelHandle(hc, appcontext.RolloutKey)
+	if rh == nil {
+		_, err = ac.AddLevelValue(hc, appcontext.RolloutKey, r)
+	} else {
+		err = ac.UpdateValue(rh, r)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetAppContextRolloutStatus returns the progress of the rollout of the AppContext
+func GetAppContextRolloutStatus(ctxid string) (appcontext.RolloutStatus, error) {
+	ac, err := GetAppContextFromId(ctxid)
+	if err != nil {
+		return appcontext.RolloutStatus{}, err
+	}
+	hc, err := ac.GetCompositeAppHandle()
+	if err != nil {
+		return appcontext.RolloutStatus{}, err
+	}
+	rh, err := ac.GetLevelHandle(hc, appcontext.RolloutStatusKey)
+	if err != nil {
+		return appcontext.RolloutStatus{}, err
+	}
+	v, err := ac.GetValue(rh)
+	if err != nil {
+		return appcontext.RolloutStatus{}, err
+	}
+	js, err := json.Marshal(v)
+	if err != nil {
+		return appcontext.RolloutStatus{}, err
+	}
+	rs := appcontext.RolloutStatus{}
+	err = json.Unmarshal(js, &rs)
+	if err != nil {
+		return appcontext.RolloutStatus{}, err
+	}
+	return rs, nil
+}
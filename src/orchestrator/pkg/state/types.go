@@ -16,11 +16,13 @@ type StateInfo struct {
 
 // ActionEntry is used to keep track of the time an action (e.g. Created, Instantiate, Terminate) was invoked
 // For actions where an AppContext is relevent, the ContextId field will be non-zero length
+// Reason explains actions that were not requested by the user, e.g. an automatic rollback
 type ActionEntry struct {
 	State     StateValue `json:"state"`
 	ContextId string     `json:"instance"`
 	TimeStamp time.Time  `json:"time"`
 	Revision  int64      `json:"revision"`
+	Reason    string     `json:"reason,omitempty"`
 }
 
 type StateValue = string
@@ -479,6 +479,17 @@ func prepareStatusResult(statusType string, stateInfo state.StateInfo, qInstance
 	}
 
 	statusResult.Status = acStatus.Status
+	// The children are linked to the current AppContext
+	var childCtxIds []string
+	if statusType != clusterStatus {
+		childCtxIds = getChildContextIds(ac)
+		if len(childCtxIds) > 0 {
+			// Add the child context IDs to status result
+			statusResult.ChildContextIDs = childCtxIds
+		}
+		// Orphaned resources found by rsync on the clusters
+		statusResult.Prune = getPruneStatus(ac)
+	}
 	// For App and cluster level status use status AppContext
 	ac, err = state.GetAppContextFromId(statusCtxId)
 	if err != nil {
@@ -490,10 +501,6 @@ func prepareStatusResult(statusType string, stateInfo state.StateInfo, qInstance
 		if err != nil {
 			return StatusResult{}, pkgerrors.Wrap(err, "Error getting CompositeAppMeta")
 		}
-		if len(caMeta.ChildContextIDs) > 0 {
-			// Add the child context IDs to status result
-			statusResult.ChildContextIDs = caMeta.ChildContextIDs
-		}
 	}
 
 	rsyncStatusCnts := make(map[string]int)
@@ -501,17 +508,91 @@ func prepareStatusResult(statusType string, stateInfo state.StateInfo, qInstance
 
 	// Get the list of apps from the app context
 	apps := getListOfApps(currentCtxId)
+	// and of the child contexts
+	childApps := make(map[string][]string)
+	allApps := apps
+	for _, id := range childCtxIds {
+		childApps[id] = getListOfApps(id)
+		allApps = append(allApps, childApps[id]...)
+	}
 
 	// If filter-apps list is provided, ensure that every app to be
 	// filtered is part of this composite app
 	for _, fApp := range fApps {
-		if !isNameInList(fApp, apps) {
+		if !isNameInList(fApp, allApps) {
 			return StatusResult{},
 				fmt.Errorf("Filter app %s not in list of apps for composite app %s",
 					fApp, caMeta.CompositeApp)
 		}
 	}
 
+	addAppsStatus(&statusResult, ac, apps, qType, qOutput, fApps, fClusters, fResources, rsyncStatusCnts, clusterStatusCnts)
+	// The status of the child contexts is aggregated with the status of
+	// the composite app
+	for _, id := range childCtxIds {
+		cac, err := state.GetAppContextFromId(id)
+		if err != nil {
+			log.Info(":: Error loading the child context ::", log.Fields{"childContext": id, "Error": err})
+			continue
+		}
+		addAppsStatus(&statusResult, cac, childApps[id], qType, qOutput, fApps, fClusters, fResources, rsyncStatusCnts, clusterStatusCnts)
+	}
+	statusResult.RsyncStatus = rsyncStatusCnts
+	statusResult.ClusterStatus = clusterStatusCnts
+
+	return statusResult, nil
+}
+
+// getPruneStatus returns the report of the last prune of an AppContext, nil
+// if rsync didn't prune it
+func getPruneStatus(ac appcontext.AppContext) *appcontext.PruneStatus {
+	h, err := ac.GetCompositeAppHandle()
+	if err != nil {
+		return nil
+	}
+	ph, err := ac.GetLevelHandle(h, appcontext.PruneStatusKey)
+	if err != nil {
+		return nil
+	}
+	v, err := ac.GetValue(ph)
+	if err != nil {
+		return nil
+	}
+	js, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	ps := appcontext.PruneStatus{}
+	if err := json.Unmarshal(js, &ps); err != nil {
+		return nil
+	}
+	return &ps
+}
+
+// getChildContextIds returns the IDs of the child contexts of an AppContext
+// and of their children
+func getChildContextIds(ac appcontext.AppContext) []string {
+	caMeta, err := ac.GetCompositeAppMeta()
+	if err != nil {
+		return nil
+	}
+	var ids []string
+	for _, id := range caMeta.ChildContextIDs {
+		if id == "" {
+			continue
+		}
+		ids = append(ids, id)
+		cac, err := state.GetAppContextFromId(id)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, getChildContextIds(cac)...)
+	}
+	return ids
+}
+
+// addAppsStatus adds the status of the apps of an AppContext to the statusResult
+func addAppsStatus(statusResult *StatusResult, ac appcontext.AppContext, apps []string, qType, qOutput string, fApps, fClusters, fResources []string, rsyncStatusCnts, clusterStatusCnts map[string]int) {
 	// Loop through each app and get the status data for each cluster in the app
 	for _, app := range apps {
 		appCount := 0
@@ -538,6 +619,7 @@ func prepareStatusResult(statusType string, stateInfo state.StateInfo, qInstance
 			clusterStatus.ClusterProvider = pc[0]
 			clusterStatus.Cluster = pc[1]
 			clusterStatus.ReadyStatus = getClusterReadyStatus(ac, app, cluster)
+			clusterStatus.Error = getClusterFailure(ac, app, cluster)
 
 			if qType == "cluster" {
 				csh, err := ac.GetClusterStatusHandle(app, cluster)
@@ -600,10 +682,6 @@ func prepareStatusResult(statusType string, stateInfo state.StateInfo, qInstance
 			statusResult.Apps = append(statusResult.Apps, appStatus)
 		}
 	}
-	statusResult.RsyncStatus = rsyncStatusCnts
-	statusResult.ClusterStatus = clusterStatusCnts
-
-	return statusResult, nil
 }
 
 // PrepareAppsListStatusResult takes in a resource stateInfo object, the list of apps and the query parameters.
@@ -860,11 +938,30 @@ func PrepareResourcesByAppStatusResult(stateInfo state.StateInfo, qInstance, qTy
 }
 // Read readystatus from reference
 func getClusterReadyStatus(ac appcontext.AppContext, app, cluster string) string {
+	status, err := getClusterReferenceValue(ac, app, cluster, "readystatus")
+	if err != nil || status == nil {
+		return string(appcontext.ClusterReadyStatusEnum.Unknown)
+	}
+	return status.(string)
+}
+
+// Read the error of a cluster the AppContext continued without from reference
+func getClusterFailure(ac appcontext.AppContext, app, cluster string) string {
+	failure, err := getClusterReferenceValue(ac, app, cluster, appcontext.ClusterFailureKey)
+	if err != nil || failure == nil {
+		return ""
+	}
+	msg, _ := failure.(string)
+	return msg
+}
+
+// Read the value of the key of a cluster from reference, nil if the key doesn't exist
+func getClusterReferenceValue(ac appcontext.AppContext, app, cluster, key string) (interface{}, error) {
 
 	ch, err := ac.GetClusterHandle(app, cluster)
 	if err != nil {
 		log.Error("Cluster handle not found", log.Fields{"cluster": cluster})
-		return string(appcontext.ClusterReadyStatusEnum.Unknown)
+		return nil, err
 	}
 	var val = ""
 	// Read refernce appContext value
@@ -880,29 +977,29 @@ func getClusterReadyStatus(ac appcontext.AppContext, app, cluster string) string
 	}
 	if err != nil{
 		log.Error("Reference not found for cluster status", log.Fields{"cluster": cluster, "error": err})
-		return string(appcontext.ClusterReadyStatusEnum.Unknown)
+		return nil, err
 	}
 	// Load the reference appContext
 	ref := appcontext.AppContext{}
 	_, err = ref.LoadAppContext(val)
 	if err != nil {
 		log.Error(":: Error loading the app context::", log.Fields{"appContextId": val, "error": err})
-		return string(appcontext.ClusterReadyStatusEnum.Unknown)
+		return nil, err
 	}
 	rlh, err := ref.GetClusterHandle(app, cluster)
 	if err != nil {
 		log.Error("Error getting cluster handle for Reference", log.Fields{"cluster": cluster, "error": err})
-		return string(appcontext.ClusterReadyStatusEnum.Unknown)
+		return nil, err
 	}
-	rsh, err := ref.GetLevelHandle(rlh, "readystatus")
+	rsh, err := ref.GetLevelHandle(rlh, key)
 
 	if rsh != nil {
-		status, err := ref.GetValue(rsh)
+		value, err := ref.GetValue(rsh)
 		if err != nil {
-			log.Error("Error getting readystatus from Reference", log.Fields{"cluster": cluster, "error": err})
-			return string(appcontext.ClusterReadyStatusEnum.Unknown)
+			log.Error("Error getting "+key+" from Reference", log.Fields{"cluster": cluster, "error": err})
+			return nil, err
 		}
-		return status.(string)
+		return value, nil
 	}
-	return string(appcontext.ClusterReadyStatusEnum.Unknown)
+	return nil, nil
 }
@@ -126,10 +126,59 @@ func createSampleVfwAppContext() (appContext, error) {
 	return appContext{ac: ac, ctxVal: ctxVal}, nil
 }
 
+// populate a child app context of an app context, with an app on one cluster
+func createSampleChildAppContext(parent appContext) (string, error) {
+	ac := appcontext.AppContext{}
+	ctxVal, err := ac.InitAppContext()
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Error making appcontext")
+	}
+	cah, err := ac.CreateCompositeApp()
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Error making composite app handle")
+	}
+	childId := fmt.Sprintf("%v", ctxVal)
+	caMeta := appcontext.CompositeAppMeta{Project: "testvfw", CompositeApp: "service-discovery", Version: "v1", Release: "fw0", DeploymentIntentGroup: "vfw_deployment_intent_group", Namespace: "default", Level: "0", ParentContextID: fmt.Sprintf("%v", parent.ctxVal)}
+	err = ac.AddCompositeAppMeta(caMeta)
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Error making ca meta data")
+	}
+	_, err = ac.AddLevelValue(cah, "status", appcontext.AppContextStatus{Status: appcontext.AppContextStatusEnum.Instantiated})
+	apph, err := ac.AddApp(cah, "sds")
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Error making app: sds")
+	}
+	clh, err := ac.AddCluster(apph, "vfw-cluster-provider+edge01")
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Error making cluster: edge01")
+	}
+	_, err = ac.AddLevelValue(clh, "reference", ctxVal)
+	rh, err := ac.AddResource(clh, "fw0-sink+Deployment", sinkContent)
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Error making resource")
+	}
+	_, err = ac.AddLevelValue(rh, "reference", ctxVal)
+	_, err = ac.AddLevelValue(rh, "status", resourcestatus.ResourceStatus{Status: resourcestatus.RsyncStatusEnum.Applied})
+
+	// Add the child to the parent
+	pMeta, err := parent.ac.GetCompositeAppMeta()
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Error getting parent ca meta data")
+	}
+	pMeta.ChildContextIDs = append(pMeta.ChildContextIDs, childId)
+	err = parent.ac.AddCompositeAppMeta(pMeta)
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Error making parent ca meta data")
+	}
+	return childId, nil
+}
+
 var _ = Describe("StatusHelper", func() {
 	var (
 		cdb *contextdb.MockConDb
 
+		vfwAppContext appContext
+
 		stateInfoInstantiated state.StateInfo
 
 		actionCreated      state.ActionEntry
@@ -151,6 +200,7 @@ var _ = Describe("StatusHelper", func() {
 		if err != nil {
 			fmt.Printf("make app context  error: %v\n", err)
 		}
+		vfwAppContext = appContext
 
 		actionCreated = state.ActionEntry{
 			State: state.StateEnum.Created,
@@ -383,4 +433,19 @@ var _ = Describe("StatusHelper", func() {
 		Expect(err).To(BeNil())
 		Expect(result).Should(Equal(expectedRsyncStatusResult))
 	})
+
+	It("get rsync status with the child context of instantiated vfw", func() {
+		childId, err := createSampleChildAppContext(vfwAppContext)
+		Expect(err).To(BeNil())
+		result, err := status.PrepareStatusResult(stateInfoInstantiated, "", "rsync", "summary", []string{}, []string{}, []string{})
+		Expect(err).To(BeNil())
+		Expect(result.ChildContextIDs).Should(Equal([]string{childId}))
+		Expect(result.RsyncStatus).Should(Equal(map[string]int{"Applied": 7}))
+
+		result, err = status.PrepareStatusResult(stateInfoInstantiated, "", "rsync", "all", []string{"sds"}, []string{}, []string{})
+		Expect(err).To(BeNil())
+		Expect(len(result.Apps)).Should(Equal(1))
+		Expect(result.Apps[0].Name).Should(Equal("sds"))
+		Expect(result.Apps[0].Clusters[0].Resources[0].Name).Should(Equal("fw0-sink"))
+	})
 })
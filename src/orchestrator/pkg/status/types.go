@@ -50,13 +50,14 @@ type ClusterStatusResult struct {
 }
 
 type StatusResult struct {
-	Name            string                 `json:"name,omitempty,inline"`
-	State           state.StateInfo        `json:"states,omitempty,inline"`
-	Status          appcontext.StatusValue `json:"status,omitempty,inline"`
-	RsyncStatus     map[string]int         `json:"rsync-status,omitempty,inline"`
-	ClusterStatus   map[string]int         `json:"cluster-status,omitempty,inline"`
-	Apps            []AppStatus            `json:"apps,omitempty,inline"`
-	ChildContextIDs []string               `json:"ChildContextIDs,omitempty,inline"`
+	Name            string                  `json:"name,omitempty,inline"`
+	State           state.StateInfo         `json:"states,omitempty,inline"`
+	Status          appcontext.StatusValue  `json:"status,omitempty,inline"`
+	RsyncStatus     map[string]int          `json:"rsync-status,omitempty,inline"`
+	ClusterStatus   map[string]int          `json:"cluster-status,omitempty,inline"`
+	Apps            []AppStatus             `json:"apps,omitempty,inline"`
+	ChildContextIDs []string                `json:"ChildContextIDs,omitempty,inline"`
+	Prune           *appcontext.PruneStatus `json:"prune,omitempty,inline"`
 }
 
 type AppStatus struct {
@@ -65,10 +66,12 @@ type AppStatus struct {
 }
 
 type ClusterStatus struct {
-	ClusterProvider string           `json:"cluster-provider,omitempty"`
-	Cluster         string           `json:"cluster,omitempty"`
-	ReadyStatus     string           `json:"readystatus,omitempty"`
-	Resources       []ResourceStatus `json:"resources,omitempty"`
+	ClusterProvider string `json:"cluster-provider,omitempty"`
+	Cluster         string `json:"cluster,omitempty"`
+	ReadyStatus     string `json:"readystatus,omitempty"`
+	// Error of the cluster if the AppContext continued without it
+	Error     string           `json:"error,omitempty"`
+	Resources []ResourceStatus `json:"resources,omitempty"`
 }
 
 type ResourceStatus struct {
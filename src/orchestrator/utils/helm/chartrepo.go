@@ -0,0 +1,266 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package helm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	logger "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	pkgerrors "github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+// The media types of the layer holding the chart in an OCI manifest
+var ociChartMediaTypes = map[string]bool{
+	"application/vnd.cncf.helm.chart.content.v1.tar+gzip": true,
+	// Used by Helm before 3.7
+	"application/tar+gzip": true,
+}
+
+// maxChartSize limits the size of the charts downloaded
+const maxChartSize = 1 << 30
+
+// ChartFetcher downloads charts from Helm chart repositories and OCI registries
+type ChartFetcher struct {
+	Client *http.Client
+}
+
+// NewChartFetcher returns a ChartFetcher
+func NewChartFetcher() *ChartFetcher {
+	return &ChartFetcher{
+		Client: &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+// Digest returns the sha256 digest of a chart, in hex
+func Digest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// credentials are sent to the host of the repository or registry only
+type credentials struct {
+	host, username, password string
+	token                    string
+}
+
+func (f *ChartFetcher) get(u string, accept string, cred *credentials) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if req.URL.Host == cred.host {
+		if cred.token != "" {
+			req.Header.Set("Authorization", "Bearer "+cred.token)
+		} else if cred.username != "" {
+			req.SetBasicAuth(cred.username, cred.password)
+		}
+	}
+	return f.Client.Do(req)
+}
+
+// read reads the body of a successful response
+func read(resp *http.Response, u string) ([]byte, error) {
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, pkgerrors.Errorf("Error fetching %s: %s", u, resp.Status)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxChartSize+1))
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "Error reading %s", u)
+	}
+	if len(data) > maxChartSize {
+		return nil, pkgerrors.Errorf("Error fetching %s: larger than 1 GB", u)
+	}
+	return data, nil
+}
+
+// FetchRepoChart downloads the version of the chart name from the Helm chart
+// repository at repoURL. The credentials are optional.
+func (f *ChartFetcher) FetchRepoChart(repoURL, name, version, username, password string) ([]byte, error) {
+	base, err := url.Parse(repoURL)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Invalid chart repository URL")
+	}
+	cred := &credentials{host: base.Host, username: username, password: password}
+
+	indexURL := strings.TrimSuffix(repoURL, "/") + "/index.yaml"
+	resp, err := f.get(indexURL, "", cred)
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "Error fetching %s", indexURL)
+	}
+	data, err := read(resp, indexURL)
+	if err != nil {
+		return nil, err
+	}
+	var index repo.IndexFile
+	err = yaml.Unmarshal(data, &index)
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "Error parsing %s", indexURL)
+	}
+	cv, err := index.Get(name, version)
+	if err != nil || cv.Version != version {
+		return nil, pkgerrors.Errorf("Chart %s version %s not found in %s", name, version, repoURL)
+	}
+	if len(cv.URLs) == 0 {
+		return nil, pkgerrors.Errorf("Chart %s version %s has no URL in %s", name, version, repoURL)
+	}
+
+	chartURL, err := repo.ResolveReferenceURL(repoURL, cv.URLs[0])
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Fetching chart", logger.Fields{"url": chartURL})
+	resp, err = f.get(chartURL, "", cred)
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "Error fetching %s", chartURL)
+	}
+	content, err := read(resp, chartURL)
+	if err != nil {
+		return nil, err
+	}
+	if cv.Digest != "" && cv.Digest != Digest(content) {
+		return nil, pkgerrors.Errorf("Digest of %s does not match the repository index", chartURL)
+	}
+	return content, nil
+}
+
+type ociDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+}
+
+type ociManifest struct {
+	Layers []ociDescriptor `json:"layers"`
+}
+
+// FetchOCIChart downloads the chart tagged version at the OCI reference ref,
+// like oci://registry.example.com/charts/nginx. The credentials are optional.
+func (f *ChartFetcher) FetchOCIChart(ref, version, username, password string) ([]byte, error) {
+	if !strings.HasPrefix(ref, "oci://") {
+		return nil, pkgerrors.Errorf("Invalid OCI reference %s", ref)
+	}
+	parts := strings.SplitN(strings.TrimPrefix(ref, "oci://"), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, pkgerrors.Errorf("Invalid OCI reference %s", ref)
+	}
+	host, repository := parts[0], parts[1]
+	cred := &credentials{host: host, username: username, password: password}
+
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", host, repository, version)
+	data, err := f.getRegistry(manifestURL, "application/vnd.oci.image.manifest.v1+json", repository, cred)
+	if err != nil {
+		return nil, err
+	}
+	var manifest ociManifest
+	err = json.Unmarshal(data, &manifest)
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "Error parsing %s", manifestURL)
+	}
+	var layer *ociDescriptor
+	for i, l := range manifest.Layers {
+		if ociChartMediaTypes[l.MediaType] {
+			layer = &manifest.Layers[i]
+			break
+		}
+	}
+	if layer == nil {
+		return nil, pkgerrors.Errorf("No chart in %s:%s", ref, version)
+	}
+
+	blobURL := fmt.Sprintf("https://%s/v2/%s/blobs/%s", host, repository, layer.Digest)
+	logger.Info("Fetching chart", logger.Fields{"url": blobURL})
+	content, err := f.getRegistry(blobURL, "", repository, cred)
+	if err != nil {
+		return nil, err
+	}
+	if layer.Digest != "sha256:"+Digest(content) {
+		return nil, pkgerrors.Errorf("Digest of %s does not match the manifest", blobURL)
+	}
+	return content, nil
+}
+
+// getRegistry gets a resource of the registry, getting a token from the
+// authorization server if the registry asks for one
+func (f *ChartFetcher) getRegistry(u, accept, repository string, cred *credentials) ([]byte, error) {
+	resp, err := f.get(u, accept, cred)
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "Error fetching %s", u)
+	}
+	if resp.StatusCode == http.StatusUnauthorized && cred.token == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		if strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+			cred.token, err = f.token(challenge, repository, cred)
+			if err != nil {
+				return nil, err
+			}
+		}
+		resp, err = f.get(u, accept, cred)
+		if err != nil {
+			return nil, pkgerrors.Wrapf(err, "Error fetching %s", u)
+		}
+	}
+	return read(resp, u)
+}
+
+// token gets a pull token from the authorization server of the Bearer
+// challenge of a registry
+func (f *ChartFetcher) token(challenge, repository string, cred *credentials) (string, error) {
+	params := map[string]string{}
+	for _, p := range strings.Split(challenge[len("bearer "):], ",") {
+		kv := strings.SplitN(strings.TrimSpace(p), "=", 2)
+		if len(kv) == 2 {
+			params[strings.ToLower(kv[0])] = strings.Trim(kv[1], `"`)
+		}
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", pkgerrors.Errorf("Invalid registry authentication challenge %s", challenge)
+	}
+	q := realm.Query()
+	if params["service"] != "" {
+		q.Set("service", params["service"])
+	}
+	q.Set("scope", "repository:"+repository+":pull")
+	realm.RawQuery = q.Encode()
+
+	// The credentials go to the authorization server
+	resp, err := f.get(realm.String(), "", &credentials{host: realm.Host, username: cred.username, password: cred.password})
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Error getting registry token")
+	}
+	data, err := read(resp, realm.String())
+	if err != nil {
+		return "", err
+	}
+	var t struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	err = json.Unmarshal(data, &t)
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Error parsing registry token")
+	}
+	if t.Token == "" {
+		t.Token = t.AccessToken
+	}
+	if t.Token == "" {
+		return "", pkgerrors.New("No registry token")
+	}
+	return t.Token, nil
+}
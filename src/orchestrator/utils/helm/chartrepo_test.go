@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package helm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// chartArchive returns a chart archive with a Chart.yaml
+func chartArchive(t *testing.T, name, version string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	data := []byte(fmt.Sprintf("apiVersion: v2\nname: %s\nversion: %s\n", name, version))
+	err := tw.WriteHeader(&tar.Header{Name: name + "/Chart.yaml", Mode: 0644, Size: int64(len(data))})
+	if err != nil {
+		t.Fatalf("Error writing chart archive: %s", err)
+	}
+	tw.Write(data)
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+func TestFetchRepoChart(t *testing.T) {
+	chart := chartArchive(t, "nginx", "1.2.3")
+	digest := Digest(chart)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/charts/index.yaml", func(w http.ResponseWriter, r *http.Request) {
+		if u, p, ok := r.BasicAuth(); !ok || u != "user" || p != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, `apiVersion: v1
+entries:
+  nginx:
+  - name: nginx
+    version: 1.2.3
+    digest: %s
+    urls:
+    - nginx-1.2.3.tgz
+  - name: nginx
+    version: 1.2.4
+    digest: 0000
+    urls:
+    - nginx-1.2.4.tgz
+`, digest)
+	})
+	mux.HandleFunc("/charts/nginx-1.2.3.tgz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(chart)
+	})
+	mux.HandleFunc("/charts/nginx-1.2.4.tgz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(chart)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	f := &ChartFetcher{Client: server.Client()}
+	testCases := []struct {
+		label         string
+		name          string
+		version       string
+		password      string
+		expectedError string
+	}{
+		{label: "Fetch chart", name: "nginx", version: "1.2.3", password: "secret"},
+		{label: "Wrong credentials", name: "nginx", version: "1.2.3", password: "wrong", expectedError: "401"},
+		{label: "Unknown version", name: "nginx", version: "1.0.0", password: "secret", expectedError: "not found"},
+		{label: "Unknown chart", name: "redis", version: "1.2.3", password: "secret", expectedError: "not found"},
+		{label: "Digest mismatch", name: "nginx", version: "1.2.4", password: "secret", expectedError: "does not match"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.label, func(t *testing.T) {
+			got, err := f.FetchRepoChart(server.URL+"/charts/", tc.name, tc.version, "user", tc.password)
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Fatalf("Expected an error with %q, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchRepoChart returned an unexpected error %s", err)
+			}
+			if !bytes.Equal(got, chart) {
+				t.Errorf("FetchRepoChart returned an unexpected chart")
+			}
+		})
+	}
+}
+
+func TestFetchOCIChart(t *testing.T) {
+	chart := chartArchive(t, "nginx", "1.2.3")
+	digest := "sha256:" + Digest(chart)
+	var server *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("scope") != "repository:charts/nginx:pull" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if u, p, ok := r.BasicAuth(); !ok || u != "user" || p != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"token": "t1"})
+	})
+	authorized := func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Header.Get("Authorization") != "Bearer t1" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return false
+		}
+		return true
+	}
+	mux.HandleFunc("/v2/charts/nginx/manifests/1.2.3", func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(w, r) {
+			return
+		}
+		json.NewEncoder(w).Encode(ociManifest{Layers: []ociDescriptor{
+			{MediaType: "application/vnd.cncf.helm.config.v1+json", Digest: "sha256:0000"},
+			{MediaType: "application/vnd.cncf.helm.chart.content.v1.tar+gzip", Digest: digest},
+		}})
+	})
+	mux.HandleFunc("/v2/charts/nginx/blobs/"+digest, func(w http.ResponseWriter, r *http.Request) {
+		if authorized(w, r) {
+			w.Write(chart)
+		}
+	})
+	server = httptest.NewTLSServer(mux)
+	defer server.Close()
+
+	f := &ChartFetcher{Client: server.Client()}
+	ref := "oci://" + strings.TrimPrefix(server.URL, "https://") + "/charts/nginx"
+	got, err := f.FetchOCIChart(ref, "1.2.3", "user", "secret")
+	if err != nil {
+		t.Fatalf("FetchOCIChart returned an unexpected error %s", err)
+	}
+	if !bytes.Equal(got, chart) {
+		t.Errorf("FetchOCIChart returned an unexpected chart")
+	}
+
+	_, err = f.FetchOCIChart(ref, "1.2.3", "user", "wrong")
+	if err == nil {
+		t.Errorf("FetchOCIChart with the wrong credentials returned no error")
+	}
+	_, err = f.FetchOCIChart(ref, "2.0.0", "user", "secret")
+	if err == nil {
+		t.Errorf("FetchOCIChart of an unknown version returned no error")
+	}
+}
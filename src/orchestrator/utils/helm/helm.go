@@ -13,6 +13,7 @@ import (
 	"strings"
 	"sort"
 
+	"github.com/ghodss/yaml"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart/loader"
 	"helm.sh/helm/v3/pkg/cli"
@@ -71,7 +72,8 @@ func NewTemplateClient(k8sversion, namespace, releasename, manifestFileName stri
 }
 
 // Combines valueFiles and values into a single values stream.
-// values takes precedence over valueFiles
+// The valueFiles are deep-merged in order, and values takes precedence
+// over valueFiles
 func (h *TemplateClient) processValues(valueFiles []string, values []string) (map[string]interface{}, error) {
 	settings := cli.New()
 	providers := getter.All(settings)
@@ -216,7 +218,7 @@ func getGroupVersionKind(data string) (schema.GroupVersionKind, error) {
 
 // Resolver is an interface exposes the helm related functionalities
 type Resolver interface {
-	Resolve(appContent, appProfileContent []byte, overrideValuesOfAppStr []string, appName string) ([]KubernetesResourceTemplate, error)
+	Resolve(appContent, appProfileContent []byte, overrideValues map[string]interface{}, overrideValuesOfAppStr []string, appName string) ([]KubernetesResourceTemplate, error)
 }
 
 func cleanupTempFiles(fp string) error {
@@ -232,7 +234,15 @@ func cleanupTempFiles(fp string) error {
 }
 
 // Resolve function
-func (h *TemplateClient) Resolve(appContent []byte, appProfileContent []byte, overrideValuesOfAppStr []string, appName string) ([]KubernetesResourceTemplate, error) {
+func (h *TemplateClient) Resolve(appContent []byte, appProfileContent []byte, overrideValues map[string]interface{}, overrideValuesOfAppStr []string, appName string) ([]KubernetesResourceTemplate, error) {
+	return resolve(h, h.manifestName, appContent, appProfileContent, overrideValues, overrideValuesOfAppStr, appName)
+}
+
+// resolve extracts the file and the profile of an app, applies the overrides
+// of the profile and renders the app with r. The values of the chart are
+// overridden by the values of the profile, then by the override values, and
+// last by the override values in the foo=bar format.
+func resolve(r Renderer, manifestName string, appContent []byte, appProfileContent []byte, overrideValues map[string]interface{}, overrideValuesOfAppStr []string, appName string) ([]KubernetesResourceTemplate, error) {
 
 	var sortedTemplates []KubernetesResourceTemplate
 
@@ -245,33 +255,80 @@ func (h *TemplateClient) Resolve(appContent []byte, appProfileContent []byte, ov
 	}
 	logger.Info("The chartBasePath ::", logger.Fields{"chartBasePath": chartBasePath})
 
-	//prPath is the tmp path where the appProfileContent is extracted.
-	prPath, err := utils.ExtractTarBall(bytes.NewBuffer(appProfileContent))
-	defer cleanupTempFiles(prPath)
-	if err != nil {
-		logger.Error("Error while extracting Profile Content", logger.Fields{})
-		return sortedTemplates, pkgerrors.Wrap(err, "Error while extracting Profile Content")
+	// An app without a profile is rendered with its default values
+	valueFiles := []string{}
+	if len(appProfileContent) > 0 {
+		//prPath is the tmp path where the appProfileContent is extracted.
+		prPath, err := utils.ExtractTarBall(bytes.NewBuffer(appProfileContent))
+		defer cleanupTempFiles(prPath)
+		if err != nil {
+			logger.Error("Error while extracting Profile Content", logger.Fields{})
+			return sortedTemplates, pkgerrors.Wrap(err, "Error while extracting Profile Content")
+		}
+		logger.Info("The profile path:: ", logger.Fields{"Profile Path": prPath})
+
+		prYamlClient, err := ProcessProfileYaml(prPath, manifestName)
+		if err != nil {
+			logger.Error("Error while processing Profile Manifest", logger.Fields{})
+			return sortedTemplates, pkgerrors.Wrap(err, "Error while processing Profile Manifest")
+		}
+		logger.Info("Got the profileYamlClient..", logger.Fields{})
+
+		err = prYamlClient.CopyConfigurationOverrides(chartBasePath)
+		if err != nil {
+			logger.Error("Error while copying configresources to chart", logger.Fields{})
+			return sortedTemplates, pkgerrors.Wrap(err, "Error while copying configresources to chart")
+		}
+		valueFiles = append(valueFiles, prYamlClient.GetValues())
 	}
-	logger.Info("The profile path:: ", logger.Fields{"Profile Path": prPath})
 
-	prYamlClient, err := ProcessProfileYaml(prPath, h.manifestName)
-	if err != nil {
-		logger.Error("Error while processing Profile Manifest", logger.Fields{})
-		return sortedTemplates, pkgerrors.Wrap(err, "Error while processing Profile Manifest")
+	// The override values are merged over the values of the profile like a
+	// values file given after it
+	if len(overrideValues) > 0 {
+		data, err := yaml.Marshal(overrideValues)
+		if err != nil {
+			return sortedTemplates, pkgerrors.Wrap(err, "Error marshaling the override values")
+		}
+		overridePath := filepath.Join(chartBasePath, "override-values.yaml")
+		err = ioutil.WriteFile(overridePath, data, 0600)
+		if err != nil {
+			return sortedTemplates, pkgerrors.Wrap(err, "Error writing the override values")
+		}
+		valueFiles = append(valueFiles, overridePath)
 	}
-	logger.Info("Got the profileYamlClient..", logger.Fields{})
 
-	err = prYamlClient.CopyConfigurationOverrides(chartBasePath)
+	chartPath, err := chartDir(chartBasePath, appName)
 	if err != nil {
-		logger.Error("Error while copying configresources to chart", logger.Fields{})
-		return sortedTemplates, pkgerrors.Wrap(err, "Error while copying configresources to chart")
+		return sortedTemplates, err
 	}
-
-	chartPath := filepath.Join(chartBasePath, appName)
-	sortedTemplates, err = h.GenerateKubernetesArtifacts(chartPath, []string{prYamlClient.GetValues()}, overrideValuesOfAppStr)
+	sortedTemplates, err = r.GenerateKubernetesArtifacts(chartPath, valueFiles, overrideValuesOfAppStr)
 	if err != nil {
 		logger.Error("Error while generating final k8s yaml", logger.Fields{})
 		return sortedTemplates, pkgerrors.Wrap(err, "Error while generating final k8s yaml")
 	}
 	return sortedTemplates, nil
 }
+
+// chartDir returns the directory of the chart extracted in basePath. It is
+// named after the app, or after the chart for the charts fetched from a
+// repository.
+func chartDir(basePath, appName string) (string, error) {
+	chartPath := filepath.Join(basePath, appName)
+	if _, err := os.Stat(chartPath); err == nil {
+		return chartPath, nil
+	}
+	files, err := ioutil.ReadDir(basePath)
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Error reading the chart directory")
+	}
+	var dirs []string
+	for _, f := range files {
+		if f.IsDir() {
+			dirs = append(dirs, f.Name())
+		}
+	}
+	if len(dirs) != 1 {
+		return "", pkgerrors.Errorf("No chart directory %s in the app file", appName)
+	}
+	return filepath.Join(basePath, dirs[0]), nil
+}
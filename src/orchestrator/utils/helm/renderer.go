@@ -0,0 +1,312 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package helm
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/krusty"
+
+	logger "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	utils "github.com/open-ness/EMCO/src/orchestrator/utils"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// The types of apps, which tell how the file of an app is rendered
+const (
+	// AppTypeHelm is a Helm chart, the default
+	AppTypeHelm = "helm"
+	// AppTypeKustomize is a directory with a kustomization.yaml
+	AppTypeKustomize = "kustomize"
+	// AppTypeManifests is a directory of Kubernetes YAML or JSON manifests
+	AppTypeManifests = "manifests"
+)
+
+// Renderer renders the extracted file of an app into Kubernetes resources,
+// with the values files and values of the profile
+type Renderer interface {
+	GenerateKubernetesArtifacts(inputPath string, valueFiles []string, values []string) ([]KubernetesResourceTemplate, error)
+}
+
+// NewResolver returns the Resolver of the apps of type appType
+func NewResolver(appType, k8sversion, namespace, releasename, manifestFileName string) (Resolver, error) {
+	switch appType {
+	case "", AppTypeHelm:
+		return NewTemplateClient(k8sversion, namespace, releasename, manifestFileName), nil
+	case AppTypeKustomize:
+		return &KustomizeClient{manifestName: manifestFileName}, nil
+	case AppTypeManifests:
+		return &ManifestClient{manifestName: manifestFileName}, nil
+	}
+	return nil, pkgerrors.Errorf("Unknown app type %s", appType)
+}
+
+// KustomizeClient renders the apps that are kustomize overlays
+type KustomizeClient struct {
+	manifestName string
+}
+
+// Resolve function
+func (k *KustomizeClient) Resolve(appContent []byte, appProfileContent []byte, overrideValues map[string]interface{}, overrideValuesOfAppStr []string, appName string) ([]KubernetesResourceTemplate, error) {
+	return resolve(k, k.manifestName, appContent, appProfileContent, overrideValues, overrideValuesOfAppStr, appName)
+}
+
+// GenerateKubernetesArtifacts runs kustomize build in inputPath. Kustomize
+// has no values, the profile overrides the files of the overlay.
+func (k *KustomizeClient) GenerateKubernetesArtifacts(inputPath string, valueFiles []string,
+	values []string) ([]KubernetesResourceTemplate, error) {
+	err := checkNoValues(AppTypeKustomize, valueFiles, values)
+	if err != nil {
+		return nil, err
+	}
+	root, err := filepath.Abs(inputPath)
+	if err == nil {
+		root, err = filepath.EvalSymlinks(root)
+	}
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Error reading the kustomize app")
+	}
+	err = checkKustomizations(root)
+	if err != nil {
+		return nil, err
+	}
+	m, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(rootedFS{FileSystem: filesys.MakeFsOnDisk(), root: root}, root)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Error running kustomize build")
+	}
+	out, err := m.AsYaml()
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Error running kustomize build")
+	}
+	return writeManifests(filepath.Base(inputPath), map[string]string{"kustomization.yaml": string(out)})
+}
+
+// checkNoValues returns an error if the values files of the profile or the
+// override values set values, the apps of the type have no values
+func checkNoValues(appType string, valueFiles []string, values []string) error {
+	if len(values) > 0 {
+		return pkgerrors.Errorf("Invalid values: %s apps have no values, got %v", appType, values)
+	}
+	for _, f := range valueFiles {
+		data, err := ioutil.ReadFile(f)
+		if err != nil {
+			// The profiles of the apps without values may have no values file
+			continue
+		}
+		var v map[string]interface{}
+		err = yaml.Unmarshal(data, &v)
+		if err != nil {
+			return pkgerrors.Wrapf(err, "Error reading the values file %s", filepath.Base(f))
+		}
+		if len(v) > 0 {
+			return pkgerrors.Errorf("Invalid values: %s apps have no values, got values file %s", appType, filepath.Base(f))
+		}
+	}
+	return nil
+}
+
+// checkKustomizations checks the bases and resources of the kustomizations of
+// the app are files of the app. Kustomize would clone the remote ones with git.
+func checkKustomizations(root string) error {
+	r := rootedFS{root: root}
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		switch info.Name() {
+		case "kustomization.yaml", "kustomization.yml", "Kustomization":
+		default:
+			return nil
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var k struct {
+			Bases     []string `json:"bases"`
+			Resources []string `json:"resources"`
+		}
+		err = yaml.Unmarshal(data, &k)
+		if err != nil {
+			return pkgerrors.Wrapf(err, "Error reading %s", info.Name())
+		}
+		for _, b := range append(k.Bases, k.Resources...) {
+			name := filepath.Join(filepath.Dir(path), b)
+			if err := r.check(name); err != nil {
+				return err
+			}
+			if _, err := os.Stat(name); err != nil {
+				return pkgerrors.Errorf("Kustomize base %s is not in the app, remote bases are not supported", b)
+			}
+		}
+		return nil
+	})
+}
+
+// rootedFS is the file system of kustomize restricted to the directory of an
+// app, so the app can't read the files of the orchestrator
+type rootedFS struct {
+	filesys.FileSystem
+	root string
+}
+
+func (r rootedFS) check(name string) error {
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(r.root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return pkgerrors.Errorf("%s is outside of the app", name)
+	}
+	return nil
+}
+
+func (r rootedFS) Open(name string) (filesys.File, error) {
+	if err := r.check(name); err != nil {
+		return nil, err
+	}
+	return r.FileSystem.Open(name)
+}
+
+func (r rootedFS) IsDir(name string) bool {
+	return r.check(name) == nil && r.FileSystem.IsDir(name)
+}
+
+func (r rootedFS) CleanedAbs(path string) (filesys.ConfirmedDir, string, error) {
+	if err := r.check(path); err != nil {
+		return "", "", err
+	}
+	return r.FileSystem.CleanedAbs(path)
+}
+
+func (r rootedFS) Exists(name string) bool {
+	return r.check(name) == nil && r.FileSystem.Exists(name)
+}
+
+func (r rootedFS) Glob(pattern string) ([]string, error) {
+	matches, err := r.FileSystem.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	var inRoot []string
+	for _, m := range matches {
+		if r.check(m) == nil {
+			inRoot = append(inRoot, m)
+		}
+	}
+	return inRoot, nil
+}
+
+func (r rootedFS) ReadFile(name string) ([]byte, error) {
+	if err := r.check(name); err != nil {
+		return nil, err
+	}
+	return r.FileSystem.ReadFile(name)
+}
+
+func (r rootedFS) Walk(path string, walkFn filepath.WalkFunc) error {
+	if err := r.check(path); err != nil {
+		return err
+	}
+	return r.FileSystem.Walk(path, walkFn)
+}
+
+// ManifestClient renders the apps that are plain Kubernetes manifests
+type ManifestClient struct {
+	manifestName string
+}
+
+// Resolve function
+func (m *ManifestClient) Resolve(appContent []byte, appProfileContent []byte, overrideValues map[string]interface{}, overrideValuesOfAppStr []string, appName string) ([]KubernetesResourceTemplate, error) {
+	return resolve(m, m.manifestName, appContent, appProfileContent, overrideValues, overrideValuesOfAppStr, appName)
+}
+
+// GenerateKubernetesArtifacts reads the YAML and JSON manifests in inputPath
+// and its subdirectories. The manifests have no values, the profile
+// overrides the files.
+func (m *ManifestClient) GenerateKubernetesArtifacts(inputPath string, valueFiles []string,
+	values []string) ([]KubernetesResourceTemplate, error) {
+	err := checkNoValues(AppTypeManifests, valueFiles, values)
+	if err != nil {
+		return nil, err
+	}
+	files := map[string]string{}
+	err = filepath.Walk(inputPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(inputPath, path)
+		if err != nil {
+			return err
+		}
+		files[rel] = string(data)
+		return nil
+	})
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Error reading the manifests")
+	}
+	return writeManifests(filepath.Base(inputPath), files)
+}
+
+var blankRegex = regexp.MustCompile(`^\s*$`)
+
+// writeManifests splits the files into one file per resource in a temp
+// directory, in the order Helm installs them. Like Helm, each resource
+// starts with a comment naming its file in the app.
+func writeManifests(appDir string, files map[string]string) ([]KubernetesResourceTemplate, error) {
+	var retData []KubernetesResourceTemplate
+
+	_, manifests, err := releaseutil.SortManifests(files, chartutil.DefaultVersionSet, releaseutil.InstallOrder)
+	if err != nil {
+		return retData, pkgerrors.Wrap(err, "Error sorting the manifests")
+	}
+
+	//Create a temp directory in the system temp folder
+	outputDir, err := ioutil.TempDir("", "helm-tmpl-")
+	if err != nil {
+		return retData, pkgerrors.Wrap(err, "Got error creating temp dir")
+	}
+	logger.Info(":: The o/p dir:: ", logger.Fields{"OutPutDirectory ": outputDir})
+
+	for i, m := range manifests {
+		if blankRegex.MatchString(m.Content) || m.Head == nil || m.Head.Kind == "" {
+			continue
+		}
+		mfilePath := filepath.Join(outputDir, fmt.Sprintf("%d-%s", i, filepath.Base(m.Name)))
+		utils.EnsureDirectory(mfilePath)
+		content := fmt.Sprintf("# Source: %s\n%s", filepath.ToSlash(filepath.Join(appDir, m.Name)), m.Content)
+		err = ioutil.WriteFile(mfilePath, []byte(content), 0600)
+		if err != nil {
+			return retData, err
+		}
+		gvk, err := getGroupVersionKind(m.Content)
+		if err != nil {
+			return retData, err
+		}
+		retData = append(retData, KubernetesResourceTemplate{
+			GVK:      gvk,
+			FilePath: mfilePath,
+		})
+	}
+	return retData, nil
+}
@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package helm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const deploymentYaml = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+  - port: 80
+`
+
+func configMapYaml(value string) string {
+	return "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web-config\ndata:\n  value: \"" + value + "\"\n"
+}
+
+// archive returns a tar.gz of the files
+func archive(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		if err != nil {
+			t.Fatalf("Error writing archive: %s", err)
+		}
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+func readTemplates(t *testing.T, templates []KubernetesResourceTemplate) []string {
+	var kinds []string
+	for _, tmpl := range templates {
+		kinds = append(kinds, tmpl.GVK.Kind)
+	}
+	if len(templates) > 0 {
+		defer os.RemoveAll(filepath.Dir(templates[0].FilePath))
+	}
+	return kinds
+}
+
+func TestManifestResolve(t *testing.T) {
+	app := archive(t, map[string]string{
+		"web/deploy.yaml": deploymentYaml,
+		"web/config.yaml": configMapYaml("1"),
+		"web/README.md":   "Not a manifest",
+	})
+	profile := archive(t, map[string]string{
+		"manifest.yaml": "version: v1\ntype:\n  values: values.yaml\n  configresource:\n  - filepath: config.yaml\n    chartpath: web/config.yaml\n",
+		"values.yaml":   "",
+		"config.yaml":   configMapYaml("2"),
+	})
+
+	r, err := NewResolver(AppTypeManifests, "", "default", "r1", "manifest.yaml")
+	if err != nil {
+		t.Fatalf("NewResolver returned an unexpected error %s", err)
+	}
+	templates, err := r.Resolve(app, profile, nil, []string{}, "web")
+	if err != nil {
+		t.Fatalf("Resolve returned an unexpected error %s", err)
+	}
+	if len(templates) != 3 {
+		t.Fatalf("Expected 3 resources, got %+v", templates)
+	}
+	// The profile overrides the config
+	config, err := ioutil.ReadFile(templates[0].FilePath)
+	if err != nil || !strings.Contains(string(config), `value: "2"`) {
+		t.Errorf("Expected the ConfigMap of the profile, got %s (%v)", config, err)
+	}
+	kinds := readTemplates(t, templates)
+	if strings.Join(kinds, ",") != "ConfigMap,Service,Deployment" {
+		t.Errorf("Expected the resources in install order, got %v", kinds)
+	}
+
+	// Manifest apps have no values
+	_, err = r.Resolve(app, profile, nil, []string{"replicas=2"}, "web")
+	if err == nil || !strings.Contains(err.Error(), "have no values") {
+		t.Errorf("Expected the override values to be rejected, got %v", err)
+	}
+	profile = archive(t, map[string]string{
+		"manifest.yaml": "version: v1\ntype:\n  values: values.yaml\n",
+		"values.yaml":   "replicas: 2\n",
+	})
+	_, err = r.Resolve(app, profile, nil, []string{}, "web")
+	if err == nil || !strings.Contains(err.Error(), "have no values") {
+		t.Errorf("Expected the values of the profile to be rejected, got %v", err)
+	}
+}
+
+func TestKustomizeGenerateKubernetesArtifacts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-test-")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, "kustomization.yaml"), []byte("namePrefix: dev-\nresources:\n- deploy.yaml\n- config.yaml\n"), 0600)
+	ioutil.WriteFile(filepath.Join(dir, "deploy.yaml"), []byte(deploymentYaml), 0600)
+	ioutil.WriteFile(filepath.Join(dir, "config.yaml"), []byte(configMapYaml("1")), 0600)
+
+	r, err := NewResolver(AppTypeKustomize, "", "default", "r1", "manifest.yaml")
+	if err != nil {
+		t.Fatalf("NewResolver returned an unexpected error %s", err)
+	}
+	templates, err := r.(Renderer).GenerateKubernetesArtifacts(dir, nil, nil)
+	if err != nil {
+		t.Fatalf("GenerateKubernetesArtifacts returned an unexpected error %s", err)
+	}
+	service, err := ioutil.ReadFile(templates[1].FilePath)
+	if err != nil || !strings.Contains(string(service), "name: dev-web") {
+		t.Errorf("Expected the overlay to be applied, got %s (%v)", service, err)
+	}
+	kinds := readTemplates(t, templates)
+	if strings.Join(kinds, ",") != "ConfigMap,Service,Deployment" {
+		t.Errorf("Expected the resources in install order, got %v", kinds)
+	}
+
+	// The bases must be in the app
+	app := filepath.Join(dir, "app")
+	os.Mkdir(app, 0700)
+	ioutil.WriteFile(filepath.Join(app, "kustomization.yaml"), []byte("bases:\n- ..\n"), 0600)
+	_, err = r.(Renderer).GenerateKubernetesArtifacts(app, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "outside of the app") {
+		t.Errorf("Expected the base outside of the app to be rejected, got %v", err)
+	}
+	ioutil.WriteFile(filepath.Join(app, "kustomization.yaml"), []byte("bases:\n- github.com/kubernetes-sigs/kustomize//examples/helloWorld\n"), 0600)
+	_, err = r.(Renderer).GenerateKubernetesArtifacts(app, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "not supported") {
+		t.Errorf("Expected the remote base to be rejected, got %v", err)
+	}
+
+	_, err = NewResolver("jsonnet", "", "default", "r1", "manifest.yaml")
+	if err == nil {
+		t.Errorf("NewResolver of an unknown app type returned no error")
+	}
+}
@@ -0,0 +1,235 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package helm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/googleapis/gnostic/compiler"
+	openapi_v2 "github.com/googleapis/gnostic/openapiv2"
+	yaml "gopkg.in/yaml.v2"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/kubectl/pkg/util/openapi"
+	"k8s.io/kubectl/pkg/util/openapi/validation"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// ManifestError lists the errors of a file of an app
+type ManifestError struct {
+	File   string   `json:"file"`
+	Errors []string `json:"errors"`
+}
+
+// ValidationError is returned when an app fails to render or its
+// manifests are invalid
+type ValidationError struct {
+	App   string          `json:"app"`
+	Files []ManifestError `json:"files"`
+}
+
+func (e *ValidationError) Error() string {
+	var files []string
+	for _, f := range e.Files {
+		errs := strings.Join(f.Errors, ", ")
+		if f.File != "" {
+			errs = f.File + ": " + errs
+		}
+		files = append(files, errs)
+	}
+	return fmt.Sprintf("Invalid manifests of app %s: %s", e.App, strings.Join(files, "; "))
+}
+
+func (e *ValidationError) add(file, msg string) {
+	for i := range e.Files {
+		if e.Files[i].File == file {
+			e.Files[i].Errors = append(e.Files[i].Errors, msg)
+			return
+		}
+	}
+	e.Files = append(e.Files, ManifestError{File: file, Errors: []string{msg}})
+}
+
+// renderFileRegex finds the template a Helm rendering error is in
+var renderFileRegex = regexp.MustCompile(`(?:template: |parse error at \(|YAML parse error on )([^:\s()]+)`)
+
+// NewRenderError returns the ValidationError of an app that failed to
+// render, with the error reported on the template Helm names
+func NewRenderError(app string, err error) *ValidationError {
+	verr := &ValidationError{App: app}
+	file := ""
+	if m := renderFileRegex.FindStringSubmatch(err.Error()); m != nil {
+		file = m[1]
+	}
+	verr.add(file, err.Error())
+	return verr
+}
+
+// SchemaValidator validates manifests against the OpenAPI schemas of a
+// Kubernetes version, like kubectl --validate
+type SchemaValidator struct {
+	schema *validation.SchemaValidation
+}
+
+// NewSchemaValidator returns the SchemaValidator of the OpenAPI (swagger)
+// document of a Kubernetes version
+func NewSchemaValidator(spec []byte) (*SchemaValidator, error) {
+	var info yaml.MapSlice
+	err := yaml.Unmarshal(spec, &info)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Error parsing the OpenAPI document")
+	}
+	doc, err := openapi_v2.NewDocument(info, compiler.NewContext("$root", nil))
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Error parsing the OpenAPI document")
+	}
+	resources, err := openapi.NewOpenAPIData(doc)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Error reading the OpenAPI schemas")
+	}
+	return &SchemaValidator{schema: validation.NewSchemaValidation(resources)}, nil
+}
+
+var (
+	schemaValidators     = map[string]*SchemaValidator{}
+	schemaValidatorsLock sync.Mutex
+)
+
+// LoadSchemaValidator returns the SchemaValidator of the OpenAPI document in
+// file, parsed once
+func LoadSchemaValidator(file string) (*SchemaValidator, error) {
+	schemaValidatorsLock.Lock()
+	defer schemaValidatorsLock.Unlock()
+
+	if v, ok := schemaValidators[file]; ok {
+		return v, nil
+	}
+	spec, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "Error reading the OpenAPI document")
+	}
+	v, err := NewSchemaValidator(spec)
+	if err != nil {
+		return nil, err
+	}
+	schemaValidators[file] = v
+	return v, nil
+}
+
+// sourceRegex finds the file of a rendered resource in the app
+var sourceRegex = regexp.MustCompile(`(?m)^# Source: (\S+)`)
+
+// templateFile returns the file of the app a rendered resource comes from,
+// or the name of the rendered template
+func templateFile(fp string, data []byte) string {
+	if m := sourceRegex.FindSubmatch(data); m != nil {
+		return string(m[1])
+	}
+	return filepath.Base(fp)
+}
+
+// ValidateTemplates lints the rendered templates of an app. Each resource
+// needs a name and must be unique in the app, and matches the schemas of v
+// if it is not nil. It returns a ValidationError listing the errors of each
+// file.
+func ValidateTemplates(app string, templates []KubernetesResourceTemplate, v *SchemaValidator) error {
+	verr := &ValidationError{App: app}
+	resources := map[string]string{}
+	for _, t := range templates {
+		data, err := ioutil.ReadFile(t.FilePath)
+		if err != nil {
+			return pkgerrors.Wrap(err, "Error reading the manifest")
+		}
+		file := templateFile(t.FilePath, data)
+
+		var obj struct {
+			Metadata struct {
+				Name         string `json:"name"`
+				GenerateName string `json:"generateName"`
+			} `json:"metadata"`
+		}
+		out, err := k8syaml.ToJSON(data)
+		if err == nil {
+			err = json.Unmarshal(out, &obj)
+		}
+		if err != nil {
+			verr.add(file, err.Error())
+			continue
+		}
+		name := obj.Metadata.Name
+		switch {
+		case name == "" && obj.Metadata.GenerateName == "":
+			verr.add(file, fmt.Sprintf("%s has no metadata.name", t.GVK.Kind))
+		case name != "":
+			key := t.GVK.Kind + "/" + name
+			if other, ok := resources[key]; ok {
+				verr.add(file, fmt.Sprintf("%s is also defined in %s", key, other))
+			} else {
+				resources[key] = file
+			}
+		}
+
+		if v == nil {
+			continue
+		}
+		err = v.schema.ValidateBytes(data)
+		if agg, ok := err.(utilerrors.Aggregate); ok {
+			for _, e := range agg.Errors() {
+				verr.add(file, e.Error())
+			}
+		} else if err != nil {
+			verr.add(file, err.Error())
+		}
+	}
+	if len(verr.Files) > 0 {
+		return verr
+	}
+	return nil
+}
+
+// valuesValidator validates the values of a chart against the
+// values.schema.json of the chart and its subcharts
+type valuesValidator struct {
+	TemplateClient
+}
+
+// GenerateKubernetesArtifacts merges the values like the TemplateClient and
+// validates them. It renders no templates.
+func (v *valuesValidator) GenerateKubernetesArtifacts(inputPath string, valueFiles []string,
+	values []string) ([]KubernetesResourceTemplate, error) {
+	vals, err := v.processValues(valueFiles, values)
+	if err != nil {
+		return nil, err
+	}
+	chrt, err := loader.Load(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	// The values of the chart are the defaults, like helm install
+	vals, err = chartutil.CoalesceValues(chrt, vals)
+	if err != nil {
+		return nil, err
+	}
+	return nil, chartutil.ValidateAgainstSchema(chrt, vals)
+}
+
+// ValidateValues validates the values of the chart of an app, overridden by
+// the values of the profile and the override values, against the
+// values.schema.json of the chart. A chart without a schema has valid values.
+func ValidateValues(appContent, appProfileContent []byte, overrideValues map[string]interface{}, overrideValuesOfAppStr []string, appName, manifestFileName string) error {
+	_, err := resolve(&valuesValidator{}, manifestFileName, appContent, appProfileContent, overrideValues, overrideValuesOfAppStr, appName)
+	if err != nil {
+		return pkgerrors.Errorf("Invalid override values of app %s: %s", appName, pkgerrors.Cause(err))
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package helm
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+// testSchema is the OpenAPI document of the ConfigMaps of Kubernetes
+const testSchema = `{
+  "swagger": "2.0",
+  "info": {"title": "Kubernetes", "version": "v1.19.4"},
+  "paths": {},
+  "definitions": {
+    "io.k8s.api.core.v1.ConfigMap": {
+      "type": "object",
+      "properties": {
+        "apiVersion": {"type": "string"},
+        "kind": {"type": "string"},
+        "metadata": {"$ref": "#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"},
+        "data": {"type": "object", "additionalProperties": {"type": "string"}}
+      },
+      "x-kubernetes-group-version-kind": [{"group": "", "kind": "ConfigMap", "version": "v1"}]
+    },
+    "io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta": {
+      "type": "object",
+      "properties": {
+        "name": {"type": "string"},
+        "generateName": {"type": "string"}
+      }
+    }
+  }
+}`
+
+func TestValidateTemplates(t *testing.T) {
+	v, err := NewSchemaValidator([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("NewSchemaValidator returned an unexpected error %s", err)
+	}
+
+	app := archive(t, map[string]string{
+		"web/deploy.yaml": deploymentYaml,
+		"web/config.yaml": configMapYaml("1"),
+		"web/invalid.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web-env\ndata: web\nimmutable: true\n" +
+			"---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  labels: {}\n",
+		"web/duplicate.yaml": configMapYaml("2"),
+	})
+	r, _ := NewResolver(AppTypeManifests, "", "default", "r1", "manifest.yaml")
+	templates, err := r.Resolve(app, nil, nil, nil, "web")
+	if err != nil {
+		t.Fatalf("Resolve returned an unexpected error %s", err)
+	}
+	defer readTemplates(t, templates)
+
+	err = ValidateTemplates("web", templates, v)
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Expected a ValidationError, got %v", err)
+	}
+	expected := []string{
+		`got "string", expected "map"`,
+		`unknown field "immutable"`,
+		"ConfigMap has no metadata.name",
+		"ConfigMap/web-config is also defined in",
+	}
+	for _, e := range expected {
+		if !strings.Contains(verr.Error(), e) {
+			t.Errorf("Expected the error %q, got %s", e, verr)
+		}
+	}
+	for _, f := range verr.Files {
+		if !strings.HasPrefix(f.File, "web/") || f.File == "web/deploy.yaml" || f.File == "web/config.yaml" {
+			t.Errorf("Unexpected errors of file %s: %v", f.File, f.Errors)
+		}
+	}
+
+	// The valid manifests pass without the schemas too
+	err = ValidateTemplates("web", templates[len(templates)-2:], nil)
+	if err != nil {
+		t.Errorf("ValidateTemplates returned an unexpected error %s", err)
+	}
+}
+
+func TestNewRenderError(t *testing.T) {
+	app := archive(t, map[string]string{
+		"web/Chart.yaml":           "apiVersion: v2\nname: web\nversion: 1.0.0\n",
+		"web/templates/cm.yaml":    "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Values.config.name }}\n",
+		"web/templates/notes.yaml": "",
+	})
+	r, _ := NewResolver(AppTypeHelm, "", "default", "r1", "manifest.yaml")
+	_, err := r.Resolve(app, nil, nil, nil, "web")
+	if err == nil {
+		t.Fatalf("Resolve of a broken chart returned no error")
+	}
+	verr := NewRenderError("web", err)
+	if len(verr.Files) != 1 || verr.Files[0].File != "web/templates/cm.yaml" {
+		t.Errorf("Expected the error of web/templates/cm.yaml, got %+v", verr.Files)
+	}
+}
+
+// valuesChart returns a chart rendering its image values in a ConfigMap
+func valuesChart(t *testing.T) []byte {
+	return archive(t, map[string]string{
+		"web/Chart.yaml":  "apiVersion: v2\nname: web\nversion: 1.0.0\n",
+		"web/values.yaml": "replicas: 1\nimage:\n  repository: nginx\n  tag: \"1.0\"\n  pullPolicy: IfNotPresent\n",
+		"web/values.schema.json": `{"type": "object", "required": ["replicas"],
+  "properties": {"replicas": {"type": "integer", "minimum": 1}}}`,
+		"web/templates/cm.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web\ndata:\n  image: {{ .Values.image.repository }}:{{ .Values.image.tag }}\n  pullPolicy: {{ .Values.image.pullPolicy }}\n",
+	})
+}
+
+func TestResolveOverrideValues(t *testing.T) {
+	profile := archive(t, map[string]string{
+		"manifest.yaml": "version: v1\ntype:\n  values: values.yaml\n",
+		"values.yaml":   "image:\n  tag: \"2.0\"\n  pullPolicy: Always\n",
+	})
+	r, _ := NewResolver(AppTypeHelm, "", "default", "r1", "manifest.yaml")
+	overrides := map[string]interface{}{"image": map[string]interface{}{"tag": "3.0"}}
+	templates, err := r.Resolve(valuesChart(t), profile, overrides, []string{"image.pullPolicy=Never"}, "web")
+	if err != nil {
+		t.Fatalf("Resolve returned an unexpected error %s", err)
+	}
+	defer readTemplates(t, templates)
+	cm, err := ioutil.ReadFile(templates[0].FilePath)
+	if err != nil {
+		t.Fatalf("Error reading the ConfigMap: %s", err)
+	}
+	// The repository of the chart, the tag of the override values and the
+	// pull policy set last
+	for _, e := range []string{"image: nginx:3.0", "pullPolicy: Never"} {
+		if !strings.Contains(string(cm), e) {
+			t.Errorf("Expected %q in the ConfigMap, got %s", e, cm)
+		}
+	}
+}
+
+func TestValidateValues(t *testing.T) {
+	testCases := []struct {
+		label         string
+		overrides     map[string]interface{}
+		set           []string
+		expectedError string
+	}{
+		{label: "Chart values"},
+		{label: "Valid override values", overrides: map[string]interface{}{"replicas": 3}},
+		{label: "Wrong type", overrides: map[string]interface{}{"replicas": "three"}, expectedError: "replicas: Invalid type"},
+		{label: "Wrong set value", set: []string{"replicas=0"}, expectedError: "replicas: Must be greater than or equal to 1"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.label, func(t *testing.T) {
+			err := ValidateValues(valuesChart(t), nil, tc.overrides, tc.set, "web", "manifest.yaml")
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Fatalf("ValidateValues returned an unexpected error %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) || !strings.Contains(err.Error(), "Invalid override values") {
+				t.Errorf("Expected an error with %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
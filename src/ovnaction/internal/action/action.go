@@ -4,6 +4,7 @@
 package action
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 
@@ -20,12 +21,13 @@ import (
 )
 
 // Action applies the supplied intent against the given AppContext ID
-func UpdateAppContext(intentName, appContextId string) error {
+func UpdateAppContext(ctx context.Context, intentName, appContextId string) error {
 	var ac appcontext.AppContext
 	_, err := ac.LoadAppContext(appContextId)
 	if err != nil {
 		return pkgerrors.Wrapf(err, "Error getting AppContext with Id: %v", appContextId)
 	}
+	ac = ac.WithContext(ctx)
 	caMeta, err := ac.GetCompositeAppMeta()
 	if err != nil {
 		return pkgerrors.Wrapf(err, "Error getting metadata for AppContext with Id: %v", appContextId)
@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+// The agent of a pull-mode cluster. It runs on the cluster and connects to
+// rsync, so rsync doesn't need to reach the API server of the cluster.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	v1alpha1 "github.com/open-ness/EMCO/src/monitor/pkg/apis/k8splugin/v1alpha1"
+	clientset "github.com/open-ness/EMCO/src/monitor/pkg/generated/clientset/versioned"
+	informers "github.com/open-ness/EMCO/src/monitor/pkg/generated/informers/externalversions"
+	"github.com/open-ness/EMCO/src/rsync/pkg/agent"
+	kubeclient "github.com/open-ness/EMCO/src/rsync/pkg/client"
+	pb "github.com/open-ness/EMCO/src/rsync/pkg/grpc/agent"
+	"github.com/open-ness/EMCO/src/rsync/pkg/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// reconnectWait is the wait before connecting to rsync again
+const reconnectWait = 5 * time.Second
+
+// watchStatus sends the ResourceBundleState CRs of the cluster on status
+func watchStatus(kubeconfig string, status chan<- []byte, stop <-chan struct{}) error {
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return err
+	}
+	k8sClient, err := clientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	send := func(obj interface{}) {
+		v, ok := obj.(*v1alpha1.ResourceBundleState)
+		if !ok {
+			return
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			log.Printf("Error marshalling status %v", err)
+			return
+		}
+		status <- b
+	}
+	mInformerFactory := informers.NewSharedInformerFactory(k8sClient, 0)
+	mInformer := mInformerFactory.K8splugin().V1alpha1().ResourceBundleStates().Informer()
+	mInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    send,
+		UpdateFunc: func(oldObj, obj interface{}) { send(obj) },
+	})
+	go mInformer.Run(stop)
+	return nil
+}
+
+func main() {
+	rsync := flag.String("rsync", "", "address of the rsync gRPC server, like rsync.example.com:9031")
+	cluster := flag.String("cluster", "", "cluster of the agent, like provider1+cluster1")
+	kubeconfig := flag.String("kubeconfig", "", "kubeconfig of the cluster, the in-cluster config is used if empty")
+	caFile := flag.String("ca-file", "", "CA certificate of rsync, TLS is not used if empty")
+	serverName := flag.String("server-name-override", "", "server name of the rsync certificate")
+	flag.Parse()
+	if *rsync == "" || *cluster == "" {
+		log.Fatalln("Both -rsync and -cluster are required")
+	}
+
+	var opts []grpc.DialOption
+	if *caFile != "" {
+		creds, err := credentials.NewClientTLSFromFile(*caFile, *serverName)
+		if err != nil {
+			log.Fatalf("Could not load the CA certificate %v", err)
+		}
+		opts = append(opts, grpc.WithTransportCredentials(creds))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+	conn, err := grpc.Dial(*rsync, opts...)
+	if err != nil {
+		log.Fatalf("Could not connect to rsync %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt)
+	go func() {
+		<-c
+		cancel()
+	}()
+
+	status := make(chan []byte, 100)
+	if err := watchStatus(*kubeconfig, status, ctx.Done()); err != nil {
+		log.Fatalf("Could not watch the status of the resources %v", err)
+	}
+
+	a := &agent.Agent{
+		Cluster: *cluster,
+		// The token is not passed as a flag to keep it out of the process list
+		Token: os.Getenv("AGENT_TOKEN"),
+		NewClient: func(namespace string) (types.ClientProvider, error) {
+			return kubeclient.NewE("", *kubeconfig, namespace)
+		},
+	}
+	client := pb.NewAgentClient(conn)
+	for {
+		err := a.Run(ctx, client, status)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Disconnected from rsync %v", err)
+		time.Sleep(reconnectWait)
+	}
+}
@@ -14,6 +14,8 @@ import (
 	"time"
 
 	register "github.com/open-ness/EMCO/src/rsync/pkg/grpc"
+	agentpb "github.com/open-ness/EMCO/src/rsync/pkg/grpc/agent"
+	"github.com/open-ness/EMCO/src/rsync/pkg/grpc/agentserver"
 	installpb "github.com/open-ness/EMCO/src/rsync/pkg/grpc/installapp"
 	updatepb "github.com/open-ness/EMCO/src/rsync/pkg/grpc/updateapp"
 	"github.com/open-ness/EMCO/src/rsync/pkg/grpc/installappserver"
@@ -24,6 +26,9 @@ import (
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/config"
 	contextDb "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/contextdb"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/db"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/metrics"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/tracing"
+	"github.com/open-ness/EMCO/src/rsync/pkg/connector"
 	"github.com/open-ness/EMCO/src/rsync/pkg/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -62,10 +67,19 @@ func startGrpcServer() error {
 		opts = []grpc.ServerOption{grpc.Creds(creds)}
 	}
 	
+	opts = append(opts, metrics.ServerOptions()...)
+	opts = append(opts, tracing.ServerOptions()...)
 	grpcServer := grpc.NewServer(opts...)
 	installpb.RegisterInstallappServer(grpcServer, installappserver.NewInstallAppServer())
 	readynotifypb.RegisterReadyNotifyServer(grpcServer, readynotifyserver.NewReadyNotifyServer())
 	updatepb.RegisterUpdateappServer(grpcServer, updateappserver.NewUpdateAppServer())
+	// The agents of pull-mode clusters connect to rsync, only once a token
+	// authorizes them
+	if token := config.GetConfiguration().AgentToken; token != "" {
+		agentpb.RegisterAgentServer(grpcServer, agentserver.NewAgentServer(token, connector.HandleAgentStatus))
+	} else {
+		log.Println("agent-token not set, pull-mode clusters are disabled")
+	}
 
 	log.Println("Starting rsync gRPC Server")
 	err = grpcServer.Serve(lis)
@@ -78,6 +92,7 @@ func startGrpcServer() error {
 func main() {
 
 	rand.Seed(time.Now().UnixNano())
+	tracing.Init("rsync")
 
 	// Initialize the mongodb
 	err := db.InitializeDatabaseConnection("mco")
@@ -95,6 +110,8 @@ func main() {
 		log.Fatalln("Exiting...")
 	}
 
+	go metrics.Serve(config.GetConfiguration().MetricsPort)
+
 	go func() {
 		err := startGrpcServer()
 		if err != nil {
@@ -112,6 +129,7 @@ func main() {
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt)
 	<-c
+	tracing.Shutdown()
 	close(connectionsClose)
 
 }
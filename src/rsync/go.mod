@@ -10,7 +10,9 @@ require (
 	github.com/open-ness/EMCO/src/monitor v0.0.0-00010101000000-000000000000
 	github.com/open-ness/EMCO/src/orchestrator v0.0.0-00010101000000-000000000000
 	github.com/pkg/errors v0.9.1
+	github.com/prometheus/client_golang v1.7.1
 	github.com/sirupsen/logrus v1.7.0
+	go.opentelemetry.io/otel v1.0.1
 	golang.org/x/net v0.0.0-20200707034311-ab3426394381
 	golang.org/x/sync v0.0.0-20201207232520-09787c993a3a
 	google.golang.org/grpc v1.28.0
@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+// Package agent runs on a pull-mode cluster. It connects to rsync, applies the
+// operations rsync sends on the resources of the cluster and reports the
+// status of the resources back.
+package agent
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	pb "github.com/open-ness/EMCO/src/rsync/pkg/grpc/agent"
+	. "github.com/open-ness/EMCO/src/rsync/pkg/types"
+	pkgerrors "github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Agent handles the operations of rsync on a cluster
+type Agent struct {
+	// Cluster of the agent, like "provider1+cluster1"
+	Cluster string
+	// Token the agent is authorized with by rsync
+	Token string
+	// NewClient returns a client of the cluster for a namespace
+	NewClient func(namespace string) (ClientProvider, error)
+
+	clients map[string]ClientProvider
+	lock    sync.Mutex
+}
+
+// getClient returns the client of the cluster for a namespace
+func (a *Agent) getClient(namespace string) (ClientProvider, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.clients == nil {
+		a.clients = make(map[string]ClientProvider)
+	}
+	if cl, ok := a.clients[namespace]; ok {
+		return cl, nil
+	}
+	cl, err := a.NewClient(namespace)
+	if err != nil {
+		return nil, err
+	}
+	a.clients[namespace] = cl
+	return cl, nil
+}
+
+// Run connects to rsync and handles its operations until the stream fails or
+// ctx is done. The ResourceBundleState CRs received on status are sent to
+// rsync.
+func (a *Agent) Run(ctx context.Context, c pb.AgentClient, status <-chan []byte) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := c.Connect(ctx)
+	if err != nil {
+		return err
+	}
+	// Send isn't safe to call from several goroutines
+	var sendLock sync.Mutex
+	send := func(m *pb.AgentMessage) error {
+		sendLock.Lock()
+		defer sendLock.Unlock()
+		return stream.Send(m)
+	}
+	if err := send(&pb.AgentMessage{Cluster: a.Cluster, Token: a.Token}); err != nil {
+		return err
+	}
+	log.Info("Connected to rsync", log.Fields{"cluster": a.Cluster})
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case s, ok := <-status:
+				if !ok {
+					return
+				}
+				if err := send(&pb.AgentMessage{Status: s}); err != nil {
+					log.Error("Error sending status to rsync", log.Fields{"error": err})
+				}
+			}
+		}
+	}()
+
+	for {
+		op, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		// Operations on different resources don't wait for each other
+		go func(op *pb.Operation) {
+			r := a.handle(op)
+			if err := send(&pb.AgentMessage{Result: r}); err != nil {
+				log.Error("Error sending result to rsync", log.Fields{"error": err, "op": op.Op})
+			}
+		}(op)
+	}
+}
+
+// handle applies an operation on the cluster
+func (a *Agent) handle(op *pb.Operation) *pb.Result {
+	r := &pb.Result{Id: op.Id}
+	cl, err := a.getClient(op.Namespace)
+	if err == nil {
+		switch op.Op {
+		case pb.Operation_APPLY:
+			err = cl.Apply(op.Content)
+		case pb.Operation_FORCE_APPLY:
+			if f, ok := cl.(ForceApplier); ok {
+				err = f.ForceApply(op.Content)
+			} else {
+				err = cl.Apply(op.Content)
+			}
+		case pb.Operation_DELETE:
+			err = cl.Delete(op.Content)
+		case pb.Operation_GET:
+			r.Content, err = cl.Get(op.Content, op.Namespace)
+		case pb.Operation_APPROVE:
+			err = cl.Approve(op.Name, op.Content)
+		case pb.Operation_LIST:
+			if l, ok := cl.(ResourceLister); ok {
+				gvk := schema.GroupVersionKind{Group: op.Gvk.GetGroup(), Version: op.Gvk.GetVersion(), Kind: op.Gvk.GetKind()}
+				r.Resources, err = l.ListResources(gvk, op.Label)
+			} else {
+				err = pkgerrors.New("Listing resources not supported")
+			}
+		default:
+			err = pkgerrors.Errorf("Unsupported operation %s", op.Op)
+		}
+	}
+	if err != nil {
+		r.Error = err.Error()
+		var conflict *ConflictError
+		r.Conflict = errors.As(err, &conflict)
+	}
+	return r
+}
@@ -4,21 +4,53 @@
 package client
 
 import (
+	"context"
+	"strings"
+
+	rsynctypes "github.com/open-ness/EMCO/src/rsync/pkg/types"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/rest"
 	"k8s.io/kubectl/pkg/util"
 )
 
+// FieldManager is the manager of the fields set by the server-side apply
+const FieldManager = "emco-rsync"
+
+// clientSideManager is the manager of the fields rsync applied before it
+// applied the resources server-side, named after the user agent of rsync
+var clientSideManager = strings.SplitN(rest.DefaultKubernetesUserAgent(), "/", 2)[0]
+
+// IsRsyncManager returns whether the fields of the manager were set by rsync
+func IsRsyncManager(manager string) bool {
+	return manager == FieldManager || manager == clientSideManager
+}
+
 // Apply creates a resource with the given content
 func (c *Client) Apply(content []byte) error {
 	r := c.ResultForContent(content, nil)
 	return c.ApplyResource(r)
 }
 
+// ForceApply applies a resource with the given content and takes over the
+// fields owned by other managers
+func (c *Client) ForceApply(content []byte) error {
+	r := c.ResultForContent(content, nil)
+	return c.applyResource(r, true)
+}
+
+// ApplyContext applies a resource with the given content, taking over the
+// fields owned by other managers if forced. The server-side apply stops when
+// the context is done.
+func (c *Client) ApplyContext(ctx context.Context, content []byte, force bool) error {
+	r := c.ResultForContent(content, nil)
+	return c.applyResourceContext(ctx, r, force || c.forceConflicts)
+}
+
 // ApplyFiles create the resource(s) from the given filenames (file, directory or STDIN) or HTTP URLs
 func (c *Client) ApplyFiles(filenames ...string) error {
 	r := c.ResultForFilenameParam(filenames, nil)
@@ -27,13 +59,23 @@ func (c *Client) ApplyFiles(filenames ...string) error {
 
 // ApplyResource applies the given resource. Create the resources with `ResultForFilenameParam` or `ResultForContent`
 func (c *Client) ApplyResource(r *resource.Result) error {
+	return c.applyResource(r, c.forceConflicts)
+}
+
+func (c *Client) applyResource(r *resource.Result, force bool) error {
+	return c.applyResourceContext(context.Background(), r, force)
+}
+
+func (c *Client) applyResourceContext(ctx context.Context, r *resource.Result, force bool) error {
 	if err := r.Err(); err != nil {
 		return err
 	}
 
 	// Is ServerSideApply requested
 	if c.ServerSideApply {
-		return r.Visit(serverSideApply)
+		return r.Visit(func(info *resource.Info, err error) error {
+			return serverSideApply(ctx, info, err, force)
+		})
 	}
 
 	return r.Visit(apply)
@@ -60,7 +102,10 @@ func apply(info *resource.Info, err error) error {
 	return patch(info, current)
 }
 
-func serverSideApply(info *resource.Info, err error) error {
+// serverSideApply applies the resource as FieldManager. The fields of the
+// resource owned by other managers are only taken over with force, a
+// ConflictError is returned otherwise.
+func serverSideApply(ctx context.Context, info *resource.Info, err error, force bool) error {
 	if err != nil {
 		return failedTo("serverside apply", info, err)
 	}
@@ -71,14 +116,51 @@ func serverSideApply(info *resource.Info, err error) error {
 	}
 
 	options := metav1.PatchOptions{
-		// TODO: Find out how to get the force conflict flag
-		// Force:        &forceConflicts,
-		// FieldManager: FieldManager,
+		Force:        &force,
+		FieldManager: FieldManager,
+	}
+	obj, err := applyPatch(ctx, info, data, &options)
+	if errors.IsConflict(err) && !force && ownConflict(err) {
+		// The fields rsync applied client-side are taken over
+		force = true
+		obj, err = applyPatch(ctx, info, data, &options)
 	}
-	obj, err := resource.NewHelper(info.Client, info.Mapping).Patch(info.Namespace, info.Name, types.ApplyPatchType, data, &options)
 	if err != nil {
+		if errors.IsConflict(err) {
+			return &rsynctypes.ConflictError{Err: failedTo("serverside patch", info, err)}
+		}
 		return failedTo("serverside patch", info, err)
 	}
 	info.Refresh(obj, true)
 	return nil
 }
+
+// applyPatch sends the apply patch of the resource like resource.Helper, which
+// doesn't take a context
+func applyPatch(ctx context.Context, info *resource.Info, data []byte, options *metav1.PatchOptions) (runtime.Object, error) {
+	helper := resource.NewHelper(info.Client, info.Mapping)
+	return helper.RESTClient.Patch(types.ApplyPatchType).
+		NamespaceIfScoped(info.Namespace, helper.NamespaceScoped).
+		Resource(helper.Resource).
+		Name(info.Name).
+		VersionedParams(options, metav1.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Get()
+}
+
+// ownConflict returns whether all the fields of a conflict are owned by the
+// client-side apply of rsync
+func ownConflict(err error) bool {
+	status, ok := err.(errors.APIStatus)
+	if !ok || status.Status().Details == nil || len(status.Status().Details.Causes) == 0 {
+		return false
+	}
+	for _, c := range status.Status().Details.Causes {
+		if c.Type != metav1.CauseTypeFieldManagerConflict ||
+			!strings.HasPrefix(c.Message, "conflict with \""+clientSideManager+"\"") {
+			return false
+		}
+	}
+	return true
+}
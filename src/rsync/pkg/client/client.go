@@ -115,6 +115,7 @@ func NewE(context, kubeconfig string, ns string) (*Client, error) {
 		validator:        validator,
 		namespace:        namespace,
 		enforceNamespace: enforceNamespace,
+		ServerSideApply:  true,
 	}, nil
 }
 
@@ -212,6 +213,11 @@ func (c *Client) IsReachable() error {
 }
 //TagResource with label
 func (c *Client) TagResource(res []byte, label string) ( []byte, error) {
+	return TagResource(res, label)
+}
+
+// TagResource adds the label to the resource and to its pods, if any
+func TagResource(res []byte, label string) ([]byte, error) {
 
 	//Decode the yaml to create a runtime.Object
 	unstruct := &unstructured.Unstructured{}
@@ -227,7 +233,7 @@ func (c *Client) TagResource(res []byte, label string) ( []byte, error) {
 		labels = map[string]string{}
 	}
 	//labels[config.GetConfiguration().KubernetesLabelName] = client.GetInstanceID()
-	labels["emco/deployment-id"] = label
+	labels[DeploymentIDLabel] = label
 	unstruct.SetLabels(labels)
 
 	// This checks if the resource we are creating has a podSpec in it
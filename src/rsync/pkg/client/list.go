@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// DeploymentIDLabel is the label TagResource adds to the resources rsync applies
+const DeploymentIDLabel = "emco/deployment-id"
+
+// ListResources lists the resources of a GVK on the remote cluster that
+// TagResource tagged with the label. Namespaced resources are listed in the
+// namespace of the client, or in all the namespaces for the default one like
+// Get does, as the clients of standard logical clouds can't list the others.
+func (c *Client) ListResources(gvk schema.GroupVersionKind, label string) ([][]byte, error) {
+	// Create a mapper for the GVK
+	mapping, err := c.RestMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, fmt.Errorf("RESTMapping for GVK failed %v", err)
+	}
+	opts := metav1.ListOptions{LabelSelector: DeploymentIDLabel + "=" + label}
+	var list *unstructured.UnstructuredList
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace && c.namespace != "" && c.namespace != "default" {
+		list, err = c.DynamicClient.Resource(mapping.Resource).Namespace(c.namespace).List(context.TODO(), opts)
+	} else {
+		list, err = c.DynamicClient.Resource(mapping.Resource).List(context.TODO(), opts)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Listing resources of %s failed %v", gvk.String(), err)
+	}
+	var resources [][]byte
+	for i := range list.Items {
+		b, err := list.Items[i].MarshalJSON()
+		if err != nil {
+			return nil, fmt.Errorf("Failed unstruct MarshalJSON %v", err)
+		}
+		resources = append(resources, b)
+	}
+	return resources, nil
+}
@@ -14,6 +14,7 @@ import (
 	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
 	kubeclient "github.com/open-ness/EMCO/src/rsync/pkg/client"
 	"github.com/open-ness/EMCO/src/rsync/pkg/db"
+	"github.com/open-ness/EMCO/src/rsync/pkg/grpc/agentserver"
 	pkgerrors "github.com/pkg/errors"
 )
 
@@ -29,6 +30,10 @@ type Connection struct {
 
 const basePath string = "/tmp/rsync/"
 
+// errAgentCluster is returned for the clusters registered without a kubeconfig,
+// they are reached through the agent they run
+var errAgentCluster = pkgerrors.New("Cluster is reached through its agent")
+
 // Init Connection for an app context
 func (c *Connection) Init(id interface{}) error {
 	log.Info("Init with interface", log.Fields{})
@@ -82,6 +87,9 @@ func (c *Connection) GetClient(cluster string, level string, namespace string) (
 		if err != nil {
 			return nil, err
 		}
+		if len(dec) == 0 {
+			return nil, errAgentCluster
+		}
 		var kubeConfigPath string = basePath + c.Cid + "/" + cluster + "/"
 		if _, err := os.Stat(kubeConfigPath); os.IsNotExist(err) {
 			err = os.MkdirAll(kubeConfigPath, 0700)
@@ -117,6 +125,12 @@ func (c *Connection) RemoveClient() {
 	}
 }
 
+// GetClientInternal returns the client for the cluster, the client of its
+// agent for a pull-mode cluster
 func (c *Connection) GetClientInternal(cluster string, level string, namespace string) (types.ClientProvider, error) {
-	return c.GetClient(cluster, level, namespace)
+	client, err := c.GetClient(cluster, level, namespace)
+	if err == errAgentCluster {
+		return agentserver.NewClient(cluster, namespace), nil
+	}
+	return client, err
 }
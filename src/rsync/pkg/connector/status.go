@@ -94,6 +94,20 @@ func HandleStatusUpdate(clusterId string, id string, v *v1alpha1.ResourceBundleS
 	return
 }
 
+// HandleAgentStatus handles a ResourceBundleState CR reported by the agent
+// of a pull-mode cluster
+func HandleAgentStatus(clusterId string, status []byte) {
+	var v v1alpha1.ResourceBundleState
+	if err := json.Unmarshal(status, &v); err != nil {
+		logrus.Info(clusterId, "::Error unmarshalling status from agent::", err)
+		return
+	}
+	l, ok := v.GetLabels()[monitorLabel]
+	if ok {
+		HandleStatusUpdate(clusterId, l, &v)
+	}
+}
+
 // StartClusterWatcher watches for CR
 // configBytes - Kubectl file data
 func (c *Connection) StartClusterWatcher(clusterId string) error {
@@ -105,6 +119,10 @@ func (c *Connection) StartClusterWatcher(clusterId string) error {
 	if err != nil {
 		return err
 	}
+	// The agent of a pull-mode cluster reports the status
+	if len(configBytes) == 0 {
+		return nil
+	}
 
 	//key := provider + "+" + name
 	// Get the lock
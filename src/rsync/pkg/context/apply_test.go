@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package context
+
+import (
+	"context"
+	"testing"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/contextdb"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/resourcestatus"
+	. "github.com/open-ness/EMCO/src/rsync/pkg/types"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// conflictClient is a cluster client where another manager owns fields of
+// the resources
+type conflictClient struct {
+	MockClient
+	forced int
+}
+
+func (cc *conflictClient) Apply(content []byte) error {
+	return &ConflictError{Err: pkgerrors.New("conflict with \"hpa-controller\": .spec.replicas")}
+}
+
+func (cc *conflictClient) ForceApply(content []byte) error {
+	cc.forced++
+	return nil
+}
+
+func TestApplyConflict(t *testing.T) {
+	edb := new(contextdb.MockConDb)
+	edb.Err = nil
+	contextdb.Db = edb
+
+	cid, err := CreateCompApp(rolloutTestCA())
+	if err != nil {
+		t.Fatalf("Error creating AppContext %v", err)
+	}
+	ac := appcontext.AppContext{}
+	if _, err := ac.LoadAppContext(cid); err != nil {
+		t.Fatalf("Error loading AppContext %v", err)
+	}
+	utils := &AppContextUtils{ac: ac}
+	c := &Context{acID: cid, ac: ac, statusAcID: cid, sc: ac, forceApply: utils.GetForceApply()}
+	if c.forceApply {
+		t.Fatal("Expected resources not to be force applied by default")
+	}
+
+	// The conflict is reported apart from the other failures
+	cl := &conflictClient{}
+	if err := c.instantiateResource(context.Background(), cl, "r1", "a1", "provider1+cluster1"); err == nil {
+		t.Fatal("Expected the apply to fail")
+	}
+	if s := utils.GetResourceStatus("r1", "a1", "provider1+cluster1"); s != resourcestatus.RsyncStatusEnum.Conflict {
+		t.Errorf("Expected resource status Conflict, got %v", s)
+	}
+
+	// The fields are taken over once the AppContext forces the apply
+	h, _ := ac.GetCompositeAppHandle()
+	if _, err := ac.AddLevelValue(h, appcontext.ForceApplyKey, true); err != nil {
+		t.Fatalf("Error adding the force apply flag %v", err)
+	}
+	c.forceApply = utils.GetForceApply()
+	if err := c.instantiateResource(context.Background(), cl, "r1", "a1", "provider1+cluster1"); err != nil {
+		t.Fatalf("Unexpected apply error %v", err)
+	}
+	if cl.forced != 1 {
+		t.Errorf("Expected the resource to be force applied once, got %d", cl.forced)
+	}
+	if s := utils.GetResourceStatus("r1", "a1", "provider1+cluster1"); s != resourcestatus.RsyncStatusEnum.Applied {
+		t.Errorf("Expected resource status Applied, got %v", s)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/config"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/tracing"
 	"github.com/open-ness/EMCO/src/rsync/pkg/connector"
 	. "github.com/open-ness/EMCO/src/rsync/pkg/types"
 )
@@ -41,6 +42,16 @@ type Context struct {
 	waitTime int
 	// Structure to hold CompositeApp Information
 	ca CompositeApp
+	// Waves of clusters an update is rolled out in
+	rollout appcontext.Rollout
+	// Clusters of the wave being rolled out, nil if not rolling out in waves
+	wave map[string]bool
+	// Set while the resources are periodically checked for drift
+	reconciling bool
+	// How long the clusters are retried
+	retry appcontext.RetryPolicy
+	// Take over the fields of the resources owned by other managers
+	forceApply bool
 }
 
 // AppContextData struct
@@ -54,12 +65,17 @@ var appContextData = AppContextData{
 }
 // HandleAppContext adds event to queue and starts main thread
 func HandleAppContext(a interface{}, ucid interface{}, e RsyncEvent, con Connector) error {
+	return handleAppContext(context.Background(), a, ucid, e, con)
+}
+
+// handleAppContext queues the event with the trace context of ctx
+func handleAppContext(ctx context.Context, a interface{}, ucid interface{}, e RsyncEvent, con Connector) error {
 
 	acID := fmt.Sprintf("%v", a)
 	// Create AppContext data if not already created
 	_, c := CreateAppContextData(acID)
 	// Add event to queue
-	err := c.EnqueueToAppContext(a, ucid, e)
+	err := c.enqueueToAppContext(ctx, a, ucid, e)
 	if err != nil {
 		return err
 	}
@@ -78,6 +94,10 @@ func HandleAppContext(a interface{}, ucid interface{}, e RsyncEvent, con Connect
 }
 // EnqueueToAppContext adds the event to the appContext Queue
 func (c *Context)EnqueueToAppContext(a interface{}, ucid interface{}, e RsyncEvent) error {
+	return c.enqueueToAppContext(context.Background(), a, ucid, e)
+}
+
+func (c *Context) enqueueToAppContext(ctx context.Context, a interface{}, ucid interface{}, e RsyncEvent) error {
 	acID := fmt.Sprintf("%v", a)
 	ac := appcontext.AppContext{}
 	_, err := ac.LoadAppContext(acID)
@@ -94,6 +114,8 @@ func (c *Context)EnqueueToAppContext(a interface{}, ucid interface{}, e RsyncEve
 	} else {
 		elem = AppContextQueueElement{Event: e, Status: "Pending"}
 	}
+	elem.TraceContext = tracing.Inject(ctx)
+	elem.Rollback = isRollback(ctx)
 	// Acquire Mutex before adding to queue
 	c.Lock.Lock()
 	// Push the appContext to ActiveContext space of etcD
@@ -103,6 +125,7 @@ func (c *Context)EnqueueToAppContext(a interface{}, ucid interface{}, e RsyncEve
 	}
 	// Enqueue event
 	qUtils.Enqueue(elem)
+	updateQueueDepth(acID, &qUtils)
 	c.Lock.Unlock()
 	return nil
 }
@@ -168,32 +191,44 @@ type CompositeAppContext struct {
 	cid interface{}
 }
 // InstantiateComApp Instantiatep Aps in Composite App
-func (instca *CompositeAppContext) InstantiateComApp(cid interface{}) error {
+func (instca *CompositeAppContext) InstantiateComApp(ctx context.Context, cid interface{}) error {
 	instca.cid = cid
+	// A child is added to its parent, which instantiates it with itself
+	if parent := getParentContext(cid); parent != "" {
+		con := connector.Connection{}
+		con.Init(parent)
+		return handleAppContext(ctx, parent, cid, AddChildContextEvent, &con)
+	}
 	con := connector.Connection{}
 	con.Init(instca.cid)
-	return HandleAppContext(instca.cid, nil, InstantiateEvent, &con)
+	return handleAppContext(ctx, instca.cid, nil, InstantiateEvent, &con)
 }
 // TerminateComApp Terminates Apps in Composite App
-func (instca *CompositeAppContext) TerminateComApp(cid interface{}) error {
+func (instca *CompositeAppContext) TerminateComApp(ctx context.Context, cid interface{}) error {
 	instca.cid = cid
 	con := connector.Connection{}
 	con.Init(instca.cid)
-	return HandleAppContext(instca.cid, nil, TerminateEvent, &con)
+	return handleAppContext(ctx, instca.cid, nil, TerminateEvent, &con)
 }
 
 // UpdateComApp Updates Apps in Composite App
-func (instca *CompositeAppContext) UpdateComApp(cid interface{}, ucid interface{}) error {
+func (instca *CompositeAppContext) UpdateComApp(ctx context.Context, cid interface{}, ucid interface{}) error {
 	instca.cid = cid
 	con := connector.Connection{}
 	con.Init(instca.cid)
-	return HandleAppContext(instca.cid, ucid, UpdateEvent, &con)
+	return handleAppContext(ctx, instca.cid, ucid, UpdateEvent, &con)
+}
+
+// RollbackComApp rolls the Apps in Composite App back from the AppContext to
+// an earlier one
+func (instca *CompositeAppContext) RollbackComApp(ctx context.Context, cid interface{}, ucid interface{}) error {
+	return instca.UpdateComApp(withRollback(ctx), cid, ucid)
 }
 
 // ReadComApp Reads resources in AppContext
-func (instca *CompositeAppContext) ReadComApp(cid interface{}) error {
+func (instca *CompositeAppContext) ReadComApp(ctx context.Context, cid interface{}) error {
 	instca.cid = cid
 	con := connector.Connection{}
 	con.Init(instca.cid)
-	return HandleAppContext(instca.cid, nil, ReadEvent, &con)
+	return handleAppContext(ctx, instca.cid, nil, ReadEvent, &con)
 }
@@ -6,6 +6,7 @@ package context_test
 import (
 //"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -464,3 +465,97 @@ func TestTerminateWithInstantiate(t *testing.T) {
 		})
 	}
 }
+
+var TestChildCA CompositeApp = CompositeApp{
+	CompMetadata: appcontext.CompositeAppMeta{Project: "proj1", CompositeApp: "sd1", Version: "v1", Release: "r1",
+		DeploymentIntentGroup: "dig1", Namespace: "default", Level: "0"},
+	AppOrder: []string{"s1"},
+	Apps: map[string]*App{"s1": &App{
+		Name: "s1",
+		Clusters: map[string]*Cluster{"provider1+cluster1": &Cluster{
+			Name:      "provider1+cluster1",
+			Resources: map[string]*AppResource{"r5": &AppResource{Name: "r5", Data: "s1c1r5"}},
+			ResOrder:  []string{"r5"}}},
+	},
+	},
+}
+
+func getCompositeAppMeta(t *testing.T, cid string) appcontext.CompositeAppMeta {
+	ac := appcontext.AppContext{}
+	if _, err := ac.LoadAppContext(cid); err != nil {
+		t.Fatalf("Error loading AppContext %s: %s", cid, err)
+	}
+	m, err := ac.GetCompositeAppMeta()
+	if err != nil {
+		t.Fatalf("Error getting CompositeAppMeta of %s: %s", cid, err)
+	}
+	return m
+}
+
+func TestChildContext(t *testing.T) {
+	cid, _ := CreateCompApp(TestCA)
+	ccid, _ := CreateCompApp(TestChildCA)
+	con := MockConnector{}
+	con.Init(cid)
+
+	_ = HandleAppContext(cid, nil, InstantiateEvent, &con)
+	time.Sleep(2 * time.Second)
+
+	// The child of an instantiated parent is instantiated when added
+	_ = HandleAppContext(cid, ccid, AddChildContextEvent, &con)
+	time.Sleep(1 * time.Second)
+	expectedApply := map[string]string{"provider1+cluster1": "a1c1r1,a1c1r2,a2c1r3,a2c1r4,s1c1r5", "provider1+cluster2": "a2c2r3,a2c2r4"}
+	if !CompareMaps(expectedApply, LoadMap("apply")) {
+		t.Error("Apply resources doesn't match", LoadMap("apply"))
+	}
+	if m := getCompositeAppMeta(t, cid); !reflect.DeepEqual(m.ChildContextIDs, []string{ccid}) {
+		t.Errorf("Expected the child context %s, got %v", ccid, m.ChildContextIDs)
+	}
+	if m := getCompositeAppMeta(t, ccid); m.ParentContextID != cid {
+		t.Errorf("Expected the parent context %s, got %s", cid, m.ParentContextID)
+	}
+
+	// Terminating the parent terminates the child
+	_ = HandleAppContext(cid, nil, TerminateEvent, &con)
+	time.Sleep(2 * time.Second)
+	if !CompareMaps(expectedApply, LoadMap("delete")) {
+		t.Error("Delete resources doesn't match", LoadMap("delete"))
+	}
+	status, _ := GetAppContextStatus(ccid, CurrentStateKey)
+	if !strings.Contains(status, string(appcontext.AppContextStatusEnum.Terminated)) {
+		t.Errorf("Expected the child context to be terminated, got %s", status)
+	}
+
+	// A child isn't added to a terminated parent
+	ccid2, _ := CreateCompApp(TestChildCA)
+	_ = HandleAppContext(cid, ccid2, AddChildContextEvent, &con)
+	time.Sleep(1 * time.Second)
+	if m := getCompositeAppMeta(t, ccid2); m.ParentContextID != "" {
+		t.Errorf("Expected no parent context, got %s", m.ParentContextID)
+	}
+}
+
+func TestUpdateChildContext(t *testing.T) {
+	cid, _ := CreateCompApp(TestCA)
+	ucid, _ := CreateCompApp(TestCA)
+	ccid, _ := CreateCompApp(TestChildCA)
+	con := MockConnector{}
+	con.Init(cid)
+
+	_ = HandleAppContext(cid, nil, InstantiateEvent, &con)
+	_ = HandleAppContext(cid, ccid, AddChildContextEvent, &con)
+	time.Sleep(2 * time.Second)
+
+	// The child moves to the AppContext the parent is updated to
+	_ = HandleAppContext(cid, ucid, UpdateEvent, &con)
+	time.Sleep(2 * time.Second)
+	if m := getCompositeAppMeta(t, cid); len(m.ChildContextIDs) != 0 {
+		t.Errorf("Expected no child context, got %v", m.ChildContextIDs)
+	}
+	if m := getCompositeAppMeta(t, ucid); !reflect.DeepEqual(m.ChildContextIDs, []string{ccid}) {
+		t.Errorf("Expected the child context %s, got %v", ccid, m.ChildContextIDs)
+	}
+	if m := getCompositeAppMeta(t, ccid); m.ParentContextID != ucid {
+		t.Errorf("Expected the parent context %s, got %s", ucid, m.ParentContextID)
+	}
+}
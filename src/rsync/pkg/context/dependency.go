@@ -0,0 +1,421 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	v1alpha1 "github.com/open-ness/EMCO/src/monitor/pkg/apis/k8splugin/v1alpha1"
+	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/resourcestatus"
+	. "github.com/open-ness/EMCO/src/rsync/pkg/types"
+	pkgerrors "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// appDependency tracks the completion of the apps of a composite app
+// so that an app is only handled once the apps it depends on are done
+type appDependency struct {
+	sync.Mutex
+	// Apps that need to complete before an app is handled
+	waitFor map[string]map[string]*Criteria
+	// Closed once the app is done
+	done map[string]chan struct{}
+	// Error of the apps that failed
+	failed map[string]error
+}
+
+// dependencyReadyTimeout bounds the wait for a dependency to be ready
+var dependencyReadyTimeout = 600 * time.Second
+
+// newAppDependency builds the dependency graph of the apps for the operation.
+// For delete operations the graph is reversed so that an app is only deleted
+// after all the apps depending on it are deleted.
+func newAppDependency(ca CompositeApp, op RsyncOperation) (*appDependency, error) {
+	d := &appDependency{
+		waitFor: make(map[string]map[string]*Criteria),
+		done:    make(map[string]chan struct{}),
+		failed:  make(map[string]error),
+	}
+	for _, app := range ca.AppOrder {
+		d.waitFor[app] = make(map[string]*Criteria)
+		d.done[app] = make(chan struct{})
+	}
+	for _, app := range ca.AppOrder {
+		a, ok := ca.Apps[app]
+		if !ok {
+			continue
+		}
+		for dep, criteria := range a.Dependency {
+			if _, ok := d.waitFor[dep]; !ok {
+				log.Info("Ignoring dependency on app not in AppContext", log.Fields{"app": app, "dependency": dep})
+				continue
+			}
+			if dep == app {
+				return nil, pkgerrors.Errorf("App %s depends on itself", app)
+			}
+			switch op {
+			case OpApply:
+				d.waitFor[app][dep] = criteria
+			case OpDelete:
+				// Only completion matters for delete
+				d.waitFor[dep][app] = &Criteria{OpStatus: OpStatusDeployed}
+			}
+		}
+	}
+	if err := d.checkCycle(ca.AppOrder); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// checkCycle verifies that the dependency graph is a DAG
+func (d *appDependency) checkCycle(order []string) error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int)
+	var visit func(app string, path []string) error
+	visit = func(app string, path []string) error {
+		switch state[app] {
+		case visiting:
+			return pkgerrors.Errorf("Dependency cycle found between apps: %s", strings.Join(append(path, app), " -> "))
+		case visited:
+			return nil
+		}
+		state[app] = visiting
+		for dep := range d.waitFor[app] {
+			if err := visit(dep, append(path, app)); err != nil {
+				return err
+			}
+		}
+		state[app] = visited
+		return nil
+	}
+	for _, app := range order {
+		if err := visit(app, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markDone signals that the app completed successfully
+func (d *appDependency) markDone(app string) {
+	d.complete(app, nil)
+}
+
+// markFailed signals that the app failed, the apps waiting for it fail too
+func (d *appDependency) markFailed(app string, err error) {
+	d.complete(app, err)
+}
+
+func (d *appDependency) complete(app string, err error) {
+	d.Lock()
+	defer d.Unlock()
+	if err != nil {
+		d.failed[app] = err
+	}
+	ch, ok := d.done[app]
+	if !ok {
+		return
+	}
+	select {
+	case <-ch:
+	default:
+		close(ch)
+	}
+}
+
+// waitForAppDependency blocks until all the apps the app depends on meet their criteria
+func (c *Context) waitForAppDependency(ctx context.Context, d *appDependency, app string) error {
+	for dep, criteria := range d.waitFor[app] {
+		log.Info("Waiting for app dependency", log.Fields{"app": app, "dependency": dep, "criteria": criteria})
+		select {
+		case <-d.done[dep]:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		d.Lock()
+		derr := d.failed[dep]
+		d.Unlock()
+		if derr != nil {
+			return pkgerrors.Errorf("App %s depends on app %s which failed: %v", app, dep, derr)
+		}
+		if criteria == nil {
+			continue
+		}
+		if criteria.OpStatus == OpStatusReady {
+			if err := c.waitForReady(ctx, func() bool { return c.isAppReady(dep) }); err != nil {
+				return pkgerrors.Wrapf(err, "App %s dependency %s not ready", app, dep)
+			}
+		}
+		if err := waitSeconds(ctx, criteria.Wait); err != nil {
+			return err
+		}
+		log.Info("App dependency met", log.Fields{"app": app, "dependency": dep})
+	}
+	return nil
+}
+
+// waitForResourceDependency blocks until all the resources a resource depends on
+// within the same cluster meet their criteria. Resources are handled in order, so a
+// resource can only depend on resources that come before it in the resource order.
+// handled holds the result of the resources handled so far.
+func (c *Context) waitForResourceDependency(ctx context.Context, cl ClientProvider, app, cluster, res string, handled map[string]error) error {
+	deps := c.ca.Apps[app].Clusters[cluster].Resources[res].Dependency
+	for dep, criteria := range deps {
+		if _, ok := c.ca.Apps[app].Clusters[cluster].Resources[dep]; !ok {
+			log.Info("Ignoring dependency on resource not in cluster", log.Fields{"app": app, "cluster": cluster, "resource": res, "dependency": dep})
+			continue
+		}
+		herr, ok := handled[dep]
+		if !ok {
+			return pkgerrors.Errorf("Resource %s depends on resource %s which is not ordered before it", res, dep)
+		}
+		if herr != nil {
+			return pkgerrors.Errorf("Resource %s depends on resource %s which failed: %v", res, dep, herr)
+		}
+		if criteria == nil {
+			continue
+		}
+		if criteria.OpStatus == OpStatusReady {
+			// Status is only reported once the status tracker is installed
+			if err := c.addStatusTracker(cl, app, cluster, c.statusAcID+"-"+app); err != nil {
+				return err
+			}
+			if err := c.waitForReady(ctx, func() bool { return c.isResourceReady(app, cluster, dep) }); err != nil {
+				return pkgerrors.Wrapf(err, "Resource %s dependency %s not ready", res, dep)
+			}
+		}
+		if err := waitSeconds(ctx, criteria.Wait); err != nil {
+			return err
+		}
+		log.Info("Resource dependency met", log.Fields{"app": app, "cluster": cluster, "resource": res, "dependency": dep})
+	}
+	return nil
+}
+
+// waitFor polls the condition every waitTime seconds until it is met or the context is done
+func (c *Context) waitFor(ctx context.Context, cond func() bool) error {
+	for !cond() {
+		select {
+		case <-time.After(time.Duration(c.waitTime) * time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// waitForReady waits for the condition like waitFor for at most dependencyReadyTimeout
+func (c *Context) waitForReady(ctx context.Context, cond func() bool) error {
+	wctx, cancel := context.WithTimeout(ctx, dependencyReadyTimeout)
+	defer cancel()
+	return c.waitFor(wctx, cond)
+}
+
+func waitSeconds(ctx context.Context, wait int) error {
+	if wait <= 0 {
+		return nil
+	}
+	select {
+	case <-time.After(time.Duration(wait) * time.Second):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// getClusterResourceBundleStatus reads the ResourceBundleState status collected for the app on the cluster
+func (c *Context) getClusterResourceBundleStatus(app, cluster string) (v1alpha1.ResourceBundleStatus, error) {
+	var rbStatus v1alpha1.ResourceBundleStatus
+	csh, err := c.sc.GetClusterStatusHandle(app, cluster)
+	if err != nil {
+		return rbStatus, err
+	}
+	v, err := c.sc.GetValue(csh)
+	if err != nil {
+		return rbStatus, err
+	}
+	err = json.Unmarshal([]byte(fmt.Sprintf("%v", v)), &rbStatus)
+	return rbStatus, err
+}
+
+// isAppReady checks whether all the resources of the app are ready on all clusters
+func (c *Context) isAppReady(app string) bool {
+	for cluster := range c.ca.Apps[app].Clusters {
+		if !c.inWave(cluster) {
+			continue
+		}
+		rbStatus, err := c.getClusterResourceBundleStatus(app, cluster)
+		if err != nil {
+			return false
+		}
+		if !isResourceBundleReady(rbStatus) {
+			return false
+		}
+	}
+	return true
+}
+
+// isResourceReady checks whether a resource has been applied and is reported ready on the cluster
+func (c *Context) isResourceReady(app, cluster, res string) bool {
+	utils := &AppContextUtils{ac: c.sc}
+	if utils.GetResourceStatus(res, app, cluster) != resourcestatus.RsyncStatusEnum.Applied {
+		return false
+	}
+	rbStatus, err := c.getClusterResourceBundleStatus(app, cluster)
+	if err != nil {
+		return false
+	}
+	// Resource names in the AppContext are of the form name+kind
+	result := strings.Split(res, "+")
+	if len(result) != 2 {
+		return false
+	}
+	ready, found := isNamedResourceReady(rbStatus, result[0], result[1])
+	if !found {
+		// Kinds that are not tracked by the monitor are ready once applied
+		return !isTrackedKind(result[1])
+	}
+	return ready
+}
+
+func isTrackedKind(kind string) bool {
+	switch kind {
+	case "Deployment", "StatefulSet", "DaemonSet", "Job", "Pod", "Service", "ConfigMap", "Secret":
+		return true
+	}
+	return false
+}
+
+// isResourceBundleReady checks the readiness of all workloads in a ResourceBundleState status
+func isResourceBundleReady(s v1alpha1.ResourceBundleStatus) bool {
+	if s.Ready {
+		return true
+	}
+	for _, d := range s.DeploymentStatuses {
+		replicas := int32(1)
+		if d.Spec.Replicas != nil {
+			replicas = *d.Spec.Replicas
+		}
+		if d.Status.ReadyReplicas < replicas {
+			return false
+		}
+		// A deployment being updated is only ready once the new replicas are
+		if d.Status.ObservedGeneration != 0 &&
+			(d.Status.ObservedGeneration < d.Generation || d.Status.UpdatedReplicas < replicas) {
+			return false
+		}
+	}
+	for _, ss := range s.StatefulSetStatuses {
+		replicas := int32(1)
+		if ss.Spec.Replicas != nil {
+			replicas = *ss.Spec.Replicas
+		}
+		if ss.Status.ReadyReplicas < replicas {
+			return false
+		}
+	}
+	for _, ds := range s.DaemonSetStatuses {
+		if ds.Status.NumberReady < ds.Status.DesiredNumberScheduled {
+			return false
+		}
+	}
+	for _, j := range s.JobStatuses {
+		if j.Status.Succeeded == 0 {
+			return false
+		}
+	}
+	for _, p := range s.PodStatuses {
+		if !isPodReady(p) {
+			return false
+		}
+	}
+	return true
+}
+
+// isNamedResourceReady checks the readiness of one resource in a ResourceBundleState status
+// The second return value is false if the resource was not found in the status
+func isNamedResourceReady(s v1alpha1.ResourceBundleStatus, name, kind string) (bool, bool) {
+	switch kind {
+	case "Deployment":
+		for _, d := range s.DeploymentStatuses {
+			if d.Name == name {
+				replicas := int32(1)
+				if d.Spec.Replicas != nil {
+					replicas = *d.Spec.Replicas
+				}
+				return d.Status.ReadyReplicas >= replicas, true
+			}
+		}
+	case "StatefulSet":
+		for _, ss := range s.StatefulSetStatuses {
+			if ss.Name == name {
+				replicas := int32(1)
+				if ss.Spec.Replicas != nil {
+					replicas = *ss.Spec.Replicas
+				}
+				return ss.Status.ReadyReplicas >= replicas, true
+			}
+		}
+	case "DaemonSet":
+		for _, ds := range s.DaemonSetStatuses {
+			if ds.Name == name {
+				return ds.Status.NumberReady >= ds.Status.DesiredNumberScheduled, true
+			}
+		}
+	case "Job":
+		for _, j := range s.JobStatuses {
+			if j.Name == name {
+				return j.Status.Succeeded > 0, true
+			}
+		}
+	case "Pod":
+		for _, p := range s.PodStatuses {
+			if p.Name == name {
+				return isPodReady(p), true
+			}
+		}
+	case "Service":
+		for _, svc := range s.ServiceStatuses {
+			if svc.Name == name {
+				return true, true
+			}
+		}
+	case "ConfigMap":
+		for _, cm := range s.ConfigMapStatuses {
+			if cm.Name == name {
+				return true, true
+			}
+		}
+	case "Secret":
+		for _, sec := range s.SecretStatuses {
+			if sec.Name == name {
+				return true, true
+			}
+		}
+	}
+	return false, false
+}
+
+func isPodReady(p corev1.Pod) bool {
+	if p.Status.Phase == corev1.PodSucceeded {
+		return true
+	}
+	for _, cond := range p.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,239 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package context
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	v1alpha1 "github.com/open-ness/EMCO/src/monitor/pkg/apis/k8splugin/v1alpha1"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/contextdb"
+	. "github.com/open-ness/EMCO/src/rsync/pkg/types"
+	pkgerrors "github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func depTestCA(a1Dep, a2Dep map[string]*Criteria) CompositeApp {
+	return CompositeApp{
+		CompMetadata: appcontext.CompositeAppMeta{Project: "proj1", CompositeApp: "ca1", Version: "v1", Release: "r1",
+			DeploymentIntentGroup: "dig1", Namespace: "default", Level: "0"},
+		AppOrder: []string{"a1", "a2"},
+		Apps: map[string]*App{"a1": &App{
+			Name:       "a1",
+			Dependency: a1Dep,
+			Clusters: map[string]*Cluster{"provider1+cluster1": &Cluster{
+				Name: "provider1+cluster1",
+				Resources: map[string]*AppResource{"r1": &AppResource{Name: "r1", Data: "a1c1r1"},
+					"r2": &AppResource{Name: "r2", Data: "a1c1r2"},
+				},
+				ResOrder: []string{"r1", "r2"}}},
+		}, "a2": &App{
+			Name:       "a2",
+			Dependency: a2Dep,
+			Clusters: map[string]*Cluster{"provider1+cluster1": &Cluster{
+				Name: "provider1+cluster1",
+				Resources: map[string]*AppResource{"r3": &AppResource{Name: "r3", Data: "a2c1r3"},
+					"r4": &AppResource{Name: "r4", Data: "a2c1r4"},
+				},
+				ResOrder: []string{"r3", "r4"}}},
+		},
+		},
+	}
+}
+
+func TestAppDependencyGraph(t *testing.T) {
+	testCases := []struct {
+		label         string
+		ca            CompositeApp
+		op            RsyncOperation
+		expectedWait  map[string][]string
+		expectedError string
+	}{
+		{
+			label:        "No dependency",
+			ca:           depTestCA(nil, nil),
+			op:           OpApply,
+			expectedWait: map[string][]string{"a1": {}, "a2": {}},
+		},
+		{
+			label:        "Apply waits for dependency",
+			ca:           depTestCA(nil, map[string]*Criteria{"a1": {OpStatus: OpStatusDeployed}}),
+			op:           OpApply,
+			expectedWait: map[string][]string{"a1": {}, "a2": {"a1"}},
+		},
+		{
+			label:        "Delete waits for dependent app",
+			ca:           depTestCA(nil, map[string]*Criteria{"a1": {OpStatus: OpStatusReady}}),
+			op:           OpDelete,
+			expectedWait: map[string][]string{"a1": {"a2"}, "a2": {}},
+		},
+		{
+			label:        "Dependency on unknown app is ignored",
+			ca:           depTestCA(nil, map[string]*Criteria{"a3": {OpStatus: OpStatusDeployed}}),
+			op:           OpApply,
+			expectedWait: map[string][]string{"a1": {}, "a2": {}},
+		},
+		{
+			label: "Cycle is rejected",
+			ca: depTestCA(map[string]*Criteria{"a2": {OpStatus: OpStatusDeployed}},
+				map[string]*Criteria{"a1": {OpStatus: OpStatusDeployed}}),
+			op:            OpApply,
+			expectedError: "Dependency cycle found",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			d, err := newAppDependency(testCase.ca, testCase.op)
+			if testCase.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), testCase.expectedError) {
+					t.Fatalf("Expected error %s, got %v", testCase.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+			for app, deps := range testCase.expectedWait {
+				if len(d.waitFor[app]) != len(deps) {
+					t.Fatalf("App %s waits for %v, expected %v", app, d.waitFor[app], deps)
+				}
+				for _, dep := range deps {
+					if _, ok := d.waitFor[app][dep]; !ok {
+						t.Fatalf("App %s doesn't wait for %s", app, dep)
+					}
+				}
+			}
+		})
+	}
+}
+
+// applyConnector sends the resources applied by its clients on applied
+type applyConnector struct {
+	MockConnector
+	applied chan string
+}
+
+func (c *applyConnector) GetClientInternal(cluster string, level string, namespace string) (ClientProvider, error) {
+	cl, err := c.MockConnector.GetClientInternal(cluster, level, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return &applyClient{ClientProvider: cl, applied: c.applied}, nil
+}
+
+type applyClient struct {
+	ClientProvider
+	applied chan string
+}
+
+func (c *applyClient) Apply(content []byte) error {
+	err := c.ClientProvider.Apply(content)
+	// The status tracker is applied with no content by the mock
+	if len(content) > 0 {
+		c.applied <- string(content)
+	}
+	return err
+}
+
+func TestInstantiateWithAppDependency(t *testing.T) {
+	edb := new(contextdb.MockConDb)
+	edb.Err = nil
+	contextdb.Db = edb
+
+	cid, err := CreateCompApp(depTestCA(map[string]*Criteria{"a2": {OpStatus: OpStatusDeployed}}, nil))
+	if err != nil {
+		t.Fatalf("Error creating AppContext %v", err)
+	}
+	ca, err := ReadAppContext(cid)
+	if err != nil {
+		t.Fatalf("Error reading AppContext %v", err)
+	}
+	if c, ok := ca.Apps["a1"].Dependency["a2"]; !ok || c.OpStatus != OpStatusDeployed {
+		t.Fatalf("App dependency not read from AppContext %v", ca.Apps["a1"].Dependency)
+	}
+	con := applyConnector{applied: make(chan string, 4)}
+	con.Init(cid)
+	_ = HandleAppContext(cid, nil, InstantiateEvent, &con)
+	var applied []string
+	for len(applied) < 4 {
+		select {
+		case res := <-con.applied:
+			applied = append(applied, res)
+		case <-time.After(10 * time.Second):
+			t.Fatal("Resources not applied", applied)
+		}
+	}
+	if strings.Join(applied, ",") != "a2c1r3,a2c1r4,a1c1r1,a1c1r2" {
+		t.Error("Apply order doesn't follow app dependency", applied)
+	}
+}
+
+func TestFailedDependency(t *testing.T) {
+	d, err := newAppDependency(depTestCA(map[string]*Criteria{"a2": {OpStatus: OpStatusDeployed}}, nil), OpApply)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	c := &Context{ca: depTestCA(nil, nil)}
+	d.markFailed("a2", pkgerrors.New("apply failed"))
+	err = c.waitForAppDependency(context.Background(), d, "a1")
+	if err == nil || !strings.Contains(err.Error(), "depends on app a2 which failed: apply failed") {
+		t.Errorf("Unexpected app dependency error %v", err)
+	}
+
+	c.ca.Apps["a1"].Clusters["provider1+cluster1"].Resources["r2"].Dependency = map[string]*Criteria{"r1": {OpStatus: OpStatusDeployed}}
+	handled := map[string]error{"r1": pkgerrors.New("apply failed")}
+	err = c.waitForResourceDependency(context.Background(), nil, "a1", "provider1+cluster1", "r2", handled)
+	if err == nil || !strings.Contains(err.Error(), "depends on resource r1 which failed: apply failed") {
+		t.Errorf("Unexpected resource dependency error %v", err)
+	}
+	err = c.waitForResourceDependency(context.Background(), nil, "a1", "provider1+cluster1", "r2", map[string]error{})
+	if err == nil || !strings.Contains(err.Error(), "not ordered before it") {
+		t.Errorf("Unexpected resource dependency error %v", err)
+	}
+}
+
+func TestDependencyReadyTimeout(t *testing.T) {
+	timeout := dependencyReadyTimeout
+	dependencyReadyTimeout = 50 * time.Millisecond
+	defer func() { dependencyReadyTimeout = timeout }()
+
+	c := &Context{waitTime: 1}
+	err := c.waitForReady(context.Background(), func() bool { return false })
+	if err != context.DeadlineExceeded {
+		t.Errorf("Expected the wait to time out, got %v", err)
+	}
+}
+
+func TestResourceBundleReady(t *testing.T) {
+	two := int32(2)
+	deploy := func(name string, ready int32) appsv1.Deployment {
+		return appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       appsv1.DeploymentSpec{Replicas: &two},
+			Status:     appsv1.DeploymentStatus{ReadyReplicas: ready},
+		}
+	}
+	notReady := v1alpha1.ResourceBundleStatus{DeploymentStatuses: []appsv1.Deployment{deploy("d1", 2), deploy("d2", 1)}}
+	ready := v1alpha1.ResourceBundleStatus{DeploymentStatuses: []appsv1.Deployment{deploy("d1", 2), deploy("d2", 2)}}
+
+	if isResourceBundleReady(notReady) {
+		t.Error("Expected resource bundle not to be ready")
+	}
+	if !isResourceBundleReady(ready) {
+		t.Error("Expected resource bundle to be ready")
+	}
+	if r, found := isNamedResourceReady(notReady, "d1", "Deployment"); !found || !r {
+		t.Error("Expected deployment d1 to be ready")
+	}
+	if r, found := isNamedResourceReady(notReady, "d2", "Deployment"); !found || r {
+		t.Error("Expected deployment d2 not to be ready")
+	}
+	if _, found := isNamedResourceReady(notReady, "d3", "Deployment"); found {
+		t.Error("Expected deployment d3 not to be found")
+	}
+}
@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package context
+
+import (
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/module/subscription"
+)
+
+// publish notifies the subscriptions of the project of the AppContext. Only the
+// AppContexts of a DeploymentIntentGroup have events.
+func (c *Context) publish(t subscription.EventType, data map[string]string) {
+	m := c.ca.CompMetadata
+	if m.Project == "" || m.DeploymentIntentGroup == "" {
+		return
+	}
+	data["instance"] = c.acID
+	subscription.Publish(m.Project, t, subscription.DigSource(m.Project, m.CompositeApp, m.Version, m.DeploymentIntentGroup),
+		m.DeploymentIntentGroup, data)
+}
+
+// publishAppContextStatus notifies the result of processing an event of the AppContext
+func (c *Context) publishAppContextStatus(s appcontext.StatusValue, err error) {
+	switch {
+	case err != nil:
+		c.publish(subscription.EventTypeEnum.DigFailed, map[string]string{"error": err.Error()})
+	case s == appcontext.AppContextStatusEnum.Instantiated:
+		c.publish(subscription.EventTypeEnum.DigInstantiated, map[string]string{})
+	case s == appcontext.AppContextStatusEnum.Terminated:
+		c.publish(subscription.EventTypeEnum.DigTerminated, map[string]string{})
+	}
+}
+
+// setClusterReadyStatus sets the ready status of the cluster of the app and
+// notifies when the cluster becomes unreachable or available again
+func (c *Context) setClusterReadyStatus(app, cluster string, s appcontext.StatusValue) {
+	utils := &AppContextUtils{ac: c.ac}
+	prev := utils.GetClusterReadyStatus(app, cluster)
+	utils.SetClusterReadyStatus(app, cluster, s)
+	data := map[string]string{"app": app, "cluster": cluster}
+	switch {
+	case s == appcontext.ClusterReadyStatusEnum.Retrying && prev != s:
+		c.publish(subscription.EventTypeEnum.ClusterUnreachable, data)
+	case s == appcontext.ClusterReadyStatusEnum.Available && prev == appcontext.ClusterReadyStatusEnum.Retrying:
+		c.publish(subscription.EventTypeEnum.ClusterAvailable, data)
+	}
+}
@@ -42,6 +42,22 @@ func CreateCompApp(ca CompositeApp) (string, error) {
 	if err != nil {
 		return "", pkgerrors.Wrap(err, "Error adding app order instruction")
 	}
+	appDep := make(map[string]interface{})
+	for _, app := range ca.Apps {
+		if len(app.Dependency) > 0 {
+			appDep[app.Name] = app.Dependency
+		} else {
+			appDep[app.Name] = "go"
+		}
+	}
+	appDependency, err := json.Marshal(map[string]interface{}{"appdependency": appDep})
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Error adding app dependency instruction")
+	}
+	_, err = context.AddInstruction(compositeHandle, "app", "dependency", string(appDependency))
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "Error adding app dependency instruction")
+	}
 	for _, app := range ca.Apps {
 		a, err := context.AddApp(compositeHandle, app.Name)
 		if err != nil {
@@ -57,6 +73,22 @@ func CreateCompApp(ca CompositeApp) (string, error) {
 			if err != nil {
 				return "", pkgerrors.Wrap(err, "Error Adding resorder")
 			}
+			resDep := make(map[string]interface{})
+			for _, res := range cluster.Resources {
+				if len(res.Dependency) > 0 {
+					resDep[res.Name] = res.Dependency
+				} else {
+					resDep[res.Name] = "go"
+				}
+			}
+			resDependency, err := json.Marshal(map[string]interface{}{"resdependency": resDep})
+			if err != nil {
+				return "", pkgerrors.Wrap(err, "Error Adding resdependency")
+			}
+			_, err = context.AddInstruction(c, "resource", "dependency", string(resDependency))
+			if err != nil {
+				return "", pkgerrors.Wrap(err, "Error Adding resdependency")
+			}
 			for _, res := range cluster.Resources {
 				_, err = context.AddResource(c, res.Name, res.Data)
 				if err != nil {
@@ -90,14 +122,15 @@ func ReadAppContext(contextID interface{}) (CompositeApp, error) {
 	if err != nil {
 		return CompositeApp{}, err
 	}
-	//depOrder, err := ac.GetAppInstruction("dependency")
-	//if err != nil {
-	//	return CompositeApp{}, err
-	//}
 	var appList map[string][]string
 	json.Unmarshal([]byte(appsOrder.(string)), &appList)
 	ca.AppOrder = appList["apporder"]
-	//ca.DepOrder = depOrder
+	// App dependency instruction is optional
+	var appDep map[string]map[string]*Criteria
+	appDepInstr, err := ac.GetAppInstruction("dependency")
+	if err == nil {
+		appDep = parseDependencyInstruction(appDepInstr.(string), "appdependency")
+	}
 	appsList := make(map[string]*App)
 	for _, app := range appList["apporder"] {
 		clusterNames, err := ac.GetClusterNames(app)
@@ -118,21 +151,48 @@ func ReadAppContext(contextID interface{}) (CompositeApp, error) {
 			var aov map[string][]string
 			json.Unmarshal([]byte(resorder.(string)), &aov)
 			//var resList []AppResource
+			// Resource dependency instruction is optional
+			var resDep map[string]map[string]*Criteria
+			resDepInstr, err := ac.GetResourceInstruction(app, cluster, "dependency")
+			if err == nil {
+				resDep = parseDependencyInstruction(resDepInstr.(string), "resdependency")
+			}
 			resList := make(map[string]*AppResource)
 			for _, res := range aov["resorder"] {
-				r := &AppResource{Name: res}
+				r := &AppResource{Name: res, Dependency: resDep[res]}
 				//resList = append(resList, r)
 				resList[res] = r
 			}
 			clusterList[cluster] = &Cluster{Name: cluster, Resources: resList, ResOrder: aov["resorder"]}
 			//clusterList = append(clusterList, Cluster{Name: cluster, Resources: resList, ResOrder: aov["resorder"]})
 		}
-		appsList[app] = &App{Name: app, Clusters: clusterList}
+		appsList[app] = &App{Name: app, Clusters: clusterList, Dependency: appDep[app]}
 	}
 	ca.Apps = appsList
 	return ca, nil
 }
 
+// parseDependencyInstruction reads a dependency instruction of the form
+// {"<key>": {"a1": "go", "a2": {"a1": {"opstatus": "Ready", "wait": 10}}}}
+// Entries with the value "go" have no dependencies and are skipped
+func parseDependencyInstruction(instr string, key string) map[string]map[string]*Criteria {
+	depMap := make(map[string]map[string]*Criteria)
+	var raw map[string]map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(instr), &raw); err != nil {
+		logutils.Info("Ignoring dependency instruction", logutils.Fields{"instruction": instr, "err": err})
+		return depMap
+	}
+	for name, v := range raw[key] {
+		var dep map[string]*Criteria
+		if err := json.Unmarshal(v, &dep); err != nil {
+			// Not a dependency list, for example "go"
+			continue
+		}
+		depMap[name] = dep
+	}
+	return depMap
+}
+
 // PrintCompositeApp prints the composite app
 func PrintCompositeApp(ca CompositeApp) {
 
@@ -0,0 +1,193 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package context
+
+/*
+hierarchy.go links child AppContexts to their parent. A child AppContext, like
+the one of the services SDS deploys for a composite app, names its parent in
+its CompositeAppMeta and the parent lists its children. Both are stored in the
+AppContexts, so the hierarchy is kept across restarts. The events of the parent
+are cascaded to its children through their own queues.
+*/
+
+import (
+	"context"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
+	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	. "github.com/open-ness/EMCO/src/rsync/pkg/types"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// getParentContext returns the ID of the parent of an AppContext, or "" if it
+// has no parent
+func getParentContext(a interface{}) string {
+	ac := appcontext.AppContext{}
+	if _, err := ac.LoadAppContext(a); err != nil {
+		return ""
+	}
+	m, err := ac.GetCompositeAppMeta()
+	if err != nil {
+		return ""
+	}
+	return m.ParentContextID
+}
+
+// getChildContexts returns the IDs of the children of an AppContext
+func getChildContexts(ac appcontext.AppContext) []string {
+	m, err := ac.GetCompositeAppMeta()
+	if err != nil {
+		return nil
+	}
+	var ids []string
+	for _, id := range m.ChildContextIDs {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// getCurrentState returns the current state of an AppContext, Created if no
+// event of the AppContext was handled yet
+func getCurrentState(ac appcontext.AppContext) appcontext.StatusValue {
+	utils := &AppContextUtils{ac: ac}
+	s, err := utils.GetAppContextStatus(CurrentStateKey)
+	if err != nil || s.Status == "" {
+		return appcontext.AppContextStatusEnum.Created
+	}
+	return s.Status
+}
+
+// removeChildContext removes a child from the children of an AppContext
+func removeChildContext(parentID, childID string) error {
+	ac := appcontext.AppContext{}
+	if _, err := ac.LoadAppContext(parentID); err != nil {
+		return err
+	}
+	m, err := ac.GetCompositeAppMeta()
+	if err != nil {
+		return err
+	}
+	var ids []string
+	for _, id := range m.ChildContextIDs {
+		if id != "" && id != childID {
+			ids = append(ids, id)
+		}
+	}
+	m.ChildContextIDs = ids
+	return ac.AddCompositeAppMeta(m)
+}
+
+// addChildContext links a child to the AppContext and instantiates it if the
+// AppContext is instantiated. A child of another AppContext, like the one
+// updated to this AppContext, is moved to this AppContext.
+func (c *Context) addChildContext(ctx context.Context, childID string) error {
+	if childID == "" || childID == c.acID {
+		return pkgerrors.Errorf("Invalid child context: %s", childID)
+	}
+	utils := &AppContextUtils{ac: c.ac}
+	tFlag, err := utils.GetAppContextFlag(PendingTerminateFlagKey)
+	if err != nil || tFlag {
+		return pkgerrors.Errorf("Terminate Flag is set, Ignoring child context: %s", childID)
+	}
+	state := getCurrentState(c.ac)
+	switch state {
+	case appcontext.AppContextStatusEnum.Terminating,
+		appcontext.AppContextStatusEnum.Terminated,
+		appcontext.AppContextStatusEnum.TerminateFailed:
+		return pkgerrors.Errorf("Invalid state %s of the parent for child context: %s", state, childID)
+	}
+
+	child := appcontext.AppContext{}
+	if _, err := child.LoadAppContext(childID); err != nil {
+		return pkgerrors.Wrapf(err, "Error loading child context: %s", childID)
+	}
+	cm, err := child.GetCompositeAppMeta()
+	if err != nil {
+		return err
+	}
+	if cm.ParentContextID != "" && cm.ParentContextID != c.acID {
+		if err := removeChildContext(cm.ParentContextID, childID); err != nil {
+			log.Warn("Error removing the child from its previous parent", log.Fields{"parent": cm.ParentContextID, "child": childID, "err": err})
+		}
+	}
+	cm.ParentContextID = c.acID
+	if err := child.AddCompositeAppMeta(cm); err != nil {
+		return err
+	}
+
+	m, err := c.ac.GetCompositeAppMeta()
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, id := range m.ChildContextIDs {
+		if id == childID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		m.ChildContextIDs = append(m.ChildContextIDs, childID)
+		if err := c.ac.AddCompositeAppMeta(m); err != nil {
+			return err
+		}
+	}
+	c.ca.CompMetadata = m
+	log.Info("Added child context", log.Fields{"parent": c.acID, "child": childID})
+
+	// The children of an AppContext being instantiated are instantiated
+	// once it is
+	if state == appcontext.AppContextStatusEnum.Instantiated {
+		return c.cascadeEvent(ctx, childID, InstantiateEvent)
+	}
+	return nil
+}
+
+// cascadeEvent queues the event of the AppContext for a child if the state of
+// the child is valid for the event and the event is not pending already. A
+// terminate is also queued behind the pending events of the child.
+func (c *Context) cascadeEvent(ctx context.Context, childID string, e RsyncEvent) error {
+	child := appcontext.AppContext{}
+	if _, err := child.LoadAppContext(childID); err != nil {
+		return pkgerrors.Wrapf(err, "Error loading child context: %s", childID)
+	}
+	qUtils := &AppContextQueueUtils{ac: child}
+	if qUtils.HasPending(e) {
+		return nil
+	}
+	state := getCurrentState(child)
+	supported := e == TerminateEvent && qUtils.GetPendingCount() > 0
+	for _, s := range StateChanges[e].SState {
+		if s == state {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		log.Info("Event not cascaded to the child context", log.Fields{"event": e, "child": childID, "state": state})
+		return nil
+	}
+	return handleAppContext(ctx, childID, nil, e, c.con)
+}
+
+// cascadeToChildren queues the event of the AppContext for its children
+func (c *Context) cascadeToChildren(ctx context.Context, e RsyncEvent) {
+	for _, id := range getChildContexts(c.ac) {
+		if err := c.cascadeEvent(ctx, id, e); err != nil {
+			log.Error("Error cascading event to the child context", log.Fields{"event": e, "child": id, "err": err})
+		}
+	}
+}
+
+// moveChildContexts queues the children of the AppContext to be added to the
+// AppContext it is updated to
+func (c *Context) moveChildContexts(ctx context.Context, ucid string) {
+	for _, id := range getChildContexts(c.ac) {
+		if err := handleAppContext(ctx, ucid, id, AddChildContextEvent, c.con); err != nil {
+			log.Error("Error moving the child context", log.Fields{"child": id, "appContext": ucid, "err": err})
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package context
+
+import (
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	queueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "rsync",
+		Name:      "queue_depth",
+		Help:      "Number of pending events in the queue of an AppContext.",
+	}, []string{"appcontext"})
+
+	clusterOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "rsync",
+		Name:      "cluster_operations_total",
+		Help:      "Number of resources applied to and deleted from a cluster.",
+	}, []string{"cluster", "operation", "result"})
+
+	clusterRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "rsync",
+		Name:      "cluster_reachability_retries_total",
+		Help:      "Number of retries to reach a cluster that is not reachable.",
+	}, []string{"cluster"})
+
+	activeContexts = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "rsync",
+		Name:      "active_appcontexts",
+		Help:      "Number of AppContexts with events being handled.",
+	}, countActiveContexts)
+)
+
+func init() {
+	prometheus.MustRegister(queueDepth, clusterOperations, clusterRetries, activeContexts)
+}
+
+func countActiveContexts() float64 {
+	ids, _ := GetAllActiveContext()
+	n := 0
+	for _, id := range ids {
+		if id != "" {
+			n++
+		}
+	}
+	return float64(n)
+}
+
+// observeClusterOperation counts an apply or delete of a resource on a cluster
+func observeClusterOperation(cluster, operation string, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	clusterOperations.WithLabelValues(cluster, operation, result).Inc()
+}
+
+// updateQueueDepth sets the number of pending events of the AppContext
+func updateQueueDepth(acID string, q *AppContextQueueUtils) {
+	queueDepth.WithLabelValues(acID).Set(float64(q.GetPendingCount()))
+}
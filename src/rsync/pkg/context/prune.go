@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package context
+
+import (
+	"context"
+
+	"github.com/ghodss/yaml"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
+	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	. "github.com/open-ness/EMCO/src/rsync/pkg/types"
+	pkgerrors "github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// pruneTarget holds the GVKs to look for orphaned resources of an app on a
+// cluster and the resources of the app that are not orphaned
+type pruneTarget struct {
+	gvks    map[schema.GroupVersionKind]bool
+	desired map[string]bool
+}
+
+// objectMeta is the part of a resource that identifies it on a cluster
+type objectMeta struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name              string        `json:"name"`
+		Namespace         string        `json:"namespace"`
+		DeletionTimestamp interface{}   `json:"deletionTimestamp"`
+		OwnerReferences   []interface{} `json:"ownerReferences"`
+	} `json:"metadata"`
+}
+
+func parseObjectMeta(b []byte) (objectMeta, error) {
+	var m objectMeta
+	if err := yaml.Unmarshal(b, &m); err != nil {
+		return m, pkgerrors.Wrap(err, "Error parsing resource")
+	}
+	if m.Kind == "" || m.Metadata.Name == "" {
+		return m, pkgerrors.New("Resource has no kind or name")
+	}
+	return m, nil
+}
+
+// pruneKey identifies a resource on a cluster. The API version is left out,
+// the same resource can be read with any version of its group.
+func pruneKey(gvk schema.GroupVersionKind, namespace, name string) string {
+	return gvk.Group + "/" + gvk.Kind + "/" + namespace + "/" + name
+}
+
+// addPruneTargets adds the apps and clusters of an AppContext to the targets.
+// The resources of the AppContext are not orphaned if desired is set, only
+// their GVKs are looked for otherwise.
+func addPruneTargets(targets map[string]map[string]*pruneTarget, ca CompositeApp, ac appcontext.AppContext, namespace string, desired bool) {
+	utils := &AppContextUtils{ac: ac}
+	for _, app := range ca.Apps {
+		if targets[app.Name] == nil {
+			targets[app.Name] = make(map[string]*pruneTarget)
+		}
+		for _, cluster := range app.Clusters {
+			t := targets[app.Name][cluster.Name]
+			if t == nil {
+				t = &pruneTarget{gvks: make(map[schema.GroupVersionKind]bool), desired: make(map[string]bool)}
+				targets[app.Name][cluster.Name] = t
+			}
+			for _, res := range cluster.ResOrder {
+				b, _, err := utils.GetRes(res, app.Name, cluster.Name)
+				if err != nil {
+					continue
+				}
+				m, err := parseObjectMeta(b)
+				if err != nil {
+					continue
+				}
+				gvk := schema.FromAPIVersionAndKind(m.APIVersion, m.Kind)
+				t.gvks[gvk] = true
+				if !desired {
+					continue
+				}
+				// Resources without a namespace are applied in the namespace
+				// of the AppContext, unless they are cluster scoped
+				if m.Metadata.Namespace == "" {
+					t.desired[pruneKey(gvk, "", m.Metadata.Name)] = true
+					t.desired[pruneKey(gvk, namespace, m.Metadata.Name)] = true
+				} else {
+					t.desired[pruneKey(gvk, m.Metadata.Namespace, m.Metadata.Name)] = true
+				}
+			}
+		}
+	}
+}
+
+// prune looks for the resources on the clusters tagged with the label of
+// the apps that are not part of the AppContext any more, like the ones left
+// behind by a failed operation. They are reported in the prune status of the
+// AppContext and deleted in enforce mode. The GVKs of the AppContext updated
+// from, if any, are looked for too.
+func (c *Context) prune(ctx context.Context, prevID string) {
+	utils := &AppContextUtils{ac: c.ac}
+	mode := utils.GetPruneMode()
+	if mode != appcontext.PruneModeEnum.DryRun && mode != appcontext.PruneModeEnum.Enforce {
+		return
+	}
+	namespace, level := utils.GetNamespace()
+	targets := make(map[string]map[string]*pruneTarget)
+	addPruneTargets(targets, c.ca, c.ac, namespace, true)
+	if prevID != "" {
+		pac := appcontext.AppContext{}
+		if _, err := pac.LoadAppContext(prevID); err == nil {
+			if pca, err := ReadAppContext(prevID); err == nil {
+				addPruneTargets(targets, pca, pac, namespace, false)
+			}
+		}
+	}
+
+	ps := appcontext.PruneStatus{Mode: mode}
+	for app, clusters := range targets {
+		for cluster, t := range clusters {
+			if ctx.Err() != nil {
+				return
+			}
+			cl, err := c.con.GetClientInternal(cluster, level, namespace)
+			if err != nil {
+				log.Error("Error in creating client", log.Fields{"error": err, "cluster": cluster, "app": app})
+				continue
+			}
+			lister, ok := cl.(ResourceLister)
+			if !ok {
+				continue
+			}
+			// Resources of unreachable clusters can't be listed
+			if err := cl.IsReachable(); err != nil {
+				continue
+			}
+			label := c.statusAcID + "-" + app
+			for gvk := range t.gvks {
+				resources, err := lister.ListResources(gvk, label)
+				if err != nil {
+					log.Error("Error listing resources to prune", log.Fields{"error": err, "cluster": cluster, "gvk": gvk.String()})
+					continue
+				}
+				for _, b := range resources {
+					m, err := parseObjectMeta(b)
+					if err != nil || m.Metadata.DeletionTimestamp != nil {
+						continue
+					}
+					// The pod templates are tagged too, the resources their
+					// controllers create belong to the controllers
+					if len(m.Metadata.OwnerReferences) > 0 {
+						continue
+					}
+					if t.desired[pruneKey(gvk, m.Metadata.Namespace, m.Metadata.Name)] {
+						continue
+					}
+					pr := appcontext.PrunedResource{App: app, Cluster: cluster, APIVersion: m.APIVersion,
+						Kind: m.Kind, Name: m.Metadata.Name, Namespace: m.Metadata.Namespace}
+					if mode == appcontext.PruneModeEnum.Enforce {
+						if err := cl.Delete(b); err != nil {
+							pr.Error = err.Error()
+						} else {
+							pr.Deleted = true
+						}
+					}
+					log.Info("Orphaned resource found", log.Fields{"context": c.acID, "cluster": cluster,
+						"resource": m.Metadata.Name, "kind": m.Kind, "mode": mode, "deleted": pr.Deleted})
+					ps.Resources = append(ps.Resources, pr)
+				}
+			}
+		}
+	}
+	if err := utils.UpdateAppContextStatus(appcontext.PruneStatusKey, ps); err != nil {
+		log.Error("Error updating prune status", log.Fields{"context": c.acID, "error": err})
+	}
+}
@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/contextdb"
+	. "github.com/open-ness/EMCO/src/rsync/pkg/types"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// listerClient is a cluster client with resources tagged by rsync
+type listerClient struct {
+	MockClient
+	labels    []string
+	resources [][]byte
+	deleted   []string
+}
+
+func (lc *listerClient) ListResources(gvk schema.GroupVersionKind, label string) ([][]byte, error) {
+	lc.labels = append(lc.labels, label)
+	return lc.resources, nil
+}
+
+func (lc *listerClient) Delete(content []byte) error {
+	lc.deleted = append(lc.deleted, string(content))
+	return nil
+}
+
+// listerConnector returns the same listerClient for all the clusters
+type listerConnector struct {
+	MockConnector
+	cl *listerClient
+}
+
+func (lc *listerConnector) GetClientInternal(cluster string, level string, namespace string) (ClientProvider, error) {
+	return lc.cl, nil
+}
+
+const pruneTestRes = "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n"
+
+func pruneTestCA() CompositeApp {
+	return CompositeApp{
+		CompMetadata: appcontext.CompositeAppMeta{Project: "proj1", CompositeApp: "ca1", Version: "v1", Release: "r1",
+			DeploymentIntentGroup: "dig1", Namespace: "default", Level: "0"},
+		AppOrder: []string{"a1"},
+		Apps: map[string]*App{"a1": &App{Name: "a1", Clusters: map[string]*Cluster{
+			"provider1+cluster1": &Cluster{
+				Name:      "provider1+cluster1",
+				Resources: map[string]*AppResource{"web+Deployment": &AppResource{Name: "web+Deployment", Data: pruneTestRes}},
+				ResOrder:  []string{"web+Deployment"},
+			},
+		}}},
+	}
+}
+
+func TestPrune(t *testing.T) {
+	edb := new(contextdb.MockConDb)
+	edb.Err = nil
+	contextdb.Db = edb
+
+	cid, err := CreateCompApp(pruneTestCA())
+	if err != nil {
+		t.Fatalf("Error creating AppContext %v", err)
+	}
+	ca, err := ReadAppContext(cid)
+	if err != nil {
+		t.Fatalf("Error reading AppContext %v", err)
+	}
+	ac := appcontext.AppContext{}
+	if _, err := ac.LoadAppContext(cid); err != nil {
+		t.Fatalf("Error loading AppContext %v", err)
+	}
+	h, _ := ac.GetCompositeAppHandle()
+	ph, err := ac.AddLevelValue(h, appcontext.PruneKey, appcontext.PruneModeEnum.DryRun)
+	if err != nil {
+		t.Fatalf("Error adding the prune mode %v", err)
+	}
+
+	web := []byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web","namespace":"default"}}`)
+	old := []byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"old","namespace":"default"}}`)
+	// The pods of the Deployment carry its label too
+	pod := []byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"web-5d4f8-x2x7k","namespace":"default",` +
+		`"ownerReferences":[{"apiVersion":"apps/v1","kind":"ReplicaSet","name":"web-5d4f8","controller":true}]}}`)
+	cl := &listerClient{resources: [][]byte{web, old, pod}}
+	c := &Context{acID: cid, ac: ac, statusAcID: cid, sc: ac, ca: ca, con: &listerConnector{cl: cl}}
+	getPruneStatus := func() appcontext.PruneStatus {
+		var ps appcontext.PruneStatus
+		sh, err := ac.GetLevelHandle(h, appcontext.PruneStatusKey)
+		if err != nil {
+			t.Fatalf("Error reading the prune status %v", err)
+		}
+		v, _ := ac.GetValue(sh)
+		b, _ := json.Marshal(v)
+		_ = json.Unmarshal(b, &ps)
+		return ps
+	}
+
+	// In dry-run mode the orphaned resource is only reported
+	c.prune(context.Background(), "")
+	if len(cl.labels) != 1 || cl.labels[0] != cid+"-a1" {
+		t.Errorf("Unexpected labels listed %v", cl.labels)
+	}
+	ps := getPruneStatus()
+	if len(ps.Resources) != 1 || ps.Resources[0].Name != "old" || ps.Resources[0].Deleted {
+		t.Errorf("Unexpected prune status %v", ps)
+	}
+	if len(cl.deleted) != 0 {
+		t.Errorf("Unexpected resources deleted %v", cl.deleted)
+	}
+
+	// In enforce mode the orphaned resource is deleted
+	if err := ac.UpdateValue(ph, appcontext.PruneModeEnum.Enforce); err != nil {
+		t.Fatalf("Error updating the prune mode %v", err)
+	}
+	c.prune(context.Background(), "")
+	ps = getPruneStatus()
+	if ps.Mode != appcontext.PruneModeEnum.Enforce || len(ps.Resources) != 1 || !ps.Resources[0].Deleted {
+		t.Errorf("Unexpected prune status %v", ps)
+	}
+	if len(cl.deleted) != 1 || cl.deleted[0] != string(old) {
+		t.Errorf("Unexpected resources deleted %v", cl.deleted)
+	}
+}
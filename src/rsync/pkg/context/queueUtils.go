@@ -174,3 +174,32 @@ func (aq *AppContextQueueUtils) UpdateStatus(index int, status string) error {
 	_, err = aq.UpdateQueue(q)
 	return err
 }
+
+// GetPendingCount shall return the number of pending elements in the AppContextQueue
+func (aq *AppContextQueueUtils) GetPendingCount() int {
+	q, err := aq.GetAppContextQueue()
+	if err != nil {
+		return 0
+	}
+	n := 0
+	for _, v := range q.AcQueue {
+		if v.Status == "Pending" {
+			n++
+		}
+	}
+	return n
+}
+
+// HasPending shall return true if the event is pending in the AppContextQueue
+func (aq *AppContextQueueUtils) HasPending(e types.RsyncEvent) bool {
+	q, err := aq.GetAppContextQueue()
+	if err != nil {
+		return false
+	}
+	for _, v := range q.AcQueue {
+		if v.Event == e && v.Status == "Pending" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,392 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package context
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/config"
+	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/module/subscription"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/resourcestatus"
+	"github.com/open-ness/EMCO/src/rsync/pkg/client"
+	. "github.com/open-ness/EMCO/src/rsync/pkg/types"
+	pkgerrors "github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// readResource identifies a resource to read from a cluster with ClientProvider.Get
+type readResource struct {
+	Gvk       schema.GroupVersionKind `json:"GVK,omitempty"`
+	Name      string                  `json:"name,omitempty"`
+	Namespace string                  `json:"namespace,omitempty"`
+}
+
+// Read reconcile interval in seconds from configuration
+func getReconcileInterval() int {
+	interval, err := strconv.Atoi(config.GetConfiguration().ReconcileInterval)
+	if err != nil || interval < 0 {
+		return 0
+	}
+	return interval
+}
+
+// startReconcile starts checking the resources of an instantiated AppContext for
+// drift if it isn't already done. Must be called with the lock held.
+func (c *Context) startReconcile() {
+	if c.reconciling {
+		return
+	}
+	utils := &AppContextUtils{ac: c.ac}
+	mode := utils.GetReconcileMode()
+	if mode != appcontext.ReconcileModeEnum.Detect && mode != appcontext.ReconcileModeEnum.Enforce {
+		return
+	}
+	s, err := utils.GetAppContextStatus(CurrentStateKey)
+	if err != nil || s.Status != appcontext.AppContextStatusEnum.Instantiated {
+		return
+	}
+	interval := getReconcileInterval()
+	if interval == 0 {
+		return
+	}
+	c.reconciling = true
+	// The checks are resumed from the record when rsync restarts
+	if err := recordReconcileContext(c.acID); err != nil {
+		log.Error("Error recording the AppContext checked for drift", log.Fields{"context": c.acID, "error": err})
+	}
+	go c.reconcileRoutine(mode, time.Duration(interval)*time.Second)
+}
+
+// reconcileRoutine periodically compares the resources of the AppContext with the
+// live resources on the clusters until the AppContext is no longer instantiated
+func (c *Context) reconcileRoutine(mode appcontext.ReconcileMode, interval time.Duration) {
+	log.Info("Start checking resources for drift", log.Fields{"context": c.acID, "mode": mode})
+	defer func() {
+		c.Lock.Lock()
+		c.reconciling = false
+		c.Lock.Unlock()
+		deleteReconcileContext(c.acID)
+		log.Info("Stop checking resources for drift", log.Fields{"context": c.acID})
+	}()
+
+	utils := &AppContextUtils{ac: c.ac}
+	for {
+		time.Sleep(interval)
+		// Events are handled first, the AppContext is checked again after
+		if c.isRunning() {
+			continue
+		}
+		s, err := utils.GetAppContextStatus(CurrentStateKey)
+		if err != nil || s.Status != appcontext.AppContextStatusEnum.Instantiated {
+			return
+		}
+		if flag, err := utils.GetAppContextFlag(StopFlagKey); err != nil || flag {
+			return
+		}
+		c.reconcile(mode)
+	}
+}
+
+func (c *Context) isRunning() bool {
+	c.Lock.Lock()
+	defer c.Lock.Unlock()
+	return c.Running
+}
+
+// reconcile checks all the resources of the AppContext for drift once
+func (c *Context) reconcile(mode appcontext.ReconcileMode) {
+	utils := &AppContextUtils{ac: c.ac}
+	namespace, level := utils.GetNamespace()
+	for _, app := range c.ca.Apps {
+		for _, cluster := range app.Clusters {
+			cl, err := c.con.GetClientInternal(cluster.Name, level, namespace)
+			if err != nil {
+				log.Error("Error in creating client", log.Fields{"error": err, "cluster": cluster.Name, "app": app.Name})
+				continue
+			}
+			// Resources of unreachable clusters can't be checked
+			if err := cl.IsReachable(); err != nil {
+				continue
+			}
+			for _, res := range cluster.ResOrder {
+				if c.isRunning() {
+					return
+				}
+				c.reconcileResource(cl, mode, namespace, app.Name, cluster.Name, res)
+			}
+		}
+	}
+}
+
+// reconcileResource checks a resource applied on a cluster for drift. A drifted
+// resource is marked in the resource status and applied again in enforce mode.
+func (c *Context) reconcileResource(cl ClientProvider, mode appcontext.ReconcileMode, namespace, app, cluster, name string) {
+	// Only resources applied by rsync are checked
+	status := (&AppContextUtils{ac: c.sc}).GetResourceStatus(name, app, cluster)
+	if status != resourcestatus.RsyncStatusEnum.Applied && status != resourcestatus.RsyncStatusEnum.Drifted {
+		return
+	}
+	utils := &AppContextUtils{ac: c.ac}
+	res, _, err := utils.GetRes(name, app, cluster)
+	if err != nil {
+		return
+	}
+	drifted, err := checkDrift(cl, res, namespace)
+	if err != nil {
+		log.Info("Error checking resource for drift", log.Fields{"error": err, "cluster": cluster, "resource": name})
+		return
+	}
+	if !drifted {
+		if status == resourcestatus.RsyncStatusEnum.Drifted {
+			c.updateResourceStatus(context.Background(), name, app, cluster,
+				resourcestatus.ResourceStatus{Status: resourcestatus.RsyncStatusEnum.Applied})
+		}
+		return
+	}
+	log.Warn("Resource drifted", log.Fields{"app": app, "cluster": cluster, "resource": name, "mode": mode})
+	if status != resourcestatus.RsyncStatusEnum.Drifted {
+		c.publish(subscription.EventTypeEnum.ResourceDrifted, map[string]string{"app": app, "cluster": cluster,
+			"resource": name, "mode": mode})
+	}
+	if mode == appcontext.ReconcileModeEnum.Enforce {
+		// Status is set to Applied on success
+		if err := c.instantiateResource(context.Background(), cl, name, app, cluster); err == nil {
+			return
+		}
+	}
+	c.updateResourceStatus(context.Background(), name, app, cluster,
+		resourcestatus.ResourceStatus{Status: resourcestatus.RsyncStatusEnum.Drifted})
+}
+
+// checkDrift compares the desired resource with the live resource on the cluster.
+// The resource drifted if it was deleted or if any field set in the desired
+// resource has a different value in the live resource. Fields set by the cluster
+// only, like the status or the containers injected by admission webhooks, are
+// ignored, and so are the fields owned by other managers, like the replicas
+// of a Deployment scaled by an HPA.
+func checkDrift(cl ClientProvider, res []byte, namespace string) (bool, error) {
+	var desired map[string]interface{}
+	if err := yaml.Unmarshal(res, &desired); err != nil {
+		return false, pkgerrors.Wrap(err, "Error parsing resource")
+	}
+	apiVersion, _ := desired["apiVersion"].(string)
+	kind, _ := desired["kind"].(string)
+	metadata, _ := desired["metadata"].(map[string]interface{})
+	name, _ := metadata["name"].(string)
+	ns, _ := metadata["namespace"].(string)
+	if kind == "" || name == "" {
+		return false, pkgerrors.New("Resource has no kind or name")
+	}
+
+	r, err := json.Marshal(readResource{Gvk: schema.FromAPIVersionAndKind(apiVersion, kind), Name: name, Namespace: ns})
+	if err != nil {
+		return false, err
+	}
+	b, err := cl.Get(r, namespace)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return true, nil
+		}
+		return false, err
+	}
+	var live map[string]interface{}
+	if err := json.Unmarshal(b, &live); err != nil {
+		return false, pkgerrors.Wrap(err, "Error parsing live resource")
+	}
+
+	delete(desired, "status")
+	normalize(desired)
+	others, own := managedFields(live)
+	dropManagedFields(desired, others, own)
+	return !isSubset(desired, live), nil
+}
+
+// managedFields returns the fields of the live resource owned by the other
+// managers and by rsync, merged in the FieldsV1 format of the managed fields
+func managedFields(live map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	others := make(map[string]interface{})
+	own := make(map[string]interface{})
+	metadata, _ := live["metadata"].(map[string]interface{})
+	entries, _ := metadata["managedFields"].([]interface{})
+	for _, e := range entries {
+		entry, _ := e.(map[string]interface{})
+		manager, _ := entry["manager"].(string)
+		fields, _ := entry["fieldsV1"].(map[string]interface{})
+		if client.IsRsyncManager(manager) {
+			mergeFields(own, fields)
+		} else {
+			mergeFields(others, fields)
+		}
+	}
+	return others, own
+}
+
+// mergeFields adds the fields of src to dst
+func mergeFields(dst, src map[string]interface{}) {
+	for k, v := range src {
+		sub, _ := v.(map[string]interface{})
+		d, ok := dst[k].(map[string]interface{})
+		if !ok {
+			d = make(map[string]interface{})
+			dst[k] = d
+		}
+		mergeFields(d, sub)
+	}
+}
+
+// dropManagedFields removes from desired the fields owned by the other
+// managers and not by rsync. The fields of a map are "f:<name>" and the
+// elements of a list "k:<keys>", matched by the values of their keys.
+func dropManagedFields(desired interface{}, others, own map[string]interface{}) {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		for k, v := range others {
+			if !strings.HasPrefix(k, "f:") {
+				continue
+			}
+			name := strings.TrimPrefix(k, "f:")
+			if _, ok := d[name]; !ok {
+				continue
+			}
+			sub, _ := v.(map[string]interface{})
+			ownSub, owned := own[k].(map[string]interface{})
+			if isLeafField(sub) {
+				if !owned {
+					delete(d, name)
+				}
+				continue
+			}
+			dropManagedFields(d[name], sub, ownSub)
+		}
+	case []interface{}:
+		for k, v := range others {
+			if !strings.HasPrefix(k, "k:") {
+				continue
+			}
+			var keys map[string]interface{}
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(k, "k:")), &keys); err != nil {
+				continue
+			}
+			sub, _ := v.(map[string]interface{})
+			ownSub, _ := own[k].(map[string]interface{})
+			for _, elem := range d {
+				if isSubset(keys, elem) {
+					dropManagedFields(elem, sub, ownSub)
+				}
+			}
+		}
+	}
+}
+
+// isLeafField returns whether the managed field has no managed children
+func isLeafField(fields map[string]interface{}) bool {
+	for k := range fields {
+		if k != "." {
+			return false
+		}
+	}
+	return true
+}
+
+// normalize converts the fields of the desired resource the API server only
+// returns in another form
+func normalize(desired map[string]interface{}) {
+	// The stringData of a Secret is merged into its data
+	if desired["kind"] == "Secret" {
+		stringData, ok := desired["stringData"].(map[string]interface{})
+		if !ok {
+			return
+		}
+		data, ok := desired["data"].(map[string]interface{})
+		if !ok {
+			data = make(map[string]interface{})
+		}
+		for k, v := range stringData {
+			data[k] = base64.StdEncoding.EncodeToString([]byte(fmt.Sprint(v)))
+		}
+		desired["data"] = data
+		delete(desired, "stringData")
+	}
+}
+
+// isSubset checks that all the fields set in want have the same value in have.
+// The elements of a list in want must match distinct elements of the list in
+// have, in any order, as the cluster may add elements to lists.
+func isSubset(want, have interface{}) bool {
+	switch w := want.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		h, ok := have.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for k, v := range w {
+			if !isSubset(v, h[k]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		h, ok := have.([]interface{})
+		if !ok || len(h) < len(w) {
+			return false
+		}
+		used := make([]bool, len(h))
+		for i := range w {
+			found := false
+			for j := range h {
+				if !used[j] && isSubset(w[i], h[j]) {
+					used[j] = true
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	default:
+		return isEqualScalar(want, have)
+	}
+}
+
+// isEqualScalar compares two values like the API server does, so 1 is equal
+// to "1" and a cpu of 0.5 is equal to "500m"
+func isEqualScalar(want, have interface{}) bool {
+	if reflect.DeepEqual(want, have) {
+		return true
+	}
+	if want == nil || have == nil {
+		return false
+	}
+	if _, ok := want.(bool); ok {
+		return false
+	}
+	w, h := fmt.Sprint(want), fmt.Sprint(have)
+	if w == h {
+		return true
+	}
+	wq, err := resource.ParseQuantity(w)
+	if err != nil {
+		return false
+	}
+	hq, err := resource.ParseQuantity(h)
+	if err != nil {
+		return false
+	}
+	return wq.Cmp(hq) == 0
+}
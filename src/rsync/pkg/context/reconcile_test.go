@@ -0,0 +1,240 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package context
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/contextdb"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/resourcestatus"
+	. "github.com/open-ness/EMCO/src/rsync/pkg/types"
+	pkgerrors "github.com/pkg/errors"
+)
+
+const driftTestDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: d1
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: c1
+        image: nginx:1.19
+`
+
+// liveClient returns the live resource for Get and records applied resources
+type liveClient struct {
+	MockClient
+	live    string
+	applied []string
+}
+
+func (l *liveClient) Get(gvkRes []byte, namespace string) ([]byte, error) {
+	if l.live == "" {
+		return nil, pkgerrors.New("deployments.apps \"d1\" not found")
+	}
+	return []byte(l.live), nil
+}
+
+func (l *liveClient) Apply(content []byte) error {
+	l.applied = append(l.applied, string(content))
+	return nil
+}
+
+func TestCheckDrift(t *testing.T) {
+	testCases := []struct {
+		label    string
+		desired  string
+		live     string
+		expected bool
+	}{
+		{
+			label: "Live resource has defaulted fields",
+			live: `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"d1","namespace":"default","labels":{"emco/deployment-id":"1"}},
+				"spec":{"replicas":2,"strategy":{"type":"RollingUpdate"},"template":{"spec":{"containers":[{"name":"c1","image":"nginx:1.19","imagePullPolicy":"IfNotPresent"}]}}},
+				"status":{"readyReplicas":2}}`,
+			expected: false,
+		},
+		{
+			label: "Live resource was scaled",
+			live: `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"d1"},
+				"spec":{"replicas":5,"template":{"spec":{"containers":[{"name":"c1","image":"nginx:1.19"}]}}}}`,
+			expected: true,
+		},
+		{
+			label: "Live resource was scaled by an HPA",
+			live: `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"d1","managedFields":[
+				{"manager":"emco-rsync","operation":"Apply","fieldsV1":{"f:spec":{"f:template":{"f:spec":{"f:containers":{"k:{\"name\":\"c1\"}":{".":{},"f:image":{},"f:name":{}}}}}}}},
+				{"manager":"kube-controller-manager","operation":"Update","fieldsV1":{"f:spec":{"f:replicas":{}}}}]},
+				"spec":{"replicas":5,"template":{"spec":{"containers":[{"name":"c1","image":"nginx:1.19"}]}}}}`,
+			expected: false,
+		},
+		{
+			label: "Live resource has another image owned by rsync",
+			live: `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"d1","managedFields":[
+				{"manager":"emco-rsync","operation":"Apply","fieldsV1":{"f:spec":{"f:replicas":{},"f:template":{"f:spec":{"f:containers":{"k:{\"name\":\"c1\"}":{".":{},"f:image":{},"f:name":{}}}}}}}},
+				{"manager":"vpa-updater","operation":"Update","fieldsV1":{"f:spec":{"f:template":{"f:spec":{"f:containers":{"k:{\"name\":\"c1\"}":{"f:resources":{}}}}}}}}]},
+				"spec":{"replicas":2,"template":{"spec":{"containers":[{"name":"c1","image":"nginx:1.20","resources":{}}]}}}}`,
+			expected: true,
+		},
+		{
+			label: "Live resource has an image owned by another manager",
+			live: `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"d1","managedFields":[
+				{"manager":"emco-rsync","operation":"Apply","fieldsV1":{"f:spec":{"f:replicas":{},"f:template":{"f:spec":{"f:containers":{"k:{\"name\":\"c1\"}":{".":{},"f:name":{}}}}}}}},
+				{"manager":"kubectl-set","operation":"Update","fieldsV1":{"f:spec":{"f:template":{"f:spec":{"f:containers":{"k:{\"name\":\"c1\"}":{"f:image":{}}}}}}}}]},
+				"spec":{"replicas":2,"template":{"spec":{"containers":[{"name":"c1","image":"nginx:1.20"}]}}}}`,
+			expected: false,
+		},
+		{
+			label: "Live resource has an injected sidecar",
+			live: `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"d1"},
+				"spec":{"replicas":2,"template":{"spec":{"containers":[{"name":"istio-proxy","image":"istio/proxyv2"},{"name":"c1","image":"nginx:1.19"}]}}}}`,
+			expected: false,
+		},
+		{
+			label: "Live resource has another image",
+			live: `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"d1"},
+				"spec":{"replicas":2,"template":{"spec":{"containers":[{"name":"c1","image":"nginx:1.20"}]}}}}`,
+			expected: true,
+		},
+		{
+			label: "Live Secret has the string data encoded",
+			desired: `apiVersion: v1
+kind: Secret
+metadata:
+  name: s1
+stringData:
+  password: secret
+`,
+			live:     `{"apiVersion":"v1","kind":"Secret","metadata":{"name":"s1"},"data":{"password":"c2VjcmV0"},"type":"Opaque"}`,
+			expected: false,
+		},
+		{
+			label: "Live Secret has other string data",
+			desired: `apiVersion: v1
+kind: Secret
+metadata:
+  name: s1
+stringData:
+  password: secret
+`,
+			live:     `{"apiVersion":"v1","kind":"Secret","metadata":{"name":"s1"},"data":{"password":"b3RoZXI="},"type":"Opaque"}`,
+			expected: true,
+		},
+		{
+			label: "Live resource has the quantities in canonical form",
+			desired: `apiVersion: v1
+kind: Pod
+metadata:
+  name: p1
+spec:
+  containers:
+  - name: c1
+    image: nginx:1.19
+    resources:
+      limits:
+        cpu: 1
+      requests:
+        cpu: 0.5
+        memory: 1Gi
+`,
+			live: `{"apiVersion":"v1","kind":"Pod","metadata":{"name":"p1"},
+				"spec":{"containers":[{"name":"c1","image":"nginx:1.19","resources":{"limits":{"cpu":"1"},"requests":{"cpu":"500m","memory":"1Gi"}}}]}}`,
+			expected: false,
+		},
+		{
+			label:    "Live resource was deleted",
+			expected: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.label, func(t *testing.T) {
+			desired := testCase.desired
+			if desired == "" {
+				desired = driftTestDeployment
+			}
+			drifted, err := checkDrift(&liveClient{live: testCase.live}, []byte(desired), "default")
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+			if drifted != testCase.expected {
+				t.Errorf("checkDrift returned %v, expected %v", drifted, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestReconcileResource(t *testing.T) {
+	edb := new(contextdb.MockConDb)
+	edb.Err = nil
+	contextdb.Db = edb
+
+	ca := CompositeApp{
+		CompMetadata: appcontext.CompositeAppMeta{Project: "proj1", CompositeApp: "ca1", Version: "v1", Release: "r1",
+			DeploymentIntentGroup: "dig1", Namespace: "default", Level: "0"},
+		AppOrder: []string{"a1"},
+		Apps: map[string]*App{"a1": &App{Name: "a1", Clusters: map[string]*Cluster{"provider1+cluster1": &Cluster{
+			Name:      "provider1+cluster1",
+			Resources: map[string]*AppResource{"d1+Deployment": &AppResource{Name: "d1+Deployment", Data: driftTestDeployment}},
+			ResOrder:  []string{"d1+Deployment"},
+		}}}},
+	}
+	cid, err := CreateCompApp(ca)
+	if err != nil {
+		t.Fatalf("Error creating AppContext %v", err)
+	}
+	ac := appcontext.AppContext{}
+	if _, err := ac.LoadAppContext(cid); err != nil {
+		t.Fatalf("Error loading AppContext %v", err)
+	}
+	c := &Context{Lock: &sync.Mutex{}, acID: cid, ac: ac, statusAcID: cid, sc: ac}
+	status := func() string {
+		return (&AppContextUtils{ac: ac}).GetResourceStatus("d1+Deployment", "a1", "provider1+cluster1")
+	}
+	c.updateResourceStatus(context.Background(), "d1+Deployment", "a1", "provider1+cluster1",
+		resourcestatus.ResourceStatus{Status: resourcestatus.RsyncStatusEnum.Applied})
+
+	// Drift is only reported in detect mode
+	cl := &liveClient{}
+	c.reconcileResource(cl, appcontext.ReconcileModeEnum.Detect, "default", "a1", "provider1+cluster1", "d1+Deployment")
+	if status() != resourcestatus.RsyncStatusEnum.Drifted || len(cl.applied) != 0 {
+		t.Fatalf("Expected drift to be reported only, status %s, applied %v", status(), cl.applied)
+	}
+
+	// The resource is applied again in enforce mode
+	c.reconcileResource(cl, appcontext.ReconcileModeEnum.Enforce, "default", "a1", "provider1+cluster1", "d1+Deployment")
+	if status() != resourcestatus.RsyncStatusEnum.Applied || len(cl.applied) != 1 {
+		t.Fatalf("Expected resource to be applied again, status %s, applied %v", status(), cl.applied)
+	}
+
+	// Resources rsync didn't apply are not checked
+	c.updateResourceStatus(context.Background(), "d1+Deployment", "a1", "provider1+cluster1",
+		resourcestatus.ResourceStatus{Status: resourcestatus.RsyncStatusEnum.Deleted})
+	c.reconcileResource(cl, appcontext.ReconcileModeEnum.Enforce, "default", "a1", "provider1+cluster1", "d1+Deployment")
+	if status() != resourcestatus.RsyncStatusEnum.Deleted || len(cl.applied) != 1 {
+		t.Errorf("Expected deleted resource to be ignored, status %s, applied %v", status(), cl.applied)
+	}
+}
+
+func TestReconcileContextRecords(t *testing.T) {
+	edb := new(contextdb.MockConDb)
+	edb.Err = nil
+	contextdb.Db = edb
+
+	if err := recordReconcileContext("1234"); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if err := recordReconcileContext("5678"); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	deleteReconcileContext("1234")
+	if acIDs := getAllReconcileContext(); len(acIDs) != 1 || acIDs[0] != "5678" {
+		t.Errorf("Unexpected reconcile contexts %v", acIDs)
+	}
+}
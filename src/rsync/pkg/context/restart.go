@@ -19,6 +19,10 @@ import (
 
 const prefix string = "/activecontext/"
 
+// reconcilePrefix is the prefix of the AppContexts whose resources are
+// checked for drift
+const reconcilePrefix string = "/reconcilecontext/"
+
 
 // RecordActiveContext shall insert into contextDB a key and value like /activecontext/99999999888/->99999999888. 99999999888 is sample AppcontextID
 // It shall take in activeContextID
@@ -58,6 +62,35 @@ func GetAllActiveContext() ([]string, error){
 	return aCtxIDs, nil
 }
 
+// recordReconcileContext records that the resources of the AppContext are
+// checked for drift
+func recordReconcileContext(acID string) error {
+	return contextdb.Db.Put(reconcilePrefix+acID+"/", acID)
+}
+
+// deleteReconcileContext deletes the record of an AppContext no longer checked for drift
+func deleteReconcileContext(acID string) {
+	if err := contextdb.Db.Delete(reconcilePrefix + acID + "/"); err != nil {
+		logutils.Info("Error deleting the reconcile contextID", logutils.Fields{"acID": acID, "Error": err.Error()})
+	}
+}
+
+// getAllReconcileContext returns the contextIDs whose resources are checked for drift
+func getAllReconcileContext() []string {
+	keys, err := contextdb.Db.GetAllKeys(reconcilePrefix)
+	if err != nil {
+		return nil
+	}
+	var acIDs []string
+	for _, k := range keys {
+		key := strings.Split(k, "/")
+		if len(key) == 4 && key[1] == "reconcilecontext" {
+			acIDs = append(acIDs, key[2])
+		}
+	}
+	return acIDs
+}
+
 
 // ifContextIDActive takes in a contextID and checks if its active or not
 func ifContextIDActive(acID string) (bool, error){
@@ -93,6 +126,10 @@ func DeleteActiveContextRecord(acID string) (bool, error) {
 // RestoreActiveContext shall be called everytime the rsync restarts.
 // It makes sure that the AppContexts which were in active state before rsync
 // got cancelled, are restored and queued up again for processing.
+// The parents and children of the AppContexts are kept in their
+// CompositeAppMeta. The events a parent cascades to its children are queued
+// before the event of the parent is done, so a restored parent cascades them
+// again if they were lost.
 func RestoreActiveContext() error {
 	logutils.Info("Restoring active context....", logutils.Fields{});
 	acIDs, err := GetAllActiveContext()
@@ -116,5 +153,18 @@ func RestoreActiveContext() error {
 			return nil
 		}
 	}
+
+	// The AppContexts checked for drift start checking again once their
+	// queue is processed, like after an event
+	for _, acID := range getAllReconcileContext() {
+		con := connector.Connection{}
+		if err := con.Init(acID); err != nil {
+			logutils.Info("Error in restoring reconcile contextID while instantiating connector", logutils.Fields{"acID": acID, "Error": err})
+			continue
+		}
+		if err := RestartAppContext(acID, &con); err != nil {
+			logutils.Info("Error in restoring reconcile contextID", logutils.Fields{"acID": acID, "Error": err})
+		}
+	}
 	return nil
 }
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package context
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
+	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	. "github.com/open-ness/EMCO/src/rsync/pkg/types"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// defaultBackoffFactor multiplies the wait between the checks of an
+// unreachable cluster if the retry policy has a max backoff
+const defaultBackoffFactor = 2
+
+// backoff returns the wait before the check retry of an unreachable cluster.
+// The wait starts at the initial backoff of the retry policy, or the wait time
+// of rsync, and grows up to the max backoff of the retry policy.
+func (c *Context) backoff(retry int) time.Duration {
+	initial := float64(c.waitTime)
+	if c.retry.InitialBackoff > 0 {
+		initial = float64(c.retry.InitialBackoff)
+	}
+	max := float64(c.retry.MaxBackoff)
+	if max <= initial {
+		return time.Duration(initial * float64(time.Second))
+	}
+	factor := c.retry.BackoffFactor
+	if factor < 1 {
+		factor = defaultBackoffFactor
+	}
+	wait := math.Min(initial*math.Pow(factor, float64(retry)), max)
+	return time.Duration(wait * float64(time.Second))
+}
+
+// withApplyTimeout returns an error if apply doesn't return within the apply
+// timeout of the retry policy. The context of the apply is cancelled then.
+func (c *Context) withApplyTimeout(ctx context.Context, name string, apply func(context.Context) error) error {
+	if c.retry.ApplyTimeout <= 0 {
+		return apply(ctx)
+	}
+	actx, cancel := context.WithTimeout(ctx, time.Duration(c.retry.ApplyTimeout)*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- apply(actx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-actx.Done():
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return pkgerrors.Errorf("Apply timed out after %d seconds: %s", c.retry.ApplyTimeout, name)
+	}
+}
+
+// applyResource applies the resource with the client, which stops when the
+// context is done if it can
+func applyResource(ctx context.Context, cl ClientProvider, b []byte, force bool) error {
+	if a, ok := cl.(ContextApplier); ok {
+		return a.ApplyContext(ctx, b, force)
+	}
+	if f, ok := cl.(ForceApplier); ok && force {
+		return f.ForceApply(b)
+	}
+	return cl.Apply(b)
+}
+
+// clusterFailed returns the error of a cluster, or nil if the retry policy
+// continues with the other clusters when a cluster fails. The error of the
+// cluster is recorded in its status then.
+func (c *Context) clusterFailed(ctx context.Context, app, cluster string, err error) error {
+	if ctx.Err() != nil || c.retry.OnClusterFailure != appcontext.ClusterFailureActionEnum.Continue {
+		return err
+	}
+	utils := &AppContextUtils{ac: c.ac.WithContext(ctx)}
+	if err == nil {
+		// Clears the failure of an earlier event
+		utils.SetClusterFailure(app, cluster, "")
+		return nil
+	}
+	log.Error("Cluster failed, continuing with the other clusters", log.Fields{"app": app, "cluster": cluster, "error": err})
+	utils.SetClusterFailure(app, cluster, err.Error())
+	return nil
+}
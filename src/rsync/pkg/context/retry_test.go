@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package context
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/contextdb"
+	pkgerrors "github.com/pkg/errors"
+)
+
+func TestBackoff(t *testing.T) {
+	testCases := []struct {
+		label    string
+		retry    appcontext.RetryPolicy
+		expected []int
+	}{
+		{label: "Wait time of rsync", expected: []int{2, 2, 2, 2}},
+		{label: "Initial backoff", retry: appcontext.RetryPolicy{InitialBackoff: 5}, expected: []int{5, 5, 5, 5}},
+		{label: "Default factor", retry: appcontext.RetryPolicy{InitialBackoff: 1, MaxBackoff: 10}, expected: []int{1, 2, 4, 8, 10, 10}},
+		{label: "Backoff factor", retry: appcontext.RetryPolicy{InitialBackoff: 1, MaxBackoff: 10, BackoffFactor: 3}, expected: []int{1, 3, 9, 10}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.label, func(t *testing.T) {
+			c := &Context{waitTime: 2, retry: tc.retry}
+			for i, e := range tc.expected {
+				if d := c.backoff(i); d != time.Duration(e)*time.Second {
+					t.Errorf("Unexpected backoff of retry %d: got %v; expected %ds", i, d, e)
+				}
+			}
+		})
+	}
+}
+
+func TestWithApplyTimeout(t *testing.T) {
+	c := &Context{retry: appcontext.RetryPolicy{ApplyTimeout: 1}}
+	cancelled := make(chan error, 1)
+	err := c.withApplyTimeout(context.Background(), "r1", func(ctx context.Context) error {
+		<-ctx.Done()
+		cancelled <- ctx.Err()
+		return ctx.Err()
+	})
+	if err == nil || !strings.Contains(err.Error(), "Apply timed out") {
+		t.Errorf("Expected the apply to time out, got %v", err)
+	}
+	select {
+	case err := <-cancelled:
+		if err != context.DeadlineExceeded {
+			t.Errorf("Unexpected error of the context of the apply %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Context of the apply not cancelled on timeout")
+	}
+	applyErr := pkgerrors.New("apply failed")
+	if err := c.withApplyTimeout(context.Background(), "r1", func(context.Context) error { return applyErr }); err != applyErr {
+		t.Errorf("Expected the error of the apply, got %v", err)
+	}
+}
+
+// unreachableClient is a cluster client that is never reachable
+type unreachableClient struct {
+	MockClient
+}
+
+func (u *unreachableClient) IsReachable() error {
+	return pkgerrors.New("Unreachable")
+}
+
+func TestMaxUnreachableTime(t *testing.T) {
+	edb := new(contextdb.MockConDb)
+	edb.Err = nil
+	contextdb.Db = edb
+
+	cid, err := CreateCompApp(rolloutTestCA())
+	if err != nil {
+		t.Fatalf("Error creating AppContext %v", err)
+	}
+	ac := appcontext.AppContext{}
+	if _, err := ac.LoadAppContext(cid); err != nil {
+		t.Fatalf("Error loading AppContext %v", err)
+	}
+	// The max unreachable time replaces the unlimited retries
+	c := &Context{acID: cid, ac: ac, waitTime: 1, maxRetry: -1,
+		retry: appcontext.RetryPolicy{MaxUnreachableTime: 2}}
+	start := time.Now()
+	err = c.waitForClusterReady(context.Background(), &unreachableClient{}, "a1", "provider1+cluster1")
+	if err == nil || !strings.Contains(err.Error(), "unreachable for more than 2 seconds") {
+		t.Errorf("Expected the cluster to fail, got %v", err)
+	}
+	if time.Since(start) > 4*time.Second {
+		t.Errorf("The cluster failed after %v", time.Since(start))
+	}
+}
+
+func TestClusterFailed(t *testing.T) {
+	edb := new(contextdb.MockConDb)
+	edb.Err = nil
+	contextdb.Db = edb
+
+	cid, err := CreateCompApp(rolloutTestCA())
+	if err != nil {
+		t.Fatalf("Error creating AppContext %v", err)
+	}
+	ac := appcontext.AppContext{}
+	if _, err := ac.LoadAppContext(cid); err != nil {
+		t.Fatalf("Error loading AppContext %v", err)
+	}
+	failure := func() interface{} {
+		ch, _ := ac.GetClusterHandle("a1", "provider1+cluster1")
+		fh, _ := ac.GetLevelHandle(ch, appcontext.ClusterFailureKey)
+		if fh == nil {
+			return nil
+		}
+		v, _ := ac.GetValue(fh)
+		return v
+	}
+
+	clusterErr := pkgerrors.New("cluster failed")
+	c := &Context{acID: cid, ac: ac}
+	if err := c.clusterFailed(context.Background(), "a1", "provider1+cluster1", clusterErr); err != clusterErr {
+		t.Errorf("Expected the cluster to fail the AppContext, got %v", err)
+	}
+	if f := failure(); f != nil {
+		t.Errorf("Unexpected failure recorded for a cluster failing the AppContext %v", f)
+	}
+	c.retry.OnClusterFailure = appcontext.ClusterFailureActionEnum.Continue
+	if err := c.clusterFailed(context.Background(), "a1", "provider1+cluster1", clusterErr); err != nil {
+		t.Errorf("Expected to continue with the other clusters, got %v", err)
+	}
+	if f := failure(); f != clusterErr.Error() {
+		t.Errorf("Expected the error of the cluster to be recorded, got %v", f)
+	}
+	// A later success clears the failure
+	if err := c.clusterFailed(context.Background(), "a1", "provider1+cluster1", nil); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if f := failure(); f != "" {
+		t.Errorf("Expected the failure of the cluster to be cleared, got %v", f)
+	}
+}
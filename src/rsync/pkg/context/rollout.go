@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package context
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
+	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	. "github.com/open-ness/EMCO/src/rsync/pkg/types"
+	pkgerrors "github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+// Seconds to wait for the clusters of a wave to be ready if not set in the rollout
+const defaultRolloutReadyTimeout = 600
+
+// runWaves handles the clusters of the AppContext one wave at a time. A wave is
+// only started once all the apps on the clusters of the previous wave are ready.
+// The rollout stops at the first wave that fails or isn't ready in time.
+// Resources are deleted in the reverse order of the waves, without waiting.
+func (c *Context) runWaves(ctx context.Context, op RsyncOperation) error {
+	waves := c.getRolloutWaves()
+	defer func() { c.wave = nil }()
+	if op == OpDelete {
+		for i, j := 0, len(waves)-1; i < j; i, j = i+1, j-1 {
+			waves[i], waves[j] = waves[j], waves[i]
+		}
+	}
+
+	for i, wave := range waves {
+		log.Info("Rolling out wave", log.Fields{"context": c.acID, "wave": i, "clusters": wave})
+		c.updateRolloutStatus(i, len(waves), appcontext.RolloutStatusEnum.InProgress, "")
+		c.wave = make(map[string]bool)
+		for _, cluster := range wave {
+			c.wave[cluster] = true
+		}
+
+		g, gctx := errgroup.WithContext(ctx)
+		g.Go(func() error {
+			return c.run(gctx, g, op)
+		})
+		if err := g.Wait(); err != nil {
+			c.updateRolloutStatus(i, len(waves), appcontext.RolloutStatusEnum.Failed, err.Error())
+			return err
+		}
+
+		if op == OpDelete {
+			log.Info("Wave deleted", log.Fields{"context": c.acID, "wave": i})
+			continue
+		}
+		if err := c.waitForWaveReady(ctx); err != nil {
+			err = pkgerrors.Wrapf(err, "Clusters of wave %d not ready", i)
+			c.updateRolloutStatus(i, len(waves), appcontext.RolloutStatusEnum.Failed, err.Error())
+			return err
+		}
+		log.Info("Wave ready", log.Fields{"context": c.acID, "wave": i})
+	}
+	c.updateRolloutStatus(len(waves)-1, len(waves), appcontext.RolloutStatusEnum.Completed, "")
+	return nil
+}
+
+// getRolloutWaves returns the waves of the rollout restricted to the clusters of
+// the AppContext. Clusters not part of any wave are rolled out in a last wave.
+func (c *Context) getRolloutWaves() [][]string {
+	clusters := make(map[string]bool)
+	for _, app := range c.ca.Apps {
+		for cluster := range app.Clusters {
+			clusters[cluster] = true
+		}
+	}
+
+	var waves [][]string
+	for _, w := range c.rollout.Waves {
+		var wave []string
+		for _, cluster := range w {
+			if clusters[cluster] {
+				wave = append(wave, cluster)
+				delete(clusters, cluster)
+			}
+		}
+		if len(wave) > 0 {
+			waves = append(waves, wave)
+		}
+	}
+
+	if len(clusters) > 0 {
+		var wave []string
+		for cluster := range clusters {
+			wave = append(wave, cluster)
+		}
+		sort.Strings(wave)
+		waves = append(waves, wave)
+	}
+	return waves
+}
+
+// waitForWaveReady blocks until all the apps are ready on the clusters of the wave
+func (c *Context) waitForWaveReady(ctx context.Context) error {
+	timeout := c.rollout.ReadyTimeout
+	if timeout <= 0 {
+		timeout = defaultRolloutReadyTimeout
+	}
+	wctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	return c.waitFor(wctx, func() bool {
+		for _, app := range c.ca.AppOrder {
+			if !c.isAppReady(app) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// inWave checks whether the cluster is part of the wave being rolled out
+func (c *Context) inWave(cluster string) bool {
+	return c.wave == nil || c.wave[cluster]
+}
+
+func (c *Context) updateRolloutStatus(wave, waves int, status appcontext.StatusValue, msg string) {
+	utils := &AppContextUtils{ac: c.ac}
+	rs := appcontext.RolloutStatus{Wave: wave, Waves: waves, Status: status, Message: msg}
+	if err := utils.UpdateAppContextStatus(appcontext.RolloutStatusKey, rs); err != nil {
+		log.Error("Error updating rollout status", log.Fields{"context": c.acID, "error": err})
+	}
+}
+
+// rollbackKey marks the context of a rollback request
+type rollbackKey struct{}
+
+// withRollback marks the events queued with the context as rollbacks
+func withRollback(ctx context.Context) context.Context {
+	return context.WithValue(ctx, rollbackKey{}, true)
+}
+
+func isRollback(ctx context.Context) bool {
+	rollback, _ := ctx.Value(rollbackKey{}).(bool)
+	return rollback
+}
+
+// rollsBackFailedWave checks whether the event rolls back an update that
+// stopped at a failed wave. The AppContext updated to is left InstantiateFailed
+// then, which other updates aren't allowed from.
+func (c *Context) rollsBackFailedWave(ele AppContextQueueElement, status appcontext.StatusValue) bool {
+	if ele.Event != UpdateEvent || !ele.Rollback || status != appcontext.AppContextStatusEnum.InstantiateFailed {
+		return false
+	}
+	utils := &AppContextUtils{ac: c.ac}
+	rs, err := utils.GetAppContextStatus(appcontext.RolloutStatusKey)
+	return err == nil && rs.Status == appcontext.RolloutStatusEnum.Failed
+}
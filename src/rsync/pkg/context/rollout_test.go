@@ -0,0 +1,205 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package context
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/contextdb"
+	. "github.com/open-ness/EMCO/src/rsync/pkg/types"
+)
+
+func rolloutTestCA() CompositeApp {
+	clusters := map[string]*Cluster{}
+	for _, name := range []string{"provider1+cluster1", "provider1+cluster2", "provider1+cluster3"} {
+		clusters[name] = &Cluster{
+			Name:      name,
+			Resources: map[string]*AppResource{"r1": &AppResource{Name: "r1", Data: "a1r1"}},
+			ResOrder:  []string{"r1"},
+		}
+	}
+	return CompositeApp{
+		CompMetadata: appcontext.CompositeAppMeta{Project: "proj1", CompositeApp: "ca1", Version: "v1", Release: "r1",
+			DeploymentIntentGroup: "dig1", Namespace: "default", Level: "0"},
+		AppOrder: []string{"a1"},
+		Apps:     map[string]*App{"a1": &App{Name: "a1", Clusters: clusters}},
+	}
+}
+
+func TestRolloutWaves(t *testing.T) {
+	c := &Context{ca: rolloutTestCA()}
+	c.rollout = appcontext.Rollout{Waves: [][]string{{"provider1+cluster2", "provider2+cluster1"}, {}, {"provider1+cluster1"}}}
+	expected := [][]string{{"provider1+cluster2"}, {"provider1+cluster1"}, {"provider1+cluster3"}}
+	if waves := c.getRolloutWaves(); !reflect.DeepEqual(waves, expected) {
+		t.Errorf("Unexpected waves: got %v; expected %v", waves, expected)
+	}
+}
+
+func TestRunWaves(t *testing.T) {
+	edb := new(contextdb.MockConDb)
+	edb.Err = nil
+	contextdb.Db = edb
+
+	cid, err := CreateCompApp(rolloutTestCA())
+	if err != nil {
+		t.Fatalf("Error creating AppContext %v", err)
+	}
+	ca, err := ReadAppContext(cid)
+	if err != nil {
+		t.Fatalf("Error reading AppContext %v", err)
+	}
+	ac := appcontext.AppContext{}
+	if _, err := ac.LoadAppContext(cid); err != nil {
+		t.Fatalf("Error loading AppContext %v", err)
+	}
+	con := MockConnector{}
+	con.Init(cid)
+	c := &Context{Lock: &sync.Mutex{}, acID: cid, ac: ac, statusAcID: cid, sc: ac, con: &con, waitTime: 1, ca: ca,
+		rollout: appcontext.Rollout{Waves: [][]string{{"provider1+cluster1"}}, ReadyTimeout: 2}}
+
+	// The first wave never gets ready, the other clusters are left untouched
+	if err := c.runWaves(context.Background(), OpApply); err == nil {
+		t.Fatal("Expected rollout to fail")
+	}
+	applied := LoadMap("apply")
+	if len(applied) != 1 || applied["provider1+cluster1"] != "a1r1" {
+		t.Fatalf("Unexpected clusters applied %v", applied)
+	}
+	utils := &AppContextUtils{ac: ac}
+	rs, _ := utils.GetAppContextStatus(appcontext.RolloutStatusKey)
+	if rs.Status != appcontext.RolloutStatusEnum.Failed {
+		t.Errorf("Expected rollout status Failed, got %v", rs)
+	}
+
+	// Once the clusters report ready the rollout completes
+	for cluster := range ca.Apps["a1"].Clusters {
+		ch, _ := ac.GetClusterHandle("a1", cluster)
+		if _, err := ac.AddLevelValue(ch, "status", "{\"ready\":true}"); err != nil {
+			t.Fatalf("Error adding cluster status %v", err)
+		}
+	}
+	if err := c.runWaves(context.Background(), OpApply); err != nil {
+		t.Fatalf("Unexpected rollout error %v", err)
+	}
+	if applied := LoadMap("apply"); len(applied) != 3 {
+		t.Errorf("Expected all clusters to be applied %v", applied)
+	}
+	rs, _ = utils.GetAppContextStatus(appcontext.RolloutStatusKey)
+	if rs.Status != appcontext.RolloutStatusEnum.Completed {
+		t.Errorf("Expected rollout status Completed, got %v", rs)
+	}
+}
+
+// deleteOrderConnector records the clusters its clients delete resources from
+type deleteOrderConnector struct {
+	MockConnector
+	lock     sync.Mutex
+	clusters []string
+}
+
+func (c *deleteOrderConnector) GetClientInternal(cluster string, level string, namespace string) (ClientProvider, error) {
+	cl, err := c.MockConnector.GetClientInternal(cluster, level, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return &deleteOrderClient{ClientProvider: cl, cluster: cluster, con: c}, nil
+}
+
+type deleteOrderClient struct {
+	ClientProvider
+	cluster string
+	con     *deleteOrderConnector
+}
+
+func (c *deleteOrderClient) Delete(content []byte) error {
+	// The status tracker is deleted with no content by the mock
+	if len(content) > 0 {
+		c.con.lock.Lock()
+		c.con.clusters = append(c.con.clusters, c.cluster)
+		c.con.lock.Unlock()
+	}
+	return c.ClientProvider.Delete(content)
+}
+
+func TestDeleteWaves(t *testing.T) {
+	edb := new(contextdb.MockConDb)
+	edb.Err = nil
+	contextdb.Db = edb
+
+	cid, err := CreateCompApp(rolloutTestCA())
+	if err != nil {
+		t.Fatalf("Error creating AppContext %v", err)
+	}
+	ca, err := ReadAppContext(cid)
+	if err != nil {
+		t.Fatalf("Error reading AppContext %v", err)
+	}
+	ac := appcontext.AppContext{}
+	if _, err := ac.LoadAppContext(cid); err != nil {
+		t.Fatalf("Error loading AppContext %v", err)
+	}
+	con := deleteOrderConnector{}
+	con.Init(cid)
+	for cluster := range ca.Apps["a1"].Clusters {
+		cl, _ := con.MockConnector.GetClientInternal(cluster, "0", "default")
+		cl.Apply([]byte("a1r1"))
+	}
+	c := &Context{Lock: &sync.Mutex{}, acID: cid, ac: ac, statusAcID: cid, sc: ac, con: &con, waitTime: 1, ca: ca,
+		rollout: appcontext.Rollout{Waves: [][]string{{"provider1+cluster2"}, {"provider1+cluster1"}}}}
+
+	// The clusters of the last wave are deleted first, without waiting for them
+	if err := c.runWaves(context.Background(), OpDelete); err != nil {
+		t.Fatalf("Unexpected rollout error %v", err)
+	}
+	expected := []string{"provider1+cluster3", "provider1+cluster1", "provider1+cluster2"}
+	if !reflect.DeepEqual(con.clusters, expected) {
+		t.Errorf("Unexpected delete order: got %v; expected %v", con.clusters, expected)
+	}
+}
+
+func TestRollbackFailedWave(t *testing.T) {
+	edb := new(contextdb.MockConDb)
+	edb.Err = nil
+	contextdb.Db = edb
+
+	cid, err := CreateCompApp(rolloutTestCA())
+	if err != nil {
+		t.Fatalf("Error creating AppContext %v", err)
+	}
+	ac := appcontext.AppContext{}
+	if _, err := ac.LoadAppContext(cid); err != nil {
+		t.Fatalf("Error loading AppContext %v", err)
+	}
+	c := &Context{Lock: &sync.Mutex{}, acID: cid, ac: ac}
+	utils := &AppContextUtils{ac: ac}
+	utils.UpdateAppContextFlag(StopFlagKey, false)
+	utils.UpdateAppContextFlag(PendingTerminateFlagKey, false)
+	update := AppContextQueueElement{Event: UpdateEvent, UCID: "1"}
+	rollback := AppContextQueueElement{Event: UpdateEvent, UCID: "1", Rollback: true}
+	failed := func(rollout appcontext.StatusValue) {
+		utils.UpdateAppContextStatus(CurrentStateKey, appcontext.AppContextStatus{Status: appcontext.AppContextStatusEnum.InstantiateFailed})
+		utils.UpdateAppContextStatus(appcontext.RolloutStatusKey, appcontext.RolloutStatus{Status: rollout})
+	}
+
+	// A failed AppContext is only rolled back if a wave failed
+	failed(appcontext.RolloutStatusEnum.Completed)
+	if _, err := c.checkStateChange(rollback); err == nil {
+		t.Errorf("Expected rollback of a failed AppContext without failed wave to be invalid")
+	}
+	failed(appcontext.RolloutStatusEnum.Failed)
+	if _, err := c.checkStateChange(update); err == nil {
+		t.Errorf("Expected update of a failed AppContext to be invalid")
+	}
+	if _, err := c.checkStateChange(rollback); err != nil {
+		t.Errorf("Unexpected error rolling back a failed wave %v", err)
+	}
+
+	if !isRollback(withRollback(context.Background())) || isRollback(context.Background()) {
+		t.Errorf("Rollback not carried by the context")
+	}
+}
@@ -6,20 +6,24 @@ package context
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
 	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	"github.com/open-ness/EMCO/src/orchestrator/pkg/infra/tracing"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/resourcestatus"
 	. "github.com/open-ness/EMCO/src/rsync/pkg/types"
 	pkgerrors "github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/sync/errgroup"
 )
 
 // Check status of AppContext against the event to see if it is valid
-func (c *Context) checkStateChange(e RsyncEvent) (StateChange, error) {
+func (c *Context) checkStateChange(ele AppContextQueueElement) (StateChange, error) {
+	e := ele.Event
 	var supported bool = false
 	var err error
 	var dState, cState appcontext.AppContextStatus
@@ -58,6 +62,9 @@ func (c *Context) checkStateChange(e RsyncEvent) (StateChange, error) {
 			break
 		}
 	}
+	if !supported && c.rollsBackFailedWave(ele, state.Status) {
+		supported = true
+	}
 	if !supported {
 		return StateChange{}, pkgerrors.Errorf("Invalid Source state %s for the Event %s:", state, e)
 	} else {
@@ -126,6 +133,9 @@ func (c *Context) startMainThread(a interface{}, con Connector) error {
 	// Wait for 2 secs
 	c.waitTime = 2
 	c.maxRetry = getMaxRetries()
+	// The retry policy is optional
+	c.retry, _ = utils.GetAppContextRetryPolicy()
+	c.forceApply = utils.GetForceApply()
 	// Check flags in AppContext to create if they don't exist and add default values
 	_, err = utils.GetAppContextStatus(CurrentStateKey)
 	// If CurrentStateKey doesn't exist assuming this is the very first event for the appcontext
@@ -217,11 +227,28 @@ func (c *Context) appContextRoutine() {
 	for {
 		// Get first event to process
 		c.Lock.Lock()
+		updateQueueDepth(c.acID, qUtils)
 		index, ele := qUtils.FindFirstPending()
 		if index >= 0 {
 			c.Lock.Unlock()
 			e := ele.Event
-			state, err := c.checkStateChange(e)
+			// Adding a child doesn't change the state of the AppContext
+			if e == AddChildContextEvent {
+				ectx, span := tracing.Start(tracing.Extract(ctx, ele.TraceContext), "rsync "+string(e),
+					attribute.String("appContext", c.acID))
+				err := c.addChildContext(ectx, ele.UCID)
+				tracing.End(span, err)
+				qStatus := "Done"
+				if err != nil {
+					log.Error("Error adding child context", log.Fields{"error": err, "child": ele.UCID})
+					qStatus = "Error"
+				}
+				if err := c.UpdateQStatus(index, qStatus); err != nil {
+					break
+				}
+				continue
+			}
+			state, err := c.checkStateChange(ele)
 			// Event is not valid event for the current state of AppContext
 			if err != nil {
 				log.Error("State Change Error", log.Fields{"error": err})
@@ -241,17 +268,26 @@ func (c *Context) appContextRoutine() {
 				// Continue to process more events
 				continue
 			}
+			// The event continues the trace of the request that queued it
+			ectx, span := tracing.Start(tracing.Extract(ctx, ele.TraceContext), "rsync "+string(e),
+				attribute.String("appContext", c.acID))
 			// Create a derived context
-			l, lDone = context.WithCancel(ctx)
+			l, lDone = context.WithCancel(ectx)
 			lGroup, lctx = errgroup.WithContext(l)
 			c.Lock.Lock()
 			c.cancel = lDone
 			c.Lock.Unlock()
+			eutils := &AppContextUtils{ac: c.ac.WithContext(ectx)}
+			// Rollout waves are optional and only apply to updates and terminate
+			c.rollout = appcontext.Rollout{}
 			switch e {
 			case InstantiateEvent:
 				op = OpApply
 			case TerminateEvent:
 				op = OpDelete
+				// Terminate the children with the AppContext
+				c.cascadeToChildren(ectx, TerminateEvent)
+				c.rollout, _ = eutils.GetAppContextRollout()
 			case ReadEvent:
 				op = OpRead
 			case UpdateEvent:
@@ -260,8 +296,18 @@ func (c *Context) appContextRoutine() {
 					break
 				}
 				op = OpDelete
-				// Enqueue Modify Phase for the AppContext that is being updated to
-				go HandleAppContext(ele.UCID, c.acID, UpdateModifyEvent, c.con)
+				// The waves of the update are in the AppContext being updated to
+				uac := appcontext.AppContext{}
+				if _, err := uac.LoadAppContext(ele.UCID); err == nil {
+					uutils := &AppContextUtils{ac: uac.WithContext(ectx)}
+					c.rollout, _ = uutils.GetAppContextRollout()
+				}
+				// Enqueue Modify Phase for the AppContext that is being updated to,
+				// followed by the children moving to it
+				go func() {
+					handleAppContext(ectx, ele.UCID, c.acID, UpdateModifyEvent, c.con)
+					c.moveChildContexts(ectx, ele.UCID)
+				}()
 			case UpdateModifyEvent:
 				// In Modify Phase find out resources that need to be modified and
 				// set skip to be true for those that match
@@ -269,21 +315,24 @@ func (c *Context) appContextRoutine() {
 				if err := c.updateModifyPhase(ele); err != nil {
 					break
 				}
+				c.rollout, _ = eutils.GetAppContextRollout()
 				op = OpApply
-			case AddChildContextEvent:
-				log.Error("Not Implemented", log.Fields{"event": e})
-				if err := c.UpdateQStatus(index, "Skip"); err != nil {
-					break
-				}
-				continue
 			}
 			lGroup.Go(func() error {
+				if len(c.rollout.Waves) > 0 {
+					return c.runWaves(lctx, op)
+				}
 				return c.run(lctx, lGroup, op)
 			})
 			// Wait for all subtasks to complete
 			log.Info("Wait for all subtasks to complete", log.Fields{})
-			if err := lGroup.Wait(); err != nil {
+			err = lGroup.Wait()
+			tracing.End(span, err)
+			// Skip bits only apply to this event
+			c.clearSkip()
+			if err != nil {
 				log.Error("Failed run", log.Fields{"error": err})
+				c.publishAppContextStatus(state.ErrState, err)
 				// Mark the event in Queue
 				if err := c.UpdateQStatus(index, "Error"); err != nil {
 					break
@@ -301,6 +350,11 @@ func (c *Context) appContextRoutine() {
 				continue
 			}
 			log.Info("Success all subtasks completed", log.Fields{})
+			// Instantiate the children with the AppContext, before the event
+			// is done so a restart cascades it again
+			if e == InstantiateEvent {
+				c.cascadeToChildren(ectx, InstantiateEvent)
+			}
 			// Mark the event in Queue
 			if err := c.UpdateQStatus(index, "Done"); err != nil {
 				break
@@ -310,6 +364,14 @@ func (c *Context) appContextRoutine() {
 			ds, _ := utils.GetAppContextStatus(DesiredStateKey)
 			err = utils.UpdateAppContextStatus(StatusKey, ds)
 			err = utils.UpdateAppContextStatus(CurrentStateKey, ds)
+			c.publishAppContextStatus(ds.Status, nil)
+			// Look for resources left behind on the clusters
+			switch e {
+			case InstantiateEvent:
+				c.prune(ectx, "")
+			case UpdateModifyEvent:
+				c.prune(ectx, ele.UCID)
+			}
 
 		} else {
 			// Done Processing all elements in queue
@@ -322,6 +384,9 @@ func (c *Context) appContextRoutine() {
 				log.Info("Deleting activeContextID failed", log.Fields{"context": c.acID, "error": err})
 			}
 			c.Running = false
+			queueDepth.DeleteLabelValues(c.acID)
+			// Check the resources for drift once instantiated
+			c.startReconcile()
 			c.Lock.Unlock()
 			return
 		}
@@ -336,6 +401,7 @@ func (c *Context) appContextRoutine() {
 		log.Info("Deleting activeContextID failed", log.Fields{"context": c.acID, "error": err})
 	}
 	c.Running = false
+	queueDepth.DeleteLabelValues(c.acID)
 	c.Lock.Unlock()
 }
 
@@ -443,39 +509,54 @@ func (c *Context) updateModifyPhase(e AppContextQueueElement) error {
 
 // Iterate over the appcontext to apply/delete/read resources
 func (c *Context) run(ctx context.Context, g *errgroup.Group, op RsyncOperation) error {
+	// Build the app dependency graph, apps with dependencies wait for them to complete
+	dep, err := newAppDependency(c.ca, op)
+	if err != nil {
+		log.Error("Error in app dependency", log.Fields{"error": err})
+		return err
+	}
 	// Iterate over all the subapps and start go Routines per app
 	for _, a := range c.ca.AppOrder {
 		app := a
 		// If marked to skip then no processing needed
 		if c.ca.Apps[app].Skip {
 			log.Info("Update Skipping App::", log.Fields{"App": app})
-			// Reset bit and skip app
-			t := c.ca.Apps[app]
-			t.Skip = false
+			dep.markDone(app)
 			continue
 		}
-		// TODO - this code should be removed once support for App Dependency
-		// instructions which allow a time based delay before applying are supported.
-		if app == "network-chain-intents" && op == OpApply {
-			log.Info("Chain App - sleep for 100 seconds::", log.Fields{"App": app})
-			time.Sleep(100 * time.Second)
-			log.Info("Chain App - done sleeping for 100 seconds::", log.Fields{"App": app})
-		}
 		g.Go(func() error {
-			return c.runApp(ctx, g, op, app)
+			if op != OpRead {
+				if err := c.waitForAppDependency(ctx, dep, app); err != nil {
+					dep.markFailed(app, err)
+					return err
+				}
+			}
+			actx, span := tracing.Start(ctx, "runApp", attribute.String("app", app))
+			err := c.runApp(actx, g, op, app)
+			tracing.End(span, err)
+			if err != nil {
+				dep.markFailed(app, err)
+				return err
+			}
+			dep.markDone(app)
+			return nil
 		})
 	}
 	return nil
 }
 
+// runApp handles all the clusters of an app and returns once all the clusters are done
 func (c *Context) runApp(ctx context.Context, g *errgroup.Group, op RsyncOperation, app string) error {
+	appGroup, actx := errgroup.WithContext(ctx)
 	// Iterate over all clusters
 	for _, cluster := range c.ca.Apps[app].Clusters {
+		// Clusters of other waves are handled separately
+		if !c.inWave(cluster.Name) {
+			continue
+		}
 		// If marked to skip then no processing needed
 		if cluster.Skip {
 			log.Info("Update Skipping Cluster::", log.Fields{"App": app, "cluster": cluster})
-			// Reset bit and skip cluster
-			cluster.Skip = false
 			continue
 		}
 		cluster := cluster.Name
@@ -486,16 +567,19 @@ func (c *Context) runApp(ctx context.Context, g *errgroup.Group, op RsyncOperati
 				"cluster": cluster,
 			})
 		}
-		g.Go(func() error {
-			return c.runCluster(ctx, g, op, app, cluster)
+		appGroup.Go(func() error {
+			cctx, span := tracing.Start(actx, "runCluster", attribute.String("app", app), attribute.String("cluster", cluster))
+			err := c.runCluster(cctx, g, op, app, cluster)
+			tracing.End(span, err)
+			return c.clusterFailed(cctx, app, cluster, err)
 		})
 	}
-	return nil
+	return appGroup.Wait()
 }
 
 func (c *Context) runCluster(ctx context.Context, g *errgroup.Group, op RsyncOperation, app, cluster string) error {
 	log.Info(" runCluster::", log.Fields{"app": app, "cluster": cluster})
-	utils := &AppContextUtils{ac: c.ac}
+	utils := &AppContextUtils{ac: c.ac.WithContext(ctx)}
 	namespace, level := utils.GetNamespace()
 	cl, err := c.con.GetClientInternal(cluster, level, namespace)
 	if err != nil {
@@ -511,18 +595,25 @@ func (c *Context) runCluster(ctx context.Context, g *errgroup.Group, op RsyncOpe
 			return err
 		}
 		reachable := true
+		// Resources handled so far, used for resource dependencies
+		handled := make(map[string]error)
 		// Handle all resources in order
 		for i, res := range c.ca.Apps[app].Clusters[cluster].ResOrder {
 			// If marked to skip then no processing needed
 			if c.ca.Apps[app].Clusters[cluster].Resources[res].Skip {
-				// Reset bit and skip resource
 				log.Info("Update Skipping Resource::", log.Fields{"App": app, "cluster": cluster, "resource": res})
-				r := c.ca.Apps[app].Clusters[cluster].Resources[res]
-				r.Skip = false
+				handled[res] = nil
 				continue
 			}
-			// Dependency here
+			// Wait for the resources this resource depends on
+			if op == OpApply {
+				if err := c.waitForResourceDependency(ctx, cl, app, cluster, res, handled); err != nil {
+					log.Error("Error in resource dependency", log.Fields{"error": err, "cluster": cluster, "resource": res})
+					return err
+				}
+			}
 			breakonError, err := c.handleResource(ctx, g, cl, op, app, cluster, res)
+			handled[res] = err
 			if err != nil {
 				log.Error("Error in resource", log.Fields{"error": err, "cluster": cluster, "resource": res})
 				// If failure is due to reachability issues start retrying
@@ -554,25 +645,41 @@ func (c *Context) runCluster(ctx context.Context, g *errgroup.Group, op RsyncOpe
 	}
 }
 
-func (c *Context) handleResource(ctx context.Context, g *errgroup.Group, cl ClientProvider, op RsyncOperation, app, cluster, res string) (bool, error) {
+// clearSkip resets the skip bits set for an update once the event is handled
+func (c *Context) clearSkip() {
+	for _, app := range c.ca.Apps {
+		app.Skip = false
+		for _, cluster := range app.Clusters {
+			cluster.Skip = false
+			for _, res := range cluster.Resources {
+				res.Skip = false
+			}
+		}
+	}
+}
+
+func (c *Context) handleResource(ctx context.Context, g *errgroup.Group, cl ClientProvider, op RsyncOperation, app, cluster, res string) (breakonError bool, err error) {
 	log.Info(" handleResource::", log.Fields{"app": app, "cluster": cluster, "res": res})
+	ctx, span := tracing.Start(ctx, op.String(), attribute.String("app", app), attribute.String("cluster", cluster),
+		attribute.String("resource", res))
+	defer func() { tracing.End(span, err) }()
 
 	switch op {
 	case OpApply:
 		// Get resource dependency here
-		err := c.instantiateResource(cl, res, app, cluster)
+		err := c.instantiateResource(ctx, cl, res, app, cluster)
 		if err != nil {
 			// return true for breakon error
 			return true, err
 		}
 	case OpDelete:
-		err := c.terminateResource(cl, res, app, cluster)
+		err := c.terminateResource(ctx, cl, res, app, cluster)
 		if err != nil {
 			// return false for breakon error
 			return false, err
 		}
 	case OpRead:
-		err := c.readResource(cl, res, app, cluster)
+		err := c.readResource(ctx, cl, res, app, cluster)
 		if err != nil {
 			// return false for breakon error
 			return false, err
@@ -600,11 +707,11 @@ func (c *Context) handleStatusTracking(ctx context.Context, g *errgroup.Group, c
 	return nil
 }
 
-func (c *Context) instantiateResource(cl ClientProvider, name, app, cluster string) error {
-	utils := &AppContextUtils{ac: c.ac}
+func (c *Context) instantiateResource(ctx context.Context, cl ClientProvider, name, app, cluster string) error {
+	utils := &AppContextUtils{ac: c.ac.WithContext(ctx)}
 	res, _, err := utils.GetRes(name, app, cluster)
 	if err != nil {
-		c.updateResourceStatus(name, app, cluster,
+		c.updateResourceStatus(ctx, name, app, cluster,
 			resourcestatus.ResourceStatus{Status: resourcestatus.RsyncStatusEnum.Failed})
 		return err
 	}
@@ -615,16 +722,27 @@ func (c *Context) instantiateResource(cl ClientProvider, name, app, cluster stri
 	if err != nil {
 		return err
 	}
-	if err := cl.Apply(b); err != nil {
-		c.updateResourceStatus(name, app, cluster,
-			resourcestatus.ResourceStatus{Status: resourcestatus.RsyncStatusEnum.Failed})
+	err = c.withApplyTimeout(ctx, name, func(actx context.Context) error {
+		return applyResource(actx, cl, b, c.forceApply)
+	})
+	observeClusterOperation(cluster, "apply", err)
+	if err != nil {
+		// Fields of the resource owned by another manager, like the replicas
+		// set by an HPA, are reported apart from the other failures
+		status := resourcestatus.RsyncStatusEnum.Failed
+		var conflict *ConflictError
+		if errors.As(err, &conflict) {
+			status = resourcestatus.RsyncStatusEnum.Conflict
+		}
+		c.updateResourceStatus(ctx, name, app, cluster,
+			resourcestatus.ResourceStatus{Status: status})
 		log.Error("Failed to apply res", log.Fields{
 			"error":    err,
 			"resource": name,
 		})
 		return err
 	}
-	c.updateResourceStatus(name, app, cluster,
+	c.updateResourceStatus(ctx, name, app, cluster,
 		resourcestatus.ResourceStatus{Status: resourcestatus.RsyncStatusEnum.Applied})
 	log.Info("Installed::", log.Fields{
 		"cluster":  cluster,
@@ -644,19 +762,21 @@ func (c *Context) instantiateResource(cl ClientProvider, name, app, cluster stri
 	return nil
 }
 
-func (c *Context) terminateResource(cl ClientProvider, name, app, cluster string) error {
+func (c *Context) terminateResource(ctx context.Context, cl ClientProvider, name, app, cluster string) error {
 
-	utils := &AppContextUtils{ac: c.ac}
+	utils := &AppContextUtils{ac: c.ac.WithContext(ctx)}
 	res, sh, err := utils.GetRes(name, app, cluster)
 	if err != nil {
 		if sh != nil {
-			c.updateResourceStatus(name, app, cluster,
+			c.updateResourceStatus(ctx, name, app, cluster,
 				resourcestatus.ResourceStatus{Status: resourcestatus.RsyncStatusEnum.Failed})
 		}
 		return err
 	}
-	if err := cl.Delete(res); err != nil {
-		c.updateResourceStatus(name, app, cluster,
+	err = cl.Delete(res)
+	observeClusterOperation(cluster, "delete", err)
+	if err != nil {
+		c.updateResourceStatus(ctx, name, app, cluster,
 			resourcestatus.ResourceStatus{Status: resourcestatus.RsyncStatusEnum.Failed})
 		log.Error("Failed to delete res", log.Fields{
 			"error":    err,
@@ -664,7 +784,7 @@ func (c *Context) terminateResource(cl ClientProvider, name, app, cluster string
 		})
 		return err
 	}
-	c.updateResourceStatus(name, app, cluster,
+	c.updateResourceStatus(ctx, name, app, cluster,
 		resourcestatus.ResourceStatus{Status: resourcestatus.RsyncStatusEnum.Deleted})
 	log.Info("Deleted::", log.Fields{
 		"cluster":  cluster,
@@ -673,12 +793,12 @@ func (c *Context) terminateResource(cl ClientProvider, name, app, cluster string
 	return nil
 }
 
-func (c *Context) readResource(cl ClientProvider, name, app, cluster string) error {
+func (c *Context) readResource(ctx context.Context, cl ClientProvider, name, app, cluster string) error {
 
-	utils := &AppContextUtils{ac: c.ac}
+	utils := &AppContextUtils{ac: c.ac.WithContext(ctx)}
 	res, _, err := utils.GetRes(name, app, cluster)
 	if err != nil {
-		c.updateResourceStatus(name, app, cluster,
+		c.updateResourceStatus(ctx, name, app, cluster,
 			resourcestatus.ResourceStatus{Status: resourcestatus.RsyncStatusEnum.Failed})
 		return err
 	}
@@ -687,7 +807,7 @@ func (c *Context) readResource(cl ClientProvider, name, app, cluster string) err
 	// Get the resource from the cluster
 	b, err := cl.Get(res, namespace)
 	if err != nil {
-		c.updateResourceStatus(name, app, cluster,
+		c.updateResourceStatus(ctx, name, app, cluster,
 			resourcestatus.ResourceStatus{Status: resourcestatus.RsyncStatusEnum.Failed})
 		log.Error("Failed to read res", log.Fields{
 			"error":    err,
@@ -697,7 +817,7 @@ func (c *Context) readResource(cl ClientProvider, name, app, cluster string) err
 	}
 	// Store result back in AppContext
 	utils.PutRes(name, app, cluster, b)
-	c.updateResourceStatus(name, app, cluster,
+	c.updateResourceStatus(ctx, name, app, cluster,
 		resourcestatus.ResourceStatus{Status: resourcestatus.RsyncStatusEnum.Applied})
 	log.Info("Applied::", log.Fields{
 		"cluster":  cluster,
@@ -707,32 +827,40 @@ func (c *Context) readResource(cl ClientProvider, name, app, cluster string) err
 }
 
 func (c *Context) waitForClusterReady(ctx context.Context, cl ClientProvider, app string, cluster string) error {
-	utils := &AppContextUtils{ac: c.ac}
 	// Check if reachable
 	if err := cl.IsReachable(); err == nil {
-		utils.SetClusterReadyStatus(app, cluster, appcontext.ClusterReadyStatusEnum.Available)
+		c.setClusterReadyStatus(app, cluster, appcontext.ClusterReadyStatusEnum.Available)
 		return nil
 	}
-	utils.SetClusterReadyStatus(app, cluster, appcontext.ClusterReadyStatusEnum.Retrying)
+	c.setClusterReadyStatus(app, cluster, appcontext.ClusterReadyStatusEnum.Retrying)
 	timedOut := false
 	retryCnt := 0
 	forceDone := false
+	start := time.Now()
 Loop:
 	for {
 		select {
-		// Wait for wait time before checking cluster ready
-		case <-time.After(time.Duration(c.waitTime) * time.Second):
+		// Wait for the backoff before checking cluster ready
+		case <-time.After(c.backoff(retryCnt)):
 			// Context is canceled
 			if ctx.Err() != nil {
 				return ctx.Err()
 			}
 			// If cluster is reachable then done
 			if err := cl.IsReachable(); err == nil {
-				utils.SetClusterReadyStatus(app, cluster, appcontext.ClusterReadyStatusEnum.Available)
+				c.setClusterReadyStatus(app, cluster, appcontext.ClusterReadyStatusEnum.Available)
 				return nil
 			}
 			log.Info("Cluster is not reachable - keep trying::", log.Fields{"cluster": cluster, "retry count": retryCnt})
 			retryCnt++
+			clusterRetries.WithLabelValues(cluster).Inc()
+			// The max unreachable time of the retry policy replaces the max retries
+			if c.retry.MaxUnreachableTime > 0 {
+				if time.Since(start) >= time.Duration(c.retry.MaxUnreachableTime)*time.Second {
+					return pkgerrors.Errorf("Cluster unreachable for more than %d seconds: %s", c.retry.MaxUnreachableTime, cluster)
+				}
+				break
+			}
 			if c.maxRetry >= 0 && retryCnt > c.maxRetry {
 				timedOut = true
 				break Loop
@@ -804,9 +932,9 @@ func (c *Context) deleteStatusTracker(cl ClientProvider, app string, cluster str
 	return nil
 }
 
-func (c *Context) updateResourceStatus(name, app, cluster string, status interface{}) {
+func (c *Context) updateResourceStatus(ctx context.Context, name, app, cluster string, status interface{}) {
 	// Use utils with status appContext
-	utils := &AppContextUtils{ac: c.sc}
+	utils := &AppContextUtils{ac: c.sc.WithContext(ctx)}
 	_ = utils.AddResourceStatus(name, app, cluster, status, c.acID)
 	// Treating status errors as non fatal
 }
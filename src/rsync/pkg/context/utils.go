@@ -11,6 +11,7 @@ import (
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
 	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/resourcestatus"
+	"github.com/open-ness/EMCO/src/rsync/pkg/grpc/readynotifyserver"
 	pkgerrors "github.com/pkg/errors"
 	//	. "github.com/open-ness/EMCO/src/rsync/pkg/types"
 )
@@ -19,6 +20,21 @@ type AppContextUtils struct {
 	ac appcontext.AppContext
 }
 
+// notifyStatus notifies the ReadyNotify subscribers of the AppContext that
+// its status changed
+func (a *AppContextUtils) notifyStatus() {
+	h, err := a.ac.GetCompositeAppHandle()
+	if err != nil {
+		return
+	}
+	// The handle is /context/<id>/
+	acID := strings.Split(strings.Trim(fmt.Sprintf("%v", h), "/"), "/")
+	if len(acID) < 2 {
+		return
+	}
+	readynotifyserver.SendAppContextNotification(acID[1])
+}
+
 //GetAppContextFlag gets the stop flag
 func (a *AppContextUtils) GetAppContextFlag(key string) (bool, error) {
 	h, err := a.ac.GetCompositeAppHandle()
@@ -72,8 +88,10 @@ func (a *AppContextUtils) UpdateAppContextStatus(key string, status interface{})
 	}
 	if err != nil {
 		log.Error("Error UpdateAppContextStatus", log.Fields{"err": err})
+		return err
 	}
-	return err
+	a.notifyStatus()
+	return nil
 
 }
 
@@ -123,6 +141,7 @@ func (a *AppContextUtils) SetClusterReadyStatus(app, cluster string, status appc
 	} else {
 		a.ac.UpdateStatusValue(rsh, status)
 	}
+	a.notifyStatus()
 	return
 }
 
@@ -140,12 +159,37 @@ func (a *AppContextUtils) GetClusterReadyStatus(app, cluster string) appcontext.
 		if err != nil {
 			return appcontext.ClusterReadyStatusEnum.Unknown
 		}
-		return status.(appcontext.StatusValue)
+		// The value is decoded as a string from the AppContext store
+		switch s := status.(type) {
+		case appcontext.StatusValue:
+			return s
+		case string:
+			return appcontext.StatusValue(s)
+		}
 	}
 
 	return appcontext.ClusterReadyStatusEnum.Unknown
 }
 
+// SetClusterFailure sets the error of the cluster of the app, or clears it if
+// the error is empty
+func (a *AppContextUtils) SetClusterFailure(app, cluster, msg string) {
+	ch, err := a.ac.GetClusterHandle(app, cluster)
+	if err != nil {
+		return
+	}
+	fh, _ := a.ac.GetLevelHandle(ch, appcontext.ClusterFailureKey)
+	switch {
+	case fh != nil:
+		a.ac.UpdateStatusValue(fh, msg)
+	case msg != "":
+		a.ac.AddLevelValue(ch, appcontext.ClusterFailureKey, msg)
+	default:
+		return
+	}
+	a.notifyStatus()
+}
+
 // GetRes Reads resource
 func (a *AppContextUtils) GetRes(name string, app string, cluster string) ([]byte, interface{}, error) {
 	var byteRes []byte
@@ -185,6 +229,28 @@ func (a *AppContextUtils) GetRes(name string, app string, cluster string) ([]byt
 	return byteRes, sh, nil
 }
 
+// GetResourceStatus reads the rsync status of a resource
+// returns an empty status if the status is not found
+func (a *AppContextUtils) GetResourceStatus(name string, app string, cluster string) resourcestatus.RsyncStatus {
+	sh, err := a.ac.GetResourceStatusHandle(app, cluster, name)
+	if err != nil {
+		return ""
+	}
+	v, err := a.ac.GetValue(sh)
+	if err != nil {
+		return ""
+	}
+	var rStatus resourcestatus.ResourceStatus
+	js, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	if err := json.Unmarshal(js, &rStatus); err != nil {
+		return ""
+	}
+	return rStatus.Status
+}
+
 // GetSubResApprove Reads sub resource
 func (a *AppContextUtils) GetSubResApprove(name string, app string, cluster string) ([]byte, interface{}, error) {
 	var byteRes []byte
@@ -262,6 +328,107 @@ func (a *AppContextUtils) GetStatusAppContext(key string) (string, error) {
 	return "", err
 }
 
+// GetReconcileMode returns what to do with resources that drifted on the clusters
+func (a *AppContextUtils) GetReconcileMode() appcontext.ReconcileMode {
+	h, err := a.ac.GetCompositeAppHandle()
+	if err != nil {
+		return appcontext.ReconcileModeEnum.Off
+	}
+	rh, err := a.ac.GetLevelHandle(h, appcontext.ReconcileKey)
+	if err != nil {
+		return appcontext.ReconcileModeEnum.Off
+	}
+	v, err := a.ac.GetValue(rh)
+	if err != nil {
+		return appcontext.ReconcileModeEnum.Off
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// GetPruneMode returns what to do with orphaned resources on the clusters
+func (a *AppContextUtils) GetPruneMode() appcontext.PruneMode {
+	h, err := a.ac.GetCompositeAppHandle()
+	if err != nil {
+		return appcontext.PruneModeEnum.Off
+	}
+	ph, err := a.ac.GetLevelHandle(h, appcontext.PruneKey)
+	if err != nil {
+		return appcontext.PruneModeEnum.Off
+	}
+	v, err := a.ac.GetValue(ph)
+	if err != nil {
+		return appcontext.PruneModeEnum.Off
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// GetForceApply returns whether the resources are applied taking over the
+// fields owned by other managers on the clusters
+func (a *AppContextUtils) GetForceApply() bool {
+	h, err := a.ac.GetCompositeAppHandle()
+	if err != nil {
+		return false
+	}
+	fh, err := a.ac.GetLevelHandle(h, appcontext.ForceApplyKey)
+	if err != nil {
+		return false
+	}
+	v, err := a.ac.GetValue(fh)
+	if err != nil {
+		return false
+	}
+	force, ok := v.(bool)
+	return ok && force
+}
+
+// GetAppContextRollout returns the waves of clusters the AppContext is rolled out in
+func (a *AppContextUtils) GetAppContextRollout() (appcontext.Rollout, error) {
+	var r appcontext.Rollout
+	h, err := a.ac.GetCompositeAppHandle()
+	if err != nil {
+		log.Error("Error GetAppContextRollout", log.Fields{"err": err})
+		return r, err
+	}
+	rh, err := a.ac.GetLevelHandle(h, appcontext.RolloutKey)
+	if err != nil {
+		return r, err
+	}
+	v, err := a.ac.GetValue(rh)
+	if err != nil {
+		return r, err
+	}
+	js, err := json.Marshal(v)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(js, &r)
+	return r, err
+}
+
+// GetAppContextRetryPolicy returns how long the clusters of the AppContext are retried
+func (a *AppContextUtils) GetAppContextRetryPolicy() (appcontext.RetryPolicy, error) {
+	var r appcontext.RetryPolicy
+	h, err := a.ac.GetCompositeAppHandle()
+	if err != nil {
+		log.Error("Error GetAppContextRetryPolicy", log.Fields{"err": err})
+		return r, err
+	}
+	rh, err := a.ac.GetLevelHandle(h, appcontext.RetryPolicyKey)
+	if err != nil {
+		return r, err
+	}
+	v, err := a.ac.GetValue(rh)
+	if err != nil {
+		return r, err
+	}
+	js, err := json.Marshal(v)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(js, &r)
+	return r, err
+}
+
 // Add resource level for a status
 // Function adds any missing levels to AppContext
 func (a *AppContextUtils) AddResourceStatus(name string, app string, cluster string, status interface{}, acID string) error {
@@ -336,5 +503,6 @@ func (a *AppContextUtils) AddResourceStatus(name string, app string, cluster str
 	} else {
 		a.ac.UpdateStatusValue(lch, link)
 	}
+	a.notifyStatus()
 	return nil
 }
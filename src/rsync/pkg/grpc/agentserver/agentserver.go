@@ -0,0 +1,280 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package agentserver
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/open-ness/EMCO/src/orchestrator/pkg/infra/logutils"
+	kubeclient "github.com/open-ness/EMCO/src/rsync/pkg/client"
+	pb "github.com/open-ness/EMCO/src/rsync/pkg/grpc/agent"
+	. "github.com/open-ness/EMCO/src/rsync/pkg/types"
+	pkgerrors "github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// OperationTimeout is how long to wait for the result of an operation sent
+// to an agent
+var OperationTimeout = 2 * time.Minute
+
+// StatusHandler handles a ResourceBundleState CR reported by the agent of a
+// cluster
+type StatusHandler func(cluster string, status []byte)
+
+// agentConn is the stream of the agent of a cluster
+type agentConn struct {
+	cluster string
+	stream  pb.Agent_ConnectServer
+	// Send isn't safe to call from several goroutines
+	sendLock sync.Mutex
+	// Results of the operations sent to the agent by operation ID
+	pending map[string]chan *pb.Result
+	lock    sync.Mutex
+}
+
+// agents holds the connected agents by cluster
+var agents = struct {
+	conns map[string]*agentConn
+	sync.Mutex
+}{conns: make(map[string]*agentConn)}
+
+var opID uint64
+
+type agentServer struct {
+	token  string
+	status StatusHandler
+}
+
+// ClusterToken returns the token the agent of a cluster sends to rsync, the
+// HMAC-SHA256 of the cluster keyed by the agent token of rsync. The agent of
+// a cluster can't connect as another cluster with it.
+func ClusterToken(token, cluster string) string {
+	mac := hmac.New(sha256.New, []byte(token))
+	mac.Write([]byte(cluster))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewAgentServer returns the server the agents of pull-mode clusters connect
+// to. The agents must send the token of their cluster, no agent connects
+// without a token.
+func NewAgentServer(token string, status StatusHandler) pb.AgentServer {
+	return &agentServer{token: token, status: status}
+}
+
+// Connect registers the agent of a cluster and handles its messages until it
+// disconnects. A new stream of an agent replaces the previous one.
+func (s *agentServer) Connect(stream pb.Agent_ConnectServer) error {
+	m, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	if m.Cluster == "" {
+		return pkgerrors.New("Agent did not send its cluster")
+	}
+	if s.token == "" || !hmac.Equal([]byte(m.Token), []byte(ClusterToken(s.token, m.Cluster))) {
+		log.Error("[Agent gRPC] Agent not authorized", log.Fields{"cluster": m.Cluster})
+		return pkgerrors.New("Agent not authorized")
+	}
+	conn := &agentConn{cluster: m.Cluster, stream: stream, pending: make(map[string]chan *pb.Result)}
+	agents.Lock()
+	agents.conns[conn.cluster] = conn
+	agents.Unlock()
+	log.Info("[Agent gRPC] Agent connected", log.Fields{"cluster": conn.cluster})
+
+	defer func() {
+		agents.Lock()
+		if agents.conns[conn.cluster] == conn {
+			delete(agents.conns, conn.cluster)
+		}
+		agents.Unlock()
+		// Operations waiting for a result fail
+		conn.lock.Lock()
+		for id, ch := range conn.pending {
+			close(ch)
+			delete(conn.pending, id)
+		}
+		conn.lock.Unlock()
+		log.Info("[Agent gRPC] Agent disconnected", log.Fields{"cluster": conn.cluster})
+	}()
+
+	for {
+		m, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if m.Result != nil {
+			conn.lock.Lock()
+			ch, ok := conn.pending[m.Result.Id]
+			delete(conn.pending, m.Result.Id)
+			conn.lock.Unlock()
+			if ok {
+				ch <- m.Result
+			}
+		}
+		if m.Status != nil && s.status != nil {
+			s.status(conn.cluster, m.Status)
+		}
+	}
+}
+
+func getAgent(cluster string) *agentConn {
+	agents.Lock()
+	defer agents.Unlock()
+	return agents.conns[cluster]
+}
+
+// IsConnected checks whether the agent of a cluster is connected
+func IsConnected(cluster string) bool {
+	return getAgent(cluster) != nil
+}
+
+// Client reaches a pull-mode cluster through its agent
+type Client struct {
+	cluster   string
+	namespace string
+}
+
+// NewClient returns a client for the cluster. The resources without a
+// namespace are applied in the namespace.
+func NewClient(cluster, namespace string) *Client {
+	return &Client{cluster: cluster, namespace: namespace}
+}
+
+// call sends an operation to the agent of the cluster and waits for its result
+func (c *Client) call(op *pb.Operation) (*pb.Result, error) {
+	return c.callContext(context.Background(), op)
+}
+
+// callContext sends an operation to the agent of the cluster and waits for its
+// result until the context is done
+func (c *Client) callContext(ctx context.Context, op *pb.Operation) (*pb.Result, error) {
+	conn := getAgent(c.cluster)
+	if conn == nil {
+		return nil, pkgerrors.Errorf("Agent of cluster %s not connected", c.cluster)
+	}
+	op.Id = fmt.Sprintf("%d", atomic.AddUint64(&opID, 1))
+	if op.Namespace == "" {
+		op.Namespace = c.namespace
+	}
+	ch := make(chan *pb.Result, 1)
+	conn.lock.Lock()
+	conn.pending[op.Id] = ch
+	conn.lock.Unlock()
+
+	conn.sendLock.Lock()
+	err := conn.stream.Send(op)
+	conn.sendLock.Unlock()
+	if err != nil {
+		conn.lock.Lock()
+		delete(conn.pending, op.Id)
+		conn.lock.Unlock()
+		return nil, pkgerrors.Wrapf(err, "Error sending %s to the agent of cluster %s", op.Op, c.cluster)
+	}
+
+	t := time.NewTimer(OperationTimeout)
+	defer t.Stop()
+	select {
+	case r, ok := <-ch:
+		if !ok {
+			return nil, pkgerrors.Errorf("Agent of cluster %s disconnected", c.cluster)
+		}
+		if r.Error != "" {
+			err := pkgerrors.New(r.Error)
+			if r.Conflict {
+				return r, &ConflictError{Err: err}
+			}
+			return r, err
+		}
+		return r, nil
+	case <-t.C:
+		conn.lock.Lock()
+		delete(conn.pending, op.Id)
+		conn.lock.Unlock()
+		return nil, pkgerrors.Errorf("Agent of cluster %s did not answer %s", c.cluster, op.Op)
+	case <-ctx.Done():
+		conn.lock.Lock()
+		delete(conn.pending, op.Id)
+		conn.lock.Unlock()
+		return nil, pkgerrors.Wrapf(ctx.Err(), "Agent of cluster %s did not answer %s", c.cluster, op.Op)
+	}
+}
+
+// Apply applies the resource on the cluster
+func (c *Client) Apply(content []byte) error {
+	_, err := c.call(&pb.Operation{Op: pb.Operation_APPLY, Content: content})
+	return err
+}
+
+// ForceApply applies the resource on the cluster and takes over the fields
+// owned by other managers
+func (c *Client) ForceApply(content []byte) error {
+	_, err := c.call(&pb.Operation{Op: pb.Operation_FORCE_APPLY, Content: content})
+	return err
+}
+
+// ApplyContext applies the resource on the cluster, taking over the fields
+// owned by other managers if forced, and stops waiting for the agent when the
+// context is done
+func (c *Client) ApplyContext(ctx context.Context, content []byte, force bool) error {
+	op := pb.Operation_APPLY
+	if force {
+		op = pb.Operation_FORCE_APPLY
+	}
+	_, err := c.callContext(ctx, &pb.Operation{Op: op, Content: content})
+	return err
+}
+
+// Delete deletes the resource from the cluster
+func (c *Client) Delete(content []byte) error {
+	_, err := c.call(&pb.Operation{Op: pb.Operation_DELETE, Content: content})
+	return err
+}
+
+// Get reads a resource from the cluster
+func (c *Client) Get(gvkRes []byte, namespace string) ([]byte, error) {
+	r, err := c.call(&pb.Operation{Op: pb.Operation_GET, Content: gvkRes, Namespace: namespace})
+	if err != nil {
+		return nil, err
+	}
+	return r.Content, nil
+}
+
+// Approve approves a certificate signing request on the cluster
+func (c *Client) Approve(name string, sa []byte) error {
+	_, err := c.call(&pb.Operation{Op: pb.Operation_APPROVE, Name: name, Content: sa})
+	return err
+}
+
+// ListResources lists the resources of a GVK on the cluster tagged with the label
+func (c *Client) ListResources(gvk schema.GroupVersionKind, label string) ([][]byte, error) {
+	r, err := c.call(&pb.Operation{Op: pb.Operation_LIST, Gvk: &pb.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind}, Label: label})
+	if err != nil {
+		return nil, err
+	}
+	return r.Resources, nil
+}
+
+// IsReachable checks whether the agent of the cluster is connected
+func (c *Client) IsReachable() error {
+	if !IsConnected(c.cluster) {
+		return pkgerrors.Errorf("Agent of cluster %s not connected", c.cluster)
+	}
+	return nil
+}
+
+// TagResource adds the label to the resource
+func (c *Client) TagResource(res []byte, label string) ([]byte, error) {
+	return kubeclient.TagResource(res, label)
+}
@@ -0,0 +1,258 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2021 Intel Corporation
+
+package agentserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-ness/EMCO/src/rsync/pkg/agent"
+	pb "github.com/open-ness/EMCO/src/rsync/pkg/grpc/agent"
+	. "github.com/open-ness/EMCO/src/rsync/pkg/types"
+	pkgerrors "github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// fakeCluster is the cluster of the fake agent. Applying "conflict" conflicts
+// with another manager unless it is forced.
+type fakeCluster struct {
+	resources map[string]string
+	sync.Mutex
+}
+
+func (f *fakeCluster) Apply(content []byte) error {
+	if string(content) == "conflict" {
+		return &ConflictError{Err: pkgerrors.New("conflict with \"hpa-controller\"")}
+	}
+	return f.ForceApply(content)
+}
+
+func (f *fakeCluster) ForceApply(content []byte) error {
+	f.Lock()
+	defer f.Unlock()
+	f.resources[string(content)] = string(content)
+	return nil
+}
+
+func (f *fakeCluster) Delete(content []byte) error {
+	f.Lock()
+	defer f.Unlock()
+	if _, ok := f.resources[string(content)]; !ok {
+		return pkgerrors.New("not found")
+	}
+	delete(f.resources, string(content))
+	return nil
+}
+
+func (f *fakeCluster) Get(gvkRes []byte, namespace string) ([]byte, error) {
+	f.Lock()
+	defer f.Unlock()
+	if _, ok := f.resources[string(gvkRes)]; !ok {
+		return nil, pkgerrors.New("not found")
+	}
+	return []byte(namespace + "/" + string(gvkRes)), nil
+}
+
+func (f *fakeCluster) Approve(name string, sa []byte) error {
+	return nil
+}
+
+func (f *fakeCluster) IsReachable() error {
+	return nil
+}
+
+func (f *fakeCluster) TagResource(res []byte, label string) ([]byte, error) {
+	return res, nil
+}
+
+func (f *fakeCluster) ListResources(gvk schema.GroupVersionKind, label string) ([][]byte, error) {
+	f.Lock()
+	defer f.Unlock()
+	var resources [][]byte
+	for r := range f.resources {
+		resources = append(resources, []byte(r))
+	}
+	return resources, nil
+}
+
+// startServer starts an agent server and returns a client of the server and
+// a function stopping both
+func startServer(t *testing.T, token string, status StatusHandler) (pb.AgentClient, func()) {
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	pb.RegisterAgentServer(s, NewAgentServer(token, status))
+	go s.Serve(lis)
+
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(),
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
+			return lis.Dial()
+		}))
+	if err != nil {
+		s.Stop()
+		t.Fatalf("Error connecting to the agent server %v", err)
+	}
+	return pb.NewAgentClient(conn), func() {
+		conn.Close()
+		s.Stop()
+	}
+}
+
+func waitConnected(cluster string, connected bool) bool {
+	for i := 0; i < 100; i++ {
+		if IsConnected(cluster) == connected {
+			return true
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return false
+}
+
+func TestAgentClient(t *testing.T) {
+	var statusLock sync.Mutex
+	var statuses []string
+	c, stop := startServer(t, "secret", func(cluster string, status []byte) {
+		statusLock.Lock()
+		defer statusLock.Unlock()
+		statuses = append(statuses, cluster+":"+string(status))
+	})
+	defer stop()
+
+	cl := NewClient("provider1+cluster1", "ns1")
+	if err := cl.IsReachable(); err == nil {
+		t.Fatal("Expected the cluster to be unreachable before the agent connects")
+	}
+	if err := cl.Apply([]byte("r1")); err == nil {
+		t.Fatal("Expected the apply to fail before the agent connects")
+	}
+
+	fake := &fakeCluster{resources: make(map[string]string)}
+	var namespaces []string
+	a := &agent.Agent{Cluster: "provider1+cluster1", Token: ClusterToken("secret", "provider1+cluster1"), NewClient: func(namespace string) (ClientProvider, error) {
+		namespaces = append(namespaces, namespace)
+		return fake, nil
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	status := make(chan []byte, 1)
+	done := make(chan error, 1)
+	go func() { done <- a.Run(ctx, c, status) }()
+	if !waitConnected("provider1+cluster1", true) {
+		t.Fatal("Agent did not connect")
+	}
+	if err := cl.IsReachable(); err != nil {
+		t.Errorf("Unexpected unreachable cluster %v", err)
+	}
+
+	// Operations are run by the agent on its cluster
+	if err := cl.Apply([]byte("r1")); err != nil {
+		t.Fatalf("Unexpected apply error %v", err)
+	}
+	if b, err := cl.Get([]byte("r1"), "ns2"); err != nil || string(b) != "ns2/r1" {
+		t.Errorf("Unexpected get result %s %v", b, err)
+	}
+	if _, err := cl.Get([]byte("r2"), "ns2"); err == nil {
+		t.Error("Expected the get of a missing resource to fail")
+	}
+	err := cl.Apply([]byte("conflict"))
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Errorf("Expected a conflict, got %v", err)
+	}
+	if err := cl.ForceApply([]byte("conflict")); err != nil {
+		t.Errorf("Unexpected force apply error %v", err)
+	}
+	if l, err := cl.ListResources(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, "label"); err != nil || len(l) != 2 {
+		t.Errorf("Unexpected list result %v %v", l, err)
+	}
+	if err := cl.Delete([]byte("r1")); err != nil {
+		t.Errorf("Unexpected delete error %v", err)
+	}
+	if !reflect.DeepEqual(fake.resources, map[string]string{"conflict": "conflict"}) {
+		t.Errorf("Unexpected resources on the cluster %v", fake.resources)
+	}
+	if !reflect.DeepEqual(namespaces, []string{"ns1", "ns2"}) {
+		t.Errorf("Unexpected namespaces of the clients %v", namespaces)
+	}
+
+	// The status of the resources is reported by the agent
+	status <- []byte("rbs")
+	for i := 0; i < 100; i++ {
+		statusLock.Lock()
+		n := len(statuses)
+		statusLock.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	statusLock.Lock()
+	if !reflect.DeepEqual(statuses, []string{"provider1+cluster1:rbs"}) {
+		t.Errorf("Unexpected statuses %v", statuses)
+	}
+	statusLock.Unlock()
+
+	// The cluster is unreachable once the agent disconnects
+	cancel()
+	<-done
+	if !waitConnected("provider1+cluster1", false) {
+		t.Fatal("Agent did not disconnect")
+	}
+	if err := cl.Apply([]byte("r1")); err == nil {
+		t.Error("Expected the apply to fail once the agent disconnected")
+	}
+}
+
+func TestAgentNoToken(t *testing.T) {
+	c, stop := startServer(t, "", nil)
+	defer stop()
+	fake := &fakeCluster{resources: make(map[string]string)}
+	newClient := func(namespace string) (ClientProvider, error) { return fake, nil }
+
+	// No agent connects to rsync without a token
+	for _, token := range []string{"", ClusterToken("", "provider1+cluster3")} {
+		a := &agent.Agent{Cluster: "provider1+cluster3", Token: token, NewClient: newClient}
+		if err := a.Run(context.Background(), c, nil); err == nil {
+			t.Error("Expected the agent not to be authorized")
+		}
+	}
+	if IsConnected("provider1+cluster3") {
+		t.Error("Unexpected connected agent")
+	}
+}
+
+func TestAgentToken(t *testing.T) {
+	c, stop := startServer(t, "secret", nil)
+	defer stop()
+	fake := &fakeCluster{resources: make(map[string]string)}
+	newClient := func(namespace string) (ClientProvider, error) { return fake, nil }
+
+	a := &agent.Agent{Cluster: "provider1+cluster2", Token: "wrong", NewClient: newClient}
+	if err := a.Run(context.Background(), c, nil); err == nil {
+		t.Error("Expected the agent not to be authorized")
+	}
+	if IsConnected("provider1+cluster2") {
+		t.Error("Unexpected connected agent")
+	}
+
+	// The token of another cluster is not accepted
+	a = &agent.Agent{Cluster: "provider1+cluster2", Token: ClusterToken("secret", "provider1+cluster1"), NewClient: newClient}
+	if err := a.Run(context.Background(), c, nil); err == nil {
+		t.Error("Expected the agent not to be authorized with the token of another cluster")
+	}
+
+	a = &agent.Agent{Cluster: "provider1+cluster2", Token: ClusterToken("secret", "provider1+cluster2"), NewClient: newClient}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go a.Run(ctx, c, nil)
+	if !waitConnected("provider1+cluster2", true) {
+		t.Error("Agent did not connect")
+	}
+}
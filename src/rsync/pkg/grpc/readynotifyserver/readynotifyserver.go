@@ -21,6 +21,8 @@ type readyNotifyServer struct {
 	alertNotify   map[string]map[string]pb.ReadyNotify_AlertServer
 	streamChannel map[pb.ReadyNotify_AlertServer]chan int
 	mutex         sync.Mutex
+	// sendMutex serializes the notifications, a stream can't be sent to concurrently
+	sendMutex sync.Mutex
 }
 
 var notifServer *readyNotifyServer
@@ -53,7 +55,18 @@ func (s *readyNotifyServer) Alert(topic *pb.Topic, stream pb.ReadyNotify_AlertSe
 
 //SendAppContextNotification sends appcontext back to the subscriber if pending
 func SendAppContextNotification(appContextID string) error {
-	streams := notifServer.alertNotify[appContextID]
+	// No subscriber if the server isn't running
+	if notifServer == nil {
+		return nil
+	}
+	notifServer.mutex.Lock()
+	streams := make([]pb.ReadyNotify_AlertServer, 0, len(notifServer.alertNotify[appContextID]))
+	for _, stream := range notifServer.alertNotify[appContextID] {
+		streams = append(streams, stream)
+	}
+	notifServer.mutex.Unlock()
+	notifServer.sendMutex.Lock()
+	defer notifServer.sendMutex.Unlock()
 	var err error = nil
 	for _, stream := range streams {
 		err := stream.Send(&pb.Notification{AppContext: appContextID})
@@ -4,6 +4,8 @@
 package types
 
 import (
+	"context"
+
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
@@ -118,18 +120,27 @@ type Resource struct {
 	Res string                  `json:"name,omitempty"`
 	GVK schema.GroupVersionKind `json:"gvk,omitempty"`
 }
+
+// Operational status values a dependency can wait for
+const (
+	// OpStatusDeployed is reached once all resources are applied on all clusters
+	OpStatusDeployed string = "Deployed"
+	// OpStatusReady is reached once the ResourceBundleState reports the resources as ready
+	OpStatusReady string = "Ready"
+)
+
 // Criteria for Resource dependency
 type Criteria struct {
 	// Ready or deployed
 	OpStatus string `json:"opstatus,omitempty"`
 	// Wait time in seconds
-	Wait string `json:"wait,omitempty"`
+	Wait int `json:"wait,omitempty"`
 }
 
 // Dependency Structures
 type Dependency struct {
 	Resource Resource `json:"resource,omitempty"`
-	criteria Criteria `json:"criteria,omitempty"`
+	Criteria Criteria `json:"criteria,omitempty"`
 }
 
 // ResourceDependency structure
@@ -178,6 +189,34 @@ type ClientProvider interface {
 	IsReachable() error
 	TagResource([]byte, string) ([]byte, error)
 }
+// ForceApplier is implemented by the clients that can take over the fields
+// of a resource owned by other managers, like the replicas set by an HPA
+type ForceApplier interface {
+	ForceApply(content []byte) error
+}
+// ContextApplier is implemented by the clients that stop applying a resource
+// when the context is done
+type ContextApplier interface {
+	ApplyContext(ctx context.Context, content []byte, force bool) error
+}
+// ResourceLister is implemented by the clients that can list the resources
+// of a GVK on the cluster tagged with a label by TagResource
+type ResourceLister interface {
+	ListResources(gvk schema.GroupVersionKind, label string) ([][]byte, error)
+}
+// ConflictError is returned by a client when applying a resource conflicts
+// with the fields owned by another manager on the cluster
+type ConflictError struct {
+	Err error
+}
+
+func (e *ConflictError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
 // Connector is interface for connection to Cluster
 type Connector interface {
 	Init(id interface{}) error
@@ -191,8 +230,12 @@ type AppContextQueueElement struct {
 	Event RsyncEvent `json:"event"`
 	// Only valid in case of update events
 	UCID string `json:"uCID,omitempty"`
+	// Only set for update events rolling the AppContext back
+	Rollback bool `json:"rollback,omitempty"`
 	// Status - Pending, Done, Error, skip
 	Status string `json:"status"`
+	// Trace context of the request that queued the event
+	TraceContext map[string]string `json:"traceContext,omitempty"`
 }
 // AppContextQueue per AppContext queue
 type AppContextQueue struct {
@@ -1,6 +1,7 @@
 module github.com/open-ness/EMCO/src/sds
 
 require (
+	github.com/golang/mock v1.5.0
 	github.com/golang/protobuf v1.4.2
 	github.com/gorilla/handlers v1.3.0
 	github.com/gorilla/mux v1.7.3
@@ -14,7 +15,7 @@ require (
 	github.com/pkg/errors v0.9.1
 	google.golang.org/grpc v1.28.0
 	google.golang.org/protobuf v1.24.0
-	gopkg.in/yaml.v2 v2.3.0
+	gopkg.in/yaml.v2 v2.4.0
 	k8s.io/api v0.20.2
 	k8s.io/apimachinery v0.20.2
 )
@@ -26,9 +27,9 @@ replace (
 	github.com/open-ness/EMCO/src/clm => ../clm
 	github.com/open-ness/EMCO/src/dtc => ../dtc
 	github.com/open-ness/EMCO/src/monitor => ../monitor
-	github.com/open-ness/EMCO/src/sds => ../sds
 	github.com/open-ness/EMCO/src/orchestrator => ../orchestrator
 	github.com/open-ness/EMCO/src/rsync => ../rsync
+	github.com/open-ness/EMCO/src/sds => ../sds
 	go.etcd.io/etcd => go.etcd.io/etcd v0.5.0-alpha.5.0.20200819165624-17cef6e3e9d5 // 17cef6e3e9d5 is the SHA for git tag v3.4.12
 	k8s.io/api => k8s.io/api v0.19.4
 	k8s.io/apiextensions-apiserver => k8s.io/apiextensions-apiserver v0.19.4
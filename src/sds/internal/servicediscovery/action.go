@@ -29,7 +29,7 @@ const (
 )
 
 // CreateAppContext Action applies the supplied intent against the given AppContext ID
-func CreateAppContext(intentName, appContextID string) error {
+func CreateAppContext(ctx context.Context, intentName, appContextID string) error {
 	var ac appcontext.AppContext
 	_, err := ac.LoadAppContext(appContextID)
 	if err != nil {
@@ -38,6 +38,7 @@ func CreateAppContext(intentName, appContextID string) error {
 		})
 		return pkgerrors.Wrapf(err, "Error getting AppContext with Id: %v", appContextID)
 	}
+	ac = ac.WithContext(ctx)
 
 	caMeta, err := ac.GetCompositeAppMeta()
 	if err != nil {
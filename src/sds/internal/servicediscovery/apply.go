@@ -5,7 +5,6 @@ package servicediscovery
 
 import (
 	"encoding/json"
-	"fmt"
 
 	"github.com/open-ness/EMCO/src/sds/internal/utils"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
@@ -182,52 +181,30 @@ func DeployServiceEntry(ac appcontext.AppContext, appContextID string, serverNam
 				return utils.CleanupCompositeApp(childContext, err, "Error getting CompositeAppMeta", []string{serviceName, childCtxVal.(string)})
 			}
 
+			// rsync adds the child app context to the parent when installing it
 			err = childContext.AddCompositeAppMeta(appcontext.CompositeAppMeta{Project: m.Project, CompositeApp: compositeApp, Version: m.Version, Release: m.Release,
-				DeploymentIntentGroup: m.DeploymentIntentGroup, Namespace: m.Namespace, Level: m.Level})
+				DeploymentIntentGroup: m.DeploymentIntentGroup, Namespace: m.Namespace, Level: m.Level, ParentContextID: appContextID})
 			if err != nil {
 				return utils.CleanupCompositeApp(childContext, err, "Error adding CompositeAppMeta for child", []string{serviceName, childCtxVal.(string)})
 			}
 
-			childContextID := fmt.Sprintf("%v", childCtxVal)
-			m.ChildContextIDs = append(m.ChildContextIDs, childContextID)
-			// Add this child app context to Parent meta data
-			err = ac.AddCompositeAppMeta(appcontext.CompositeAppMeta{
-				Project:               m.Project,
-				CompositeApp:          m.CompositeApp,
-				Version:               m.Version,
-				Release:               m.Release,
-				DeploymentIntentGroup: m.DeploymentIntentGroup,
-				Namespace:             m.Namespace,
-				Level:                 m.Level,
-				ChildContextIDs:       m.ChildContextIDs})
-			if err != nil {
-				return utils.CleanupCompositeApp(childContext, err, "Error adding CompositeAppMeta", []string{serviceName, childCtxVal.(string)})
-			}
-
 			// Check for parent app context status
 			// Get the appcontext status value
 			acStatus, err := state.GetAppContextStatus(appContextID)
 			if err != nil {
-				// Remove the child entry from the parent's meta
-				utils.RemoveChildCtx(m.ChildContextIDs, childContextID)
 				// Delete the child app context
 				return utils.CleanupCompositeApp(childContext, err, "Unable to get the status of the app context", []string{serviceName, childCtxVal.(string)})
 			}
 
 			if acStatus.Status == appcontext.AppContextStatusEnum.Instantiated {
-
-				// To-Do: race condition can be observed here. Will address this issue by mmodifying the rsync code to handle race condition issues.
-				// Deploy the child app context
+				// Deploy the child app context, rsync serializes it with the
+				// events of the parent
 				err = rsyncclient.CallRsyncInstall(childCtxVal)
 				if err != nil {
-					// Remove the child entry from the parent's meta
-					utils.RemoveChildCtx(m.ChildContextIDs, childContextID)
 					// Delete the child app context
 					return utils.CleanupCompositeApp(childContext, err, "Error calling rsync", []string{serviceName, childCtxVal.(string)})
 				}
 			} else {
-				// Remove the child entry from the parent's meta
-				utils.RemoveChildCtx(m.ChildContextIDs, childContextID)
 				// Delete the child app context
 				utils.CleanupCompositeApp(childContext, err, "Parent's app is not in instantiated state", []string{serviceName, childCtxVal.(string)})
 
@@ -156,14 +156,3 @@ func CleanupCompositeApp(context appcontext.AppContext, err error, reason string
 	}
 	return newerr
 }
-
-// RemoveChildCtx removes the child context ID in the parent's meta
-func RemoveChildCtx(childContexts []string, childContextID string) {
-	for i := range childContexts {
-		if childContexts[i] == childContextID {
-			childContexts[i] = childContexts[len(childContexts)-1]
-			childContexts[len(childContexts)-1] = ""
-			childContexts = childContexts[:len(childContexts)-1]
-		}
-	}
-}
@@ -23,7 +23,7 @@ func (cs *contextupdateServer) UpdateAppContext(ctx context.Context, req *contex
 	})
 
 
-	err := servicediscovery.CreateAppContext(req.IntentName, req.AppContext)
+	err := servicediscovery.CreateAppContext(ctx, req.IntentName, req.AppContext)
 	if err != nil {
 		return &contextpb.ContextUpdateResponse{AppContextUpdated: false, AppContextUpdateMessage: err.Error()}, nil
 	}
@@ -4,6 +4,7 @@
 package action
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 
@@ -65,13 +66,14 @@ func chainClusters(apps []string, ac catypes.CompositeApp) map[string]struct{} {
 }
 
 // Action applies the supplied intent against the given AppContext ID
-func UpdateAppContext(intentName, appContextId string) error {
+func UpdateAppContext(ctx context.Context, intentName, appContextId string) error {
 
 	var ac appcontext.AppContext
 	_, err := ac.LoadAppContext(appContextId)
 	if err != nil {
 		return pkgerrors.Wrapf(err, "Error loading AppContext with Id: %v", appContextId)
 	}
+	ac = ac.WithContext(ctx)
 	cahandle, err := ac.GetCompositeAppHandle()
 	if err != nil {
 		return err
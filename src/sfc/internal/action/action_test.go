@@ -4,6 +4,7 @@
 package action_test
 
 import (
+	"context"
 	"strings"
 
 	. "github.com/onsi/ginkgo"
@@ -288,7 +289,7 @@ var _ = Describe("SFCAction", func() {
 		err = (*sfcClientSelectorClient).DeleteSfcClientSelectorIntent("sfcRightClientSelectorIntentName", "testp", "chainCA", "v1", "dig1", "netctl", "sfcIntentName")
 		Expect(err).To(BeNil())
 
-		err = action.UpdateAppContext("netctl", contextIdCA1)
+		err = action.UpdateAppContext(context.Background(), "netctl", contextIdCA1)
 		Expect(strings.Contains(err.Error(), "Missing left and right client selector intents")).To(Equal(true))
 	})
 
@@ -296,7 +297,7 @@ var _ = Describe("SFCAction", func() {
 		err := (*sfcClientSelectorClient).DeleteSfcClientSelectorIntent("sfcLeftClientSelectorIntentName", "testp", "chainCA", "v1", "dig1", "netctl", "sfcIntentName")
 		Expect(err).To(BeNil())
 
-		err = action.UpdateAppContext("netctl", contextIdCA1)
+		err = action.UpdateAppContext(context.Background(), "netctl", contextIdCA1)
 		Expect(strings.Contains(err.Error(), "Missing left client selector intent")).To(Equal(true))
 	})
 
@@ -304,7 +305,7 @@ var _ = Describe("SFCAction", func() {
 		err := (*sfcClientSelectorClient).DeleteSfcClientSelectorIntent("sfcRightClientSelectorIntentName", "testp", "chainCA", "v1", "dig1", "netctl", "sfcIntentName")
 		Expect(err).To(BeNil())
 
-		err = action.UpdateAppContext("netctl", contextIdCA1)
+		err = action.UpdateAppContext(context.Background(), "netctl", contextIdCA1)
 		Expect(strings.Contains(err.Error(), "Missing right client selector intent")).To(Equal(true))
 	})
 
@@ -314,7 +315,7 @@ var _ = Describe("SFCAction", func() {
 		err = (*sfcProviderNetworkClient).DeleteSfcProviderNetworkIntent("sfcRightProviderNetworkIntentName", "testp", "chainCA", "v1", "dig1", "netctl", "sfcIntentName")
 		Expect(err).To(BeNil())
 
-		err = action.UpdateAppContext("netctl", contextIdCA1)
+		err = action.UpdateAppContext(context.Background(), "netctl", contextIdCA1)
 		Expect(strings.Contains(err.Error(), "Missing left and right provider network intent")).To(Equal(true))
 	})
 
@@ -322,7 +323,7 @@ var _ = Describe("SFCAction", func() {
 		err := (*sfcProviderNetworkClient).DeleteSfcProviderNetworkIntent("sfcLeftProviderNetworkIntentName", "testp", "chainCA", "v1", "dig1", "netctl", "sfcIntentName")
 		Expect(err).To(BeNil())
 
-		err = action.UpdateAppContext("netctl", contextIdCA1)
+		err = action.UpdateAppContext(context.Background(), "netctl", contextIdCA1)
 		Expect(strings.Contains(err.Error(), "Missing left provider network intent")).To(Equal(true))
 	})
 
@@ -330,17 +331,17 @@ var _ = Describe("SFCAction", func() {
 		err := (*sfcProviderNetworkClient).DeleteSfcProviderNetworkIntent("sfcRightProviderNetworkIntentName", "testp", "chainCA", "v1", "dig1", "netctl", "sfcIntentName")
 		Expect(err).To(BeNil())
 
-		err = action.UpdateAppContext("netctl", contextIdCA1)
+		err = action.UpdateAppContext(context.Background(), "netctl", contextIdCA1)
 		Expect(strings.Contains(err.Error(), "Missing right provider network intent")).To(Equal(true))
 	})
 
 	It("Successful Apply SFC to an App Context", func() {
-		err := action.UpdateAppContext("netctl", contextIdCA1)
+		err := action.UpdateAppContext(context.Background(), "netctl", contextIdCA1)
 		Expect(err).To(BeNil())
 	})
 
 	It("Net Control Intent does not exist", func() {
-		err := action.UpdateAppContext("netctlNot", contextIdCA1)
+		err := action.UpdateAppContext(context.Background(), "netctlNot", contextIdCA1)
 		Expect(strings.Contains(err.Error(), "Net Control Intent not found")).To(Equal(true))
 	})
 
@@ -360,7 +361,7 @@ var _ = Describe("SFCAction", func() {
 		resultingCA, err = cacontext.ReadAppContext(contextIdCA1)
 		cacontext.PrintCompositeApp(resultingCA)
 
-		err = action.UpdateAppContext("netctl", contextIdCA1)
+		err = action.UpdateAppContext(context.Background(), "netctl", contextIdCA1)
 		Expect(strings.Contains(err.Error(), "No SFC Intents are defined for the Network Control Intent")).To(Equal(true))
 	})
 
@@ -380,7 +381,7 @@ var _ = Describe("SFCAction", func() {
 		resultingCA, err = cacontext.ReadAppContext(contextIdCA1)
 		cacontext.PrintCompositeApp(resultingCA)
 
-		err = action.UpdateAppContext("netctl", contextIdCA1)
+		err = action.UpdateAppContext(context.Background(), "netctl", contextIdCA1)
 		Expect(err).To(BeNil())
 	})
 })
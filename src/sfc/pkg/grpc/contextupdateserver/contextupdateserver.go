@@ -24,7 +24,7 @@ func (cs *contextupdateServer) UpdateAppContext(ctx context.Context, req *contex
 		"IntentName":   req.IntentName,
 	})
 
-	err := action.UpdateAppContext(req.IntentName, req.AppContext)
+	err := action.UpdateAppContext(ctx, req.IntentName, req.AppContext)
 
 	if err != nil {
 		return &contextpb.ContextUpdateResponse{AppContextUpdated: false, AppContextUpdateMessage: err.Error()}, nil
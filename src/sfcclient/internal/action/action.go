@@ -4,6 +4,7 @@
 package action
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 
@@ -68,13 +69,14 @@ func chainClusters(apps []string, ac catypes.CompositeApp) map[string]struct{} {
 // UpdateAppContext applies the supplied intent against the given AppContext ID
 // The SFC Client controller will handle all SFC Client intents that are found for the
 // supplied Network Control Intent (intentName).
-func UpdateAppContext(intentName, appContextId string) error {
+func UpdateAppContext(ctx context.Context, intentName, appContextId string) error {
 
 	var ac appcontext.AppContext
 	_, err := ac.LoadAppContext(appContextId)
 	if err != nil {
 		return pkgerrors.Wrapf(err, "Error loading AppContext with Id: %v", appContextId)
 	}
+	ac = ac.WithContext(ctx)
 	//cahandle, err := ac.GetCompositeAppHandle()
 	_, err = ac.GetCompositeAppHandle()
 	if err != nil {
@@ -4,6 +4,7 @@
 package action_test
 
 import (
+	"context"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/open-ness/EMCO/src/orchestrator/pkg/appcontext"
@@ -342,7 +343,7 @@ var _ = Describe("SFC Client Action", func() {
 
 	It("Successful Apply SFC to an App Context", func() {
 		// TODO - unit test code needs to be completed (setup of test appcontexts, etc. need work)
-		err := action.UpdateAppContext("netctl", contextIdCA1)
+		err := action.UpdateAppContext(context.Background(), "netctl", contextIdCA1)
 		Expect(err).To(HaveOccurred())
 	})
 